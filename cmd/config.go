@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with the urdrc",
+	// The urdrc is only read as needed, it may not load
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		useConfigFlag()
+	},
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check [urdrc]",
+	Short: "Check an urdrc for mistakes",
+	Long: `Check the urdrc, or the given file, and report lines that don't load,
+bindings to unknown actions, keys bound twice or shadowing multi-key
+bindings, actions left without a key, templates with unknown placeholders,
+and files that can't be read.
+
+Exits with 0 when nothing was found, 1 when there are errors and 2 when
+there are only warnings, so it can check the urdrc in a dotfiles repository.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigCheck,
+}
+
+var configWyrdCmd = &cobra.Command{
+	Use:   "wyrd [wyrdrc]",
+	Short: "Convert a wyrdrc to an urdrc",
+	Long: `Convert ~/.wyrdrc, or the given file, to an urdrc printed on standard
+output. Variables, bindings and colors are mapped to urd's, and files the
+wyrdrc includes are inlined. Lines urd has no equivalent for are kept as
+comments and reported on standard error.
+
+Until there's an urdrc, urd reads ~/.wyrdrc the same way. To migrate:
+
+  urd config wyrd > ~/.config/urd/urdrc`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigWyrd,
+}
+
+func init() {
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configWyrdCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) error {
+	path := config.FindConfigFile()
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		return fmt.Errorf("no urdrc found")
+	}
+
+	problems, err := config.Check(path)
+	if err != nil {
+		return err
+	}
+
+	errors := 0
+	for _, problem := range problems {
+		if problem.Line > 0 {
+			fmt.Printf("%s:%d: %s\n", path, problem.Line, problem)
+		} else {
+			fmt.Printf("%s: %s\n", path, problem)
+		}
+		if !problem.Warning {
+			errors++
+		}
+	}
+
+	switch {
+	case errors > 0:
+		fmt.Fprintf(os.Stderr, "%d errors, %d warnings\n", errors, len(problems)-errors)
+		os.Exit(1)
+	case len(problems) > 0:
+		fmt.Fprintf(os.Stderr, "%d warnings\n", len(problems))
+		os.Exit(2)
+	}
+	fmt.Printf("%s is fine\n", path)
+	return nil
+}
+
+func runConfigWyrd(cmd *cobra.Command, args []string) error {
+	path := config.DefaultWyrdConfigPath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	lines, problems, err := config.TranslateWyrd(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# Converted from %s by urd config wyrd\n", path)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, problem.Line, problem)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+// demoDate is when urd --demo starts, so every demo looks the same and bug
+// reports made with it can be reproduced
+var demoDate = time.Date(2025, time.August, 25, 9, 40, 0, 0, time.Local)
+
+// demoReminders is the sample calendar of urd --demo. It only uses REM lines
+// the built-in evaluator understands, so the demo also works without remind.
+const demoReminders = `# Sample calendar for urd --demo. Edits are thrown away on exit.
+
+# Every week
+REM Mon Tue Wed Thu Fri AT 9:30 DURATION 0:15 TAG work MSG Standup
+REM Mon Wed Fri AT 7:00 DURATION 1:00 MSG Run
+REM Tue AT 14:00 DURATION 0:30 TAG work INFO "Location: Room 4" MSG 1:1 with Sam
+REM Thu AT 16:00 DURATION 1:00 TAG work MSG Design review
+REM Fri AT 12:30 DURATION 1:00 MSG Lunch with Alex
+REM Sat AT 10:00 DURATION 2:00 MSG Farmers market
+
+# This week and next
+REM 25 Aug 2025 AT 11:00 DURATION 1:30 TAG work PRIORITY 9000 MSG Quarterly planning
+REM 26 Aug 2025 AT 10:00 DURATION 2:00 TAG work MSG Focus: write the proposal
+REM 27 Aug 2025 AT 15:30 DURATION 0:45 INFO "Location: Dr. Lee, 12 Main St" MSG Dentist
+REM 28 Aug 2025 AT 18:30 DURATION 2:30 MSG Concert
+REM 2 Sep 2025 AT 9:00 DURATION 8:00 TAG work MSG Offsite
+REM 29 Aug 2025 PRIORITY 9000 TAG work MSG Proposal due
+
+# All day and yearly
+REM 27 Aug MSG Jordan's birthday
+REM 1 MSG Pay rent
+OMIT 1 Sep 2025 MSG Labor Day
+`
+
+// setupDemo writes the sample calendar and an urdrc using it to a temporary
+// directory, which the caller removes. The urdrc is the one urd reads and
+// edits, unless another was given with --config. The clipboard and pinned
+// events are kept there too, leaving the real ones alone.
+func setupDemo() (string, error) {
+	dir, err := os.MkdirTemp("", "urd-demo-")
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, "demo.rem")
+	if err := os.WriteFile(file, []byte(demoReminders), 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	remindFiles = []string{file}
+	os.Setenv("XDG_STATE_HOME", dir)
+
+	if cfgFile == "" {
+		rc := filepath.Join(dir, "urdrc")
+		if err := os.WriteFile(rc, []byte(config.StarterConfig(file, cfg.RemindCommand)), 0644); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		os.Setenv("URD_CONFIG", rc)
+		if cfg, err = config.LoadConfig(); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	return dir, nil
+}
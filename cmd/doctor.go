@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Fetch a week of events from each source and report how it went",
+	Long: `Fetch the coming week of events from each configured source, and start
+watching them, reporting for each the events found, how long fetching took,
+whether watching works and any error, to tell which source is failing when
+events are missing.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	remindClient := newRemindClient()
+	if remindClient.Degraded() {
+		fmt.Printf("%s not found, reading reminders with the built-in evaluator\n", remindClient.RemindPath)
+	}
+
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+	composite, ok := source.(*remind.CompositeSource)
+	if !ok {
+		composite = remind.NewCompositeSource(source)
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if _, err := composite.GetEvents(start, start.AddDate(0, 0, 7)); err != nil {
+		return err
+	}
+	composite.WatchFiles()
+	sources := composite.Health()
+	composite.StopWatching()
+
+	failed := 0
+	for _, health := range sources {
+		latency := health.Latency.Round(time.Millisecond)
+		if health.LastError != nil {
+			failed++
+			fmt.Printf("%s: FAILED after %s: %v\n", health.Name, latency, health.LastError)
+		} else {
+			fmt.Printf("%s: %d events in %s\n", health.Name, health.Events, latency)
+		}
+		switch {
+		case health.WatchError != nil:
+			fmt.Printf("  %s: %v\n", health.Watch, health.WatchError)
+		case health.Watch != "":
+			fmt.Printf("  %s\n", health.Watch)
+		}
+	}
+	printWarnings(source)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of the sources failed", failed)
+	}
+	return nil
+}
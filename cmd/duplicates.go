@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	duplicatesFrom string
+	duplicatesDays int
+)
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Find likely duplicate events across files and sources",
+	Long: `List the events that are likely duplicates, as merged calendars accumulate
+them: on the same day, both untimed or starting within 5 minutes of each
+other, with similar descriptions, from different files, lines or sources.
+Repeating reminders are listed once.
+
+On a terminal, each pair is followed by the choice of deleting one copy.
+Deleting a repeating reminder deletes all its occurrences.`,
+	RunE: runDuplicates,
+}
+
+func init() {
+	duplicatesCmd.Flags().StringVar(&duplicatesFrom, "from", "", "First day to look at, as YYYY-MM-DD (default today)")
+	duplicatesCmd.Flags().IntVar(&duplicatesDays, "days", 90, "Days to look at")
+	rootCmd.AddCommand(duplicatesCmd)
+}
+
+func runDuplicates(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if duplicatesFrom != "" {
+		var err error
+		if start, err = time.ParseInLocation("2006-01-02", duplicatesFrom, time.Local); err != nil {
+			return fmt.Errorf("invalid --from: %s", duplicatesFrom)
+		}
+	}
+	if duplicatesDays < 1 {
+		return fmt.Errorf("invalid --days: %d", duplicatesDays)
+	}
+	end := start.AddDate(0, 0, duplicatesDays)
+
+	remindClient := newRemindClient()
+	if err := checkRemind(remindClient); err != nil {
+		return err
+	}
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+
+	reader := bufio.NewReader(os.Stdin)
+	prompt := isTerminal(os.Stdin)
+	answered := make(map[string]bool)
+	listed := 0
+	for {
+		// Deleting moves the lines after, so look again after each
+		events, err := source.GetEvents(start, end)
+		if err != nil {
+			return err
+		}
+		var duplicates []remind.Duplicate
+		for _, duplicate := range remind.FindDuplicates(events) {
+			if !answered[duplicate.Key()] {
+				duplicates = append(duplicates, duplicate)
+			}
+		}
+		if len(duplicates) == 0 {
+			break
+		}
+		if !prompt {
+			for _, duplicate := range duplicates {
+				printDuplicate(duplicate)
+			}
+			listed += len(duplicates)
+			break
+		}
+
+		duplicate := duplicates[0]
+		answered[duplicate.Key()] = true
+		listed++
+		printDuplicate(duplicate)
+		fmt.Print("Delete 1, 2 or neither? [1/2/N]: ")
+		line, _ := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer != "1" && answer != "2" {
+			continue
+		}
+		event := duplicate.Events[0]
+		if answer == "2" {
+			event = duplicate.Events[1]
+		}
+		writer, ok := source.(remind.EventWriter)
+		if !ok || !remind.SourceCapabilities(source, event).Remove {
+			fmt.Printf("%s events are read-only\n", eventOrigin(event))
+			continue
+		}
+		if err := writer.RemoveEvent(event); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", eventOrigin(event))
+	}
+	printWarnings(source)
+
+	if listed == 0 {
+		fmt.Printf("No likely duplicates from %s to %s\n", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+	return nil
+}
+
+// printDuplicate lists the two events of a likely duplicate, numbered for
+// choosing which to delete
+func printDuplicate(duplicate remind.Duplicate) {
+	fmt.Printf("%s, %.0f%% similar:\n", duplicate.Events[0].Date.Format("Mon Jan 2 2006"), duplicate.Similarity*100)
+	for i, event := range duplicate.Events {
+		when := "all day"
+		if event.Time != nil {
+			when = event.Time.Format("15:04")
+		}
+		repeating := ""
+		if event.IsRepeating {
+			repeating = " (repeating)"
+		}
+		fmt.Printf("  %d. %-7s %s  [%s]%s\n", i+1, when, event.Description, eventOrigin(event), repeating)
+	}
+}
+
+// eventOrigin returns where an event comes from: its file and line, or its
+// source
+func eventOrigin(event remind.Event) string {
+	if location := event.SourceLocation(); location != "" {
+		return location
+	}
+	return event.Source
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFrom   string
+	exportTo     string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export events to other formats and exit",
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Export the events of a range of days as CSV",
+	Long: `Export the events from --from through --to, a week from today by default,
+as CSV for spreadsheets: one row per occurrence with its date, start, end,
+duration in minutes, description, tags, priority and source. Private events
+are redacted when presentation_mode is set.`,
+	RunE: runExportCSV,
+}
+
+func init() {
+	exportCSVCmd.Flags().StringVar(&exportFrom, "from", "", "First day to export, as YYYY-MM-DD (default today)")
+	exportCSVCmd.Flags().StringVar(&exportTo, "to", "", "Last day to export, as YYYY-MM-DD (default 6 days after --from)")
+	exportCSVCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write instead of stdout")
+	exportCmd.AddCommand(exportCSVCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportRange parses --from and --to
+func exportRange(now time.Time) (time.Time, time.Time, error) {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if exportFrom != "" {
+		var err error
+		if from, err = time.ParseInLocation("2006-01-02", exportFrom, time.Local); err != nil {
+			return from, from, fmt.Errorf("invalid --from: %s", exportFrom)
+		}
+	}
+	to := from.AddDate(0, 0, 6)
+	if exportTo != "" {
+		var err error
+		if to, err = time.ParseInLocation("2006-01-02", exportTo, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid --to: %s", exportTo)
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("--to is before --from")
+	}
+	return from, to, nil
+}
+
+func runExportCSV(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	from, to, err := exportRange(time.Now())
+	if err != nil {
+		return err
+	}
+
+	remindClient := newRemindClient()
+	if err := checkRemind(remindClient); err != nil {
+		return err
+	}
+
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+	events, err := source.GetEvents(from, to)
+	if err != nil {
+		return err
+	}
+	printWarnings(source)
+
+	if cfg.PresentationMode {
+		for i, event := range events {
+			if event.IsPrivate() {
+				events[i] = event.Redacted()
+			}
+		}
+	}
+
+	out := os.Stdout
+	if exportOutput != "" {
+		if out, err = os.Create(exportOutput); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	return remind.WriteCSV(out, events)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtWrite bool
+	fmtDiff  bool
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file...]",
+	Short: "Tidy remind files, showing the changes before writing them",
+	Long: `Tidy remind files, the configured ones by default: keywords in upper case,
+single spaces between the words of triggers, month and weekday names
+abbreviated and full dates written like Aug 25 2025. Runs of one-off
+reminders are sorted by date and time, with the comments above each. Blank
+lines, comments and other lines stay where they are, as do reminders using
+expressions or continued over several lines.
+
+The changes are shown as a diff, then written once confirmed, or right away
+with --write.`,
+	RunE: runFmt,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "Write the changes without asking")
+	fmtCmd.Flags().BoolVarP(&fmtDiff, "diff", "d", false, "Only show the changes")
+	fmtCmd.MarkFlagsMutuallyExclusive("write", "diff")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	files := args
+	if len(files) == 0 {
+		files = cfg.RemindFiles
+		if len(remindFiles) > 0 {
+			files = remindFiles
+		}
+		files = remind.ExpandFiles(files)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w: no remind files to format", remind.ErrNoFiles)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	prompt := !fmtWrite && !fmtDiff && isTerminal(os.Stdin)
+	unformatted := 0
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		formatted := remind.FormatReminders(string(content))
+		diff := remind.UnifiedDiff(file, string(content), formatted)
+		if diff == "" {
+			continue
+		}
+		fmt.Print(diff)
+
+		write := fmtWrite
+		if prompt {
+			fmt.Printf("Write %s? [y/N]: ", file)
+			line, _ := reader.ReadString('\n')
+			answer := strings.ToLower(strings.TrimSpace(line))
+			write = answer == "y" || answer == "yes"
+		}
+		if !write {
+			unformatted++
+			continue
+		}
+		if err := remind.WriteFormatted(file, string(content), formatted); err != nil {
+			return err
+		}
+		fmt.Printf("Formatted %s\n", file)
+	}
+
+	if unformatted > 0 && !prompt && !fmtDiff {
+		fmt.Fprintf(os.Stderr, "%d files not written, confirm on a terminal or use --write\n", unformatted)
+	}
+	return nil
+}
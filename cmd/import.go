@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importPreset  string
+	importColumns []string
+	importInto    string
+	importDryRun  bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import events from other formats and exit",
+}
+
+var importCSVCmd = &cobra.Command{
+	Use:   "csv file.csv",
+	Short: "Import events from a CSV file as REM lines",
+	Long: `Import the events of a CSV file with a header row, like the ones Outlook,
+Toggl or urd export csv write, appending a REM line for each to a remind
+file: the first remind file unless --into names another.
+
+The columns events are read from are those of --preset (urd, outlook or
+toggl), changed with --map field=column for each field that's in another
+column. The fields are date, start, end, duration, description, tags,
+priority, location and all_day; date and description are needed.
+
+Rows that can't be read are reported by their line and skipped, and urd
+exits with status 1. With --dry-run, the REM lines are printed instead of
+added.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportCSV,
+}
+
+func init() {
+	importCSVCmd.Flags().StringVar(&importPreset, "preset", "urd", "Columns of the CSV files of: urd, outlook or toggl")
+	importCSVCmd.Flags().StringArrayVar(&importColumns, "map", nil, `Column a field is in, as field=column, e.g. --map "description=Task name" (can be specified multiple times)`)
+	importCSVCmd.Flags().StringVar(&importInto, "into", "", "Remind file to add the events to (default the first remind file)")
+	importCSVCmd.Flags().BoolVarP(&importDryRun, "dry-run", "n", false, "Print the REM lines instead of adding them")
+	importCmd.AddCommand(importCSVCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+// importMapping returns the columns of --preset changed by --map
+func importMapping() (remind.CSVColumns, error) {
+	preset, ok := remind.CSVPresets[importPreset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset: %s", importPreset)
+	}
+	columns := make(remind.CSVColumns)
+	for field, column := range preset {
+		columns[field] = column
+	}
+	for _, mapping := range importColumns {
+		field, column, ok := strings.Cut(mapping, "=")
+		if !ok || !contains(remind.CSVFields, field) {
+			return nil, fmt.Errorf("invalid --map %q: expected field=column, with a field of %s", mapping, strings.Join(remind.CSVFields, ", "))
+		}
+		columns[field] = column
+	}
+	return columns, nil
+}
+
+func runImportCSV(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	columns, err := importMapping()
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	events, rowErrors, err := remind.ReadCSV(in, columns)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	var lines []string
+	for _, event := range events {
+		lines = append(lines, remind.RemLine(event))
+	}
+
+	if importDryRun {
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	} else if len(lines) > 0 {
+		file := config.ExpandHome(importInto)
+		if file == "" {
+			files := cfg.RemindFiles
+			if len(remindFiles) > 0 {
+				files = remindFiles
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no remind file to import into, use --into")
+			}
+			file = files[0]
+		}
+		if err := appendLines(file, lines); err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d events into %s\n", len(lines), file)
+	}
+
+	for _, err := range rowErrors {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+	}
+	if len(rowErrors) > 0 {
+		fmt.Fprintf(os.Stderr, "%d rows not imported\n", len(rowErrors))
+		os.Exit(1)
+	}
+	return nil
+}
+
+// appendLines adds lines to the end of a file, on a line of their own
+func appendLines(file string, lines []string) error {
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	text := strings.Join(lines, "\n") + "\n"
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		text = "\n" + text
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
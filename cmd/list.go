@@ -26,35 +26,17 @@ func runList(cmd *cobra.Command, args []string) error {
 		initConfig()
 	}
 
-	// Initialize reminder source(s)
-	var source remind.ReminderSource
-
 	// Always start with remind client
-	remindClient := remind.NewClient()
-	remindClient.RemindPath = cfg.RemindCommand
-
-	// Use command-line specified files if provided, otherwise use config files
-	if len(remindFiles) > 0 {
-		remindClient.SetFiles(remindFiles)
-	} else {
-		remindClient.SetFiles(cfg.RemindFiles)
-	}
-
-	// Test remind connection
-	if err := remindClient.TestConnection(); err != nil {
-		return fmt.Errorf("remind connection failed: %w", err)
+	remindClient := newRemindClient()
+	if err := checkRemind(remindClient); err != nil {
+		return err
 	}
 
-	// If p2 is requested, create a composite source
-	if useP2 {
-		p2Client := remind.NewP2Client()
-		p2Client.SetFiles([]string{p2File})
-		// Create composite source with both remind and p2
-		source = remind.NewCompositeSource(remindClient, p2Client)
-	} else {
-		// Use remind client alone
-		source = remindClient
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
 	}
+	defer closeSource(source)
 
 	// Get today's events - normalize to midnight for date comparison
 	now := time.Now()
@@ -64,15 +46,29 @@ func runList(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	printWarnings(source)
+
+	// MOON, SHADE and WEEK specials annotate the calendar, they aren't events
+	var todays []remind.Event
+	for _, event := range events {
+		if !event.IsSpecial() {
+			todays = append(todays, event)
+		}
+	}
+	events = todays
 
 	// Display events
-	fmt.Printf("Events for %s:\n", time.Now().Format(cfg.DateFormat))
+	fmt.Printf("Events for %s:\n", time.Now().Format(cfg.DateLayout(cfg.DateFormat)))
 	if len(events) == 0 {
 		fmt.Println("No events found.")
 		return nil
 	}
 
 	for _, event := range events {
+		if cfg.PresentationMode && event.IsPrivate() {
+			event = event.Redacted()
+		}
+
 		timeStr := "All day"
 		if event.Time != nil {
 			timeStr = event.Time.Format(cfg.TimeFormat)
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var nextCount int
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "List the next upcoming events and exit",
+	Long: `List the next upcoming events across all sources, starting from now,
+regardless of how far ahead they are.`,
+	RunE: runNext,
+}
+
+func init() {
+	nextCmd.Flags().IntVarP(&nextCount, "count", "n", 10, "Number of events to show")
+	rootCmd.AddCommand(nextCmd)
+}
+
+func runNext(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	remindClient := newRemindClient()
+	if err := checkRemind(remindClient); err != nil {
+		return err
+	}
+
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+
+	events, err := remind.Upcoming(source, time.Now(), nextCount)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printWarnings(source)
+
+	fmt.Println("Upcoming events:")
+	if len(events) == 0 {
+		fmt.Println("No events found.")
+		return nil
+	}
+
+	for _, event := range events {
+		if cfg.PresentationMode && event.IsPrivate() {
+			event = event.Redacted()
+		}
+
+		when := event.Date.Format(cfg.DateLayout(cfg.DateFormat))
+		if event.Time != nil {
+			when += " " + event.Time.Format(cfg.TimeFormat)
+		}
+
+		priorityStr := ""
+		switch event.Priority {
+		case remind.PriorityHigh:
+			priorityStr = "!!!"
+		case remind.PriorityMedium:
+			priorityStr = "!!"
+		case remind.PriorityLow:
+			priorityStr = "!"
+		}
+
+		fmt.Printf("  %s - %s%s\n", when, event.Description, priorityStr)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFrom   string
+	reportFormat string
+	reportOutput string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize events for reviews and exit",
+}
+
+var reportWeekCmd = &cobra.Command{
+	Use:   "week",
+	Short: "Summarize the week past and the week to come",
+	Long: `Summarize the seven days from --from, the week up to yesterday by default:
+the timed events and the hours they were scheduled for, by tag, and the
+reminders marked done. Then list the one-off and prioritized events of the
+week after, as highlights. The summary is Markdown, or an HTML page for
+email with --format html. Private events are redacted when presentation_mode
+is set.`,
+	RunE: runReportWeek,
+}
+
+func init() {
+	reportWeekCmd.Flags().StringVar(&reportFrom, "from", "", "First day of the week past, as YYYY-MM-DD (default 7 days ago)")
+	reportWeekCmd.Flags().StringVar(&reportFormat, "format", "markdown", "Format of the summary: markdown or html")
+	reportWeekCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "File to write instead of stdout")
+	reportCmd.AddCommand(reportWeekCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReportWeek(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()-7, 0, 0, 0, 0, now.Location())
+	if reportFrom != "" {
+		var err error
+		if start, err = time.ParseInLocation("2006-01-02", reportFrom, time.Local); err != nil {
+			return fmt.Errorf("invalid --from: %s", reportFrom)
+		}
+	}
+	var write func(remind.WeekReport, io.Writer) error
+	switch reportFormat {
+	case "markdown", "md":
+		write = remind.WeekReport.WriteMarkdown
+	case "html":
+		write = remind.WeekReport.WriteHTML
+	default:
+		return fmt.Errorf("invalid --format: %s (markdown or html)", reportFormat)
+	}
+
+	remindClient := newRemindClient()
+	if err := checkRemind(remindClient); err != nil {
+		return err
+	}
+
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+	end := start.AddDate(0, 0, 7)
+	past, err := source.GetEvents(start, end.AddDate(0, 0, -1))
+	if err != nil {
+		return err
+	}
+	coming, err := source.GetEvents(end, end.AddDate(0, 0, 6))
+	if err != nil {
+		return err
+	}
+	printWarnings(source)
+	done, err := remind.ReadDone(remindClient.Files)
+	if err != nil {
+		return err
+	}
+
+	if cfg.PresentationMode {
+		for _, events := range [][]remind.Event{past, coming} {
+			for i, event := range events {
+				if event.IsPrivate() {
+					events[i] = event.Redacted()
+				}
+			}
+		}
+		for i, item := range done {
+			if item.Private {
+				done[i].Description = remind.RedactedDescription
+			}
+		}
+	}
+
+	out := os.Stdout
+	if reportOutput != "" {
+		if out, err = os.Create(reportOutput); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	return write(remind.NewWeekReport(start, past, coming, done), out)
+}
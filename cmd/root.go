@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/cwarden/urd/internal/config"
 	"github.com/cwarden/urd/internal/remind"
@@ -17,6 +19,10 @@ var (
 	remindFiles []string
 	useP2       bool
 	p2File      string
+	accessible  bool
+	demo        bool
+	record      string
+	replay      string
 	cfg         *config.Config
 )
 
@@ -25,6 +31,9 @@ var rootCmd = &cobra.Command{
 	Short: "A terminal calendar application for the remind calendar system",
 	Long: `Urd is a terminal calendar application providing a TUI frontend for
 the remind calendar system (and the forthcoming p2 project management tool).`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
 	RunE: runTUI,
 }
 
@@ -33,14 +42,35 @@ func Execute() error {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
-	rootCmd.PersistentFlags().StringSliceVarP(&remindFiles, "file", "f", []string{}, "Remind file(s) to use (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "urdrc to use instead of the one found in the usual places")
+	rootCmd.PersistentFlags().StringSliceVarP(&remindFiles, "file", "f", []string{}, "Remind file(s) to use instead of remind_files (can be specified multiple times)")
 	rootCmd.PersistentFlags().BoolVar(&useP2, "p2", false, "Include p2 tasks as calendar events")
 	rootCmd.PersistentFlags().StringVar(&p2File, "p2-file", "tasks.rec", "Path to p2 tasks file")
+	rootCmd.Flags().BoolVar(&demo, "demo", false, "Explore urd with a sample calendar in a temporary directory, starting on "+demoDate.Format("Mon Jan 2, 2006"))
+	rootCmd.Flags().BoolVar(&accessible, "a11y", false, "Screen reader friendly output: plain labeled lines, no colors or layout")
+	rootCmd.Flags().StringVar(&record, "record", "", "Record the keys pressed, window sizes and events shown, with the text of events and what is typed redacted, to a file for a bug report")
+	rootCmd.Flags().StringVar(&replay, "replay", "", "Replay a session recorded with --record")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+}
+
+// useConfigFlag makes the urdrc given with --config the one found in place
+// of $URD_CONFIG, so that it's also the one edited with C and checked with
+// urd config check
+func useConfigFlag() {
+	if cfgFile == "" {
+		return
+	}
+	cfgFile = config.ExpandHome(cfgFile)
+	if _, err := os.Stat(cfgFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("URD_CONFIG", cfgFile)
 }
 
 func initConfig() {
+	useConfigFlag()
+
 	var err error
 	cfg, err = config.LoadConfig()
 	if err != nil {
@@ -50,46 +80,312 @@ func initConfig() {
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
-	// Initialize reminder source(s)
-	var source remind.ReminderSource
+	if demo {
+		dir, err := setupDemo()
+		if err != nil {
+			return fmt.Errorf("failed to set up demo: %w", err)
+		}
+		defer os.RemoveAll(dir)
+	}
+	if replay != "" {
+		return runReplay()
+	}
 
-	// Always start with remind client
-	remindClient := remind.NewClient()
-	remindClient.RemindPath = cfg.RemindCommand
+	// Walk new users through the setup, unless urd is run from a script
+	if !demo && needsSetup() && isTerminal(os.Stdin) {
+		if err := runSetup(os.Stdin, os.Stdout); err != nil {
+			return err
+		}
+	}
 
-	// Use command-line specified files if provided, otherwise use config files
+	// Always start with remind client
+	remindClient := newRemindClient()
+	if demo {
+		remindClient.Clock = remind.ClockFrom(demoDate)
+	}
 	if len(remindFiles) > 0 {
-		remindClient.SetFiles(remindFiles)
 		// Also update the config so the UI has the correct files for editing
 		cfg.RemindFiles = remindFiles
-	} else {
-		remindClient.SetFiles(cfg.RemindFiles)
 	}
 
-	// Test remind connection (only for remind client, not the interface)
-	if err := remindClient.TestConnection(); err != nil {
+	// Without remind the TUI still shows what it can read
+	if err := checkRemind(remindClient); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Please ensure 'remind' is installed and in your PATH\n")
 	}
 
-	// If p2 is requested, create a composite source
-	if useP2 {
-		p2Client := remind.NewP2Client()
-		p2Client.SetFiles([]string{p2File})
-		// Create composite source with both remind and p2
-		source = remind.NewCompositeSource(remindClient, p2Client)
-	} else {
-		// Use remind client alone
-		source = remindClient
+	source, err := newSource(remindClient)
+	if err != nil {
+		return err
+	}
+	defer closeSource(source)
+	// Show the reminders right away, loading the slow sources after
+	if composite, ok := source.(*remind.CompositeSource); ok {
+		for _, name := range slowSources() {
+			composite.SetLazy(name)
+		}
+	}
+
+	if accessible {
+		cfg.Accessible = true
+	}
+
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithReportFocus()}
+	var recorder *ui.SessionRecorder
+	if record != "" {
+		recorder = ui.NewSessionRecorder(remindClient.Clock.Now())
+		if recording, ok := source.(remind.RecordingSource); ok {
+			recording.SetRecorder(recorder.Record)
+		}
+		options = append(options, tea.WithFilter(recorder.Filter))
 	}
 
 	// Start TUI
 	model := ui.NewModelWithRemind(cfg, source, remindClient)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	p := tea.NewProgram(model, options...)
+
+	_, err = p.Run()
+	if recorder != nil {
+		// Saved even when urd failed, as that's what's worth reporting
+		if saveErr := recorder.Save(record); saveErr != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save the session: %v\n", saveErr)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error running program: %w", err)
+	}
+
+	return nil
+}
+
+// runReplay replays the session given with --replay, with the events
+// recorded in place of the sources and the clock set to when it was
+// recorded. Nothing is written, run, opened or copied, see replayConfig.
+func runReplay() error {
+	session, err := ui.LoadSession(replay)
+	if err != nil {
+		return err
+	}
+
+	// Keep the clipboard and pinned events of the replay apart from the real
+	// ones, which its copies, cuts and pins would otherwise overwrite
+	stateDir, err := os.MkdirTemp("", "urd-replay-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stateDir)
+	os.Setenv("XDG_STATE_HOME", stateDir)
+
+	remindClient := remind.NewClient()
+	remindClient.RemindPath = cfg.RemindCommand
+	remindClient.Clock = remind.ClockFrom(session.Started)
+	remindClient.ReadOnly = true
+	if accessible {
+		cfg.Accessible = true
+	}
+
+	model := ui.NewModelWithRemind(replayConfig(cfg, stateDir), remind.NewReplaySource(session.Responses), remindClient)
+	player := ui.NewSessionPlayer(session)
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithFilter(player.Filter))
+	go player.Play(p.Send)
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running program: %w", err)
 	}
+	return nil
+}
 
+// replayConfig returns cfg without anything a replay could write to or run:
+// no remind files, template targets, focus log, journal or hooks, and
+// screenshots kept in dir. The keys, colors and layout stay as recorded.
+func replayConfig(cfg *config.Config, dir string) *config.Config {
+	replayed := *cfg
+	replayed.RemindFiles = nil
+	replayed.TemplateTargets = nil
+	replayed.CalDAVFiles = nil
+	replayed.FocusLog = ""
+	replayed.JournalDir = ""
+	replayed.ScreenshotDir = dir
+	replayed.Hooks = nil
+	replayed.ReadOnly = true
+	return &replayed
+}
+
+// newSource combines the remind client with the p2 sources requested with
+// --p2 and defined as p2 profiles in urdrc, and the sources defined with the
+// source directive, applying the refresh rates, dedup policy and ignore rules
+// of urdrc. The remind client is returned alone when none of those is set.
+func newSource(remindClient *remind.Client) (remind.ReminderSource, error) {
+	var p2Clients []*remind.P2Client
+	if useP2 {
+		p2Client := remind.NewP2Client()
+		if cfg.P2Command != "" {
+			var err error
+			if p2Client, err = remind.NewP2Profile("", cfg.P2Command); err != nil {
+				return nil, fmt.Errorf("invalid p2_command: %w", err)
+			}
+		} else {
+			p2Client.SetFiles([]string{p2File})
+		}
+		p2Clients = append(p2Clients, p2Client)
+	}
+
+	for _, profile := range cfg.P2Profiles {
+		p2Client, err := remind.NewP2Profile(profile.Name, profile.Command)
+		if err != nil {
+			return nil, fmt.Errorf("invalid p2 profile %s: %w", profile.Name, err)
+		}
+		if profile.Color != "" {
+			var color remind.Color
+			if _, err := fmt.Sscanf(profile.Color, "#%02x%02x%02x", &color.R, &color.G, &color.B); err == nil {
+				p2Client.Color = &color
+			}
+		}
+		p2Clients = append(p2Clients, p2Client)
+	}
+
+	remind.RegisterSource(remind.IssueProviderGitHub, issueSourceFactory(remind.IssueProviderGitHub, cfg.GitHubToken, "GITHUB_TOKEN"))
+	remind.RegisterSource(remind.IssueProviderGitLab, issueSourceFactory(remind.IssueProviderGitLab, cfg.GitLabToken, "GITLAB_TOKEN"))
+	remind.RegisterSource(remind.IssueProviderJira, jiraSourceFactory)
+
+	var others []remind.ReminderSource
+	for _, sourceCfg := range cfg.Sources {
+		source, err := remind.NewSource(sourceCfg.Name, sourceCfg.Kind, sourceCfg.Args)
+		if err != nil {
+			for _, started := range others {
+				closeSource(started)
+			}
+			return nil, fmt.Errorf("invalid source %s: %w", sourceCfg.Name, err)
+		}
+		others = append(others, source)
+	}
+
+	if len(p2Clients) == 0 && len(others) == 0 && len(cfg.RefreshRates) == 0 && len(cfg.Ignore) == 0 {
+		// Use remind client alone
+		return remindClient, nil
+	}
+
+	// Create composite source with remind, p2 and the other sources
+	composite := remind.NewCompositeSource(remindClient)
+	for _, p2Client := range p2Clients {
+		p2Client.CacheTTL = cfg.P2CacheTTL
+		composite.AddSource(p2Client)
+	}
+	for _, source := range others {
+		composite.AddSource(source)
+	}
+	for name, rate := range cfg.RefreshRates {
+		composite.SetRefreshRate(name, rate)
+	}
+	composite.SetDedupPolicy(cfg.DedupPolicy)
+	var ignore []remind.IgnoreRule
+	for _, rule := range cfg.Ignore {
+		ignore = append(ignore, remind.IgnoreRule(rule))
+	}
+	composite.SetIgnore(ignore)
+	return composite, nil
+}
+
+// slowSources returns the names of the sources that may take seconds to
+// load: p2, which runs a command, and the sources that query a server or
+// run a plugin
+func slowSources() []string {
+	var names []string
+	if useP2 {
+		names = append(names, remind.P2SourceName)
+	}
+	for _, profile := range cfg.P2Profiles {
+		names = append(names, profile.Name)
+	}
+	for _, sourceCfg := range cfg.Sources {
+		if sourceCfg.Kind != "holidays" {
+			names = append(names, sourceCfg.Name)
+		}
+	}
+	return names
+}
+
+// newRemindClient returns the client reading the remind files given on the
+// command line, else the configured ones
+func newRemindClient() *remind.Client {
+	remindClient := remind.NewClient()
+	remindClient.RemindPath = cfg.RemindCommand
+	remindClient.DayFirst = cfg.QuickDayFirst
+	if len(remindFiles) > 0 {
+		remindClient.SetFiles(remindFiles)
+	} else {
+		remindClient.SetFiles(cfg.RemindFiles)
+	}
+	return remindClient
+}
+
+// checkRemind makes sure remind works. Without remind, reminders are read
+// by the built-in evaluator, with a warning.
+func checkRemind(remindClient *remind.Client) error {
+	if remindClient.Degraded() {
+		fmt.Fprintf(os.Stderr, "Warning: %s not found, reading reminders with the built-in evaluator\n", remindClient.RemindPath)
+		return nil
+	}
+	if err := remindClient.TestConnection(); err != nil {
+		return fmt.Errorf("remind connection failed: %w", err)
+	}
 	return nil
 }
+
+// closeSource ends the sources that need it, such as plugins, which would
+// otherwise outlive urd
+func closeSource(source remind.ReminderSource) {
+	if closer, ok := source.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// printWarnings reports the problems sources ran into on stderr
+func printWarnings(source remind.ReminderSource) {
+	if warner, ok := source.(remind.WarningSource); ok {
+		for _, warning := range warner.Warnings() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
+}
+
+// issueSourceFactory creates github or gitlab sources with the configured
+// token, or the one in tokenEnv. The optional argument is the API location,
+// for GitHub Enterprise or a self-hosted GitLab.
+func issueSourceFactory(provider, token, tokenEnv string) remind.SourceFactory {
+	return func(name string, args []string) (remind.ReminderSource, error) {
+		if token == "" {
+			token = os.Getenv(tokenEnv)
+		}
+		var baseURL string
+		if len(args) > 0 {
+			baseURL = args[0]
+		}
+		return remind.NewIssueSource(name, provider, baseURL, token)
+	}
+}
+
+// jiraSourceFactory creates jira sources from the Jira site and an optional
+// JQL query, with the configured token and account
+func jiraSourceFactory(name string, args []string) (remind.ReminderSource, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("jira sources need the location of the Jira site")
+	}
+	token := cfg.JiraToken
+	if token == "" {
+		token = os.Getenv("JIRA_TOKEN")
+	}
+
+	source, err := remind.NewIssueSource(name, remind.IssueProviderJira, args[0], token)
+	if err != nil {
+		return nil, err
+	}
+	source.User = cfg.JiraUser
+	if len(args) > 1 {
+		source.JQL = strings.Join(args[1:], " ")
+	}
+	if cfg.JiraSprintField != "" {
+		source.SprintField = cfg.JiraSprintField
+	}
+	return source, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set up remind files and a starter urdrc",
+	Long: `Walk through the first-run setup: check that remind is installed, find
+or create a remind file with a sample event, and write a starter urdrc with
+the common options commented out. An existing urdrc is never overwritten.
+
+urd runs the setup by itself when started without an urdrc or remind file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetup(os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setupCmd)
+}
+
+// needsSetup reports whether this is a first run: there's no urdrc, no
+// remind files were given and the default one doesn't exist
+func needsSetup() bool {
+	if config.FindConfigFile() != "" || len(remindFiles) > 0 {
+		return false
+	}
+	for _, file := range cfg.RemindFiles {
+		if _, err := os.Stat(file); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isTerminal reports whether f is a terminal someone can answer prompts on
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runSetup asks the setup questions on in and out, then reloads the
+// configuration it wrote
+func runSetup(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	ask := func(question, answer string) string {
+		if answer != "" {
+			fmt.Fprintf(out, "%s [%s]: ", question, answer)
+		} else {
+			fmt.Fprintf(out, "%s: ", question)
+		}
+		line, _ := reader.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+		return answer
+	}
+	confirm := func(question string) bool {
+		fmt.Fprintf(out, "%s? [Y/n]: ", question)
+		line, _ := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		return answer == "" || answer == "y" || answer == "yes"
+	}
+
+	fmt.Fprintln(out, "Welcome to urd! Let's set up your calendar.")
+	fmt.Fprintln(out)
+
+	// remind does the date calculations, urd is little use without it
+	remindCommand := cfg.RemindCommand
+	if _, err := exec.LookPath(remindCommand); err != nil {
+		fmt.Fprintf(out, "%s wasn't found. Install remind from your package manager, or from\n", remindCommand)
+		fmt.Fprintln(out, "https://dianne.skoll.ca/projects/remind/, or give its location.")
+		remindCommand = ask("Path to remind", remindCommand)
+	}
+	client := remind.NewClient()
+	client.RemindPath = remindCommand
+	if err := client.TestConnection(); err != nil {
+		fmt.Fprintf(out, "Warning: %v\nurd will start, but only show the reminders it can read without remind.\n", err)
+	} else {
+		fmt.Fprintf(out, "Found %s.\n", remindCommand)
+	}
+	fmt.Fprintln(out)
+
+	remindFile := config.ExpandHome(ask("Remind file", cfg.RemindFiles[0]))
+	if _, err := os.Stat(remindFile); err == nil {
+		fmt.Fprintf(out, "Using the reminders in %s.\n", remindFile)
+	} else {
+		content := "# Reminders, see man remind for the format\n"
+		if confirm("Create it with a sample event") {
+			content += sampleReminders(time.Now())
+		}
+		if err := os.MkdirAll(filepath.Dir(remindFile), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(remindFile, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to create %s: %w", remindFile, err)
+		}
+		fmt.Fprintf(out, "Created %s.\n", remindFile)
+	}
+	fmt.Fprintln(out)
+
+	if existing := config.FindConfigFile(); existing != "" {
+		fmt.Fprintf(out, "Keeping your configuration in %s.\n", existing)
+	} else if configFile := config.DefaultConfigPath(); confirm("Write a starter configuration to " + configFile) {
+		if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(configFile, []byte(config.StarterConfig(remindFile, remindCommand)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", configFile, err)
+		}
+		fmt.Fprintf(out, "Wrote %s, edit it to change the options.\n", configFile)
+	}
+
+	initConfig()
+	if config.FindConfigFile() == "" {
+		// Without an urdrc, use the answers for this run
+		cfg.RemindFiles = []string{remindFile}
+		cfg.RemindCommand = remindCommand
+	}
+	fmt.Fprintln(out, "All set. Press ? in urd for help.")
+	return nil
+}
+
+// sampleReminders returns reminders for today showing a timed and an
+// untimed event
+func sampleReminders(now time.Time) string {
+	date := now.Format("Jan 2 2006")
+	return fmt.Sprintf("REM %s AT 17:00 DURATION 0:30 MSG Try urd: press t to add a timed reminder\n", date) +
+		fmt.Sprintf("REM %s MSG Welcome to urd! Press ? for help, Enter to edit this reminder\n", date)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/spf13/cobra"
+)
+
+var syncDays int
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Push reminders one way to a CalDAV calendar and exit",
+	Long: `Mirror the reminders of the configured remind files, from a week ago
+until caldav_days ahead, to the CalDAV calendar at caldav_url, so they show
+up on devices that read CalDAV. This is a one-way push: changes made in the
+calendar aren't written back, and are replaced by the next sync.
+
+Each occurrence is uploaded once, under a UID derived from its file, date and
+content; edited reminders replace their old occurrences and removed ones are
+deleted. Only occurrences urd created for the days synced are deleted, never
+all of them when no reminders were read, and events in the calendar that urd
+didn't create are left alone.
+
+Run it from cron, or a remind file watcher, to keep the calendar current.`,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().IntVar(&syncDays, "days", 0, "Days ahead to mirror (default caldav_days)")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	// Ensure config is loaded
+	if cfg == nil {
+		initConfig()
+	}
+	if cfg.CalDAVURL == "" {
+		return fmt.Errorf("no caldav_url configured")
+	}
+
+	// Mirror the command-line files, else the selected files, else all of them
+	remindClient := newRemindClient()
+	if len(remindFiles) == 0 && len(cfg.CalDAVFiles) > 0 {
+		remindClient.SetFiles(cfg.CalDAVFiles)
+	}
+
+	if err := remindClient.TestConnection(); err != nil {
+		return fmt.Errorf("remind connection failed: %w", err)
+	}
+
+	days := cfg.CalDAVDays
+	if syncDays > 0 {
+		days = syncDays
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, end := today.AddDate(0, 0, -7), today.AddDate(0, 0, days)
+	events, err := remindClient.GetEvents(start, end)
+	if err != nil {
+		return err
+	}
+	printWarnings(remindClient)
+
+	mirror := remind.NewCalDAVMirror(cfg.CalDAVURL, cfg.CalDAVUser, cfg.CalDAVPassword)
+	result, err := mirror.Mirror(events, start, end)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mirrored to %s: %d created, %d deleted, %d unchanged\n",
+		cfg.CalDAVURL, result.Created, result.Deleted, result.Unchanged)
+	return nil
+}
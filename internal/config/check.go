@@ -0,0 +1,264 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Actions are the names keys can be bound to, in any mode
+var Actions = []string{
+	// Navigation
+	"scroll_down", "scroll_up", "previous_day", "next_day", "previous_week",
+	"next_week", "previous_month", "next_month", "home", "goto", "zoom",
+	"scroll_left", "scroll_right", "goto_monday", "goto_tuesday",
+	"goto_wednesday", "goto_thursday", "goto_friday", "goto_saturday",
+	"goto_sunday", "next_event", "prev_event", "next_area",
+	// Search
+	"begin_search", "search_next", "search_previous", "fuzzy_find",
+	// Editing reminders
+	"edit", "edit_any", "new_timed", "new_untimed", "quick_add", "extend_span",
+	"shrink_span", "visual",
+	"new_untimed_dialog", "new_template0", "new_template1", "new_template2",
+	"new_template3", "new_template4", "new_template4_dialog", "new_template5",
+	"new_template6", "new_template6_dialog", "new_template7", "new_template8",
+	"new_template9",
+	// Clipboard
+	"copy", "cut", "paste", "paste_dialog", "copy_agenda", "copy_description",
+	"copy_rem_line", "copy_date",
+	// Other actions
+	"open_url", "toggle_presentation", "toggle_agenda", "toggle_compact", "toggle_time_audit",
+	"peek", "toggle_sources", "filter_tag", "toggle_ids", "next", "execute",
+	"join", "focus", "review", "plan", "edit_config", "edit_note", "screenshot",
+	"screenshot_redacted", "export_csv", "shift_day", "undo_shift", "refresh",
+	"compare_days", "toggle_rem_line", "edit_error", "toggle_pin", "cycle_status", "repeat", "help", "quit",
+	"toggle_due_groups", "toggle_section", "expand_sections", "format_file",
+	// Dialogs and text inputs
+	"entry_complete", "entry_cancel", "backward_char", "forward_char",
+	"beginning_of_line", "end_of_line", "backward_word", "forward_word",
+	"delete_backward_char", "delete_char", "backward_kill_word", "kill_word",
+	"backward_kill_line", "kill_line", "history_previous", "history_next",
+	"next_free_slot",
+}
+
+// TemplatePlaceholders are the %name% placeholders templates are filled in
+// with
+var TemplatePlaceholders = []string{
+	"monname", "mon", "mday", "year", "hour", "min", "wdayname", "wday", "dura",
+}
+
+// Problem is something wrong with an urdrc
+type Problem struct {
+	Line    int  // Line of the urdrc, 0 for the configuration as a whole
+	Warning bool // The configuration loads, but probably not as intended
+	Message string
+}
+
+func (p Problem) String() string {
+	kind := "error"
+	if p.Warning {
+		kind = "warning"
+	}
+	return kind + ": " + p.Message
+}
+
+var placeholderRe = regexp.MustCompile(`%([A-Za-z_]+)%`)
+
+// templateVariables are the variables holding templates
+var templateVariables = map[string]func(*Config) string{
+	"quick_template":   func(c *Config) string { return c.QuickTemplate },
+	"timed_template":   func(c *Config) string { return c.TimedTemplate },
+	"allday_template":  func(c *Config) string { return c.AllDayTemplate },
+	"untimed_template": func(c *Config) string { return c.UntimedTemplate },
+}
+
+func init() {
+	for i := range 10 {
+		templateVariables[fmt.Sprintf("template%d", i)] = func(c *Config) string { return c.Templates[i] }
+	}
+}
+
+// Check reads the urdrc at path and reports lines that don't load, bindings
+// to unknown actions, keys bound twice or shadowing multi-key bindings,
+// actions left without a key, templates with unknown placeholders and files
+// that can't be read
+func Check(path string) ([]Problem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var problems []Problem
+	report := func(line int, warning bool, format string, args ...any) {
+		problems = append(problems, Problem{Line: line, Warning: warning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	c := DefaultConfig()
+	setLines := map[string]int{}
+	bindLines := map[string]int{} // By mode and key
+	bound := map[string]string{}
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := c.parseLine(line); err != nil {
+			report(lineNum, false, "%v", err)
+			continue
+		}
+
+		if matches := setRe.FindStringSubmatch(line); matches != nil {
+			name := matches[1]
+			if name == "remind_file" || name == "reminders_file" {
+				name = "remind_files"
+			}
+			setLines[name] = lineNum
+		}
+		if mode, key, action, ok := parseBind(line); ok {
+			if !isAction(action) {
+				report(lineNum, false, "unknown action: %s", action)
+			}
+			where := key
+			if mode != "" {
+				where = key + " in " + mode
+			}
+			id := mode + "\x00" + key
+			if previous, ok := bindLines[id]; ok {
+				if bound[id] == action {
+					report(lineNum, true, "%s is already bound to %s on line %d", where, action, previous)
+				} else {
+					report(lineNum, true, "%s was bound to %s on line %d, this binding replaces it", where, bound[id], previous)
+				}
+			}
+			bindLines[id] = lineNum
+			bound[id] = action
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// A key that starts a longer binding waits for the next key before it
+	// runs, keys bound after another's prefix can never be pressed
+	for id, line := range bindLines {
+		mode, key, _ := strings.Cut(id, "\x00")
+		bindings := c.KeyBindings
+		if mode != "" {
+			bindings = c.ModeKeyBindings[mode]
+		}
+		for other := range bindings {
+			if strings.HasPrefix(other, key+" ") {
+				report(line, true, "%s also starts %s, so it only runs after chord_timeout", key, other)
+			}
+		}
+	}
+
+	// Actions bound by default that the urdrc left without a key
+	var unbound []string
+	defaults := DefaultConfig().KeyBindings
+	for _, action := range defaults {
+		if !hasKeyFor(c.KeyBindings, action) && !contains(unbound, action) {
+			unbound = append(unbound, action)
+		}
+	}
+	for _, action := range unbound {
+		report(0, true, "no key is bound to %s anymore", action)
+	}
+
+	for name, template := range templateVariables {
+		line, ok := setLines[name]
+		if !ok {
+			continue
+		}
+		for _, matches := range placeholderRe.FindAllStringSubmatch(template(c), -1) {
+			if !contains(TemplatePlaceholders, matches[1]) {
+				report(line, false, "unknown placeholder in %s: %s", name, matches[0])
+			}
+		}
+	}
+
+	checkFile := func(variable, file string) {
+		if f, err := os.Open(file); err != nil {
+			report(setLines[variable], true, "%s can't be read: %v", variable, err)
+		} else {
+			f.Close()
+		}
+	}
+	for _, file := range c.RemindFiles {
+		checkFile("remind_files", file)
+	}
+	for _, file := range c.CalDAVFiles {
+		checkFile("caldav_files", file)
+	}
+	for template, target := range c.TemplateTargets {
+		if target.File == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Dir(target.File)); err != nil {
+			report(setLines[template+".file"], true, "%s.file can't be written: %v", template, err)
+		}
+	}
+	if c.FocusLog != "" {
+		if _, err := os.Stat(filepath.Dir(c.FocusLog)); err != nil {
+			report(setLines["focus_log"], true, "focus_log can't be written: %v", err)
+		}
+	}
+	// Sources are named in the urdrc, besides remind and p2 for --p2
+	sourceNames := []string{"remind", "p2"}
+	for _, profile := range c.P2Profiles {
+		sourceNames = append(sourceNames, profile.Name)
+	}
+	for _, source := range c.Sources {
+		sourceNames = append(sourceNames, source.Name)
+	}
+	for name := range c.RefreshRates {
+		if !contains(sourceNames, name) {
+			report(setLines["refresh_rate"], true, "refresh_rate set for unknown source: %s", name)
+		}
+	}
+	if c.ScreenshotDir != "" {
+		if _, err := os.Stat(c.ScreenshotDir); err != nil {
+			report(setLines["screenshot_dir"], true, "screenshot_dir can't be written: %v", err)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Message < problems[j].Message
+	})
+	return problems, nil
+}
+
+func isAction(action string) bool {
+	return contains(Actions, action)
+}
+
+func hasKeyFor(bindings map[string]string, action string) bool {
+	for _, bound := range bindings {
+		if bound == action {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
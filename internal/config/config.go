@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -23,17 +25,81 @@ type Config struct {
 	DateFormat     string
 	CalendarWidth  int
 	CalendarHeight int
+	UntimedWidth   int // Width of the sidebar, 0 for a third of the screen
+	UntimedHeight  int // Untimed reminders listed at once, the others scrolled to
+
+	// Read numeric dates day first, 03/04 being April 3, in the goto dialog
+	// (goto_big_endian false) and in quick adds (quick_date_US false). The
+	// latter also puts the day first in dates shown.
+	GotoDayFirst  bool
+	QuickDayFirst bool
 
 	// UI settings
 	Colors      map[string]string
 	KeyBindings map[string]string
 	StartupView string
 
+	// Where the schedule opens: "now", "day_start" (the start of work_hours)
+	// or a time like "08:00", shown at the "top" or "center" of the screen
+	InitialTime     string
+	InitialPosition string
+
+	// Bindings that only apply in one mode, by mode name, checked before
+	// KeyBindings
+	ModeKeyBindings map[string]map[string]string
+
+	// How long to wait for the next key of a multi-key binding
+	ChordTimeout time.Duration
+
 	// Behavior settings
-	AutoRefresh   bool
-	RefreshRate   time.Duration
-	ConfirmDelete bool
-	WrapText      bool
+	AutoRefresh    bool // Reload events every RefreshRate; the file watcher reloads them either way
+	RefreshRate    time.Duration
+	RefreshRates   map[string]time.Duration // Longer refresh rates of single sources, by source name
+	DedupPolicy    string                   // How events several sources have are combined, one of DedupPolicies
+	Ignore         []IgnoreRule             // Events hidden from every view
+	ConfirmDelete  bool
+	WrapText       bool
+	ShadeWeekends  bool          // Shade the weekend rows of the schedule
+	CenterCursor   bool          // Keep the selected slot in the middle of the schedule while scrolling
+	Compact        bool          // Start with runs of empty slots collapsed into a row each
+	JoinPrompt     time.Duration // Offer to join meetings this long before they start, 0 to never
+	Alarm          time.Duration // Ring the bell this long before events start, 0 to never
+	TravelBuffer   time.Duration // Time needed between events at different locations, 0 to not check
+	TravelBlock    bool          // Add a travel block before quick-added events with a location
+	FocusLength    time.Duration // Length of a focus session
+	P2CacheTTL     time.Duration // How long a p2 export is used before p2 is run again
+	PrefetchMonths int           // Months ahead loaded at idle for search and jumps, 0 to not
+	WorkStart      time.Duration // Start of working hours, from midnight, when suggesting free time
+	WorkEnd        time.Duration // End of working hours, from midnight
+	FocusLog       string        // File completed focus sessions are appended to, empty to not log
+	ScreenshotDir  string        // Directory screenshots and exports are written to, empty for the current one
+	JournalDir     string        // Directory of per-day notes named YYYY-MM-DD.md, empty for none
+
+	// Working hours of the weekdays that have other ones than WorkStart to
+	// WorkEnd, and whether to shade the schedule outside working hours
+	WeekdayHours  map[time.Weekday]WorkHours
+	ShadeOffHours bool
+
+	// Weeks back the time gutter is shaded by how often events took place
+	// at each hour, 0 to not shade it
+	GutterHeat int
+
+	// Days before a COUNTDOWN event from which its counter is drawn as a
+	// warning, and as urgent
+	CountdownWarning int
+	CountdownUrgent  int
+
+	// Privacy settings
+	PresentationMode bool // Start with private events redacted
+
+	// Render the schedule as plain labeled lines for screen readers
+	Accessible bool
+
+	// Write, run, open and copy nothing, set when replaying a session
+	ReadOnly bool
+
+	// Colors events and highlights are drawn with, one of Palettes
+	Palette string
 
 	// Templates
 	QuickTemplate   string
@@ -42,6 +108,35 @@ type Config struct {
 	UntimedTemplate string
 	// Numbered templates (0-9)
 	Templates [10]string
+	// Files and tags of reminders made from templates, by the variable the
+	// template is set with, like template2 or timed_template
+	TemplateTargets map[string]TemplateTarget
+
+	// p2 command line used with --p2 instead of p2 work --json <p2-file>
+	P2Command string
+	// Additional p2 sources, e.g. one per client
+	P2Profiles []P2Profile
+	// Other sources, built in or served by plugins
+	Sources []SourceConfig
+	// CalDAV calendar that urd sync mirrors reminders to
+	CalDAVURL      string
+	CalDAVUser     string
+	CalDAVPassword string
+	CalDAVFiles    []string // Remind files mirrored, all remind files when empty
+	CalDAVDays     int      // How many days ahead are mirrored
+
+	// Access tokens for github, gitlab and jira sources
+	GitHubToken string
+	GitLabToken string
+	JiraToken   string
+	// Jira account the token belongs to, for Jira Cloud's basic auth
+	JiraUser string
+	// Jira field holding sprints, when not Jira Cloud's customfield_10020
+	JiraSprintField string
+
+	// Shell commands run on lifecycle events, by hook name (on_event_added,
+	// on_event_removed, on_event_edited, on_startup, on_refresh, on_alarm)
+	Hooks map[string]string
 
 	// Editor commands
 	EditOldCommand string // Edit existing reminder at specific line
@@ -49,8 +144,85 @@ type Config struct {
 	EditAnyCommand string // Edit file without specific position
 }
 
+// P2Profile is a p2 source defined in urdrc
+type P2Profile struct {
+	Name    string // Identifies the source and tags its events
+	Color   string // #rrggbb color for its work periods, "" for the default colors
+	Command string // p2 command line printing work periods as JSON
+}
+
+// SourceConfig is a source defined in urdrc with the source directive
+type SourceConfig struct {
+	Name string // Identifies the source and its events
+	Kind string // Built-in kind, or the plugin run as urd-source-<kind>
+	Args string // Arguments, split like a command line
+}
+
+// reservedSourceNames are the names of the remind source and the --p2
+// source, which urdrc sources can't take
+var reservedSourceNames = []string{"remind", "p2"}
+
+// checkSourceName returns an error if a p2 profile or source can't be named
+// name: sources are told apart by name, so it must be unique
+func (c *Config) checkSourceName(name string) error {
+	for _, reserved := range reservedSourceNames {
+		if name == reserved {
+			return fmt.Errorf("reserved source name: %s", name)
+		}
+	}
+	for _, profile := range c.P2Profiles {
+		if profile.Name == name {
+			return fmt.Errorf("duplicate source: %s", name)
+		}
+	}
+	for _, source := range c.Sources {
+		if source.Name == name {
+			return fmt.Errorf("duplicate source: %s", name)
+		}
+	}
+	return nil
+}
+
+// IgnoreRule hides the events with a field matching Pattern, defined in
+// urdrc with the ignore directive
+type IgnoreRule struct {
+	Field   string // description, tag or file
+	Pattern *regexp.Regexp
+}
+
+// parseFileList splits a comma separated list of files, expanding ~/ and
+// $HOME/ to the home directory
+func parseFileList(value string) []string {
+	files := strings.Split(value, ",")
+	for i, file := range files {
+		files[i] = ExpandHome(strings.TrimSpace(file))
+	}
+	return files
+}
+
+// ExpandHome expands a leading ~ or $HOME to the user's home directory,
+// followed by a slash or, on Windows, a backslash
+func ExpandHome(path string) string {
+	for _, prefix := range []string{"~", "$HOME"} {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok || rest != "" && rest[0] != '/' && rest[0] != filepath.Separator {
+			continue
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		if rest == "" {
+			return home
+		}
+		return filepath.Join(home, rest[1:])
+	}
+	return path
+}
+
 func DefaultConfig() *Config {
 	home, _ := os.UserHomeDir()
+	editOld, editNew, editAny := defaultEditCommands()
 
 	return &Config{
 		RemindFiles:   []string{filepath.Join(home, ".reminders")},
@@ -62,6 +234,7 @@ func DefaultConfig() *Config {
 		DateFormat:     "Jan 2, 2006",
 		CalendarWidth:  80,
 		CalendarHeight: 24,
+		UntimedHeight:  5,
 
 		Colors: map[string]string{
 			"normal":   "default",
@@ -75,22 +248,37 @@ func DefaultConfig() *Config {
 
 		KeyBindings: map[string]string{
 			// Navigation (Hourly View)
-			"j":      "scroll_down",
-			"k":      "scroll_up",
-			"<down>": "scroll_down",
-			"<up>":   "scroll_up",
-			"H":      "previous_day",
-			"L":      "next_day",
-			"K":      "previous_week",
-			"J":      "next_week",
-			"<":      "previous_month",
-			">":      "next_month",
-			"o":      "home",
-			"g":      "goto",
-			"/":      "begin_search",
-			"n":      "search_next",
-			"N":      "search_previous",
-			"z":      "zoom",
+			"j":          "scroll_down",
+			"k":          "scroll_up",
+			"<down>":     "scroll_down",
+			"<up>":       "scroll_up",
+			"shift+down": "extend_span",
+			"shift+up":   "shrink_span",
+			"\\Cv":       "visual",
+			"H":          "previous_day",
+			"L":          "next_day",
+			"K":          "previous_week",
+			"J":          "next_week",
+			"<":          "previous_month",
+			">":          "next_month",
+			"o":          "home",
+			"g":          "goto",
+			"/":          "begin_search",
+			"F":          "fuzzy_find",
+			"n":          "search_next",
+			"N":          "search_previous",
+			"z":          "zoom",
+			"[":          "scroll_left",
+			"]":          "scroll_right",
+			"1":          "goto_monday",
+			"2":          "goto_tuesday",
+			"3":          "goto_wednesday",
+			"4":          "goto_thursday",
+			"5":          "goto_friday",
+			"6":          "goto_saturday",
+			"7":          "goto_sunday",
+			")":          "next_event",
+			"(":          "prev_event",
 
 			// Actions
 			"<enter>": "edit",
@@ -106,6 +294,40 @@ func DefaultConfig() *Config {
 			"Q":       "quit",
 			"i":       "toggle_ids",
 			"\\Cb":    "open_url",
+			"P":       "toggle_presentation",
+			"A":       "toggle_agenda",
+			"c":       "toggle_compact",
+			"%":       "toggle_time_audit",
+			"v":       "peek",
+			"r":       "toggle_rem_line",
+			"!":       "edit_error",
+			"S":       "copy_agenda",
+			"Y d":     "copy_description",
+			"Y r":     "copy_rem_line",
+			"Y D":     "copy_date",
+			"W":       "next",
+			"R":       "execute",
+			"V":       "join",
+			"T":       "focus",
+			"D":       "review",
+			"B":       "plan",
+			"O":       "toggle_sources",
+			"#":       "filter_tag",
+			"E":       "edit_note",
+			"C":       "edit_config",
+			"Z s":     "screenshot",
+			"Z r":     "screenshot_redacted",
+			"Z c":     "export_csv",
+			"Z f":     "format_file",
+			"s":       "shift_day",
+			"\\Cz":    "undo_shift",
+			"=":       "compare_days",
+			"*":       "toggle_pin",
+			".":       "repeat",
+			"~":       "cycle_status",
+			"G":       "toggle_due_groups",
+			"-":       "toggle_section",
+			"+":       "expand_sections",
 
 			// Template-Based Creation
 			"w": "new_template0",
@@ -118,12 +340,29 @@ func DefaultConfig() *Config {
 			"<tab>": "next_area",
 		},
 
+		ModeKeyBindings: map[string]map[string]string{},
+
 		StartupView:   "month",
+		ChordTimeout:  time.Second,
 		AutoRefresh:   true,
 		RefreshRate:   30 * time.Second,
 		ConfirmDelete: true,
+		TravelBuffer:  30 * time.Minute,
+		FocusLength:   25 * time.Minute,
+		P2CacheTTL:    time.Minute,
+		DedupPolicy:   "first",
+		CalDAVDays:    60,
+		WorkStart:     9 * time.Hour,
+		WorkEnd:       17 * time.Hour,
 		WrapText:      true,
 
+		CountdownWarning: 7,
+		CountdownUrgent:  2,
+
+		InitialTime:     "now",
+		InitialPosition: "center",
+		Palette:         "default",
+
 		QuickTemplate:   `REM %monname% %mday% %year% MSG %"<++>%"%`,
 		TimedTemplate:   `REM %monname% %mday% %year% <++>AT %hour%:%min% +%dura%<++> DURATION %dura%:00<++> MSG %"<++>%"%`,
 		AllDayTemplate:  `REM %monname% %mday% %year% MSG %"<++>%"%`,
@@ -141,34 +380,65 @@ func DefaultConfig() *Config {
 			``, // template9 - unused
 		},
 
-		// Default editor commands - use vim with line numbers
-		EditOldCommand: "vim +%line% %file%",
-		EditNewCommand: "vim +999999 %file%",
-		EditAnyCommand: "vim %file%",
+		Hooks: map[string]string{},
+
+		EditOldCommand: editOld,
+		EditNewCommand: editNew,
+		EditAnyCommand: editAny,
 	}
 }
 
-func LoadConfig() (*Config, error) {
-	config := DefaultConfig()
+// defaultEditCommands returns the editor commands for existing reminders,
+// new ones and whole files: vim with line numbers, or on Windows VS Code
+// when it's installed and Notepad otherwise
+func defaultEditCommands() (existing, added, file string) {
+	if runtime.GOOS == "windows" {
+		if _, err := exec.LookPath("code"); err == nil {
+			return "code --wait -g %file%:%line%", "code --wait -g %file%:999999", "code --wait %file%"
+		}
+		return "notepad %file%", "notepad %file%", "notepad %file%"
+	}
+	return "vim +%line% %file%", "vim +999999 %file%", "vim %file%"
+}
 
+// FindConfigFile returns the urdrc that LoadConfig reads, or "" if there is
+// none
+func FindConfigFile() string {
 	// Try multiple config file locations
 	configPaths := []string{
 		os.Getenv("URD_CONFIG"),
 		filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "urd", "urdrc"),
-		filepath.Join(os.Getenv("HOME"), ".config", "urd", "urdrc"),
-		filepath.Join(os.Getenv("HOME"), ".urdrc"),
+		ExpandHome("~/.config/urd/urdrc"),
+		ExpandHome("~/.urdrc"),
 	}
 
 	for _, path := range configPaths {
 		if path == "" {
 			continue
 		}
-
 		if _, err := os.Stat(path); err == nil {
-			if err := config.loadFromFile(path); err != nil {
-				return nil, fmt.Errorf("error loading config from %s: %w", path, err)
-			}
-			break
+			return path
+		}
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func LoadConfig() (*Config, error) {
+	config := DefaultConfig()
+
+	if path := FindConfigFile(); path != "" {
+		if err := config.loadFromFile(path); err != nil {
+			return nil, fmt.Errorf("error loading config from %s: %w", path, err)
+		}
+	} else if path := DefaultWyrdConfigPath(); fileExists(path) {
+		// Migrating from wyrd, use its configuration until there's an urdrc
+		if err := config.loadFromWyrd(path); err != nil {
+			return nil, fmt.Errorf("error loading wyrd config from %s: %w", path, err)
 		}
 	}
 
@@ -202,6 +472,11 @@ func (c *Config) loadFromFile(path string) error {
 	return scanner.Err()
 }
 
+var (
+	setRe  = regexp.MustCompile(`^set\s+([\w.]+)\s*=?\s*(.+)$`)
+	bindRe = regexp.MustCompile(`^bind\s+(?:(\w+)\s+)?("[^"]+"|\S+)\s+(\S+)$`)
+)
+
 func (c *Config) parseLine(line string) error {
 	// Skip comments and empty lines
 	if line == "" || strings.HasPrefix(line, "#") {
@@ -209,36 +484,107 @@ func (c *Config) parseLine(line string) error {
 	}
 
 	// Handle set commands: set variable value or set variable="value"
-	setRe := regexp.MustCompile(`^set\s+(\w+)\s*=?\s*(.+)$`)
 	if matches := setRe.FindStringSubmatch(line); matches != nil {
 		return c.setVariable(matches[1], matches[2])
 	}
 
-	// Handle bind commands: bind key action
-	// Keys can be quoted like "<down>" or unquoted like j
-	bindRe := regexp.MustCompile(`^bind\s+("[^"]+"|\S+)\s+(\S+)$`)
-	if matches := bindRe.FindStringSubmatch(line); matches != nil {
-		key := matches[1]
-		// Remove quotes if present
-		if strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) {
-			key = key[1 : len(key)-1]
+	// Handle bind commands: bind [mode] key action
+	if mode, key, action, ok := parseBind(line); ok {
+		if mode == "" {
+			// Store as key -> action mapping
+			c.KeyBindings[key] = action
+			return nil
+		}
+
+		if !isBindMode(mode) {
+			return fmt.Errorf("unknown bind mode: %s", mode)
+		}
+		if c.ModeKeyBindings == nil {
+			c.ModeKeyBindings = map[string]map[string]string{}
+		}
+		if c.ModeKeyBindings[mode] == nil {
+			c.ModeKeyBindings[mode] = map[string]string{}
+		}
+		c.ModeKeyBindings[mode][key] = action
+		return nil
+	}
+
+	// Handle p2 profiles: p2 name [#rrggbb] command...
+	p2Re := regexp.MustCompile(`^p2\s+(\w+)\s+(?:(#[0-9a-fA-F]{6})\s+)?(.+)$`)
+	if matches := p2Re.FindStringSubmatch(line); matches != nil {
+		command := matches[3]
+		if strings.HasPrefix(command, `"`) && strings.HasSuffix(command, `"`) && len(command) > 1 {
+			command = command[1 : len(command)-1]
+		}
+		if err := c.checkSourceName(matches[1]); err != nil {
+			return err
+		}
+		c.P2Profiles = append(c.P2Profiles, P2Profile{Name: matches[1], Color: matches[2], Command: command})
+		return nil
+	}
+
+	// Handle other sources: source name kind [args...]
+	sourceRe := regexp.MustCompile(`^source\s+(\w+)\s+([\w-]+)(?:\s+(.+))?$`)
+	if matches := sourceRe.FindStringSubmatch(line); matches != nil {
+		if err := c.checkSourceName(matches[1]); err != nil {
+			return err
 		}
-		action := matches[2]
-		// Store as key -> action mapping
-		c.KeyBindings[key] = action
+		c.Sources = append(c.Sources, SourceConfig{Name: matches[1], Kind: matches[2], Args: matches[3]})
 		return nil
 	}
 
-	// Handle color commands: color element color_spec
-	colorRe := regexp.MustCompile(`^color\s+(\w+)\s+(.+)$`)
+	// Handle ignore rules: ignore description|tag|file regex
+	ignoreRe := regexp.MustCompile(`^ignore\s+(\w+)\s+(.+)$`)
+	if matches := ignoreRe.FindStringSubmatch(line); matches != nil {
+		field, pattern := matches[1], matches[2]
+		if field != "description" && field != "tag" && field != "file" {
+			return fmt.Errorf("invalid ignore field: %s, one of description, tag or file", field)
+		}
+		if strings.HasPrefix(pattern, `"`) && strings.HasSuffix(pattern, `"`) && len(pattern) > 1 {
+			pattern = pattern[1 : len(pattern)-1]
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid ignore pattern: %w", err)
+		}
+		c.Ignore = append(c.Ignore, IgnoreRule{Field: field, Pattern: re})
+		return nil
+	}
+
+	// Handle color commands: color element color_spec, where the element
+	// can be a tag as tag:name
+	colorRe := regexp.MustCompile(`^color\s+(\w+|tag:@?[\w-]+)\s+(.+)$`)
 	if matches := colorRe.FindStringSubmatch(line); matches != nil {
-		c.Colors[matches[1]] = matches[2]
+		element := matches[1]
+		if tag, ok := strings.CutPrefix(element, "tag:"); ok {
+			element = "tag:" + strings.ToLower(strings.TrimPrefix(tag, "@"))
+		}
+		c.Colors[element] = matches[2]
 		return nil
 	}
 
 	return fmt.Errorf("unknown config line: %s", line)
 }
 
+// parseBind splits a bind command into its mode, key and action. Keys can
+// be quoted like "<down>" or unquoted like j. Several keys in a row, like
+// "gg" or "g t", bind a multi-key sequence, returned separated by spaces.
+func parseBind(line string) (mode, key, action string, ok bool) {
+	matches := bindRe.FindStringSubmatch(line)
+	if matches == nil {
+		return "", "", "", false
+	}
+	key = matches[2]
+	// Remove quotes if present
+	if strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) {
+		key = key[1 : len(key)-1]
+	}
+	if seq := KeySequence(key); len(seq) > 0 {
+		key = strings.Join(seq, " ")
+	}
+	return matches[1], key, matches[3], true
+}
+
 func (c *Config) setVariable(name, value string) error {
 	// Handle quoted strings - remove quotes and unescape
 	if (strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)) ||
@@ -250,24 +596,30 @@ func (c *Config) setVariable(name, value string) error {
 		value = strings.ReplaceAll(value, `\'`, `'`)
 	}
 
+	// Where reminders made from a template go, like template2.file
+	if template, field, ok := strings.Cut(name, "."); ok {
+		if _, isTemplate := templateVariables[template]; !isTemplate {
+			return fmt.Errorf("unknown configuration variable: %s", name)
+		}
+		if c.TemplateTargets == nil {
+			c.TemplateTargets = make(map[string]TemplateTarget)
+		}
+		target := c.TemplateTargets[template]
+		switch field {
+		case "file":
+			target.File = ExpandHome(value)
+		case "tags":
+			target.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+		default:
+			return fmt.Errorf("unknown configuration variable: %s", name)
+		}
+		c.TemplateTargets[template] = target
+		return nil
+	}
+
 	switch name {
 	case "remind_file", "remind_files", "reminders_file":
-		// Handle multiple files separated by commas
-		files := strings.Split(value, ",")
-		for i, file := range files {
-			files[i] = strings.TrimSpace(file)
-			// Expand ~ to home directory
-			if strings.HasPrefix(files[i], "~/") {
-				home, _ := os.UserHomeDir()
-				files[i] = filepath.Join(home, files[i][2:])
-			}
-			// Expand $HOME
-			if strings.HasPrefix(files[i], "$HOME/") {
-				home, _ := os.UserHomeDir()
-				files[i] = filepath.Join(home, files[i][6:])
-			}
-		}
-		c.RemindFiles = files
+		c.RemindFiles = parseFileList(value)
 
 	case "remind_command":
 		c.RemindCommand = value
@@ -314,13 +666,64 @@ func (c *Config) setVariable(name, value string) error {
 		}
 		c.CalendarHeight = height
 
+	case "untimed_window_width":
+		width, err := strconv.Atoi(value)
+		if err != nil || width < 0 {
+			return fmt.Errorf("invalid untimed_window_width: %s", value)
+		}
+		c.UntimedWidth = width
+
+	case "untimed_window_height":
+		height, err := strconv.Atoi(value)
+		if err != nil || height < 1 {
+			return fmt.Errorf("invalid untimed_window_height: %s", value)
+		}
+		c.UntimedHeight = height
+
 	case "startup_view":
 		c.StartupView = value
 
+	case "initial_time":
+		if value != "now" && value != "day_start" {
+			if _, err := time.Parse("15:04", value); err != nil {
+				return fmt.Errorf("invalid initial_time: %s", value)
+			}
+		}
+		c.InitialTime = value
+
+	case "initial_position":
+		if value != "top" && value != "center" {
+			return fmt.Errorf("invalid initial_position: %s", value)
+		}
+		c.InitialPosition = value
+
+	case "palette":
+		if !contains(Palettes, value) {
+			return fmt.Errorf("invalid palette: %s, one of %s", value, strings.Join(Palettes, ", "))
+		}
+		c.Palette = value
+
+	case "chord_timeout":
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as milliseconds
+			if ms, err2 := strconv.Atoi(value); err2 == nil {
+				timeout = time.Duration(ms) * time.Millisecond
+			} else {
+				return fmt.Errorf("invalid chord_timeout: %s", value)
+			}
+		}
+		c.ChordTimeout = timeout
+
 	case "auto_refresh":
 		c.AutoRefresh = strings.ToLower(value) == "true" || value == "1"
 
 	case "refresh_rate":
+		// A source name first sets the rate of that source only
+		var source string
+		if fields := strings.Fields(value); len(fields) == 2 {
+			source, value = fields[0], fields[1]
+		}
 		rate, err := time.ParseDuration(value)
 		if err != nil {
 			// Try parsing as seconds
@@ -330,7 +733,186 @@ func (c *Config) setVariable(name, value string) error {
 				return fmt.Errorf("invalid refresh_rate: %s", value)
 			}
 		}
-		c.RefreshRate = rate
+		if source == "" {
+			c.RefreshRate = rate
+			break
+		}
+		if c.RefreshRates == nil {
+			c.RefreshRates = make(map[string]time.Duration)
+		}
+		c.RefreshRates[source] = rate
+
+	case "prefetch_months":
+		months, err := strconv.Atoi(value)
+		if err != nil || months < 0 {
+			return fmt.Errorf("invalid prefetch_months: %s", value)
+		}
+		c.PrefetchMonths = months
+
+	case "join_prompt":
+		before, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as minutes
+			if minutes, err2 := strconv.Atoi(value); err2 == nil {
+				before = time.Duration(minutes) * time.Minute
+			} else {
+				return fmt.Errorf("invalid join_prompt: %s", value)
+			}
+		}
+		c.JoinPrompt = before
+
+	case "alarm":
+		before, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as minutes
+			if minutes, err2 := strconv.Atoi(value); err2 == nil {
+				before = time.Duration(minutes) * time.Minute
+			} else {
+				return fmt.Errorf("invalid alarm: %s", value)
+			}
+		}
+		c.Alarm = before
+
+	case "dedup_policy":
+		if !contains(DedupPolicies, value) {
+			return fmt.Errorf("invalid dedup_policy: %s, one of %s", value, strings.Join(DedupPolicies, ", "))
+		}
+		c.DedupPolicy = value
+
+	case "p2_cache_ttl":
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as seconds
+			if seconds, err2 := strconv.Atoi(value); err2 == nil {
+				ttl = time.Duration(seconds) * time.Second
+			} else {
+				return fmt.Errorf("invalid p2_cache_ttl: %s", value)
+			}
+		}
+		c.P2CacheTTL = ttl
+
+	case "p2_command":
+		c.P2Command = value
+
+	case "github_token":
+		c.GitHubToken = value
+
+	case "gitlab_token":
+		c.GitLabToken = value
+
+	case "caldav_url":
+		c.CalDAVURL = value
+
+	case "caldav_user":
+		c.CalDAVUser = value
+
+	case "caldav_password":
+		c.CalDAVPassword = value
+
+	case "caldav_files":
+		c.CalDAVFiles = parseFileList(value)
+
+	case "caldav_days":
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			return fmt.Errorf("invalid caldav_days: %s", value)
+		}
+		c.CalDAVDays = days
+
+	case "jira_token":
+		c.JiraToken = value
+
+	case "jira_user":
+		c.JiraUser = value
+
+	case "jira_sprint_field":
+		c.JiraSprintField = value
+
+	case "travel_buffer":
+		buffer, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as minutes
+			if minutes, err2 := strconv.Atoi(value); err2 == nil {
+				buffer = time.Duration(minutes) * time.Minute
+			} else {
+				return fmt.Errorf("invalid travel_buffer: %s", value)
+			}
+		}
+		c.TravelBuffer = buffer
+
+	case "travel_block":
+		c.TravelBlock = strings.ToLower(value) == "true" || value == "1"
+
+	case "focus_length":
+		length, err := time.ParseDuration(value)
+		if err != nil {
+			// Try parsing as minutes
+			if minutes, err2 := strconv.Atoi(value); err2 == nil {
+				length = time.Duration(minutes) * time.Minute
+			} else {
+				return fmt.Errorf("invalid focus_length: %s", value)
+			}
+		}
+		if length <= 0 {
+			return fmt.Errorf("invalid focus_length: %s", value)
+		}
+		c.FocusLength = length
+
+	case "work_hours":
+		day := time.Weekday(-1)
+		if fields := strings.Fields(value); len(fields) == 2 {
+			weekday, ok := weekdays[strings.ToLower(fields[0])]
+			if !ok {
+				return fmt.Errorf("invalid work_hours day: %s", fields[0])
+			}
+			day, value = weekday, fields[1]
+		}
+		var start, end time.Duration
+		if day < 0 || !strings.EqualFold(value, "off") {
+			var err error
+			start, end, err = parseHours(value)
+			if err != nil {
+				return fmt.Errorf("invalid work_hours: %s", value)
+			}
+		}
+		if day < 0 {
+			c.WorkStart, c.WorkEnd = start, end
+			break
+		}
+		if c.WeekdayHours == nil {
+			c.WeekdayHours = make(map[time.Weekday]WorkHours)
+		}
+		c.WeekdayHours[day] = WorkHours{Start: start, End: end}
+
+	case "focus_log":
+		c.FocusLog = ExpandHome(value)
+
+	case "screenshot_dir":
+		c.ScreenshotDir = ExpandHome(value)
+
+	case "journal_dir":
+		c.JournalDir = ExpandHome(value)
+
+	case "gutter_heat":
+		weeks, err := strconv.Atoi(value)
+		if err != nil || weeks < 0 {
+			return fmt.Errorf("invalid gutter_heat: %s", value)
+		}
+		c.GutterHeat = weeks
+
+	case "countdown_warning":
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return fmt.Errorf("invalid countdown_warning: %s", value)
+		}
+		c.CountdownWarning = days
+
+	case "countdown_urgent":
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return fmt.Errorf("invalid countdown_urgent: %s", value)
+		}
+		c.CountdownUrgent = days
 
 	case "confirm_delete":
 		c.ConfirmDelete = strings.ToLower(value) == "true" || value == "1"
@@ -338,6 +920,29 @@ func (c *Config) setVariable(name, value string) error {
 	case "wrap_text":
 		c.WrapText = strings.ToLower(value) == "true" || value == "1"
 
+	case "shade_weekends":
+		c.ShadeWeekends = strings.ToLower(value) == "true" || value == "1"
+
+	case "shade_off_hours":
+		c.ShadeOffHours = strings.ToLower(value) == "true" || value == "1"
+
+	case "center_cursor":
+		c.CenterCursor = strings.ToLower(value) == "true" || value == "1"
+	case "compact_schedule":
+		c.Compact = strings.ToLower(value) == "true" || value == "1"
+
+	case "presentation_mode":
+		c.PresentationMode = strings.ToLower(value) == "true" || value == "1"
+
+	case "accessible":
+		c.Accessible = strings.ToLower(value) == "true" || value == "1"
+
+	case "goto_big_endian":
+		c.GotoDayFirst = !(strings.ToLower(value) == "true" || value == "1")
+
+	case "quick_date_US":
+		c.QuickDayFirst = !(strings.ToLower(value) == "true" || value == "1")
+
 	case "quick_template":
 		c.QuickTemplate = value
 
@@ -356,6 +961,12 @@ func (c *Config) setVariable(name, value string) error {
 	case "untimed_template":
 		c.UntimedTemplate = value
 
+	case "on_event_added", "on_event_removed", "on_event_edited", "on_startup", "on_refresh", "on_alarm":
+		if c.Hooks == nil {
+			c.Hooks = map[string]string{}
+		}
+		c.Hooks[name] = value
+
 	case "template0":
 		c.Templates[0] = value
 	case "template1":
@@ -377,13 +988,13 @@ func (c *Config) setVariable(name, value string) error {
 	case "template9":
 		c.Templates[9] = value
 
-	case "timed_bold", "untimed_bold", "description_first", "schedule_12_hour", "busy_algorithm", "goto_big_endian", "untimed_duration", "status_12_hour", "center_cursor":
+	case "timed_bold", "untimed_bold", "description_first", "schedule_12_hour", "busy_algorithm", "untimed_duration", "status_12_hour":
 		// TODO: Implement additional display options
 
 	case "busy_level1", "busy_level2", "busy_level3", "busy_level4":
 		// TODO: Implement busy level colors
 
-	case "selection_12_hour", "description_12_hour", "quick_date_US", "number_weeks", "home_sticky", "advance_warning", "untimed_window_width":
+	case "selection_12_hour", "description_12_hour", "number_weeks", "home_sticky", "advance_warning":
 		// TODO: Implement additional display and behavior options
 
 	default:
@@ -394,6 +1005,179 @@ func (c *Config) setVariable(name, value string) error {
 	return nil
 }
 
+// monthFirstRe matches the month and day of month-first date layouts
+var monthFirstRe = regexp.MustCompile(`\b(Jan|January) (_2|02|2)\b,?`)
+
+// DateLayout returns a month-first date layout like "Mon Jan 2, 2006" as
+// "Mon 2 Jan 2006" when dates are shown day first, see QuickDayFirst
+func (c *Config) DateLayout(layout string) string {
+	if c == nil || !c.QuickDayFirst {
+		return layout
+	}
+	layout = monthFirstRe.ReplaceAllString(layout, "${2} ${1}")
+	return strings.NewReplacer("01/02", "02/01", "1/2", "2/1").Replace(layout)
+}
+
+// TemplateTarget is the file reminders made from a template are added to,
+// empty for the first remind file, and the tags they're given
+type TemplateTarget struct {
+	File string
+	Tags []string
+}
+
+// WorkHours are the working hours of a day, from midnight
+type WorkHours struct {
+	Start, End time.Duration
+}
+
+// weekdays are the days work_hours can be set for, by their names
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// WorkHoursOn returns the working hours of a weekday, from midnight. Days
+// off have none: end isn't after start.
+func (c *Config) WorkHoursOn(day time.Weekday) (start, end time.Duration) {
+	if hours, ok := c.WeekdayHours[day]; ok {
+		return hours.Start, hours.End
+	}
+	return c.WorkStart, c.WorkEnd
+}
+
+// parseHours parses a range of hours like "9-17" or "8:30-17:00" into
+// offsets from midnight
+func parseHours(value string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected start-end")
+	}
+
+	parse := func(s string) (time.Duration, error) {
+		hour, minute, hasMinute := strings.Cut(strings.TrimSpace(s), ":")
+		h, err := strconv.Atoi(hour)
+		if err != nil || h < 0 || h > 24 {
+			return 0, fmt.Errorf("invalid hour: %s", s)
+		}
+		m := 0
+		if hasMinute {
+			m, err = strconv.Atoi(minute)
+			if err != nil || m < 0 || m > 59 {
+				return 0, fmt.Errorf("invalid minute: %s", s)
+			}
+		}
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+	}
+
+	start, err := parse(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parse(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start || end > 24*time.Hour {
+		return 0, 0, fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
+
+// Palettes are the palettes set palette chooses from
+var Palettes = []string{
+	"default",
+	"high_contrast",
+	"deuteranopia", // red-green color blindness, the most common
+	"protanopia",   // red-green color blindness with red looking dark
+	"tritanopia",   // blue-yellow color blindness
+}
+
+// DedupPolicies are the ways set dedup_policy can combine events with the
+// same ID from several sources
+var DedupPolicies = []string{
+	"first",         // the event of the first source wins
+	"prefer-remind", // the event of the remind files wins
+	"merge-tags",    // the event of the first source wins, with the tags of all
+}
+
+// BindModes are the modes bind statements can be scoped to
+var BindModes = []string{
+	"schedule",  // the hourly schedule
+	"untimed",   // the untimed reminders box, when focused
+	"help",      // the help screen
+	"quick_add", // the quick add dialog
+	"goto",      // the goto date dialog
+	"search",    // the search dialog
+	"find",      // the fuzzy finder
+	"select",    // choosing which event to edit
+	"clipboard", // choosing which event to copy or cut
+	"urls",      // choosing which URL to open
+	"upcoming",  // the upcoming events list
+	"execute",   // running an event's RUN: command
+	"join",      // the prompt to join a meeting about to start
+	"review",    // the daily review
+	"plan",      // the preview of planned tasks
+	"sources",   // showing and hiding sources
+	"tags",      // choosing which tag to filter by
+	"shift",     // shifting the events of a day
+	"compare",   // comparing two days side by side
+	"format",    // the changes formatting a remind file makes
+}
+
+func isBindMode(mode string) bool {
+	for _, m := range BindModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// KeySequence splits a bound key into the individual keys that must be
+// pressed in turn. Named keys like <down>, control keys like \Cl and
+// modifier combinations like ctrl+n or <ctrl+n> count as one key; anything
+// else is one key per character, so "gg" and "g t" are both g followed by a
+// key. Control-letter keys are always returned in the \Cl form.
+func KeySequence(key string) []string {
+	var seq []string
+	for _, word := range strings.Fields(key) {
+		if combo := strings.TrimSuffix(strings.TrimPrefix(word, "<"), ">"); len(combo) > 1 && strings.Contains(combo, "+") {
+			seq = append(seq, canonicalKey(combo))
+			continue
+		}
+
+		runes := []rune(word)
+		for i := 0; i < len(runes); {
+			n := 1
+			switch {
+			case runes[i] == '<':
+				// A lone < is the key itself, <name> is a named key
+				for j := i + 2; j < len(runes); j++ {
+					if runes[j] == '>' {
+						n = j - i + 1
+						break
+					}
+				}
+			case runes[i] == '\\' && i+2 < len(runes) && runes[i+1] == 'C':
+				n = 3
+			}
+			seq = append(seq, string(runes[i:i+n]))
+			i += n
+		}
+	}
+	return seq
+}
+
+// canonicalKey writes ctrl+l style keys as \Cl, the form the UI looks
+// bindings up by
+func canonicalKey(key string) string {
+	lower := strings.ToLower(key)
+	if strings.HasPrefix(lower, "ctrl+") && len(lower) == len("ctrl+")+1 {
+		return `\C` + lower[len("ctrl+"):]
+	}
+	return key
+}
+
 func getDefaultEditor() string {
 	if editor := os.Getenv("EDITOR"); editor != "" {
 		return editor
@@ -401,5 +1185,8 @@ func getDefaultEditor() string {
 	if editor := os.Getenv("VISUAL"); editor != "" {
 		return editor
 	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
 	return "vi"
 }
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -93,6 +95,265 @@ func TestParseLine(t *testing.T) {
 			expected: true,
 			hasError: false,
 		},
+		{
+			line: `bind "gg" home`,
+			check: func(c *Config) bool {
+				return c.KeyBindings["g g"] == "home"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: `bind "g <down>" next_week`,
+			check: func(c *Config) bool {
+				return c.KeyBindings["g <down>"] == "next_week"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "bind search <ctrl+n> history_next",
+			check: func(c *Config) bool {
+				return c.ModeKeyBindings["search"][`\Cn`] == "history_next" &&
+					c.KeyBindings[`\Cn`] == ""
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "bind untimed d done",
+			check: func(c *Config) bool {
+				return c.ModeKeyBindings["untimed"]["d"] == "done" &&
+					c.KeyBindings["d"] == ""
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "bind nowhere d done",
+			hasError: true,
+		},
+		{
+			line: `set on_event_added "notify-send 'Added' \"$URD_EVENT_DESCRIPTION\""`,
+			check: func(c *Config) bool {
+				return c.Hooks["on_event_added"] == `notify-send 'Added' "$URD_EVENT_DESCRIPTION"`
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set alarm 5",
+			check: func(c *Config) bool {
+				return c.Alarm == 5*time.Minute
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set join_prompt 2",
+			check: func(c *Config) bool {
+				return c.JoinPrompt == 2*time.Minute
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set travel_buffer 45m",
+			check: func(c *Config) bool {
+				return c.TravelBuffer == 45*time.Minute
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set focus_length 50",
+			check: func(c *Config) bool {
+				return c.FocusLength == 50*time.Minute
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set focus_length 0",
+			hasError: true,
+		},
+		{
+			line: "set work_hours 8:30-17",
+			check: func(c *Config) bool {
+				return c.WorkStart == 8*time.Hour+30*time.Minute && c.WorkEnd == 17*time.Hour
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set work_hours 17-9",
+			hasError: true,
+		},
+		{
+			line: "set work_hours Fri 9-14",
+			check: func(c *Config) bool {
+				start, end := c.WorkHoursOn(time.Friday)
+				monStart, monEnd := c.WorkHoursOn(time.Monday)
+				return start == 9*time.Hour && end == 14*time.Hour && monStart == 8*time.Hour+30*time.Minute && monEnd == 17*time.Hour
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set work_hours sat off",
+			check: func(c *Config) bool {
+				start, end := c.WorkHoursOn(time.Saturday)
+				return end <= start
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set work_hours someday 9-14",
+			hasError: true,
+		},
+		{
+			line:     "set work_hours off",
+			hasError: true,
+		},
+		{
+			line: "set template2.file=~/work.rem",
+			check: func(c *Config) bool {
+				home, _ := os.UserHomeDir()
+				return c.TemplateTargets["template2"].File == filepath.Join(home, "work.rem")
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set timed_template.tags @work, meetings",
+			check: func(c *Config) bool {
+				tags := c.TemplateTargets["timed_template"].Tags
+				return len(tags) == 2 && tags[0] == "@work" && tags[1] == "meetings" && c.TemplateTargets["template2"].File != ""
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set template2.color blue",
+			hasError: true,
+		},
+		{
+			line:     "set template12.file ~/work.rem",
+			hasError: true,
+		},
+		{
+			line: "set shade_off_hours true",
+			check: func(c *Config) bool {
+				return c.ShadeOffHours
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set p2_cache_ttl 300",
+			check: func(c *Config) bool {
+				return c.P2CacheTTL == 5*time.Minute
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "p2 clientA #0080ff p2 work --json ~/clients/a/tasks.rec",
+			check: func(c *Config) bool {
+				return len(c.P2Profiles) == 1 && c.P2Profiles[0] == P2Profile{Name: "clientA", Color: "#0080ff", Command: "p2 work --json ~/clients/a/tasks.rec"}
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: `p2 clientB "p2 work --json b.rec"`,
+			check: func(c *Config) bool {
+				return len(c.P2Profiles) > 0 && c.P2Profiles[len(c.P2Profiles)-1] == P2Profile{Name: "clientB", Command: "p2 work --json b.rec"}
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "source jira jira --project OPS",
+			check: func(c *Config) bool {
+				return len(c.Sources) > 0 && c.Sources[len(c.Sources)-1] == SourceConfig{Name: "jira", Kind: "jira", Args: "--project OPS"}
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "p2 p2 p2 work --json other.rec",
+			hasError: true,
+		},
+		{
+			line:     "source remind plugin ~/bin/urd-remind",
+			hasError: true,
+		},
+		{
+			line:     "source clientA plugin ~/bin/urd-client",
+			hasError: true,
+		},
+		{
+			line:     "p2 jira p2 work --json jira.rec",
+			hasError: true,
+		},
+		{
+			line: "set github_token ghp_abc123",
+			check: func(c *Config) bool {
+				return c.GitHubToken == "ghp_abc123"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set jira_user me@example.com",
+			check: func(c *Config) bool {
+				return c.JiraUser == "me@example.com"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set caldav_files /r/work.rem, /r/home.rem",
+			check: func(c *Config) bool {
+				return len(c.CalDAVFiles) == 2 && c.CalDAVFiles[0] == "/r/work.rem" && c.CalDAVFiles[1] == "/r/home.rem"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set caldav_days 0",
+			hasError: true,
+		},
+		{
+			line: "set prefetch_months 6",
+			check: func(c *Config) bool {
+				return c.PrefetchMonths == 6
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "set prefetch_months -1",
+			hasError: true,
+		},
+		{
+			line: "source todo plugin ~/bin/todoist-urd",
+			check: func(c *Config) bool {
+				return len(c.Sources) > 0 && c.Sources[len(c.Sources)-1] == SourceConfig{Name: "todo", Kind: "plugin", Args: "~/bin/todoist-urd"}
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "set chord_timeout 500",
+			check: func(c *Config) bool {
+				return c.ChordTimeout == 500*time.Millisecond
+			},
+			expected: true,
+			hasError: false,
+		},
 		{
 			line: "color today yellow",
 			check: func(c *Config) bool {
@@ -101,6 +362,40 @@ func TestParseLine(t *testing.T) {
 			expected: true,
 			hasError: false,
 		},
+		{
+			line: "color tag:@Work-Stuff #3366cc",
+			check: func(c *Config) bool {
+				return c.Colors["tag:work-stuff"] == "#3366cc"
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: `ignore description "^Backup (done|failed)"`,
+			check: func(c *Config) bool {
+				rule := c.Ignore[len(c.Ignore)-1]
+				return rule.Field == "description" && rule.Pattern.MatchString("Backup done") && !rule.Pattern.MatchString("Check Backup done")
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line: "ignore file shared/.*\\.rem$",
+			check: func(c *Config) bool {
+				rule := c.Ignore[len(c.Ignore)-1]
+				return rule.Field == "file" && rule.Pattern.MatchString("/home/me/shared/bots.rem")
+			},
+			expected: true,
+			hasError: false,
+		},
+		{
+			line:     "ignore tag [unclosed",
+			hasError: true,
+		},
+		{
+			line:     "ignore location office",
+			hasError: true,
+		},
 		{
 			line:     "invalid command",
 			hasError: true,
@@ -137,6 +432,32 @@ func TestParseLine(t *testing.T) {
 	}
 }
 
+func TestKeySequence(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"j", []string{"j"}},
+		{"<", []string{"<"}},
+		{"<down>", []string{"<down>"}},
+		{`\Cl`, []string{`\Cl`}},
+		{"gg", []string{"g", "g"}},
+		{"g t", []string{"g", "t"}},
+		{"g<enter>", []string{"g", "<enter>"}},
+		{`\Cxd`, []string{`\Cx`, "d"}},
+		{"ctrl+x d", []string{`\Cx`, "d"}},
+		{"<ctrl+n>", []string{`\Cn`}},
+		{"alt+b", []string{"alt+b"}},
+	}
+
+	for _, tt := range tests {
+		got := KeySequence(tt.key)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("KeySequence(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
 func TestSetVariable(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -183,6 +504,37 @@ func TestSetVariable(t *testing.T) {
 			},
 			hasError: false,
 		},
+		{
+			name:  "initial_time",
+			value: "08:30",
+			check: func(c *Config) bool {
+				return c.InitialTime == "08:30"
+			},
+		},
+		{
+			name:  "initial_time",
+			value: "day_start",
+			check: func(c *Config) bool {
+				return c.InitialTime == "day_start"
+			},
+		},
+		{
+			name:     "initial_time",
+			value:    "soon",
+			hasError: true,
+		},
+		{
+			name:  "initial_position",
+			value: "top",
+			check: func(c *Config) bool {
+				return c.InitialPosition == "top"
+			},
+		},
+		{
+			name:     "initial_position",
+			value:    "bottom",
+			hasError: true,
+		},
 		{
 			name:  "confirm_delete",
 			value: "true",
@@ -199,6 +551,143 @@ func TestSetVariable(t *testing.T) {
 			},
 			hasError: false,
 		},
+		{
+			name:  "refresh_rate",
+			value: "issues 10m",
+			check: func(c *Config) bool {
+				return c.RefreshRates["issues"] == 10*time.Minute && c.RefreshRate == 5*time.Minute
+			},
+			hasError: false,
+		},
+		{
+			name:  "presentation_mode",
+			value: "true",
+			check: func(c *Config) bool {
+				return c.PresentationMode
+			},
+			hasError: false,
+		},
+		{
+			name:  "palette",
+			value: "deuteranopia",
+			check: func(c *Config) bool {
+				return c.Palette == "deuteranopia"
+			},
+			hasError: false,
+		},
+		{
+			name:     "palette",
+			value:    "sepia",
+			hasError: true,
+		},
+		{
+			name:  "dedup_policy",
+			value: "merge-tags",
+			check: func(c *Config) bool {
+				return c.DedupPolicy == "merge-tags"
+			},
+			hasError: false,
+		},
+		{
+			name:     "dedup_policy",
+			value:    "last",
+			hasError: true,
+		},
+		{
+			name:  "accessible",
+			value: "1",
+			check: func(c *Config) bool {
+				return c.Accessible
+			},
+			hasError: false,
+		},
+		{
+			name:  "shade_weekends",
+			value: "true",
+			check: func(c *Config) bool {
+				return c.ShadeWeekends
+			},
+			hasError: false,
+		},
+		{
+			name:  "compact_schedule",
+			value: "true",
+			check: func(c *Config) bool {
+				return c.Compact
+			},
+			hasError: false,
+		},
+		{
+			name:  "journal_dir",
+			value: "~/notes",
+			check: func(c *Config) bool {
+				return c.JournalDir != "~/notes" && strings.HasSuffix(c.JournalDir, "notes")
+			},
+			hasError: false,
+		},
+		{
+			name:  "gutter_heat",
+			value: "8",
+			check: func(c *Config) bool {
+				return c.GutterHeat == 8
+			},
+			hasError: false,
+		},
+		{
+			name:     "gutter_heat",
+			value:    "many",
+			hasError: true,
+		},
+		{
+			name:  "countdown_warning",
+			value: "14",
+			check: func(c *Config) bool {
+				return c.CountdownWarning == 14
+			},
+			hasError: false,
+		},
+		{
+			name:     "countdown_urgent",
+			value:    "-1",
+			hasError: true,
+		},
+		{
+			name:  "goto_big_endian",
+			value: "false",
+			check: func(c *Config) bool {
+				return c.GotoDayFirst && !c.QuickDayFirst
+			},
+			hasError: false,
+		},
+		{
+			name:  "quick_date_US",
+			value: "false",
+			check: func(c *Config) bool {
+				return c.QuickDayFirst && c.DateLayout("Mon Jan 2, 2006") == "Mon 2 Jan 2006"
+			},
+			hasError: false,
+		},
+		{
+			name:  "untimed_window_height",
+			value: "8",
+			check: func(c *Config) bool {
+				return c.UntimedHeight == 8
+			},
+			hasError: false,
+		},
+		{
+			name:     "untimed_window_height",
+			value:    "0",
+			hasError: true,
+		},
+		{
+			name:  "untimed_window_width",
+			value: "40",
+			check: func(c *Config) bool {
+				return c.UntimedWidth == 40
+			},
+			hasError: false,
+		},
 		{
 			name:     "unknown_variable",
 			value:    "something",
@@ -325,3 +814,205 @@ func TestGetDefaultEditor(t *testing.T) {
 		t.Errorf("Expected vi, got %s", editor)
 	}
 }
+
+func TestStarterConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	starter := StarterConfig(filepath.Join(home, "cal", "reminders"), "/opt/bin/remind")
+	if !strings.Contains(starter, "set remind_files ~/cal/reminders\n") {
+		t.Errorf("expected the remind file relative to ~, got:\n%s", starter)
+	}
+
+	// Every option offered must be valid once uncommented
+	uncommented := regexp.MustCompile(`(?m)^#((?:set|bind|color) )`).ReplaceAllString(starter, "$1")
+	configFile := filepath.Join(t.TempDir(), "urdrc")
+	if err := os.WriteFile(configFile, []byte(uncommented), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	if err := cfg.loadFromFile(configFile); err != nil {
+		t.Fatalf("starter config doesn't load: %v", err)
+	}
+	if len(cfg.RemindFiles) != 1 || cfg.RemindFiles[0] != filepath.Join(home, "cal", "reminders") {
+		t.Errorf("unexpected remind files %v", cfg.RemindFiles)
+	}
+	if cfg.RemindCommand != "/opt/bin/remind" {
+		t.Errorf("unexpected remind command %q", cfg.RemindCommand)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	remindFile := filepath.Join(dir, "reminders")
+	if err := os.WriteFile(remindFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := filepath.Join(dir, "urdrc")
+	content := `set remind_files ` + remindFile + `
+set bogus 1
+bind q quitt
+bind x cut
+bind x copy
+bind g goto
+bind "g t" home
+bind Q help
+set timed_template "REM %monname% %day% AT %hour%:%min% MSG %\"<++>%\"%"
+set caldav_files ` + filepath.Join(dir, "missing.rem") + `
+set refresh_rate issuez 10m
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := Check(configFile)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	expected := []Problem{
+		{Line: 0, Warning: true, Message: "no key is bound to quit anymore"},
+		{Line: 2, Message: "unknown configuration variable: bogus"},
+		{Line: 3, Message: "unknown action: quitt"},
+		{Line: 5, Warning: true, Message: "x was bound to cut on line 4, this binding replaces it"},
+		{Line: 6, Warning: true, Message: "g also starts g t, so it only runs after chord_timeout"},
+		{Line: 9, Message: "unknown placeholder in timed_template: %day%"},
+		{Line: 11, Warning: true, Message: "refresh_rate set for unknown source: issuez"},
+	}
+	var got []Problem
+	for _, problem := range problems {
+		if problem.Line == 10 && problem.Warning && strings.HasPrefix(problem.Message, "caldav_files can't be read") {
+			continue
+		}
+		got = append(got, problem)
+	}
+	if len(got) != len(expected) || len(problems) != len(expected)+1 {
+		t.Fatalf("expected %v and the missing caldav file, got %v", expected, problems)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("problem %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+
+	// The default templates and a starter config are fine
+	if err := os.WriteFile(configFile, []byte(StarterConfig(remindFile, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if problems, err := Check(configFile); err != nil || len(problems) != 0 {
+		t.Errorf("expected no problems with the starter config, got %v, %v", problems, err)
+	}
+}
+
+func TestActionsCoverDefaultBindings(t *testing.T) {
+	for key, action := range DefaultConfig().KeyBindings {
+		if !isAction(action) {
+			t.Errorf("%s is bound to %s, which isn't in Actions", key, action)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	cfg := DefaultConfig()
+	if layout := cfg.DateLayout("Mon Jan 2"); layout != "Mon Jan 2" {
+		t.Errorf("DateLayout put the day first by default: %q", layout)
+	}
+
+	cfg.QuickDayFirst = true
+	for layout, want := range map[string]string{
+		"Mon Jan 2, 2006":            "Mon 2 Jan 2006",
+		"─Mon Jan 02":                "─Mon 02 Jan",
+		"Mon Jan _2":                 "Mon _2 Jan",
+		"Monday, January 2 at 15:04": "Monday, 2 January at 15:04",
+		"January 2006":               "January 2006",
+		"01/02/2006":                 "02/01/2006",
+		"2006-01-02":                 "2006-01-02",
+	} {
+		if got := cfg.DateLayout(layout); got != want {
+			t.Errorf("DateLayout(%q) = %q, want %q", layout, got, want)
+		}
+	}
+}
+
+func TestTranslateWyrd(t *testing.T) {
+	dir := t.TempDir()
+	wyrdrc := filepath.Join(dir, "wyrdrc")
+	content := `# wyrd settings
+set reminders_file="/cal/reminders"
+set timed_template="REM %monname% %mday% %year% AT %hour%:%min% MSG %\"<++>%\"%"
+set schedule_12_hour="true"
+bind "\\Cl" refresh
+bind "<return>" edit
+bind "<tab>" switch_window
+bind "v" view_week
+unbind "z"
+color calendar_today yellow default
+color help green default
+include "keys"
+`
+	if err := os.WriteFile(wyrdrc, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "keys"), []byte(`bind "x" cut`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, problems, err := TranslateWyrd(wyrdrc)
+	if err != nil {
+		t.Fatalf("TranslateWyrd: %v", err)
+	}
+	for _, want := range []string{
+		`set reminders_file "/cal/reminders"`,
+		`bind \Cl refresh`,
+		`bind <enter> edit`,
+		`bind <tab> next_area`,
+		`color today yellow`,
+		`bind x cut`,
+	} {
+		if !contains(lines, want) {
+			t.Errorf("expected %q in:\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+
+	var unmapped []int
+	for _, problem := range problems {
+		unmapped = append(unmapped, problem.Line)
+	}
+	if fmt.Sprint(unmapped) != "[4 8 9 11]" {
+		t.Errorf("expected lines 4, 8, 9 and 11 reported, got %v", problems)
+	}
+
+	cfg := DefaultConfig()
+	if err := cfg.loadFromWyrd(wyrdrc); err != nil {
+		t.Fatalf("loadFromWyrd: %v", err)
+	}
+	if cfg.RemindFiles[0] != "/cal/reminders" || cfg.KeyBindings[`\Cl`] != "refresh" || cfg.KeyBindings["x"] != "cut" {
+		t.Errorf("wyrdrc not applied: files %v, bindings %v", cfg.RemindFiles, cfg.KeyBindings)
+	}
+	if cfg.TimedTemplate != `REM %monname% %mday% %year% AT %hour%:%min% MSG %"<++>%"%` {
+		t.Errorf("unexpected timed template %q", cfg.TimedTemplate)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := map[string]string{
+		"~":                   home,
+		"~/.reminders":        filepath.Join(home, ".reminders"),
+		"$HOME/cal/work.rem":  filepath.Join(home, "cal", "work.rem"),
+		"~other/.reminders":   "~other/.reminders",
+		"$HOMEDIR/.reminders": "$HOMEDIR/.reminders",
+		"/etc/reminders":      "/etc/reminders",
+		"cal/~/reminders.rem": "cal/~/reminders.rem",
+	}
+	for path, want := range tests {
+		if got := ExpandHome(path); got != want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
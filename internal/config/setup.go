@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultConfigPath returns where a new urdrc is written: urd/urdrc in
+// $XDG_CONFIG_HOME, or in ~/.config when it isn't set
+func DefaultConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "urd", "urdrc")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "urd", "urdrc")
+}
+
+// StatePath returns where urd keeps the named state across restarts: in
+// urd in $XDG_STATE_HOME, or in ~/.local/state when it isn't set
+func StatePath(name string) string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "urd", name)
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "urd", name)
+}
+
+// StarterConfig returns an urdrc using the given remind file and command,
+// with the most common options commented out at their defaults
+func StarterConfig(remindFile, remindCommand string) string {
+	home, _ := os.UserHomeDir()
+	if home != "" && strings.HasPrefix(remindFile, home+string(filepath.Separator)) {
+		remindFile = "~/" + remindFile[len(home)+1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(`# urd configuration
+#
+# Lines are "set <option> <value>", "bind <key> <action>" or
+# "color <element> <color>". Remove the # in front of an option to change it.
+
+# Remind files, separated by commas. New reminders are added to the first.
+set remind_files ` + remindFile + `
+`)
+	if remindCommand != "" && remindCommand != "remind" {
+		b.WriteString("set remind_command " + remindCommand + "\n")
+	} else {
+		b.WriteString("#set remind_command remind\n")
+	}
+	b.WriteString(`
+# Display
+#set week_start_day monday
+#set time_format 15:04
+#set date_format "Jan 2, 2006"
+#set startup_view month
+#set initial_time now
+#set initial_position center
+#set wrap_text true
+#set shade_weekends false
+#set shade_off_hours false
+#set palette default
+
+# Behavior
+#set auto_refresh true
+#set refresh_rate 30
+#set prefetch_months 0
+#set confirm_delete true
+#set work_hours 9-17
+#set work_hours fri 9-14
+#set work_hours sat off
+#set focus_length 25m
+
+# Editor used to edit reminders, %file% and %line% are replaced
+#set edit_old_command "vim +%line% %file%"
+#set edit_new_command "vim +999999 %file%"
+
+# Key bindings, press ? in urd to see all actions
+#bind q quit
+#bind r refresh
+
+# Colors
+#color today yellow
+#color selected reverse
+#color today_separator black yellow bold
+#color weekend_separator blue bold underline
+#color weekend_shade default 235
+#color off_hours_shade default 234
+`)
+	return b.String()
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// wyrdUnsupported are wyrd variables urd accepts but doesn't act on
+var wyrdUnsupported = []string{
+	"timed_bold", "untimed_bold", "description_first", "schedule_12_hour",
+	"busy_algorithm", "untimed_duration", "status_12_hour",
+	"busy_level1", "busy_level2", "busy_level3", "busy_level4",
+	"selection_12_hour", "description_12_hour", "number_weeks", "home_sticky",
+	"advance_warning",
+}
+
+// wyrdActions are wyrd actions urd has under another name
+var wyrdActions = map[string]string{
+	"switch_window":   "next_area",
+	"entry_backspace": "delete_backward_char",
+}
+
+// wyrdColors are the wyrd color objects urd has an element for
+var wyrdColors = map[string]string{
+	"timed_default":    "normal",
+	"calendar_today":   "today",
+	"timed_reminder1":  "event",
+	"untimed_reminder": "event",
+	"calendar_labels":  "header",
+}
+
+// wyrdKeys are wyrd key names urd knows by another name
+var wyrdKeys = map[string]string{
+	"<return>": "<enter>",
+}
+
+var (
+	wyrdBindRe    = regexp.MustCompile(`^bind\s+("(?:[^"\\]|\\.)*"|\S+)\s+(\S+)$`)
+	wyrdUnbindRe  = regexp.MustCompile(`^unbind\s+("(?:[^"\\]|\\.)*"|\S+)$`)
+	wyrdColorRe   = regexp.MustCompile(`^color\s+(\w+)\s+(\S+)(?:\s+(\S+))?$`)
+	wyrdIncludeRe = regexp.MustCompile(`^include\s+("[^"]*"|\S+)$`)
+)
+
+// DefaultWyrdConfigPath returns where wyrd keeps its configuration
+func DefaultWyrdConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".wyrdrc")
+}
+
+// TranslateWyrd reads the wyrdrc at path, and the files it includes, and
+// returns the same configuration as urdrc lines. Lines urd has no
+// equivalent for are kept as comments and reported as warnings.
+func TranslateWyrd(path string) ([]string, []Problem, error) {
+	return translateWyrd(path, map[string]bool{})
+}
+
+func translateWyrd(path string, seen map[string]bool) ([]string, []Problem, error) {
+	if seen[path] {
+		return nil, nil, fmt.Errorf("%s includes itself", path)
+	}
+	seen[path] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	var problems []Problem
+	unmapped := func(lineNum int, line, format string, args ...any) {
+		reason := fmt.Sprintf(format, args...)
+		lines = append(lines, "# "+line+" (wyrd: "+reason+")")
+		problems = append(problems, Problem{Line: lineNum, Warning: true, Message: reason})
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+			continue
+		}
+
+		if matches := setRe.FindStringSubmatch(line); matches != nil {
+			name := matches[1]
+			if contains(wyrdUnsupported, name) {
+				unmapped(lineNum, line, "urd doesn't support %s", name)
+				continue
+			}
+			if err := DefaultConfig().setVariable(name, matches[2]); err != nil {
+				unmapped(lineNum, line, "%v", err)
+				continue
+			}
+			lines = append(lines, "set "+name+" "+matches[2])
+			continue
+		}
+
+		if matches := wyrdBindRe.FindStringSubmatch(line); matches != nil {
+			key := wyrdKey(matches[1])
+			action := matches[2]
+			if renamed, ok := wyrdActions[action]; ok {
+				action = renamed
+			}
+			if !isAction(action) {
+				unmapped(lineNum, line, "urd has no %s action", matches[2])
+				continue
+			}
+			if strings.ContainsAny(key, " \t") {
+				key = `"` + key + `"`
+			}
+			lines = append(lines, "bind "+key+" "+action)
+			continue
+		}
+
+		if matches := wyrdUnbindRe.FindStringSubmatch(line); matches != nil {
+			unmapped(lineNum, line, "urd can't unbind keys, bind %s to another action", wyrdKey(matches[1]))
+			continue
+		}
+
+		if matches := wyrdColorRe.FindStringSubmatch(line); matches != nil {
+			element, ok := wyrdColors[matches[1]]
+			if !ok {
+				unmapped(lineNum, line, "urd has no color for %s", matches[1])
+				continue
+			}
+			lines = append(lines, "color "+element+" "+matches[2])
+			if background := matches[3]; background != "" && background != "default" {
+				problems = append(problems, Problem{Line: lineNum, Warning: true,
+					Message: fmt.Sprintf("urd colors %s with %s, ignoring the %s background", element, matches[2], background)})
+			}
+			continue
+		}
+
+		if matches := wyrdIncludeRe.FindStringSubmatch(line); matches != nil {
+			included := strings.Trim(matches[1], `"`)
+			if included = ExpandHome(included); !filepath.IsAbs(included) {
+				included = filepath.Join(filepath.Dir(path), included)
+			}
+			includedLines, includedProblems, err := translateWyrd(included, seen)
+			if err != nil {
+				unmapped(lineNum, line, "%v", err)
+				continue
+			}
+			lines = append(lines, "# "+line)
+			lines = append(lines, includedLines...)
+			for _, problem := range includedProblems {
+				problems = append(problems, Problem{Line: lineNum, Warning: true,
+					Message: fmt.Sprintf("%s:%d: %s", included, problem.Line, problem.Message)})
+			}
+			continue
+		}
+
+		unmapped(lineNum, line, "unknown line")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return lines, problems, nil
+}
+
+// wyrdKey converts a key from a wyrd bind command to the name urd binds it
+// by. Quoted keys escape backslashes, like "\\Cl".
+func wyrdKey(key string) string {
+	if strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) && len(key) > 1 {
+		key = strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(key[1 : len(key)-1])
+	}
+	if renamed, ok := wyrdKeys[key]; ok {
+		key = renamed
+	}
+	return key
+}
+
+// loadFromWyrd loads the configuration of a wyrdrc, skipping what urd has
+// no equivalent for
+func (c *Config) loadFromWyrd(path string) error {
+	lines, _, err := TranslateWyrd(path)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if err := c.parseLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,523 @@
+package remind
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinReminder is a REM line the built-in evaluator understands, used
+// when the remind binary isn't available
+type builtinReminder struct {
+	file      string
+	line      int
+	date      builtinDate
+	weekdays  []time.Weekday
+	back      int  // Trigger back, -N or --N
+	backAll   bool // --N counts omitted days too
+	delta     int  // Advance warning, +N or ++N; it doesn't move calendar entries
+	rep       int  // Repeat every rep days from the date, *N
+	skip      string
+	localOmit []time.Weekday
+	from      *builtinDate
+	until     *builtinDate
+	at        *int // Minutes after midnight for timed reminders
+	duration  *int // Minutes
+	priority  int
+	tags      []string
+	info      map[string]string
+	body      string
+}
+
+// builtinDate is a date that may leave out any of its parts, as in a REM
+// trigger. Parts not given are zero.
+type builtinDate struct {
+	day   int
+	month time.Month
+	year  int
+}
+
+// full reports whether day, month and year are all given
+func (d builtinDate) full() bool {
+	return d.day != 0 && d.month != 0 && d.year != 0
+}
+
+// matches reports whether day falls on the date, ignoring parts not given
+func (d builtinDate) matches(day time.Time) bool {
+	return (d.day == 0 || day.Day() == d.day) &&
+		(d.month == 0 || day.Month() == d.month) &&
+		(d.year == 0 || day.Year() == d.year)
+}
+
+// time returns the date as a time, when it is full
+func (d builtinDate) time(loc *time.Location) time.Time {
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, loc)
+}
+
+// parseDateWord adds a day, month, year or YYYY-MM-DD date to d
+func parseDateWord(word string, d *builtinDate) bool {
+	if month, ok := parseMonth(word); ok {
+		d.month = month
+		return true
+	}
+	if t, err := time.Parse("2006-01-02", word); err == nil {
+		d.day, d.month, d.year = t.Day(), t.Month(), t.Year()
+		return true
+	}
+	n, err := strconv.Atoi(word)
+	switch {
+	case err != nil || word[0] < '0' || word[0] > '9':
+		return false
+	case n >= 1 && n <= 31:
+		d.day = n
+	case n >= 1990 && n <= 2075:
+		d.year = n
+	default:
+		return false
+	}
+	return true
+}
+
+// parseBuiltinLine parses a single REM line: fixed and partial dates,
+// weekdays, back (-N), advance warnings (+N), repeats (*N), SKIP, BEFORE,
+// AFTER, OMIT, FROM, UNTIL, AT, DURATION, PRIORITY, TAG and INFO.
+// Expressions and anything else need remind.
+func parseBuiltinLine(text string) (builtinReminder, error) {
+	r := builtinReminder{priority: 5000}
+	fields, offsets := splitFields(text)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "REM") {
+		return r, fmt.Errorf("%w: not a REM line", ErrParse)
+	}
+
+	next := func(i int) (string, error) {
+		if i+1 >= len(fields) {
+			return "", fmt.Errorf("%s needs a value", fields[i])
+		}
+		return fields[i+1], nil
+	}
+
+	// dateAfter reads the date following UNTIL or FROM
+	dateAfter := func(i int) (*builtinDate, int, error) {
+		var d builtinDate
+		j := i + 1
+		for j < len(fields) && parseDateWord(strings.ToUpper(fields[j]), &d) {
+			j++
+		}
+		if !d.full() {
+			return nil, i, fmt.Errorf("%s needs a full date", fields[i])
+		}
+		return &d, j - 1, nil
+	}
+
+	for i := 1; i < len(fields); i++ {
+		word := strings.ToUpper(fields[i])
+		switch word {
+		case "MSG", "MSF", "CAL":
+			if i+1 < len(fields) {
+				r.body = strings.TrimSpace(text[offsets[i+1]:])
+			}
+			if r.rep > 0 && !r.date.full() {
+				return r, fmt.Errorf("repeats need a full date")
+			}
+			return r, nil
+		case "AT":
+			value, err := next(i)
+			if err != nil {
+				return r, err
+			}
+			minutes, err := parseClock(value)
+			if err != nil {
+				return r, err
+			}
+			r.at = &minutes
+			i++
+			// Time deltas and repeats only matter for alarms
+			for i+1 < len(fields) && (fields[i+1][0] == '+' || fields[i+1][0] == '*') {
+				i++
+			}
+		case "DURATION":
+			value, err := next(i)
+			if err != nil {
+				return r, err
+			}
+			minutes, err := parseMinutes(value)
+			if err != nil {
+				return r, err
+			}
+			r.duration = &minutes
+			i++
+		case "PRIORITY":
+			value, err := next(i)
+			if err != nil {
+				return r, err
+			}
+			if r.priority, err = strconv.Atoi(value); err != nil {
+				return r, fmt.Errorf("invalid priority: %s", value)
+			}
+			i++
+		case "TAG":
+			value, err := next(i)
+			if err != nil {
+				return r, err
+			}
+			r.tags = append(r.tags, value)
+			i++
+		case "INFO":
+			if i+1 >= len(fields) || fields[i+1][0] != '"' {
+				return r, fmt.Errorf("INFO needs a quoted value")
+			}
+			start := offsets[i+1] + 1
+			end := strings.IndexByte(text[start:], '"')
+			if end < 0 {
+				return r, fmt.Errorf("unterminated INFO")
+			}
+			if key, value, ok := strings.Cut(text[start:start+end], ":"); ok {
+				if r.info == nil {
+					r.info = make(map[string]string)
+				}
+				r.info[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+			for i+1 < len(fields) && offsets[i+1] <= start+end {
+				i++
+			}
+		case "SKIP", "BEFORE", "AFTER":
+			r.skip = word
+		case "OMIT":
+			for i+1 < len(fields) {
+				weekday, ok := parseWeekday(strings.ToUpper(fields[i+1]))
+				if !ok {
+					break
+				}
+				r.localOmit = append(r.localOmit, weekday)
+				i++
+			}
+		case "FROM", "SCANFROM":
+			d, j, err := dateAfter(i)
+			if err != nil {
+				return r, err
+			}
+			r.from, i = d, j
+		case "UNTIL":
+			d, j, err := dateAfter(i)
+			if err != nil {
+				return r, err
+			}
+			r.until, i = d, j
+		default:
+			if weekday, ok := parseWeekday(word); ok {
+				r.weekdays = append(r.weekdays, weekday)
+				continue
+			}
+			if parseDateWord(word, &r.date) {
+				continue
+			}
+			if n, ok := parseSigned(word, "--"); ok {
+				r.back, r.backAll = n, true
+			} else if n, ok := parseSigned(word, "-"); ok {
+				r.back = n
+			} else if n, ok := parseSigned(word, "++"); ok {
+				r.delta = n
+			} else if n, ok := parseSigned(word, "+"); ok {
+				r.delta = n
+			} else if n, ok := parseSigned(word, "*"); ok && n > 0 {
+				r.rep = n
+			} else {
+				return r, fmt.Errorf("unsupported: %s", fields[i])
+			}
+		}
+	}
+	return r, fmt.Errorf("no MSG")
+}
+
+// parseSigned parses a number following prefix, like the 3 of -3
+func parseSigned(word, prefix string) (int, bool) {
+	if !strings.HasPrefix(word, prefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(word[len(prefix):])
+	return n, err == nil && n >= 0
+}
+
+// splitFields splits text at whitespace like strings.Fields, also returning
+// where each field starts
+func splitFields(text string) ([]string, []int) {
+	var fields []string
+	var offsets []int
+	start := -1
+	for i, r := range text {
+		if r == ' ' || r == '\t' {
+			if start >= 0 {
+				fields = append(fields, text[start:i])
+				offsets = append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, text[start:])
+		offsets = append(offsets, start)
+	}
+	return fields, offsets
+}
+
+// parseMonth recognizes month names, abbreviated to at least three letters
+func parseMonth(word string) (time.Month, bool) {
+	if len(word) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToUpper(m.String()), word) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// parseWeekday recognizes weekday names, abbreviated to at least three
+// letters
+func parseWeekday(word string) (time.Weekday, bool) {
+	if len(word) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToUpper(d.String()), word) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses an AT time, 14:30 or 2:30pm, into minutes after midnight
+func parseClock(value string) (int, error) {
+	for _, layout := range []string{"15:04", "15.04", "3:04pm", "3pm"} {
+		if t, err := time.Parse(layout, strings.ToLower(value)); err == nil {
+			return t.Hour()*60 + t.Minute(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time: %s", value)
+}
+
+// parseMinutes parses a DURATION, h:mm or minutes
+func parseMinutes(value string) (int, error) {
+	if hours, minutes, ok := strings.Cut(value, ":"); ok {
+		h, err1 := strconv.Atoi(hours)
+		m, err2 := strconv.Atoi(minutes)
+		if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		return h*60 + m, nil
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	return minutes, nil
+}
+
+// parseOmitLine adds the days of an OMIT line to omits: a date with or
+// without its year, or weekdays. An OMIT with a MSG also reminds of the day.
+func parseOmitLine(text string, omits *builtinOmits) (*builtinReminder, error) {
+	fields, offsets := splitFields(text)
+	var d builtinDate
+	var weekdays []time.Weekday
+	for i := 1; i < len(fields); i++ {
+		word := strings.ToUpper(fields[i])
+		if word == "MSG" || word == "MSF" || word == "CAL" {
+			r, err := parseBuiltinLine("REM " + text[offsets[1]:])
+			if err != nil {
+				return nil, err
+			}
+			omits.add(d, weekdays)
+			return &r, nil
+		}
+		if weekday, ok := parseWeekday(word); ok {
+			weekdays = append(weekdays, weekday)
+		} else if !parseDateWord(word, &d) {
+			return nil, fmt.Errorf("unsupported: %s", fields[i])
+		}
+	}
+	if d.day == 0 && len(weekdays) == 0 {
+		return nil, fmt.Errorf("OMIT needs a day or weekdays")
+	}
+	omits.add(d, weekdays)
+	return nil, nil
+}
+
+// parseBuiltinFile reads the reminders and omitted days of a file the
+// built-in evaluator understands, counting the lines it had to skip. IF
+// blocks are skipped whole, as which of their lines apply takes remind.
+func parseBuiltinFile(path string, omits *builtinOmits) ([]builtinReminder, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var reminders []builtinReminder
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	lineNumber, start, depth := 0, 0, 0
+	var text strings.Builder
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if text.Len() == 0 {
+			start = lineNumber
+		}
+		// A trailing backslash continues the line
+		if strings.HasSuffix(line, "\\") {
+			text.WriteString(strings.TrimSuffix(line, "\\"))
+			continue
+		}
+		text.WriteString(line)
+		trimmed := strings.TrimSpace(text.String())
+		text.Reset()
+
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == ';' {
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		inBlock := depth > 0
+		switch strings.ToUpper(fields[0]) {
+		case "IF", "IFTRIG":
+			depth++
+			inBlock = true
+		case "ENDIF":
+			depth = max(depth-1, 0)
+			inBlock = true
+		}
+		if inBlock {
+			skipped++
+			continue
+		}
+
+		var r builtinReminder
+		if strings.EqualFold(fields[0], "OMIT") {
+			omitReminder, err := parseOmitLine(trimmed, omits)
+			if err != nil {
+				skipped++
+			}
+			if omitReminder == nil {
+				continue
+			}
+			r = *omitReminder
+		} else if r, err = parseBuiltinLine(trimmed); err != nil {
+			skipped++
+			continue
+		}
+		r.file, r.line = path, start
+		reminders = append(reminders, r)
+	}
+	return reminders, skipped, scanner.Err()
+}
+
+// entry describes the reminder on day the way remind's JSON output does
+func (r builtinReminder) entry(day time.Time) RemindEntry {
+	entry := RemindEntry{
+		Date:     day.Format("2006-01-02"),
+		Filename: r.file,
+		LineNo:   r.line,
+		Time:     r.at,
+		Duration: r.duration,
+		Priority: r.priority,
+		RawBody:  r.body,
+		Body:     r.body,
+		Tags:     TagList(r.tags),
+		Skip:     r.skip,
+		Info:     r.info,
+	}
+	if r.date.day != 0 {
+		entry.D = &r.date.day
+	}
+	if r.date.month != 0 {
+		month := int(r.date.month)
+		entry.M = &month
+	}
+	if r.date.year != 0 {
+		entry.Y = &r.date.year
+	}
+	if r.rep > 0 {
+		entry.Rep = &r.rep
+	}
+	if r.delta > 0 {
+		entry.Delta = &r.delta
+	}
+	if r.back > 0 {
+		back := -r.back
+		entry.Back = &back
+	}
+	for _, weekday := range r.weekdays {
+		entry.WD = append(entry.WD, weekday.String())
+	}
+	for _, weekday := range r.localOmit {
+		entry.LocalOmit = append(entry.LocalOmit, weekday.String())
+	}
+	return entry
+}
+
+// Degraded reports whether the remind binary can't be found. Reminders are
+// then read by the built-in evaluator, which understands common REM lines.
+func (c *Client) Degraded() bool {
+	_, err := exec.LookPath(c.RemindPath)
+	return err != nil
+}
+
+// builtinReminders reads the remind files with the built-in evaluator,
+// warning once about the lines that need remind
+func (c *Client) builtinReminders() ([]builtinReminder, builtinOmits) {
+	var reminders []builtinReminder
+	var omits builtinOmits
+	skipped := 0
+	for _, file := range c.Files {
+		fileReminders, fileSkipped, err := parseBuiltinFile(file, &omits)
+		if err != nil {
+			continue
+		}
+		reminders = append(reminders, fileReminders...)
+		skipped += fileSkipped
+	}
+
+	c.mu.Lock()
+	warn := skipped > 0 && !c.builtinWarned
+	c.builtinWarned = true
+	c.mu.Unlock()
+	if warn {
+		c.warn("%s not found: skipped %d lines that need it", c.RemindPath, skipped)
+	}
+	return reminders, omits
+}
+
+// builtinEvents returns the events from start to end without remind
+func (c *Client) builtinEvents(start, end time.Time) []Event {
+	reminders, omits := c.builtinReminders()
+	events := ConvertJSONToEvents(builtinEntries(reminders, omits, start, end), c.Timezone)
+	for i := range events {
+		events[i].Source = RemindSourceName
+	}
+	return events
+}
+
+// builtinNextOccurrences returns the next occurrence of every reminder
+// after afterTime within a year, without remind
+func (c *Client) builtinNextOccurrences(afterTime time.Time) []Event {
+	seen := make(map[string]bool)
+	var results []Event
+	events := c.builtinEvents(afterTime, afterTime.AddDate(1, 0, 0))
+	sortByStart(events)
+	for _, event := range events {
+		key := fmt.Sprintf("%s:%d", event.Filename, event.LineNumber)
+		if seen[key] || !eventStart(event).After(afterTime) {
+			continue
+		}
+		seen[key] = true
+		results = append(results, event)
+	}
+	return results
+}
@@ -0,0 +1,173 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBuiltinLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantErr  bool
+		day      int
+		month    time.Month
+		year     int
+		at       int // -1 when untimed
+		duration int // -1 without a duration
+		body     string
+	}{
+		{line: "REM Aug 26 2025 AT 14:00 DURATION 1:30 MSG Dentist", day: 26, month: time.August, year: 2025, at: 14 * 60, duration: 90, body: "Dentist"},
+		{line: "REM 26 August 2025 MSG  Pay rent  ", day: 26, month: time.August, year: 2025, at: -1, duration: -1, body: "Pay rent"},
+		{line: "rem 1 jan 2026 at 9:30am duration 45 msg New year run", day: 1, month: time.January, year: 2026, at: 9*60 + 30, duration: 45, body: "New year run"},
+		{line: "REM Aug 26 2025 PRIORITY 9000 TAG work MSG Ship it", day: 26, month: time.August, year: 2025, at: -1, duration: -1, body: "Ship it"},
+		{line: "REM Aug 26 MSG Every year", day: 26, month: time.August, at: -1, duration: -1, body: "Every year"},
+		{line: "REM 2025-08-26 AT 14:00 +15 *5 MSG Alarm", day: 26, month: time.August, year: 2025, at: 14 * 60, duration: -1, body: "Alarm"},
+		{line: `REM Aug 26 2025 INFO "Location: Main Street" MSG Dentist`, day: 26, month: time.August, year: 2025, at: -1, duration: -1, body: "Dentist"},
+		{line: "REM Mon *7 MSG Needs a full date", wantErr: true},
+		{line: "REM [today()] MSG Expression", wantErr: true},
+		{line: "REM Aug 26 2025 UNTIL Sep MSG Partial until", wantErr: true},
+		{line: "REM Aug 26 2025 AT noon MSG Lunch", wantErr: true},
+		{line: "REM Aug 26 2025", wantErr: true},
+		{line: "SET x 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			r, err := parseBuiltinLine(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseBuiltinLine() = %+v, want an error", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBuiltinLine() error: %v", err)
+			}
+			if r.date.day != tt.day || r.date.month != tt.month || r.date.year != tt.year {
+				t.Errorf("date = %d %v %d, want %d %v %d", r.date.day, r.date.month, r.date.year, tt.day, tt.month, tt.year)
+			}
+			if at := -1; r.at != nil && *r.at != tt.at || r.at == nil && tt.at != at {
+				t.Errorf("at = %v, want %d", r.at, tt.at)
+			}
+			if r.duration != nil && *r.duration != tt.duration || r.duration == nil && tt.duration != -1 {
+				t.Errorf("duration = %v, want %d", r.duration, tt.duration)
+			}
+			if r.body != tt.body {
+				t.Errorf("body = %q, want %q", r.body, tt.body)
+			}
+		})
+	}
+}
+
+func TestClientWithoutRemind(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	content := `# Simple reminders work without remind
+REM Aug 26 2025 AT 14:00 DURATION 1:00 MSG Dentist @@Main_Street
+REM Aug 27 2025 \
+    MSG Pay rent
+REM [trigger(today())] MSG Needs remind
+SET x 1
+IF today() > '2025-08-01'
+    REM Aug 27 2025 MSG Only after August 1
+ELSE
+    REM Aug 28 2025 MSG Only before
+ENDIF
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.RemindPath = filepath.Join(t.TempDir(), "no-such-remind")
+	client.SetFiles([]string{file})
+	if !client.Degraded() {
+		t.Fatal("Degraded() = false for a missing remind")
+	}
+
+	events, err := client.GetEvents(time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 8, 31, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("GetEvents() error: %v", err)
+	}
+	sortByStart(events)
+	if len(events) != 2 {
+		t.Fatalf("GetEvents() returned %d events, want 2: %+v", len(events), events)
+	}
+	dentist := events[0]
+	if dentist.Description != "Dentist" || dentist.Location != "Main Street" || dentist.LineNumber != 2 {
+		t.Errorf("Dentist event = %+v", dentist)
+	}
+	if dentist.Time == nil || dentist.Time.Hour() != 14 || dentist.Duration == nil || *dentist.Duration != time.Hour {
+		t.Errorf("Dentist time = %v, duration = %v", dentist.Time, dentist.Duration)
+	}
+	if rent := events[1]; rent.Description != "Pay rent" || rent.Time != nil || rent.LineNumber != 3 {
+		t.Errorf("rent event = %+v", rent)
+	}
+
+	// The skipped lines are reported once
+	warnings := client.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipped 7 lines") {
+		t.Errorf("Warnings() = %v, want one about 7 skipped lines", warnings)
+	}
+	client.GetEvents(time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local), time.Date(2025, 8, 31, 0, 0, 0, 0, time.Local))
+	if warnings := client.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() after reloading = %v, want none", warnings)
+	}
+
+	next, err := client.FindNext("rent", time.Date(2025, 8, 26, 15, 0, 0, 0, time.Local))
+	if err != nil || next == nil || next.Description != "Pay rent" {
+		t.Errorf("FindNext() = %+v, %v", next, err)
+	}
+}
+
+func TestBuiltinOccurrences(t *testing.T) {
+	// Christmas 2025 is a Thursday
+	omits := builtinOmits{}
+	omits.add(builtinDate{day: 25, month: time.December}, nil)
+	omits.add(builtinDate{}, []time.Weekday{time.Saturday, time.Sunday})
+
+	tests := []struct {
+		line  string
+		start string
+		end   string
+		want  []string
+	}{
+		{"REM Mon MSG Weekly", "2025-12-01", "2025-12-21", []string{"2025-12-01", "2025-12-08", "2025-12-15"}},
+		{"REM Tue Thu MSG Twice a week", "2025-12-01", "2025-12-07", []string{"2025-12-02", "2025-12-04"}},
+		{"REM 15 MSG Monthly", "2025-11-01", "2025-12-31", []string{"2025-11-15", "2025-12-15"}},
+		{"REM Dec 24 MSG Yearly", "2024-01-01", "2025-12-31", []string{"2024-12-24", "2025-12-24"}},
+		{"REM Mon 1 MSG First Monday", "2025-11-01", "2025-12-31", []string{"2025-11-03", "2025-12-01"}},
+		{"REM Thu 22 Nov MSG Thanksgiving", "2025-01-01", "2025-12-31", []string{"2025-11-27"}},
+		{"REM Dec 1 2025 *7 UNTIL 2025-12-20 MSG Every week", "2025-11-01", "2025-12-31", []string{"2025-12-01", "2025-12-08", "2025-12-15"}},
+		{"REM Dec 1 2025 *7 FROM Dec 10 2025 MSG Not before", "2025-12-01", "2025-12-16", []string{"2025-12-15"}},
+		{"REM Dec 25 +3 MSG Advance warnings don't add days", "2025-12-20", "2025-12-31", []string{"2025-12-25"}},
+		{"REM Dec 25 SKIP MSG Skipped", "2025-12-01", "2025-12-31", nil},
+		{"REM Dec 25 BEFORE MSG Before", "2025-12-01", "2025-12-31", []string{"2025-12-24"}},
+		{"REM Dec 25 AFTER MSG After", "2025-12-01", "2025-12-31", []string{"2025-12-26"}},
+		{"REM Dec 27 AFTER MSG Saturday moves to Monday", "2025-12-01", "2025-12-31", []string{"2025-12-29"}},
+		{"REM Dec 22 OMIT Mon AFTER MSG Local omit", "2025-12-01", "2025-12-31", []string{"2025-12-23"}},
+		{"REM Jan 1 -1 MSG New year's eve, no working day back", "2025-12-01", "2026-01-02", []string{"2025-12-31"}},
+		{"REM Dec 29 -2 MSG Two working days back skips the weekend", "2025-12-01", "2025-12-31", []string{"2025-12-24"}},
+		{"REM Dec 29 --2 MSG Two days back", "2025-12-01", "2025-12-31", []string{"2025-12-27"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			r, err := parseBuiltinLine(tt.line)
+			if err != nil {
+				t.Fatalf("parseBuiltinLine() error: %v", err)
+			}
+			start, _ := time.ParseInLocation("2006-01-02", tt.start, time.Local)
+			end, _ := time.ParseInLocation("2006-01-02", tt.end, time.Local)
+			var got []string
+			for _, day := range r.occurrences(omits, start, end) {
+				got = append(got, day.Format("2006-01-02"))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package remind
+
+import (
+	"slices"
+	"time"
+)
+
+// builtinOmits are the days OMIT lines leave out: dates, yearly when they
+// have no year, and weekdays
+type builtinOmits struct {
+	dates    map[builtinDate]bool
+	weekdays map[time.Weekday]bool
+}
+
+func (o *builtinOmits) add(d builtinDate, weekdays []time.Weekday) {
+	if d.day != 0 && d.month != 0 {
+		if o.dates == nil {
+			o.dates = make(map[builtinDate]bool)
+		}
+		o.dates[d] = true
+	}
+	for _, weekday := range weekdays {
+		if o.weekdays == nil {
+			o.weekdays = make(map[time.Weekday]bool)
+		}
+		o.weekdays[weekday] = true
+	}
+}
+
+// omitted reports whether day is left out, by the OMIT lines or the
+// reminder's own OMIT weekdays
+func (o builtinOmits) omitted(day time.Time, local []time.Weekday) bool {
+	if o.weekdays[day.Weekday()] || slices.Contains(local, day.Weekday()) {
+		return true
+	}
+	d := builtinDate{day: day.Day(), month: day.Month(), year: day.Year()}
+	if o.dates[d] {
+		return true
+	}
+	d.year = 0
+	return o.dates[d]
+}
+
+// matchesBase reports whether day is one the reminder's date and weekdays
+// pick, before moving it back or around omitted days
+func (r builtinReminder) matchesBase(day time.Time) bool {
+	if r.rep > 0 {
+		start := r.date.time(day.Location())
+		if day.Before(start) {
+			return false
+		}
+		days := int(day.Sub(start).Hours()/24 + 0.5)
+		return days%r.rep == 0
+	}
+	if len(r.weekdays) == 0 {
+		return r.date.matches(day)
+	}
+	if !slices.Contains(r.weekdays, day.Weekday()) {
+		return false
+	}
+	if r.date.day == 0 {
+		// Every one of the weekdays in the month or year given
+		return r.date.matches(day)
+	}
+
+	// The first of the weekdays on or after the date
+	for k := 0; k < 7; k++ {
+		ref := day.AddDate(0, 0, -k)
+		if k > 0 && slices.Contains(r.weekdays, ref.Weekday()) {
+			return false
+		}
+		if r.date.matches(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrences returns the days from start to end the reminder triggers on
+func (r builtinReminder) occurrences(omits builtinOmits, start, end time.Time) []time.Time {
+	// Moving back or around omitted days can bring base days from outside
+	// the range into it
+	margin := 2*r.back + 14
+	var days []time.Time
+	seen := make(map[time.Time]bool)
+	for base := start.AddDate(0, 0, -margin); !base.After(end.AddDate(0, 0, margin)); base = base.AddDate(0, 0, 1) {
+		if !r.matchesBase(base) {
+			continue
+		}
+
+		day := base
+		for n := r.back; n > 0; {
+			day = day.AddDate(0, 0, -1)
+			if r.backAll || !omits.omitted(day, r.localOmit) {
+				n--
+			}
+		}
+
+		if omits.omitted(day, r.localOmit) {
+			switch r.skip {
+			case "SKIP":
+				continue
+			case "BEFORE", "AFTER":
+				step := -1
+				if r.skip == "AFTER" {
+					step = 1
+				}
+				for tries := 0; omits.omitted(day, r.localOmit) && tries < 366; tries++ {
+					day = day.AddDate(0, 0, step)
+				}
+			}
+		}
+
+		if day.Before(start) || day.After(end) || seen[day] {
+			continue
+		}
+		if r.from != nil && day.Before(r.from.time(day.Location())) {
+			continue
+		}
+		if r.until != nil && day.After(r.until.time(day.Location())) {
+			continue
+		}
+		seen[day] = true
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return days
+}
+
+// builtinEntries evaluates reminders for each day from start to end
+func builtinEntries(reminders []builtinReminder, omits builtinOmits, start, end time.Time) []RemindEntry {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+
+	var entries []RemindEntry
+	for _, r := range reminders {
+		for _, day := range r.occurrences(omits, start, end) {
+			entries = append(entries, r.entry(day))
+		}
+	}
+	return entries
+}
@@ -0,0 +1,305 @@
+package remind
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calDAVPrefix starts the UIDs and resource names of mirrored events, so
+// other events in the calendar are left alone
+const calDAVPrefix = "urd-"
+
+// CalDAVUID returns a stable UID for an occurrence of a reminder, derived
+// from its file, date and content: editing a reminder gives its occurrences
+// new UIDs, moving it to another line doesn't. The UID starts with the date
+// of the occurrence, see calDAVDate.
+func CalDAVUID(event Event) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", event.Filename, event.Date.Format("2006-01-02"))
+	if event.Time != nil {
+		fmt.Fprint(h, event.Time.Format("15:04"))
+	}
+	if event.Duration != nil {
+		fmt.Fprintf(h, "+%s", *event.Duration)
+	}
+	fmt.Fprintf(h, "\x00%s\x00%s\x00%s", event.Description, event.Body, event.Location)
+	return calDAVPrefix + event.Date.Format("20060102") + "-" + hex.EncodeToString(h.Sum(nil))[:24]
+}
+
+// calDAVDate returns the date of the occurrence a UID made by CalDAVUID is
+// for, false for other UIDs
+func calDAVDate(uid string) (time.Time, bool) {
+	date, _, ok := strings.Cut(strings.TrimPrefix(uid, calDAVPrefix), "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation("20060102", date, time.Local)
+	return day, err == nil
+}
+
+// escapeICalText escapes a value of an iCalendar TEXT property
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICalLine splits a content line into lines of at most 75 octets
+func foldICalLine(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Don't split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// ICalendar renders an occurrence of a reminder as an iCalendar object with
+// the given UID
+func ICalendar(event Event, uid string, stamp time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//urd//urd//EN",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"),
+	}
+	if event.Time != nil {
+		lines = append(lines, "DTSTART:"+event.Time.UTC().Format("20060102T150405Z"))
+		if event.Duration != nil && *event.Duration > 0 {
+			lines = append(lines, "DTEND:"+event.Time.Add(*event.Duration).UTC().Format("20060102T150405Z"))
+		}
+	} else {
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+event.Date.Format("20060102"),
+			"DTEND;VALUE=DATE:"+event.Date.AddDate(0, 0, 1).Format("20060102"))
+	}
+	lines = append(lines, "SUMMARY:"+escapeICalText(event.Description))
+	if event.Body != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICalText(event.Body))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+escapeICalText(event.Location))
+	}
+	if len(event.Tags) > 0 {
+		tags := make([]string, len(event.Tags))
+		for i, tag := range event.Tags {
+			tags[i] = escapeICalText(tag)
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(tags, ","))
+	}
+	if event.IsPrivate() {
+		lines = append(lines, "CLASS:PRIVATE")
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICalLine(line))
+	}
+	return b.String()
+}
+
+// CalDAVMirror pushes reminders one way to a CalDAV calendar: changes made
+// in the calendar aren't read back. Only events it created, whose UIDs start
+// with urd-, are ever changed or deleted.
+type CalDAVMirror struct {
+	URL      string // Calendar collection, e.g. https://dav.example.com/calendars/me/urd/
+	User     string
+	Password string
+	Client   *http.Client
+}
+
+// MirrorResult counts what a mirror run changed
+type MirrorResult struct {
+	Created   int
+	Deleted   int
+	Unchanged int
+}
+
+// NewCalDAVMirror creates a mirror to the calendar collection at calendarURL
+func NewCalDAVMirror(calendarURL, user, password string) *CalDAVMirror {
+	if !strings.HasSuffix(calendarURL, "/") {
+		calendarURL += "/"
+	}
+	return &CalDAVMirror{
+		URL:      calendarURL,
+		User:     user,
+		Password: password,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Mirror makes the calendar hold exactly the given events from start until
+// end among those it created: new and changed occurrences are uploaded, and
+// those of these days no longer in events are deleted. Occurrences of other
+// days are left alone. Without any events, nothing is deleted, as that's
+// more likely a failed read than an emptied calendar.
+func (m *CalDAVMirror) Mirror(events []Event, start, end time.Time) (MirrorResult, error) {
+	var result MirrorResult
+
+	existing, err := m.list()
+	if err != nil {
+		return result, err
+	}
+
+	wanted := make(map[string]Event)
+	for _, event := range events {
+		if event.IsSpecial() {
+			continue
+		}
+		wanted[CalDAVUID(event)] = event
+	}
+
+	var stale []string
+	for uid := range existing {
+		if _, ok := wanted[uid]; ok {
+			continue
+		}
+		if day, ok := calDAVDate(uid); ok && !day.Before(start) && day.Before(end) {
+			stale = append(stale, uid)
+		}
+	}
+	if len(wanted) == 0 && len(stale) > 0 {
+		return result, fmt.Errorf("no events to mirror, not deleting the %d in the calendar", len(stale))
+	}
+
+	uids := make([]string, 0, len(wanted))
+	for uid := range wanted {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	now := time.Now()
+	for _, uid := range uids {
+		if existing[uid] {
+			result.Unchanged++
+			continue
+		}
+		if err := m.put(uid, ICalendar(wanted[uid], uid, now)); err != nil {
+			return result, err
+		}
+		result.Created++
+	}
+
+	sort.Strings(stale)
+	for _, uid := range stale {
+		if err := m.delete(uid); err != nil {
+			return result, err
+		}
+		result.Deleted++
+	}
+	return result, nil
+}
+
+// calDAVMultistatus is the answer to a PROPFIND
+type calDAVMultistatus struct {
+	Responses []struct {
+		Href string `xml:"href"`
+	} `xml:"response"`
+}
+
+// list returns the UIDs of the events urd created in the calendar
+func (m *CalDAVMirror) list() (map[string]bool, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?><d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`
+	resp, err := m.do("PROPFIND", m.URL, body, map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("listing calendar failed: %s", resp.Status)
+	}
+
+	var status calDAVMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid calendar listing: %w", err)
+	}
+
+	uids := make(map[string]bool)
+	for _, r := range status.Responses {
+		href, err := url.PathUnescape(r.Href)
+		if err != nil {
+			href = r.Href
+		}
+		name := path.Base(href)
+		if strings.HasPrefix(name, calDAVPrefix) && strings.HasSuffix(name, ".ics") {
+			uids[strings.TrimSuffix(name, ".ics")] = true
+		}
+	}
+	return uids, nil
+}
+
+// put uploads a new event
+func (m *CalDAVMirror) put(uid, ics string) error {
+	resp, err := m.do("PUT", m.URL+uid+".ics", ics, map[string]string{
+		"Content-Type":  "text/calendar; charset=utf-8",
+		"If-None-Match": "*",
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("uploading %s failed: %s", uid, resp.Status)
+	}
+	return nil
+}
+
+// delete removes an event urd created
+func (m *CalDAVMirror) delete(uid string) error {
+	resp, err := m.do("DELETE", m.URL+uid+".ics", "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deleting %s failed: %s", uid, resp.Status)
+	}
+	return nil
+}
+
+// do sends an authenticated request
+func (m *CalDAVMirror) do(method, target, body string, headers map[string]string) (*http.Response, error) {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if m.User != "" {
+		req.SetBasicAuth(m.User, m.Password)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caldav %s failed: %w", method, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,171 @@
+package remind
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCalendar is a CalDAV collection in memory
+type fakeCalendar struct {
+	mu        sync.Mutex
+	resources map[string]string
+}
+
+func (c *fakeCalendar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if user, password, ok := r.BasicAuth(); !ok || user != "me" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name := path.Base(r.URL.Path)
+	switch r.Method {
+	case "PROPFIND":
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:">`)
+		fmt.Fprint(w, `<d:response><d:href>/cal/</d:href></d:response>`)
+		for name := range c.resources {
+			fmt.Fprintf(w, `<d:response><d:href>/cal/%s</d:href></d:response>`, name)
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	case "PUT":
+		if _, exists := c.resources[name]; exists && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		c.resources[name] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	case "DELETE":
+		delete(c.resources, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestCalDAVMirror(t *testing.T) {
+	calendar := &fakeCalendar{resources: map[string]string{
+		"phone-made.ics": "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+	}}
+	server := httptest.NewServer(calendar)
+	defer server.Close()
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	at := time.Date(2025, 3, 14, 9, 30, 0, 0, time.Local)
+	hour := time.Hour
+	events := []Event{
+		{Date: day, Time: &at, Duration: &hour, Description: "Standup, daily", Filename: "/r/work.rem", LineNumber: 3, Location: "Room 1"},
+		{Date: day, Description: "Pay rent", Filename: "/r/home.rem", LineNumber: 1, Tags: []string{"money"}},
+		{Date: day, Description: "Full moon", Special: SpecialMoon},
+	}
+
+	start, end := day.AddDate(0, 0, -7), day.AddDate(0, 0, 30)
+	mirror := NewCalDAVMirror(server.URL+"/cal", "me", "secret")
+	result, err := mirror.Mirror(events, start, end)
+	if err != nil {
+		t.Fatalf("Mirror: %v", err)
+	}
+	if result != (MirrorResult{Created: 2}) {
+		t.Errorf("expected 2 events created, got %+v", result)
+	}
+
+	standup := calendar.resources[CalDAVUID(events[0])+".ics"]
+	for _, want := range []string{
+		"UID:" + CalDAVUID(events[0]),
+		"DTSTART:" + at.UTC().Format("20060102T150405Z"),
+		"DTEND:" + at.Add(hour).UTC().Format("20060102T150405Z"),
+		`SUMMARY:Standup\, daily`,
+		"LOCATION:Room 1",
+	} {
+		if !strings.Contains(standup, want+"\r\n") {
+			t.Errorf("expected %q in:\n%s", want, standup)
+		}
+	}
+	rent := calendar.resources[CalDAVUID(events[1])+".ics"]
+	if !strings.Contains(rent, "DTSTART;VALUE=DATE:20250314\r\n") || !strings.Contains(rent, "DTEND;VALUE=DATE:20250315\r\n") {
+		t.Errorf("expected an all-day event:\n%s", rent)
+	}
+
+	// Nothing changed, nothing is uploaded again
+	result, err = mirror.Mirror(events, start, end)
+	if err != nil || result != (MirrorResult{Unchanged: 2}) {
+		t.Errorf("expected no changes, got %+v, %v", result, err)
+	}
+
+	// An edited reminder replaces its old occurrence
+	events[1].Description = "Pay rent!"
+	result, err = mirror.Mirror(events, start, end)
+	if err != nil || result != (MirrorResult{Created: 1, Deleted: 1, Unchanged: 1}) {
+		t.Errorf("expected the edited event replaced, got %+v, %v", result, err)
+	}
+
+	// Occurrences of days that aren't synced are kept
+	result, err = mirror.Mirror(events[:1], day.AddDate(0, 0, 1), end)
+	if err != nil || result != (MirrorResult{Unchanged: 1}) {
+		t.Errorf("expected nothing deleted outside the days synced, got %+v, %v", result, err)
+	}
+
+	// Without any events, nothing is deleted
+	if _, err := mirror.Mirror(nil, start, end); err == nil {
+		t.Error("expected mirroring no events refused")
+	}
+	if len(calendar.resources) != 3 {
+		t.Errorf("expected nothing deleted, got %v", calendar.resources)
+	}
+
+	// Removed reminders are deleted, events urd didn't create are kept
+	result, err = mirror.Mirror(events[:1], start, end)
+	if err != nil || result != (MirrorResult{Deleted: 1, Unchanged: 1}) {
+		t.Errorf("expected 1 event deleted, got %+v, %v", result, err)
+	}
+	if len(calendar.resources) != 2 || calendar.resources["phone-made.ics"] == "" {
+		t.Errorf("expected the standup and the phone's event left, got %v", calendar.resources)
+	}
+}
+
+func TestCalDAVUID(t *testing.T) {
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	event := Event{Date: day, Description: "Pay rent", Filename: "/r/home.rem", LineNumber: 1}
+
+	if CalDAVUID(event) != CalDAVUID(event) {
+		t.Error("expected the same UID for the same occurrence")
+	}
+	moved := event
+	moved.LineNumber = 7
+	if CalDAVUID(moved) != CalDAVUID(event) {
+		t.Error("expected the same UID when the reminder moves to another line")
+	}
+	if date, ok := calDAVDate(CalDAVUID(event)); !ok || !date.Equal(day) {
+		t.Errorf("expected the date in the UID, got %v", date)
+	}
+	for name, changed := range map[string]Event{
+		"file":        {Date: day, Description: "Pay rent", Filename: "/r/work.rem", LineNumber: 1},
+		"date":        {Date: day.AddDate(0, 0, 1), Description: "Pay rent", Filename: "/r/home.rem", LineNumber: 1},
+		"description": {Date: day, Description: "Pay the rent", Filename: "/r/home.rem", LineNumber: 1},
+	} {
+		if CalDAVUID(changed) == CalDAVUID(event) {
+			t.Errorf("expected a different UID when the %s changes", name)
+		}
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := foldICalLine(line)
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("line of %d octets: %q", len(part), part)
+		}
+	}
+	if strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "") != line {
+		t.Errorf("unfolding doesn't give back the line: %q", folded)
+	}
+}
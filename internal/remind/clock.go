@@ -0,0 +1,33 @@
+package remind
+
+import "time"
+
+// Clock tells the current time. Everything that depends on the time of day
+// asks a clock, so tests and demos can fix it at a given moment.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FixedClock is a clock stopped at a moment
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time { return time.Time(c) }
+
+// clockFrom is a clock running from a moment set when it was created
+type clockFrom struct {
+	offset time.Duration
+}
+
+// ClockFrom returns a clock that starts at at and keeps running
+func ClockFrom(at time.Time) Clock {
+	return clockFrom{offset: time.Until(at)}
+}
+
+func (c clockFrom) Now() time.Time { return time.Now().Add(c.offset) }
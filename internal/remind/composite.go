@@ -1,16 +1,87 @@
 package remind
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
 
+// ErrReadOnly is returned when a write is routed to a source that doesn't support it
+var ErrReadOnly = errors.New("source is read-only")
+
+// Ways CompositeSource combines events with the same ID from several sources
+const (
+	DedupFirst        = "first"         // The event of the first source wins
+	DedupPreferRemind = "prefer-remind" // The event of the remind files wins
+	DedupMergeTags    = "merge-tags"    // The event of the first source wins, with the tags of all
+)
+
+// Fields of events an IgnoreRule can match
+const (
+	IgnoreDescription = "description"
+	IgnoreTag         = "tag"
+	IgnoreFile        = "file"
+)
+
+// IgnoreRule hides the events with a field matching Pattern
+type IgnoreRule struct {
+	Field   string // IgnoreDescription, IgnoreTag or IgnoreFile
+	Pattern *regexp.Regexp
+}
+
+// Matches reports whether the rule hides event. Tags match without the @
+// they may be written with.
+func (r IgnoreRule) Matches(event Event) bool {
+	switch r.Field {
+	case IgnoreDescription:
+		return r.Pattern.MatchString(event.Description)
+	case IgnoreTag:
+		for _, tag := range event.Tags {
+			if r.Pattern.MatchString(strings.TrimPrefix(tag, "@")) {
+				return true
+			}
+		}
+	case IgnoreFile:
+		return event.Filename != "" && r.Pattern.MatchString(event.Filename)
+	}
+	return false
+}
+
 // CompositeSource combines multiple ReminderSources
 type CompositeSource struct {
 	sources   []ReminderSource
 	mu        sync.RWMutex
 	eventChan chan FileChangeEvent
 	stopChans []chan struct{}
+	disabled  map[string]bool // Sources hidden at runtime, by name
+	rates     map[string]time.Duration
+	dedup     string // One of the Dedup policies, DedupFirst if empty
+	ignore    []IgnoreRule
+	recorder  Recorder // Receives what GetEvents returns, while recording
+
+	cacheMu sync.Mutex
+	cache   map[string]sourceEvents  // Events of sources with a refresh rate, by name
+	lazy    map[string]*lazyLoad     // Sources loaded in the background, by name, until first used
+	health  map[string]*SourceHealth // How fetching and watching went, by name
+}
+
+// lazyLoad is the first load of a source that is loaded in the background
+type lazyLoad struct {
+	started, done bool
+	loaded        sourceEvents
+	err           error
+}
+
+// sourceEvents are the events a source returned for a range of days
+type sourceEvents struct {
+	start, end time.Time
+	events     []Event
+	fetched    time.Time
 }
 
 // NewCompositeSource creates a new composite reminder source
@@ -46,16 +117,21 @@ func (c *CompositeSource) GetEvents(start, end time.Time) ([]Event, error) {
 	var allEvents []Event
 	eventMap := make(map[string]Event) // Deduplicate by ID
 
-	for _, source := range c.sources {
-		events, err := source.GetEvents(start, end)
+	for _, source := range c.enabledSources() {
+		events, err := c.eventsOf(source, start, end)
 		if err != nil {
 			// Log error but continue with other sources
 			continue
 		}
 
 		for _, event := range events {
+			if c.ignored(event) {
+				continue
+			}
 			// Use event ID for deduplication
-			if _, exists := eventMap[event.ID]; !exists {
+			if kept, exists := eventMap[event.ID]; exists {
+				eventMap[event.ID] = c.combine(kept, event)
+			} else {
 				eventMap[event.ID] = event
 			}
 		}
@@ -66,9 +142,206 @@ func (c *CompositeSource) GetEvents(start, end time.Time) ([]Event, error) {
 		allEvents = append(allEvents, event)
 	}
 
+	if c.recorder != nil {
+		c.recorder(newResponse(start, end, allEvents, nil))
+	}
 	return allEvents, nil
 }
 
+// SetRecorder implements RecordingSource - records what the sources
+// combined return, rather than each of them
+func (c *CompositeSource) SetRecorder(record Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = record
+}
+
+// SetIgnore hides the events matching any of rules from GetEvents and
+// Upcoming
+func (c *CompositeSource) SetIgnore(rules []IgnoreRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ignore = rules
+}
+
+// ignored reports whether an ignore rule hides event. Callers must hold
+// c.mu.
+func (c *CompositeSource) ignored(event Event) bool {
+	for _, rule := range c.ignore {
+		if rule.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDedupPolicy sets how events with the same ID from several sources are
+// combined, one of the Dedup policies
+func (c *CompositeSource) SetDedupPolicy(policy string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dedup = policy
+}
+
+// combine returns the event to keep of two with the same ID, the one found
+// first and a duplicate, noting the source of the other. Callers must hold
+// c.mu.
+func (c *CompositeSource) combine(kept, duplicate Event) Event {
+	if c.dedup == DedupPreferRemind && duplicate.Source == RemindSourceName && kept.Source != RemindSourceName {
+		kept, duplicate = duplicate, kept
+	}
+	if c.dedup == DedupMergeTags {
+		tags := slices.Clone(kept.Tags)
+		for _, tag := range duplicate.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		kept.Tags = tags
+	}
+
+	alsoFrom := slices.Clone(kept.AlsoFrom)
+	for _, source := range append([]string{duplicate.Source}, duplicate.AlsoFrom...) {
+		if source != "" && source != kept.Source && !slices.Contains(alsoFrom, source) {
+			alsoFrom = append(alsoFrom, source)
+		}
+	}
+	kept.AlsoFrom = alsoFrom
+	return kept
+}
+
+// SetRefreshRate makes GetEvents reuse the events of the named source for
+// the same days until they are older than rate, rather than asking the
+// source every time. They are fetched again sooner when the source reports
+// a change, after writes and on Refresh.
+func (c *CompositeSource) SetRefreshRate(name string, rate time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates == nil {
+		c.rates = make(map[string]time.Duration)
+	}
+	c.rates[name] = rate
+	c.forget(name)
+}
+
+// SetLazy makes the named source load in the background the first time its
+// events are asked for, rather than keeping GetEvents waiting. Until then it
+// has no events, and the watch channel reports when they have loaded.
+func (c *CompositeSource) SetLazy(name string) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if c.lazy == nil {
+		c.lazy = make(map[string]*lazyLoad)
+	}
+	c.lazy[name] = &lazyLoad{}
+}
+
+// loading reports whether the named source is lazy and not loaded yet
+func (c *CompositeSource) loading(name string) bool {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	load := c.lazy[name]
+	return load != nil && !load.done
+}
+
+// lazyEvents returns the events of a lazy source, starting to load them in
+// the background the first time. ok is false once the source is loaded and
+// its events are to be fetched as usual.
+func (c *CompositeSource) lazyEvents(source ReminderSource, name string, start, end time.Time) (events []Event, ok bool, err error) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	load := c.lazy[name]
+	switch {
+	case load == nil:
+		return nil, false, nil
+	case !load.started:
+		load.started = true
+		go c.loadLazy(source, load, start, end)
+		return nil, true, nil
+	case !load.done:
+		return nil, true, nil
+	}
+
+	// Hand over the loaded events once, then fetch as usual
+	delete(c.lazy, name)
+	if !load.loaded.start.Equal(start) || !load.loaded.end.Equal(end) {
+		return nil, false, nil
+	}
+	return load.loaded.events, true, load.err
+}
+
+// loadLazy loads the events of a lazy source and reports them on the watch
+// channel
+func (c *CompositeSource) loadLazy(source ReminderSource, load *lazyLoad, start, end time.Time) {
+	events, err := c.fetch(source, start, end)
+
+	c.cacheMu.Lock()
+	load.done = true
+	load.loaded = sourceEvents{start: start, end: end, events: events, fetched: time.Now()}
+	load.err = err
+	c.cacheMu.Unlock()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.eventChan != nil {
+		select {
+		case c.eventChan <- FileChangeEvent{Timestamp: time.Now()}:
+		default:
+			// Channel full, a reload is already pending
+		}
+	}
+}
+
+// eventsOf returns the events of source from start to end, reusing those
+// fetched for the same days within the source's refresh rate. Callers must
+// hold c.mu.
+func (c *CompositeSource) eventsOf(source ReminderSource, start, end time.Time) ([]Event, error) {
+	info, ok := source.(SourceInfo)
+	if ok {
+		if events, lazy, err := c.lazyEvents(source, info.Name(), start, end); lazy {
+			return events, err
+		}
+	}
+	if !ok || c.rates[info.Name()] <= 0 {
+		return c.fetch(source, start, end)
+	}
+	name := info.Name()
+
+	c.cacheMu.Lock()
+	cached, ok := c.cache[name]
+	c.cacheMu.Unlock()
+	if ok && cached.start.Equal(start) && cached.end.Equal(end) && time.Since(cached.fetched) < c.rates[name] {
+		return cached.events, nil
+	}
+
+	events, err := c.fetch(source, start, end)
+	if err != nil {
+		return nil, err
+	}
+	c.cacheMu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[string]sourceEvents)
+	}
+	c.cache[name] = sourceEvents{start: start, end: end, events: events, fetched: time.Now()}
+	c.cacheMu.Unlock()
+	return events, nil
+}
+
+// forget discards the reused events of the named source, or of every
+// source when name is empty
+func (c *CompositeSource) forget(name string) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if name == "" {
+		c.cache = nil
+	} else {
+		delete(c.cache, name)
+	}
+}
+
 // WatchFiles implements ReminderSource - watches all sources
 func (c *CompositeSource) WatchFiles() (<-chan FileChangeEvent, error) {
 	c.mu.Lock()
@@ -79,10 +352,21 @@ func (c *CompositeSource) WatchFiles() (<-chan FileChangeEvent, error) {
 		stopChan := make(chan struct{})
 		c.stopChans = append(c.stopChans, stopChan)
 
+		var name string
+		if info, ok := source.(SourceInfo); ok {
+			name = info.Name()
+		}
+
 		sourceChan, err := source.WatchFiles()
-		if err != nil || sourceChan == nil {
+		if err != nil {
+			c.recordWatch(name, WatchFailed, err)
+			continue
+		}
+		if sourceChan == nil {
+			c.recordWatch(name, WatchUnsupported, nil)
 			continue // Skip sources that don't support watching
 		}
+		c.recordWatch(name, WatchActive, nil)
 
 		// Forward events from this source to our composite channel
 		go func(src <-chan FileChangeEvent, stop chan struct{}) {
@@ -90,8 +374,16 @@ func (c *CompositeSource) WatchFiles() (<-chan FileChangeEvent, error) {
 				select {
 				case event, ok := <-src:
 					if !ok {
+						c.recordWatch(name, WatchStopped, nil)
 						return
 					}
+					if event.Err != nil {
+						c.recordWatch(name, WatchFailed, event.Err)
+					} else {
+						c.recordWatch(name, WatchActive, nil)
+					}
+					// The source changed, fetch its events again
+					c.forget(name)
 					select {
 					case c.eventChan <- event:
 					default:
@@ -130,3 +422,263 @@ func (c *CompositeSource) StopWatching() error {
 
 	return nil
 }
+
+// Close ends the sources that need it, such as plugins, which would
+// otherwise outlive urd
+func (c *CompositeSource) Close() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var errs []error
+	for _, source := range c.sources {
+		if closer, ok := source.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enabledSources returns the sources that aren't disabled. Callers must
+// hold c.mu.
+func (c *CompositeSource) enabledSources() []ReminderSource {
+	var enabled []ReminderSource
+	for _, source := range c.sources {
+		if info, ok := source.(SourceInfo); ok && c.disabled[info.Name()] {
+			continue
+		}
+		enabled = append(enabled, source)
+	}
+	return enabled
+}
+
+// SourceNames implements ToggleableSource - the names of the sources that
+// identify themselves, in order
+func (c *CompositeSource) SourceNames() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var names []string
+	for _, source := range c.sources {
+		if info, ok := source.(SourceInfo); ok {
+			names = append(names, info.Name())
+		}
+	}
+	return names
+}
+
+// SetEnabled implements ToggleableSource - disabled sources are left out of
+// GetEvents and Upcoming
+func (c *CompositeSource) SetEnabled(name string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.disabled == nil {
+		c.disabled = make(map[string]bool)
+	}
+	c.disabled[name] = !enabled
+}
+
+// Enabled implements ToggleableSource
+func (c *CompositeSource) Enabled(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.disabled[name]
+}
+
+// sourceFor returns the source an event came from, or nil if unknown.
+// Callers must hold c.mu.
+func (c *CompositeSource) sourceFor(event Event) ReminderSource {
+	for _, source := range c.sources {
+		if info, ok := source.(SourceInfo); ok && info.Name() == event.Source {
+			return source
+		}
+	}
+	return nil
+}
+
+// CapabilitiesFor returns the capabilities of the source the event came from
+func (c *CompositeSource) CapabilitiesFor(event Event) Capabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if info, ok := c.sourceFor(event).(SourceInfo); ok {
+		return info.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// AddEventStruct implements EventWriter. Events that name a source are added
+// there; otherwise the first source that supports adding is used.
+func (c *CompositeSource) AddEventStruct(event Event) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	if event.Source != "" {
+		source := c.sourceFor(event)
+		if source == nil {
+			return 0, fmt.Errorf("unknown source %q", event.Source)
+		}
+		writer, ok := source.(EventWriter)
+		if !ok || !SourceCapabilities(source, event).Add {
+			return 0, fmt.Errorf("%s: %w", event.Source, ErrReadOnly)
+		}
+		return writer.AddEventStruct(event)
+	}
+
+	for _, source := range c.sources {
+		if writer, ok := source.(EventWriter); ok && SourceCapabilities(source, event).Add {
+			return writer.AddEventStruct(event)
+		}
+	}
+	return 0, fmt.Errorf("no source supports adding events")
+}
+
+// RemoveEvent implements EventWriter - removes the event from the source it came from
+func (c *CompositeSource) RemoveEvent(event Event) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	source := c.sourceFor(event)
+	if source == nil {
+		return fmt.Errorf("unknown source %q", event.Source)
+	}
+	writer, ok := source.(EventWriter)
+	if !ok || !SourceCapabilities(source, event).Remove {
+		return fmt.Errorf("%s: %w", event.Source, ErrReadOnly)
+	}
+	return writer.RemoveEvent(event)
+}
+
+// updaterFor returns the source of an event if it can change the event in
+// place. Callers must hold c.mu.
+func (c *CompositeSource) updaterFor(event Event) (EventUpdater, error) {
+	source := c.sourceFor(event)
+	if source == nil {
+		return nil, fmt.Errorf("unknown source %q", event.Source)
+	}
+	updater, ok := source.(EventUpdater)
+	if !ok || !SourceCapabilities(source, event).Edit {
+		return nil, fmt.Errorf("%s: %w", event.Source, ErrReadOnly)
+	}
+	return updater, nil
+}
+
+// CompleteEvent implements EventUpdater - marks the event done in the source it came from
+func (c *CompositeSource) CompleteEvent(event Event, at time.Time) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	updater, err := c.updaterFor(event)
+	if err != nil {
+		return err
+	}
+	return updater.CompleteEvent(event, at)
+}
+
+// RescheduleEvent implements EventUpdater - reschedules the event in the source it came from
+func (c *CompositeSource) RescheduleEvent(event Event, date time.Time, at *time.Time, duration *time.Duration) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	updater, err := c.updaterFor(event)
+	if err != nil {
+		return err
+	}
+	return updater.RescheduleEvent(event, date, at, duration)
+}
+
+// SetEventStatus implements EventUpdater - changes the status of the event in the source it came from
+func (c *CompositeSource) SetEventStatus(event Event, status string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	updater, err := c.updaterFor(event)
+	if err != nil {
+		return err
+	}
+	return updater.SetEventStatus(event, status)
+}
+
+// Refresh implements CachedSource - refreshes every source that caches
+func (c *CompositeSource) Refresh() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	defer c.forget("")
+
+	for _, source := range c.sources {
+		if cached, ok := source.(CachedSource); ok {
+			cached.Refresh()
+		}
+	}
+}
+
+// CacheStates implements CachedSource - reports the caches of all sources
+func (c *CompositeSource) CacheStates() []CacheState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var states []CacheState
+	for _, source := range c.sources {
+		if info, ok := source.(SourceInfo); ok && c.loading(info.Name()) {
+			states = append(states, CacheState{Name: info.Name(), Loading: true})
+			continue
+		}
+		if cached, ok := source.(CachedSource); ok {
+			states = append(states, cached.CacheStates()...)
+		}
+	}
+	return states
+}
+
+// Warnings implements WarningSource - collects the warnings of all sources
+func (c *CompositeSource) Warnings() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var warnings []string
+	for _, source := range c.sources {
+		if warner, ok := source.(WarningSource); ok {
+			warnings = append(warnings, warner.Warnings()...)
+		}
+	}
+	return warnings
+}
+
+// Upcoming implements UpcomingSource - merges the upcoming events of all sources
+func (c *CompositeSource) Upcoming(after time.Time, n int) ([]Event, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var all []Event
+	seen := make(map[string]int) // Deduplicate by ID, the index of each in all
+
+	for _, source := range c.enabledSources() {
+		if info, ok := source.(SourceInfo); ok && c.loading(info.Name()) {
+			// Not waiting for it, as in GetEvents
+			continue
+		}
+		events, err := Upcoming(source, after, n)
+		if err != nil {
+			// Skip failing sources like GetEvents does
+			continue
+		}
+		for _, event := range events {
+			if c.ignored(event) {
+				continue
+			}
+			if i, ok := seen[event.ID]; ok {
+				all[i] = c.combine(all[i], event)
+			} else {
+				seen[event.ID] = len(all)
+				all = append(all, event)
+			}
+		}
+	}
+
+	return firstUpcoming(all, after, n), nil
+}
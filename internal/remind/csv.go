@@ -0,0 +1,321 @@
+package remind
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// csvColumns are the columns WriteCSV writes, in order
+var csvColumns = []string{"date", "start", "end", "duration", "description", "tags", "priority", "source"}
+
+// priorityNames name priorities in exports
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+// WriteCSV writes events as CSV for spreadsheets, in the order they take
+// place: their date, start and end times (empty for untimed events), the
+// duration in minutes, the description, tags separated by spaces, priority
+// and source. Calendar annotations are left out.
+func WriteCSV(w io.Writer, events []Event) error {
+	var rows []Event
+	for _, event := range events {
+		if !event.IsSpecial() {
+			rows = append(rows, event)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if (a.Time == nil) != (b.Time == nil) {
+			return a.Time == nil // Untimed events first, as in the schedule
+		}
+		return a.Time != nil && a.Time.Before(*b.Time)
+	})
+
+	out := csv.NewWriter(w)
+	if err := out.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, event := range rows {
+		var start, end, duration string
+		if event.Time != nil {
+			start = event.Time.Format("15:04")
+			if event.Duration != nil {
+				end = event.Time.Add(*event.Duration).Format("15:04")
+			}
+		}
+		if event.Duration != nil {
+			duration = fmt.Sprint(int(event.Duration.Minutes()))
+		}
+		err := out.Write([]string{
+			event.Date.Format("2006-01-02"),
+			start,
+			end,
+			duration,
+			event.Description,
+			strings.Join(event.Tags, " "),
+			priorityNames[event.Priority],
+			event.Source,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// CSVColumns name the column of a CSV file each field of an event is read
+// from: date, start, end, duration, description, tags, priority, location
+// and all_day. Only date and description are needed.
+type CSVColumns map[string]string
+
+// CSVFields are the fields CSVColumns can name
+var CSVFields = []string{"date", "start", "end", "duration", "description", "tags", "priority", "location", "all_day"}
+
+// CSVPresets are the columns of the CSV files other tools export. The
+// default reads the CSV files WriteCSV writes.
+var CSVPresets = map[string]CSVColumns{
+	"urd": {
+		"date": "date", "start": "start", "end": "end", "duration": "duration",
+		"description": "description", "tags": "tags", "priority": "priority",
+	},
+	"outlook": {
+		"date": "Start Date", "start": "Start Time", "end": "End Time",
+		"description": "Subject", "priority": "Priority", "location": "Location",
+		"all_day": "All day event",
+	},
+	"toggl": {
+		"date": "Start date", "start": "Start time", "end": "End time",
+		"duration": "Duration", "description": "Description", "tags": "Tags",
+	},
+}
+
+// CSVRowError is why a row of a CSV file couldn't be read
+type CSVRowError struct {
+	Row int // Line of the file the row starts on
+	Err error
+}
+
+func (e *CSVRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// csvDateFormats and csvTimeFormats are the dates and times ReadCSV reads
+var (
+	csvDateFormats = []string{"2006-01-02", "1/2/2006", "2006/1/2", "2.1.2006", "Jan 2 2006", "2 Jan 2006"}
+	csvTimeFormats = []string{"15:04", "15:04:05", "3:04 PM", "3:04:05 PM", "3:04PM", "3PM"}
+)
+
+// ReadCSV reads events from a CSV file with a header row, taking each field
+// from the column columns names for it. Rows that can't be read are skipped
+// and returned as CSVRowErrors; the error is for a file that can't be read
+// at all.
+func ReadCSV(r io.Reader, columns CSVColumns) ([]Event, []error, error) {
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	header, err := in.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the header: %w", err)
+	}
+
+	index := make(map[string]int)
+	for field, column := range columns {
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+				index[field] = i
+			}
+		}
+	}
+	for _, field := range []string{"date", "description"} {
+		if _, ok := index[field]; !ok {
+			return nil, nil, fmt.Errorf("no %q column for the %s", columns[field], field)
+		}
+	}
+
+	var events []Event
+	var rowErrors []error
+	for {
+		record, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrors = append(rowErrors, &CSVRowError{Row: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		} else if err != nil {
+			return events, rowErrors, err
+		}
+		row, _ := in.FieldPos(0)
+		value := func(field string) string {
+			if i, ok := index[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		event, err := csvEvent(value)
+		if err != nil {
+			rowErrors = append(rowErrors, &CSVRowError{Row: row, Err: err})
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, rowErrors, nil
+}
+
+// csvEvent makes an event of the fields of a row
+func csvEvent(value func(string) string) (Event, error) {
+	var event Event
+	event.Description = strings.Join(strings.Fields(value("description")), " ")
+	if event.Description == "" {
+		return event, fmt.Errorf("no description")
+	}
+
+	date, err := parseCSVValue(value("date"), csvDateFormats)
+	if err != nil {
+		return event, fmt.Errorf("invalid date: %q", value("date"))
+	}
+	event.Date = date
+
+	allDay := strings.EqualFold(value("all_day"), "true") || strings.EqualFold(value("all_day"), "yes")
+	if start := value("start"); start != "" && !allDay {
+		t, err := parseCSVValue(start, csvTimeFormats)
+		if err != nil {
+			return event, fmt.Errorf("invalid start: %q", start)
+		}
+		at := time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		event.Time = &at
+
+		if duration := value("duration"); duration != "" {
+			d, err := parseCSVDuration(duration)
+			if err != nil {
+				return event, err
+			}
+			event.Duration = &d
+		} else if end := value("end"); end != "" {
+			t, err := parseCSVValue(end, csvTimeFormats)
+			if err != nil {
+				return event, fmt.Errorf("invalid end: %q", end)
+			}
+			d := time.Duration(t.Hour()*60+t.Minute()-at.Hour()*60-at.Minute()) * time.Minute
+			if d < 0 {
+				d += 24 * time.Hour // Ends after midnight
+			}
+			event.Duration = &d
+		}
+	}
+
+	event.Tags = strings.FieldsFunc(value("tags"), func(r rune) bool { return r == ',' || r == ' ' })
+	event.Location = value("location")
+	switch strings.ToLower(value("priority")) {
+	case "", "none", "normal":
+	case "low", "!":
+		event.Priority = PriorityLow
+	case "medium", "!!":
+		event.Priority = PriorityMedium
+	case "high", "!!!":
+		event.Priority = PriorityHigh
+	default:
+		return event, fmt.Errorf("invalid priority: %q", value("priority"))
+	}
+	return event, nil
+}
+
+// parseCSVValue parses a date or time in any of formats
+func parseCSVValue(value string, formats []string) (time.Time, error) {
+	for _, format := range formats {
+		if t, err := time.ParseInLocation(format, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown format")
+}
+
+// parseCSVDuration reads a duration as minutes, H:MM(:SS) or like 1h30m
+func parseCSVDuration(value string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(value); err == nil && minutes >= 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	if parts := strings.Split(value, ":"); len(parts) == 2 || len(parts) == 3 {
+		var total time.Duration
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration: %q", value)
+			}
+			total += time.Duration(n) * []time.Duration{time.Hour, time.Minute, time.Second}[i]
+		}
+		return total.Round(time.Minute), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid duration: %q", value)
+}
+
+// RemLine returns the REM line for an event read from another calendar,
+// keeping its time, duration, priority, tags and location
+func RemLine(event Event) string {
+	line := "REM " + event.Date.Format("Jan 2 2006")
+	if event.Time != nil {
+		line += " AT " + event.Time.Format("15:04")
+		if event.Duration != nil && *event.Duration > 0 {
+			minutes := int(event.Duration.Minutes())
+			line += fmt.Sprintf(" DURATION %d:%.2d", minutes/60, minutes%60)
+		}
+	}
+	switch event.Priority {
+	case PriorityLow:
+		line += " PRIORITY 5500"
+	case PriorityMedium:
+		line += " PRIORITY 6500"
+	case PriorityHigh:
+		line += " PRIORITY 9000"
+	}
+	for _, tag := range event.Tags {
+		// A tag is one word of the trigger, where brackets and quotes
+		// would start an expression or string
+		tag = strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', '[', ']', '"':
+				return '_'
+			}
+			return r
+		}, importedField(tag))
+		if tag != "" {
+			line += " TAG " + tag
+		}
+	}
+	if location := importedField(event.Location); location != "" {
+		line += fmt.Sprintf(` INFO "Location: %s"`, strings.ReplaceAll(location, `"`, "'"))
+	}
+	description := strings.ReplaceAll(importedField(event.Description), "%", "%%") // % starts a substitution
+	description = strings.ReplaceAll(description, "[", `["["]`)                    // [ starts an expression
+	return line + " MSG " + description
+}
+
+// importedField makes a field from another calendar safe to put on a REM
+// line: control characters like newlines, which would end it, become
+// spaces, and trailing backslashes, which would continue it, are dropped
+func importedField(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, value)
+	return strings.TrimRight(value, " \\")
+}
@@ -0,0 +1,117 @@
+package remind
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) *time.Time {
+		t := time.Date(2025, 8, 25, hour, minute, 0, 0, time.Local)
+		return &t
+	}
+	hour := 90 * time.Minute
+
+	events := []Event{
+		{Date: day, Time: at(14, 0), Duration: &hour, Description: `Review "Q3", part 2`, Tags: []string{"work", "@office"}, Priority: PriorityHigh, Source: RemindSourceName},
+		{Date: day.AddDate(0, 0, -1), Description: "Rent", Source: "p2"},
+		{Date: day, Special: SpecialMoon},
+		{Date: day, Time: at(9, 30), Description: "Standup", Source: RemindSourceName},
+		{Date: day, Description: "Birthday", Priority: PriorityLow, Source: RemindSourceName},
+	}
+
+	var out strings.Builder
+	if err := WriteCSV(&out, events); err != nil {
+		t.Fatal(err)
+	}
+	want := `date,start,end,duration,description,tags,priority,source
+2025-08-24,,,,Rent,,,p2
+2025-08-25,,,,Birthday,,low,remind
+2025-08-25,09:30,,,Standup,,,remind
+2025-08-25,14:00,15:30,90,"Review ""Q3"", part 2",work @office,high,remind
+`
+	if out.String() != want {
+		t.Errorf("WriteCSV wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	outlook := "\ufeffSubject,Start Date,Start Time,End Date,End Time,All day event,Location,Priority\n" +
+		"Team sync,9/1/2025,10:00 AM,9/1/2025,11:30 AM,False,Room 4,High\n" +
+		"Holiday,9/2/2025,,,,True,,Normal\n" +
+		"Broken,13/45/2025,10:00 AM,,,False,,\n" +
+		"Late call,9/3/2025,11:00 PM,9/4/2025,12:30 AM,False,,\n" +
+		",9/5/2025,,,,False,,\n"
+
+	events, rowErrors, err := ReadCSV(strings.NewReader(outlook), CSVPresets["outlook"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, event := range events {
+		lines = append(lines, RemLine(event))
+	}
+	want := []string{
+		`REM Sep 1 2025 AT 10:00 DURATION 1:30 PRIORITY 9000 INFO "Location: Room 4" MSG Team sync`,
+		"REM Sep 2 2025 MSG Holiday",
+		"REM Sep 3 2025 AT 23:00 DURATION 1:30 MSG Late call",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Read\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Error() != `row 4: invalid date: "13/45/2025"` || rowErrors[1].Error() != "row 6: no description" {
+		t.Errorf("Row errors = %v", rowErrors)
+	}
+
+	// What WriteCSV writes reads back, with a column mapped elsewhere
+	columns := CSVColumns{"date": "date", "start": "start", "duration": "minutes", "description": "description", "tags": "tags", "priority": "priority"}
+	urd := "date,start,minutes,description,tags,priority\n2025-08-25,14:00,90,100% done,work @office,high\n"
+	events, rowErrors, err = ReadCSV(strings.NewReader(urd), columns)
+	if err != nil || len(rowErrors) > 0 || len(events) != 1 {
+		t.Fatalf("ReadCSV = %v, %v, %v", events, rowErrors, err)
+	}
+	if line := RemLine(events[0]); line != "REM Aug 25 2025 AT 14:00 DURATION 1:30 PRIORITY 9000 TAG work TAG @office MSG 100%% done" {
+		t.Errorf("RemLine = %q", line)
+	}
+
+	if _, _, err := ReadCSV(strings.NewReader(urd), CSVPresets["toggl"]); err == nil {
+		t.Error("Expected an error without a date column")
+	}
+}
+
+func TestParseCSVDuration(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"45":      45 * time.Minute,
+		"1:30":    90 * time.Minute,
+		"1:30:40": 91 * time.Minute,
+		"2h15m":   135 * time.Minute,
+	} {
+		if got, err := parseCSVDuration(value); err != nil || got != want {
+			t.Errorf("parseCSVDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"soon", "-5", "1:xx"} {
+		if _, err := parseCSVDuration(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestRemLineHostileFields(t *testing.T) {
+	event := Event{
+		Date:        time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+		Description: "Pwned [shell(\"rm -rf ~\")]\nREM Aug 26 2025 MSG injected \\",
+		Location:    "HQ\" [shell(\"id\")] \\",
+		Tags:        []string{"a[b]", "new\nline"},
+	}
+	line := RemLine(event)
+	if strings.ContainsAny(line, "\n\r") || strings.HasSuffix(line, "\\") {
+		t.Errorf("RemLine = %q, expected one line not continued", line)
+	}
+	want := `REM Aug 25 2025 TAG a_b_ TAG new_line INFO "Location: HQ' [shell('id')]" MSG Pwned ["["]shell("rm -rf ~")] REM Aug 26 2025 MSG injected`
+	if line != want {
+		t.Errorf("RemLine = %q\nwant %q", line, want)
+	}
+}
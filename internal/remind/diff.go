@@ -0,0 +1,143 @@
+package remind
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffLine is a line of a diff: kept, removed or added
+type DiffLine struct {
+	Kind byte // ' ' when kept, '-' when removed, '+' when added
+	Text string
+}
+
+// diffContext is how many kept lines are shown around changes
+const diffContext = 2
+
+// maxDiffCells limits the memory used comparing the lines that changed
+const maxDiffCells = 4 << 20
+
+// DiffLines returns the lines of before and after, each kept, removed or
+// added, with as many kept as possible
+func DiffLines(before, after []string) []DiffLine {
+	// Lines the same at the start and the end need no comparing
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	var lines []DiffLine
+	for _, line := range before[:prefix] {
+		lines = append(lines, DiffLine{' ', line})
+	}
+	lines = append(lines, diffChanged(before[prefix:len(before)-suffix], after[prefix:len(after)-suffix])...)
+	for _, line := range before[len(before)-suffix:] {
+		lines = append(lines, DiffLine{' ', line})
+	}
+	return lines
+}
+
+// diffChanged compares the lines between those the same at the start and
+// the end, keeping the longest common subsequence
+func diffChanged(a, b []string) []DiffLine {
+	var lines []DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		// Too much changed to compare line by line
+		for _, line := range a {
+			lines = append(lines, DiffLine{'-', line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{'+', line})
+		}
+		return lines
+	}
+
+	// common[i][j] is how many lines a[i:] and b[j:] have in common
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, DiffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, DiffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{'+', b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// UnifiedDiff returns the changes from before to after in the unified
+// format of diff -u, for the file name, or nothing when they're the same
+func UnifiedDiff(name, before, after string) string {
+	if before == after {
+		return ""
+	}
+	lines := DiffLines(strings.Split(before, "\n"), strings.Split(after, "\n"))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", name, name)
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(lines); {
+		if lines[start].Kind == ' ' {
+			oldLine++
+			newLine++
+			start++
+			continue
+		}
+
+		// A hunk goes from the context before a change to the context
+		// after the last change closer than twice the context
+		from := max(start-diffContext, 0)
+		end := start
+		for next := start; next < len(lines); next++ {
+			if lines[next].Kind != ' ' {
+				end = next + 1
+			} else if next-end >= 2*diffContext {
+				break
+			}
+		}
+		to := min(end+diffContext, len(lines))
+
+		oldStart, newStart := oldLine-(start-from), newLine-(start-from)
+		oldCount, newCount := 0, 0
+		for _, line := range lines[from:to] {
+			if line.Kind != '+' {
+				oldCount++
+			}
+			if line.Kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, line := range lines[from:to] {
+			fmt.Fprintf(&out, "%c%s\n", line.Kind, line.Text)
+		}
+
+		oldLine, newLine = oldStart+oldCount, newStart+newCount
+		start = to
+	}
+	return out.String()
+}
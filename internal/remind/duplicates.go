@@ -0,0 +1,162 @@
+package remind
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DuplicateWindow is how far apart the starts of likely duplicates can be
+const DuplicateWindow = 5 * time.Minute
+
+// duplicateSimilarity is how similar the descriptions of likely duplicates
+// are at least, see similarity
+const duplicateSimilarity = 0.8
+
+// Duplicate is two events that are likely the same, from different files,
+// lines or sources
+type Duplicate struct {
+	Events     [2]Event // The earlier first, or the first defined
+	Similarity float64  // Of their descriptions, 1 when the same
+}
+
+// Key identifies the reminders of a duplicate, the same for every
+// occurrence of repeating ones
+func (d Duplicate) Key() string {
+	a, b := duplicateOrigin(d.Events[0]), duplicateOrigin(d.Events[1])
+	if b < a {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
+
+// duplicateOrigin returns where an event is defined: its file and line, or
+// its source and description for sources without files
+func duplicateOrigin(event Event) string {
+	if event.Filename != "" {
+		return fmt.Sprintf("%s:%d", event.Filename, event.LineNumber)
+	}
+	return event.Source + ":" + event.Description
+}
+
+// FindDuplicates returns the pairs of events that are likely duplicates: on
+// the same day, both untimed or starting within DuplicateWindow of each
+// other, with similar descriptions. Repeating reminders are only reported
+// for their first occurrence together.
+func FindDuplicates(events []Event) []Duplicate {
+	var candidates []Event
+	for _, event := range events {
+		if !event.IsSpecial() && !event.IsCancelled() {
+			candidates = append(candidates, event)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := duplicateStart(candidates[i]), duplicateStart(candidates[j])
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return duplicateOrigin(candidates[i]) < duplicateOrigin(candidates[j])
+	})
+
+	var found []Duplicate
+	seen := make(map[string]bool)
+	for i, a := range candidates {
+		for _, b := range candidates[i+1:] {
+			if !sameDate(a.Date, b.Date) {
+				break
+			}
+			if (a.Time == nil) != (b.Time == nil) || duplicateOrigin(a) == duplicateOrigin(b) {
+				continue
+			}
+			if a.Time != nil && b.Time.Sub(*a.Time) > DuplicateWindow {
+				break
+			}
+			score := similarity(a.Description, b.Description)
+			if score < duplicateSimilarity {
+				continue
+			}
+			duplicate := Duplicate{Events: [2]Event{a, b}, Similarity: score}
+			if key := duplicate.Key(); !seen[key] {
+				seen[key] = true
+				found = append(found, duplicate)
+			}
+		}
+	}
+	return found
+}
+
+// duplicateStart orders events by day, untimed ones first
+func duplicateStart(event Event) time.Time {
+	if event.Time != nil {
+		return time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), event.Time.Hour(), event.Time.Minute(), 0, 0, event.Date.Location())
+	}
+	return time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, event.Date.Location()).Add(-time.Nanosecond)
+}
+
+// sameDate reports whether a and b fall on the same calendar day
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// similarity scores how alike two descriptions are, from 0 to 1, ignoring
+// case, punctuation and spacing: by how few characters need changing, or by
+// how many words of the shorter the longer has, whichever is higher
+func similarity(a, b string) float64 {
+	wordsA, wordsB := descriptionWords(a), descriptionWords(b)
+	a, b = strings.Join(wordsA, " "), strings.Join(wordsB, " ")
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	score := 1 - float64(editDistance(ra, rb))/float64(max(len(ra), len(rb)))
+
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+	has := make(map[string]bool)
+	for _, word := range wordsB {
+		has[word] = true
+	}
+	shared := 0
+	for _, word := range wordsA {
+		if has[word] {
+			shared++
+		}
+	}
+	return max(score, float64(shared)/float64(len(wordsA)))
+}
+
+// descriptionWords returns the words of a description in lower case,
+// without punctuation
+func descriptionWords(description string) []string {
+	return strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r > 127)
+	})
+}
+
+// editDistance returns how many runes need inserting, deleting or changing
+// to turn a into b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
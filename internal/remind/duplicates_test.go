@@ -0,0 +1,70 @@
+package remind
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	monday := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(day time.Time, hour, minute int) *time.Time {
+		tm := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		return &tm
+	}
+	tuesday := monday.AddDate(0, 0, 1)
+
+	events := []Event{
+		{Date: monday, Time: at(monday, 10, 0), Description: "Dentist", Filename: "/r/home.rem", LineNumber: 1},
+		{Date: monday, Time: at(monday, 10, 5), Description: "dentist!", Filename: "/r/work.rem", LineNumber: 7},
+		{Date: monday, Time: at(monday, 10, 30), Description: "Dentist", Filename: "/r/work.rem", LineNumber: 8},
+		{Date: monday, Description: "Pay rent", Filename: "/r/home.rem", LineNumber: 2},
+		{Date: monday, Description: "Pay the rent", Source: "caldav"},
+		{Date: monday, Description: "Water plants", Filename: "/r/home.rem", LineNumber: 3},
+		{Date: monday, Time: at(monday, 9, 0), Description: "Standup", Filename: "/r/work.rem", LineNumber: 1, IsRepeating: true},
+		{Date: monday, Time: at(monday, 9, 0), Description: "Team standup", Source: "caldav", IsRepeating: true},
+		{Date: tuesday, Time: at(tuesday, 9, 0), Description: "Team standup", Source: "caldav", IsRepeating: true},
+		{Date: tuesday, Time: at(tuesday, 9, 0), Description: "Standup", Filename: "/r/work.rem", LineNumber: 1, IsRepeating: true},
+		{Date: tuesday, Time: at(tuesday, 11, 0), Description: "Review", Filename: "/r/work.rem", LineNumber: 9},
+		{Date: tuesday, Time: at(tuesday, 11, 0), Description: "Lunch", Filename: "/r/home.rem", LineNumber: 4},
+		{Date: tuesday, Time: at(tuesday, 12, 0), Description: "Retro", Filename: "/r/work.rem", LineNumber: 10, Tags: []string{"status:cancelled"}},
+		{Date: tuesday, Time: at(tuesday, 12, 0), Description: "Retro", Filename: "/r/home.rem", LineNumber: 5},
+	}
+
+	found := FindDuplicates(events)
+	expected := [][2]string{
+		{"Pay rent", "Pay the rent"},
+		{"Standup", "Team standup"},
+		{"Dentist", "dentist!"},
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("got %d duplicates, want %d: %+v", len(found), len(expected), found)
+	}
+	for i, want := range expected {
+		got := found[i]
+		if got.Events[0].Description != want[0] || got.Events[1].Description != want[1] {
+			t.Errorf("duplicate %d = %q and %q, want %q and %q", i, got.Events[0].Description, got.Events[1].Description, want[0], want[1])
+		}
+	}
+	if found[2].Similarity != 1 {
+		t.Errorf("expected descriptions differing by case and punctuation the same, got %v", found[2].Similarity)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"Dentist", "DENTIST.", 1, 1},
+		{"Weekly sync", "Weekly synk", 0.9, 0.95},
+		{"1:1 with Sam", "1:1", 1, 1},
+		{"Lunch", "Review", 0, 0.5},
+		{"", "Lunch", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); got < tt.min || got > tt.max {
+			t.Errorf("similarity(%q, %q) = %v, want between %v and %v", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package remind
+
+import (
+	"errors"
+)
+
+// Errors callers tell apart with errors.Is, to suggest how to fix them
+var (
+	// ErrNoFiles is returned when no remind file is configured to read or
+	// write
+	ErrNoFiles = errors.New("no remind files configured")
+	// ErrRemindNotFound is returned when the remind command can't be run
+	ErrRemindNotFound = errors.New("remind command not found")
+	// ErrParse is returned for reminders that can't be understood, by remind
+	// or by urd, RemindSyntaxError included
+	ErrParse = errors.New("can't parse reminder")
+	// ErrWriteConflict is returned when a reminder is no longer where it
+	// was read from, as the file changed since
+	ErrWriteConflict = errors.New("remind file changed since it was read")
+)
+
+// Is makes a RemindSyntaxError match ErrParse
+func (e *RemindSyntaxError) Is(target error) bool {
+	return target == ErrParse
+}
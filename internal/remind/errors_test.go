@@ -0,0 +1,51 @@
+package remind
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestErrorKinds(t *testing.T) {
+	client := NewClient()
+	if err := client.AddEvent("Lunch", "Aug 25 2025", ""); !errors.Is(err, ErrNoFiles) {
+		t.Errorf("AddEvent without files = %v, want ErrNoFiles", err)
+	}
+
+	client.RemindPath = "urd-test-no-remind"
+	if err := client.TestConnection(); !errors.Is(err, ErrRemindNotFound) {
+		t.Errorf("TestConnection = %v, want ErrRemindNotFound", err)
+	}
+
+	date := time.Date(2025, 8, 26, 0, 0, 0, 0, time.Local)
+	if _, err := RescheduleLine("OMIT Dec 25", date, nil, nil); !errors.Is(err, ErrParse) {
+		t.Errorf("RescheduleLine on OMIT = %v, want ErrParse", err)
+	}
+	if err := error(&RemindSyntaxError{Message: "Expecting time after AT"}); !errors.Is(err, ErrParse) {
+		t.Error("expected a syntax error to be a parse error")
+	}
+
+	// The file lost lines since the event was read from it
+	file := filepath.Join(t.TempDir(), "test.rem")
+	if err := os.WriteFile(file, []byte("REM Aug 25 2025 MSG Lunch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	event := Event{Filename: file, LineNumber: 5, Date: date}
+	if err := client.RescheduleEvent(event, date, nil, nil); !errors.Is(err, ErrWriteConflict) {
+		t.Errorf("RescheduleEvent = %v, want ErrWriteConflict", err)
+	}
+
+	// Another reminder took the event's line
+	event = Event{Filename: file, LineNumber: 1, Date: date, Description: "Dentist"}
+	if err := client.RescheduleEvent(event, date, nil, nil); !errors.Is(err, ErrWriteConflict) {
+		t.Errorf("RescheduleEvent = %v, want ErrWriteConflict", err)
+	}
+	if err := client.CompleteEvent(event, date); !errors.Is(err, ErrWriteConflict) {
+		t.Errorf("CompleteEvent = %v, want ErrWriteConflict", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "REM Aug 25 2025 MSG Lunch\n" {
+		t.Errorf("expected the file left alone, got %q", data)
+	}
+}
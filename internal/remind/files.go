@@ -0,0 +1,259 @@
+package remind
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+// RemindFileExt is the extension used when collecting files from a directory
+const RemindFileExt = ".rem"
+
+// ExpandFiles expands configured remind file entries into concrete file paths.
+// Entries may be plain files, glob patterns (e.g. ~/reminders/*.rem) or
+// directories, which are searched recursively for *.rem files. Plain files
+// are kept even if they don't exist yet so new reminders can be written to them.
+func ExpandFiles(entries []string) []string {
+	var files []string
+	seen := make(map[string]bool)
+
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, entry := range entries {
+		entry = config.ExpandHome(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if isGlob(entry) {
+			matches, err := filepath.Glob(entry)
+			if err != nil {
+				continue
+			}
+			sort.Strings(matches)
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && !info.IsDir() {
+					add(match)
+				}
+			}
+			continue
+		}
+
+		if info, err := os.Stat(entry); err == nil && info.IsDir() {
+			for _, file := range remindFilesInDir(entry) {
+				add(file)
+			}
+			continue
+		}
+
+		add(entry)
+	}
+
+	return files
+}
+
+// remindFilesInDir walks dir recursively and returns all remind files, sorted
+func remindFilesInDir(dir string) []string {
+	var files []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if !d.IsDir() && filepath.Ext(path) == RemindFileExt {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files
+}
+
+// watchDirs returns the directories that should be watched for newly created
+// files, along with a matcher deciding whether a new file belongs to the set
+func watchDirs(entries []string) map[string]func(string) bool {
+	dirs := make(map[string]func(string) bool)
+
+	for _, entry := range entries {
+		entry = config.ExpandHome(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		// The watcher reports absolute paths, so match against absolute ones
+		if abs, err := filepath.Abs(entry); err == nil {
+			entry = abs
+		}
+
+		if isGlob(entry) {
+			pattern := entry
+			dirs[filepath.Dir(pattern)] = func(path string) bool {
+				matched, _ := filepath.Match(pattern, path)
+				return matched
+			}
+			continue
+		}
+
+		if info, err := os.Stat(entry); err == nil && info.IsDir() {
+			filepath.WalkDir(entry, func(path string, d os.DirEntry, err error) error {
+				if err == nil && d.IsDir() {
+					dirs[path] = func(file string) bool {
+						return filepath.Ext(file) == RemindFileExt
+					}
+				}
+				return nil
+			})
+		}
+	}
+
+	return dirs
+}
+
+// IncludedFiles follows INCLUDE and DO statements starting from the given
+// files and returns every file reached, excluding the starting files.
+// INCLUDE paths are relative to the working directory, DO paths are relative
+// to the directory of the including file, matching remind's semantics.
+func IncludedFiles(files []string) []string {
+	visited := make(map[string]bool)
+	for _, file := range files {
+		visited[file] = true
+	}
+
+	var included []string
+	queue := append([]string{}, files...)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+
+		for _, inc := range scanIncludes(file) {
+			if visited[inc] {
+				continue
+			}
+			visited[inc] = true
+			included = append(included, inc)
+			queue = append(queue, inc)
+		}
+	}
+
+	return included
+}
+
+// scanIncludes returns the files referenced by INCLUDE/DO lines in a single file
+func scanIncludes(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var includes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		keyword := strings.ToUpper(fields[0])
+		if keyword != "INCLUDE" && keyword != "DO" {
+			continue
+		}
+
+		target := config.ExpandHome(strings.Trim(fields[1], `"`))
+		if !filepath.IsAbs(target) && keyword == "DO" {
+			target = filepath.Join(filepath.Dir(file), target)
+		}
+
+		if info, err := os.Stat(target); err == nil && info.IsDir() {
+			includes = append(includes, remindFilesInDir(target)...)
+		} else {
+			includes = append(includes, target)
+		}
+	}
+
+	return includes
+}
+
+// SourceLine returns the reminder an event was generated from, joining
+// lines continued with a trailing backslash
+func SourceLine(event Event) (string, error) {
+	lines, err := sourceLines(event)
+	if err != nil {
+		return "", err
+	}
+	reminder, _ := reminderAt(lines, event.LineNumber)
+	return reminder, nil
+}
+
+// SourceLineCount returns how many lines of its file the reminder an event
+// was generated from takes, with the lines continuing it
+func SourceLineCount(event Event) (int, error) {
+	lines, err := sourceLines(event)
+	if err != nil {
+		return 0, err
+	}
+	_, end := reminderAt(lines, event.LineNumber)
+	return end - event.LineNumber + 2, nil
+}
+
+// sourceLines returns the lines of the file an event was generated from
+func sourceLines(event Event) ([]string, error) {
+	if event.Filename == "" || event.LineNumber <= 0 {
+		return nil, fmt.Errorf("event has no source location")
+	}
+
+	content, err := os.ReadFile(event.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remind file: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if event.LineNumber > len(lines) {
+		return nil, fmt.Errorf("%w: line %d is past the end of %s", ErrWriteConflict, event.LineNumber, event.Filename)
+	}
+	return lines, nil
+}
+
+// reminderAt returns the reminder starting at line lineNumber of lines, with
+// lines continued with a trailing backslash joined, and the index of its
+// last line
+func reminderAt(lines []string, lineNumber int) (string, int) {
+	var parts []string
+	end := lineNumber - 1
+	for ; end < len(lines); end++ {
+		line := strings.TrimRight(lines[end], "\r")
+		if !strings.HasSuffix(line, "\\") {
+			parts = append(parts, line)
+			break
+		}
+		parts = append(parts, strings.TrimSuffix(line, "\\"))
+	}
+	return strings.Join(parts, ""), min(end, len(lines)-1)
+}
+
+// resolveSourcePath makes a file name reported by remind absolute. remind
+// reports included files as written in the INCLUDE line, which is relative
+// to the directory remind was run from.
+func resolveSourcePath(name string) string {
+	if name == "" || name == "-" {
+		return name
+	}
+	name = config.ExpandHome(name)
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return name
+}
+
+// isGlob reports whether the path contains glob metacharacters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
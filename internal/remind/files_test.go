@@ -0,0 +1,273 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestExpandFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "cal", "work.rem"), "REM MSG work\n")
+	writeTestFile(t, filepath.Join(dir, "cal", "sub", "home.rem"), "REM MSG home\n")
+	writeTestFile(t, filepath.Join(dir, "cal", "notes.txt"), "not a remind file\n")
+	writeTestFile(t, filepath.Join(dir, "glob", "a.rem"), "REM MSG a\n")
+	writeTestFile(t, filepath.Join(dir, "glob", "b.rem"), "REM MSG b\n")
+
+	missing := filepath.Join(dir, "new.rem")
+
+	tests := []struct {
+		name     string
+		entries  []string
+		expected []string
+	}{
+		{
+			name:     "plain file that does not exist yet",
+			entries:  []string{missing},
+			expected: []string{missing},
+		},
+		{
+			name:    "directory is searched recursively",
+			entries: []string{filepath.Join(dir, "cal")},
+			expected: []string{
+				filepath.Join(dir, "cal", "sub", "home.rem"),
+				filepath.Join(dir, "cal", "work.rem"),
+			},
+		},
+		{
+			name:    "glob pattern",
+			entries: []string{filepath.Join(dir, "glob", "*.rem")},
+			expected: []string{
+				filepath.Join(dir, "glob", "a.rem"),
+				filepath.Join(dir, "glob", "b.rem"),
+			},
+		},
+		{
+			name:    "duplicates are removed and order is kept",
+			entries: []string{missing, filepath.Join(dir, "glob", "a.rem"), filepath.Join(dir, "glob", "*.rem")},
+			expected: []string{
+				missing,
+				filepath.Join(dir, "glob", "a.rem"),
+				filepath.Join(dir, "glob", "b.rem"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandFiles(tt.entries)
+			if !slicesEqual(got, tt.expected) {
+				t.Errorf("ExpandFiles() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncludedFiles(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.rem")
+	work := filepath.Join(dir, "work.rem")
+	nested := filepath.Join(dir, "nested.rem")
+
+	writeTestFile(t, main, "REM MSG main\nDO work.rem\nINCLUDE "+work+"\n")
+	writeTestFile(t, work, "REM MSG work\nDO nested.rem\n")
+	writeTestFile(t, nested, "REM MSG nested\nDO main.rem\n") // Cycle back to main
+
+	got := IncludedFiles([]string{main})
+	expected := []string{work, nested}
+	if !slicesEqual(got, expected) {
+		t.Errorf("IncludedFiles() = %v, want %v", got, expected)
+	}
+}
+
+func TestFileWatcherPicksUpNewFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	changed := make(chan string, 10)
+	watcher, err := NewFileWatcher(func(path string) {
+		changed <- path
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	for watchDir, match := range watchDirs([]string{filepath.Join(dir, "*.rem")}) {
+		if err := watcher.AddDir(watchDir, match); err != nil {
+			t.Fatalf("Failed to watch directory: %v", err)
+		}
+	}
+
+	newFile := filepath.Join(dir, "new.rem")
+	writeTestFile(t, filepath.Join(dir, "ignored.txt"), "ignored\n")
+	writeTestFile(t, newFile, "REM MSG new\n")
+
+	select {
+	case path := <-changed:
+		if path != newFile {
+			t.Errorf("Change reported for %s, want %s", path, newFile)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for new file to be reported")
+	}
+}
+
+func TestWatchDirsRelativeGlob(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	dirs := watchDirs([]string{"*.rem"})
+	match, ok := dirs[dir]
+	if !ok || len(dirs) != 1 {
+		t.Fatalf("Expected %s watched, got %v", dir, dirs)
+	}
+	if !match(filepath.Join(dir, "new.rem")) {
+		t.Error("Expected a new file reported by absolute path to match a relative glob")
+	}
+}
+
+func TestFileWatcherFollowsReplacedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.rem")
+	writeTestFile(t, file, "REM MSG one\n")
+
+	changed := make(chan string, 10)
+	watcher, err := NewFileWatcher(func(path string) {
+		changed <- path
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.AddFile(file); err != nil {
+		t.Fatalf("Failed to watch file: %v", err)
+	}
+
+	// Save like editors that write a new file and rename it over the old
+	// one, twice: the second save is only seen if the first didn't lose the
+	// watch
+	for i, content := range []string{"REM MSG two\n", "REM MSG three\n"} {
+		tmp := filepath.Join(dir, ".main.rem.swp")
+		writeTestFile(t, tmp, content)
+		if err := os.Rename(tmp, file); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case path := <-changed:
+			if path != file {
+				t.Errorf("Change reported for %s, want %s", path, file)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for save %d to be reported", i+1)
+		}
+
+		// The rename and create of one save are reported once
+		select {
+		case path := <-changed:
+			t.Errorf("Save %d reported again for %s", i+1, path)
+		case <-time.After(3 * watchDebounce):
+		}
+	}
+}
+
+func TestFileWatcherFollowsSymlinks(t *testing.T) {
+	dotfiles, home := t.TempDir(), t.TempDir()
+	target := filepath.Join(dotfiles, "reminders.rem")
+	link := filepath.Join(home, ".reminders")
+	writeTestFile(t, target, "REM MSG one\n")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Can't create symlinks: %v", err)
+	}
+
+	changed := make(chan string, 10)
+	watcher, err := NewFileWatcher(func(path string) {
+		changed <- path
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+	if err := watcher.AddFile(link); err != nil {
+		t.Fatalf("Failed to watch file: %v", err)
+	}
+
+	// Saved through the symlink, the change is to the file it points to
+	writeTestFile(t, target, "REM MSG two\n")
+	select {
+	case path := <-changed:
+		if path != link {
+			t.Errorf("Change reported for %s, want %s", path, link)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the symlinked file's change")
+	}
+
+	if err := watcher.RemoveFile(link); err != nil {
+		t.Fatalf("Failed to stop watching: %v", err)
+	}
+	if len(watcher.watched) != 0 {
+		t.Errorf("Expected both directories unwatched, got %v", watcher.watched)
+	}
+}
+
+func TestResolveSourcePath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+
+	if got := resolveSourcePath("included.rem"); got != filepath.Join(wd, "included.rem") {
+		t.Errorf("resolveSourcePath(relative) = %q, want %q", got, filepath.Join(wd, "included.rem"))
+	}
+	if got := resolveSourcePath("/tmp/main.rem"); got != "/tmp/main.rem" {
+		t.Errorf("resolveSourcePath(absolute) = %q, want /tmp/main.rem", got)
+	}
+	if got := resolveSourcePath("-"); got != "-" {
+		t.Errorf("resolveSourcePath(stdin) = %q, want -", got)
+	}
+}
+
+func TestSourceLine(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.rem")
+	writeTestFile(t, file, "# header\nREM Mon AT 9:00 MSG Standup\nREM Tue \\\n  AT 10:00 MSG Review\n")
+
+	tests := []struct {
+		line    int
+		want    string
+		count   int
+		wantErr bool
+	}{
+		{line: 2, want: "REM Mon AT 9:00 MSG Standup", count: 1},
+		{line: 3, want: "REM Tue   AT 10:00 MSG Review", count: 2},
+		{line: 99, wantErr: true},
+		{line: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := SourceLine(Event{Filename: file, LineNumber: tt.line})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SourceLine(line %d): expected error", tt.line)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("SourceLine(line %d) = %q, %v; want %q", tt.line, got, err, tt.want)
+		}
+		if count, err := SourceLineCount(Event{Filename: file, LineNumber: tt.line}); err != nil || count != tt.count {
+			t.Errorf("SourceLineCount(line %d) = %d, %v; want %d", tt.line, count, err, tt.count)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package remind
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatLine tidies a REM line: keywords in upper case, one space between
+// the words of the trigger, month and weekday names abbreviated, and a full
+// date written like Jan 2 2006. The body is kept as is. Other lines, and
+// REM lines with expressions, only lose their trailing spaces.
+func FormatLine(line string) string {
+	if strings.HasSuffix(line, "\\") {
+		// Continued on the next line
+		return line
+	}
+	line = strings.TrimRight(line, " \t")
+	tokens := tokenizeTrigger(line)
+	if len(tokens) < 2 || !strings.EqualFold(tokens[0].text, "REM") {
+		return line
+	}
+	last := tokens[len(tokens)-1]
+	if !bodyKeywords[strings.ToUpper(last.text)] {
+		return line
+	}
+	trigger := tokens[1 : len(tokens)-1]
+	for _, token := range trigger {
+		if strings.ContainsAny(token.text, "[]") {
+			return line
+		}
+	}
+
+	parts := []string{"REM"}
+	run := dateRun(trigger)
+	if date, ok := fullDate(trigger[:run]); ok {
+		parts = append(parts, date.Format("Jan 2 2006"))
+	} else {
+		for _, token := range trigger[:run] {
+			parts = append(parts, formatDateWord(token.text))
+		}
+	}
+	for i := run; i < len(trigger); i++ {
+		word := trigger[i].text
+		keyword := strings.ToUpper(word)
+		switch {
+		case dateArgs[keyword]:
+			// The date that follows is formatted like the others
+			parts = append(parts, keyword)
+		case triggerArgs[keyword]:
+			parts = append(parts, keyword)
+			if i+1 < len(trigger) {
+				i++
+				parts = append(parts, trigger[i].text)
+			}
+		case triggerKeywords[keyword] || plainArgs[keyword]:
+			parts = append(parts, keyword)
+		default:
+			parts = append(parts, formatDateWord(word))
+		}
+	}
+	parts = append(parts, strings.ToUpper(last.text))
+	return line[:tokens[0].offset] + strings.Join(parts, " ") + line[last.offset+len(last.text):]
+}
+
+// dateArgs are the trigger keywords followed by a date
+var dateArgs = map[string]bool{"UNTIL": true, "FROM": true, "SCANFROM": true}
+
+// dateRun returns how many of the first words of a trigger are its date
+func dateRun(trigger []remToken) int {
+	run := 0
+	for run < len(trigger) && isDateToken(trigger[run].text) {
+		run++
+	}
+	return run
+}
+
+// fullDate returns the date the words of a trigger's date name, when they
+// name one day: a month, day and year, or an ISO date
+func fullDate(words []remToken) (time.Time, bool) {
+	if len(words) == 1 {
+		date, err := time.ParseInLocation("2006-01-02", words[0].text, time.Local)
+		return date, err == nil
+	}
+	if len(words) != 3 {
+		return time.Time{}, false
+	}
+	var month time.Month
+	day, year := 0, 0
+	for _, word := range words {
+		if n, err := strconv.Atoi(word.text); err == nil {
+			if n >= 1990 && year == 0 {
+				year = n
+			} else if n <= 31 && day == 0 {
+				day = n
+			} else {
+				return time.Time{}, false
+			}
+		} else if m, ok := parseMonth(strings.ToUpper(word.text)); ok && month == 0 {
+			month = m
+		} else {
+			return time.Time{}, false
+		}
+	}
+	if month == 0 || day == 0 || year == 0 {
+		return time.Time{}, false
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	if date.Day() != day {
+		// Like Feb 30, left for remind to report
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// formatDateWord abbreviates month and weekday names to their first three
+// letters, like Aug and Mon, leaving other words alone
+func formatDateWord(word string) string {
+	if _, err := strconv.Atoi(word); err == nil {
+		return word
+	}
+	if m, ok := parseMonth(strings.ToUpper(word)); ok {
+		return m.String()[:3]
+	}
+	if d, ok := parseWeekday(strings.ToUpper(word)); ok {
+		return d.String()[:3]
+	}
+	return word
+}
+
+// formatItem is a one-off REM line of a remind file, with the comments
+// right above it, sorted by when it is
+type formatItem struct {
+	lines []string
+	date  time.Time
+	at    int // Minutes after midnight, -1 when untimed
+}
+
+// oneOff returns when a formatted REM line is, if it has a full date
+func oneOff(line string) (formatItem, bool) {
+	tokens := tokenizeTrigger(line)
+	if len(tokens) < 3 || tokens[0].text != "REM" || !bodyKeywords[tokens[len(tokens)-1].text] {
+		return formatItem{}, false
+	}
+	trigger := tokens[1 : len(tokens)-1]
+	date, ok := fullDate(trigger[:dateRun(trigger)])
+	if !ok {
+		return formatItem{}, false
+	}
+	item := formatItem{lines: []string{line}, date: date, at: -1}
+	for i, token := range trigger {
+		if token.text == "AT" && i+1 < len(trigger) {
+			if at, err := parseClock(trigger[i+1].text); err == nil {
+				item.at = at
+			}
+		}
+	}
+	return item, true
+}
+
+// FormatReminders tidies the content of a remind file. Each REM line is
+// formatted by FormatLine, and runs of one-off reminders, not split by
+// blank lines or other lines, are sorted by date and time, taking the
+// comments right above each along, except those heading the run.
+// Everything else stays where it is.
+func FormatReminders(content string) string {
+	lines := strings.Split(content, "\n")
+	var out, comments []string
+	var run []formatItem
+	flush := func() {
+		sort.SliceStable(run, func(i, j int) bool {
+			if !run[i].date.Equal(run[j].date) {
+				return run[i].date.Before(run[j].date)
+			}
+			return run[i].at < run[j].at
+		})
+		for _, item := range run {
+			out = append(out, item.lines...)
+		}
+		run = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasSuffix(line, "\\") {
+			// Lines continued are kept together, as they are
+			flush()
+			out = append(out, comments...)
+			comments = nil
+			for ; i < len(lines) && strings.HasSuffix(lines[i], "\\"); i++ {
+				out = append(out, lines[i])
+			}
+			if i < len(lines) {
+				out = append(out, lines[i])
+			}
+			continue
+		}
+
+		line = FormatLine(line)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			comments = append(comments, line)
+			continue
+		}
+		if item, ok := oneOff(line); ok {
+			if len(run) == 0 {
+				// Comments above the first are about them all
+				out = append(out, comments...)
+			} else {
+				item.lines = append(comments, item.lines...)
+			}
+			comments = nil
+			run = append(run, item)
+			continue
+		}
+		flush()
+		out = append(out, comments...)
+		comments = nil
+		out = append(out, line)
+	}
+	flush()
+	out = append(out, comments...)
+	return strings.Join(out, "\n")
+}
+
+// WriteFormatted replaces the content of a remind file, as it was read, by
+// the content formatted, keeping its permissions
+func WriteFormatted(file, content, formatted string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if string(current) != content {
+		return fmt.Errorf("%w: %s", ErrWriteConflict, file)
+	}
+	return os.WriteFile(file, []byte(formatted), info.Mode().Perm())
+}
@@ -0,0 +1,114 @@
+package remind
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{"rem 25 august 2025 at 10:00 duration 1:00 msg Dentist  ", "REM Aug 25 2025 AT 10:00 DURATION 1:00 MSG Dentist"},
+		{"REM 2025-08-25   PRIORITY 9000 tag work MSG Report %b", "REM Aug 25 2025 PRIORITY 9000 TAG work MSG Report %b"},
+		{"  REM monday  +2 MSG  Weekly  sync", "  REM Mon +2 MSG  Weekly  sync"},
+		{`REM Aug 1 2025 *7 until september 30 2025 INFO "Location: sunday school" MSG Class`, `REM Aug 1 2025 *7 UNTIL Sep 30 2025 INFO "Location: sunday school" MSG Class`},
+		{"REM Feb 30 2025 MSG Not a day", "REM Feb 30 2025 MSG Not a day"},
+		{"rem [trigger(today())] msg Expression", "rem [trigger(today())] msg Expression"},
+		{"# rem aug 25 2025 msg Comment  ", "# rem aug 25 2025 msg Comment"},
+		{"omit dec 25", "omit dec 25"},
+		{"REM Aug 25 2025 MSG Continued \\", "REM Aug 25 2025 MSG Continued \\"},
+	}
+	for _, tt := range tests {
+		if got := FormatLine(tt.line); got != tt.expected {
+			t.Errorf("FormatLine(%q) = %q, want %q", tt.line, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatReminders(t *testing.T) {
+	content := `# Appointments
+rem Aug 27 2025 msg Haircut
+# Bring the forms
+REM 2025-08-25 AT 15:00 MSG Dentist
+REM Aug 25 2025 AT 9:00 MSG Standup
+
+SET x 1
+REM Sep 2 2025 MSG Later
+REM Sun MSG Weekly
+REM Sep 1 2025 MSG Earlier
+`
+	expected := `# Appointments
+REM Aug 25 2025 AT 9:00 MSG Standup
+# Bring the forms
+REM Aug 25 2025 AT 15:00 MSG Dentist
+REM Aug 27 2025 MSG Haircut
+
+SET x 1
+REM Sep 2 2025 MSG Later
+REM Sun MSG Weekly
+REM Sep 1 2025 MSG Earlier
+`
+	formatted := FormatReminders(content)
+	if formatted != expected {
+		t.Errorf("FormatReminders got:\n%s\nwant:\n%s", formatted, expected)
+	}
+	if again := FormatReminders(formatted); again != formatted {
+		t.Errorf("formatting again changed:\n%s", again)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk"
+	expected := `--- f.rem
++++ f.rem
+@@ -1,4 +1,4 @@
+ a
+-b
++B
+ c
+ d
+@@ -9,2 +9,3 @@
+ i
+ j
++k
+`
+	if got := UnifiedDiff("f.rem", before, after); got != expected {
+		t.Errorf("UnifiedDiff got:\n%s\nwant:\n%s", got, expected)
+	}
+	if got := UnifiedDiff("f.rem", before, before); got != "" {
+		t.Errorf("expected no diff of the same content, got:\n%s", got)
+	}
+}
+
+func TestWriteFormatted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	content := "rem aug 25 2025 msg Lunch\n"
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	formatted := FormatReminders(content)
+	if err := WriteFormatted(file, content, formatted); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "REM Aug 25 2025 MSG Lunch\n" {
+		t.Errorf("got %q", data)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the permissions kept, got %v", info.Mode().Perm())
+	}
+
+	// Changed since it was read, it's left alone
+	if err := WriteFormatted(file, content, formatted); !errors.Is(err, ErrWriteConflict) {
+		t.Errorf("expected a write conflict, got %v", err)
+	}
+	if data, _ := os.ReadFile(file); !strings.Contains(string(data), "REM Aug 25") {
+		t.Errorf("expected the file unchanged, got %q", data)
+	}
+}
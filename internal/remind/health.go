@@ -0,0 +1,112 @@
+package remind
+
+import (
+	"time"
+)
+
+// States of the watcher of a source, see SourceHealth
+const (
+	WatchActive      = "watching"
+	WatchUnsupported = "not watched"
+	WatchFailed      = "watch failed"
+	WatchStopped     = "watch stopped"
+)
+
+// SourceHealth describes how fetching the events of a source has been
+// going, to tell which source is failing when events are missing
+type SourceHealth struct {
+	Name        string
+	Enabled     bool
+	LastSuccess time.Time     // When events were last fetched, zero if never
+	LastError   error         // Error of the last fetch, nil if it succeeded
+	ErrorAt     time.Time     // When the last fetch failed
+	Latency     time.Duration // How long the last fetch took
+	Events      int           // Events the last successful fetch returned
+	Watch       string        // One of the Watch states, empty before watching starts
+	WatchError  error         // Why the watcher failed, with WatchFailed
+}
+
+// HealthSource is implemented by sources that keep track of how the
+// sources they combine are doing
+type HealthSource interface {
+	Health() []SourceHealth
+}
+
+// recordFetch notes how fetching the events of the named source went
+func (c *CompositeSource) recordFetch(name string, events int, err error, started time.Time) {
+	if name == "" {
+		return
+	}
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	health := c.healthOf(name)
+	health.Latency = time.Since(started)
+	if err != nil {
+		health.LastError, health.ErrorAt = err, time.Now()
+		return
+	}
+	health.LastError = nil
+	health.LastSuccess, health.Events = time.Now(), events
+}
+
+// recordWatch notes the state of the watcher of the named source
+func (c *CompositeSource) recordWatch(name, state string, err error) {
+	if name == "" {
+		return
+	}
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	health := c.healthOf(name)
+	health.Watch, health.WatchError = state, err
+}
+
+// healthOf returns the health kept for the named source, adding it the
+// first time. Callers must hold c.cacheMu.
+func (c *CompositeSource) healthOf(name string) *SourceHealth {
+	if c.health == nil {
+		c.health = make(map[string]*SourceHealth)
+	}
+	health, ok := c.health[name]
+	if !ok {
+		health = &SourceHealth{Name: name}
+		c.health[name] = health
+	}
+	return health
+}
+
+// Health implements HealthSource - how each source that identifies itself
+// is doing, in order
+func (c *CompositeSource) Health() []SourceHealth {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	var all []SourceHealth
+	for _, source := range c.sources {
+		info, ok := source.(SourceInfo)
+		if !ok {
+			continue
+		}
+		health := SourceHealth{Name: info.Name()}
+		if kept, ok := c.health[info.Name()]; ok {
+			health = *kept
+		}
+		health.Enabled = !c.disabled[info.Name()]
+		all = append(all, health)
+	}
+	return all
+}
+
+// fetch returns the events of source from start to end, noting how
+// fetching them went
+func (c *CompositeSource) fetch(source ReminderSource, start, end time.Time) ([]Event, error) {
+	started := time.Now()
+	events, err := source.GetEvents(start, end)
+	if info, ok := source.(SourceInfo); ok {
+		c.recordFetch(info.Name(), len(events), err, started)
+	}
+	return events, err
+}
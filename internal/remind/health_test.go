@@ -0,0 +1,52 @@
+package remind
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Mock source whose fetches fail with err
+type failingSource struct {
+	mockWritableSource
+	err error
+}
+
+func (f *failingSource) GetEvents(start, end time.Time) ([]Event, error) {
+	return nil, f.err
+}
+
+func TestCompositeHealth(t *testing.T) {
+	now := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	good := &mockWritableSource{name: "good", mockSource: mockSource{events: []Event{{ID: "a", Date: now}, {ID: "b", Date: now}}}}
+	bad := &failingSource{mockWritableSource: mockWritableSource{name: "bad"}, err: errors.New("connection refused")}
+	composite := NewCompositeSource(good, bad, &mockSource{})
+	composite.SetEnabled("bad", false)
+
+	health := composite.Health()
+	if len(health) != 2 || !health[0].LastSuccess.IsZero() || health[1].Enabled {
+		t.Fatalf("expected two sources not fetched yet, the second hidden, got %+v", health)
+	}
+
+	composite.SetEnabled("bad", true)
+	if _, err := composite.GetEvents(now, now.AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	composite.WatchFiles()
+	defer composite.StopWatching()
+
+	health = composite.Health()
+	if h := health[0]; h.Name != "good" || h.Events != 2 || h.LastSuccess.IsZero() || h.LastError != nil || h.Watch != WatchUnsupported {
+		t.Errorf("unexpected health of the good source %+v", h)
+	}
+	if h := health[1]; h.Name != "bad" || h.LastError == nil || h.ErrorAt.IsZero() || !h.LastSuccess.IsZero() {
+		t.Errorf("unexpected health of the failing source %+v", h)
+	}
+
+	// Once it works again, the error is gone
+	bad.err = nil
+	composite.GetEvents(now, now.AddDate(0, 0, 1))
+	if h := composite.Health()[1]; h.LastError != nil || h.LastSuccess.IsZero() {
+		t.Errorf("expected the source to have recovered, got %+v", h)
+	}
+}
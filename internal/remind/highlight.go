@@ -0,0 +1,111 @@
+package remind
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HighlightKind is what a part of a reminder line is, for syntax
+// highlighting
+type HighlightKind int
+
+const (
+	HighlightText    HighlightKind = iota // Spaces and other words
+	HighlightKeyword                      // REM, AT, DURATION, MSG and the other keywords
+	HighlightDate                         // Months, weekdays, days, years and ISO dates
+	HighlightTime                         // Times of day and durations, like 9:30
+	HighlightDelta                        // Deltas and repeats, like +2, -1 and *7
+	HighlightBody                         // What follows MSG and the other body keywords
+	HighlightComment                      // Lines starting with # or ;
+)
+
+// HighlightSpan is a part of a reminder line of one kind
+type HighlightSpan struct {
+	Kind HighlightKind
+	Text string
+}
+
+// triggerKeywords are the keywords of a trigger that aren't followed by a
+// body
+var triggerKeywords = map[string]bool{
+	"REM": true, "OMIT": true, "SKIP": true, "BEFORE": true, "AFTER": true,
+	"ONCE": true, "AT": true, "DURATION": true, "THROUGH": true, "ADDOMIT": true,
+	"NOQUEUE": true, "SATISFY": true, "FSET": true, "SET": true, "IF": true,
+	"ELSE": true, "ENDIF": true, "INCLUDE": true, "PUSH-OMIT-CONTEXT": true,
+	"POP-OMIT-CONTEXT": true,
+}
+
+// plainArgs are the trigger keywords whose argument is never a date
+var plainArgs = map[string]bool{
+	"PRIORITY": true, "TAG": true, "INFO": true, "SCHED": true, "WARN": true,
+	"OMITFUNC": true,
+}
+
+// backRegex matches the back of a trigger, like -1 or --2
+var backRegex = regexp.MustCompile(`^--?\d+$`)
+
+// HighlightLine splits a reminder line into the spans to highlight. Joined,
+// their text is the line.
+func HighlightLine(line string) []HighlightSpan {
+	if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+		return []HighlightSpan{{HighlightComment, line}}
+	}
+
+	var spans []HighlightSpan
+	add := func(kind HighlightKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(spans); n > 0 && spans[n-1].Kind == kind {
+			spans[n-1].Text += text
+			return
+		}
+		spans = append(spans, HighlightSpan{kind, text})
+	}
+
+	end, previous := 0, ""
+	for _, token := range tokenizeTrigger(line) {
+		add(HighlightText, line[end:token.offset])
+		end = token.offset + len(token.text)
+
+		word := strings.ToUpper(token.text)
+		switch {
+		case bodyKeywords[word]:
+			add(HighlightKeyword, token.text)
+			add(HighlightBody, line[end:])
+			return joinSpans(spans)
+		case triggerKeywords[word] || plainArgs[word] || triggerArgs[word]:
+			add(HighlightKeyword, token.text)
+		case plainArgs[previous]:
+			add(HighlightText, token.text)
+		case timeRegex.MatchString(token.text):
+			add(HighlightTime, token.text)
+		case deltaRegex.MatchString(token.text) || backRegex.MatchString(token.text):
+			add(HighlightDelta, token.text)
+		case isDateToken(token.text):
+			add(HighlightDate, token.text)
+		default:
+			add(HighlightText, token.text)
+		}
+		previous = word
+	}
+	add(HighlightText, line[end:])
+	return joinSpans(spans)
+}
+
+// joinSpans joins the spans of a kind separated only by spaces, like the
+// parts of a date
+func joinSpans(spans []HighlightSpan) []HighlightSpan {
+	var joined []HighlightSpan
+	for i := 0; i < len(spans); i++ {
+		n := len(joined)
+		if n > 0 && i+1 < len(spans) && spans[i].Kind == HighlightText && strings.TrimSpace(spans[i].Text) == "" &&
+			joined[n-1].Kind != HighlightText && joined[n-1].Kind != HighlightKeyword && spans[i+1].Kind == joined[n-1].Kind {
+			joined[n-1].Text += spans[i].Text + spans[i+1].Text
+			i++
+			continue
+		}
+		joined = append(joined, spans[i])
+	}
+	return joined
+}
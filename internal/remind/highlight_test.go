@@ -0,0 +1,65 @@
+package remind
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlightLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []HighlightSpan
+	}{
+		{
+			line: "REM Aug 25 2025 +2 AT 9:30 DURATION 1:00 PRIORITY 7000 MSG Planning at 10",
+			expected: []HighlightSpan{
+				{HighlightKeyword, "REM"}, {HighlightText, " "},
+				{HighlightDate, "Aug 25 2025"}, {HighlightText, " "},
+				{HighlightDelta, "+2"}, {HighlightText, " "},
+				{HighlightKeyword, "AT"}, {HighlightText, " "},
+				{HighlightTime, "9:30"}, {HighlightText, " "},
+				{HighlightKeyword, "DURATION"}, {HighlightText, " "},
+				{HighlightTime, "1:00"}, {HighlightText, " "},
+				{HighlightKeyword, "PRIORITY"}, {HighlightText, " 7000 "},
+				{HighlightKeyword, "MSG"}, {HighlightBody, " Planning at 10"},
+			},
+		},
+		{
+			line: "  rem Mon TAG 5 msg Standup",
+			expected: []HighlightSpan{
+				{HighlightText, "  "}, {HighlightKeyword, "rem"}, {HighlightText, " "},
+				{HighlightDate, "Mon"}, {HighlightText, " "},
+				{HighlightKeyword, "TAG"}, {HighlightText, " 5 "},
+				{HighlightKeyword, "msg"}, {HighlightBody, " Standup"},
+			},
+		},
+		{
+			line:     "# REM Aug 25 MSG old",
+			expected: []HighlightSpan{{HighlightComment, "# REM Aug 25 MSG old"}},
+		},
+		{
+			line:     "REM 2025-08-25 *7 UNTIL 2025-12-31",
+			expected: []HighlightSpan{{HighlightKeyword, "REM"}, {HighlightText, " "}, {HighlightDate, "2025-08-25"}, {HighlightText, " "}, {HighlightDelta, "*7"}, {HighlightText, " "}, {HighlightKeyword, "UNTIL"}, {HighlightText, " "}, {HighlightDate, "2025-12-31"}},
+		},
+	}
+
+	for _, tt := range tests {
+		spans := HighlightLine(tt.line)
+		var joined strings.Builder
+		for _, span := range spans {
+			joined.WriteString(span.Text)
+		}
+		if joined.String() != tt.line {
+			t.Errorf("HighlightLine(%q) spans join to %q", tt.line, joined.String())
+		}
+		if len(spans) != len(tt.expected) {
+			t.Errorf("HighlightLine(%q) = %+v, want %+v", tt.line, spans, tt.expected)
+			continue
+		}
+		for i := range spans {
+			if spans[i] != tt.expected[i] {
+				t.Errorf("HighlightLine(%q)[%d] = %+v, want %+v", tt.line, i, spans[i], tt.expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package remind
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HolidayTag is carried by the events of a HolidaySource
+const HolidayTag = "holiday"
+
+// DefaultHolidayShade is the color holidays are shaded with in the calendar
+var DefaultHolidayShade = Color{R: 95, G: 95, B: 135}
+
+// holidayRule gives the date of a holiday in a year, ok false in years it
+// isn't held
+type holidayRule struct {
+	name string
+	date func(year int) (date time.Time, ok bool)
+}
+
+// holidayCalendar is the public holidays of a country, and how holidays on
+// a weekend are observed
+type holidayCalendar struct {
+	name     string
+	rules    []holidayRule
+	observed func(day time.Time, taken map[time.Time]bool) time.Time
+}
+
+// holidayCalendars are the bundled countries, by ISO 3166 code. Only
+// nationwide holidays are included, not regional ones.
+var holidayCalendars = map[string]holidayCalendar{
+	"US": {
+		name: "United States",
+		rules: []holidayRule{
+			fixedHoliday("New Year's Day", time.January, 1),
+			nthWeekdayHoliday("Martin Luther King Jr. Day", time.January, time.Monday, 3),
+			nthWeekdayHoliday("Washington's Birthday", time.February, time.Monday, 3),
+			nthWeekdayHoliday("Memorial Day", time.May, time.Monday, -1),
+			since(2021, fixedHoliday("Juneteenth", time.June, 19)),
+			fixedHoliday("Independence Day", time.July, 4),
+			nthWeekdayHoliday("Labor Day", time.September, time.Monday, 1),
+			nthWeekdayHoliday("Columbus Day", time.October, time.Monday, 2),
+			fixedHoliday("Veterans Day", time.November, 11),
+			nthWeekdayHoliday("Thanksgiving Day", time.November, time.Thursday, 4),
+			fixedHoliday("Christmas Day", time.December, 25),
+		},
+		observed: nearestWeekday,
+	},
+	"CA": {
+		name: "Canada",
+		rules: []holidayRule{
+			fixedHoliday("New Year's Day", time.January, 1),
+			easterHoliday("Good Friday", -2),
+			{"Victoria Day", func(year int) (time.Time, bool) {
+				// The last Monday before May 25
+				day := time.Date(year, time.May, 24, 0, 0, 0, 0, time.Local)
+				return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), true
+			}},
+			fixedHoliday("Canada Day", time.July, 1),
+			nthWeekdayHoliday("Labour Day", time.September, time.Monday, 1),
+			since(2021, fixedHoliday("National Day for Truth and Reconciliation", time.September, 30)),
+			nthWeekdayHoliday("Thanksgiving", time.October, time.Monday, 2),
+			fixedHoliday("Remembrance Day", time.November, 11),
+			fixedHoliday("Christmas Day", time.December, 25),
+			fixedHoliday("Boxing Day", time.December, 26),
+		},
+	},
+	"GB": {
+		name: "United Kingdom",
+		rules: []holidayRule{
+			fixedHoliday("New Year's Day", time.January, 1),
+			easterHoliday("Good Friday", -2),
+			easterHoliday("Easter Monday", 1),
+			nthWeekdayHoliday("Early May bank holiday", time.May, time.Monday, 1),
+			nthWeekdayHoliday("Spring bank holiday", time.May, time.Monday, -1),
+			nthWeekdayHoliday("Summer bank holiday", time.August, time.Monday, -1),
+			fixedHoliday("Christmas Day", time.December, 25),
+			fixedHoliday("Boxing Day", time.December, 26),
+		},
+		observed: nextFreeWeekday,
+	},
+	"DE": {
+		name: "Germany",
+		rules: []holidayRule{
+			fixedHoliday("New Year's Day", time.January, 1),
+			easterHoliday("Good Friday", -2),
+			easterHoliday("Easter Monday", 1),
+			fixedHoliday("Labour Day", time.May, 1),
+			easterHoliday("Ascension Day", 39),
+			easterHoliday("Whit Monday", 50),
+			fixedHoliday("German Unity Day", time.October, 3),
+			fixedHoliday("Christmas Day", time.December, 25),
+			fixedHoliday("Second Day of Christmas", time.December, 26),
+		},
+	},
+	"FR": {
+		name: "France",
+		rules: []holidayRule{
+			fixedHoliday("New Year's Day", time.January, 1),
+			easterHoliday("Easter Monday", 1),
+			fixedHoliday("Labour Day", time.May, 1),
+			fixedHoliday("Victory in Europe Day", time.May, 8),
+			easterHoliday("Ascension Day", 39),
+			easterHoliday("Whit Monday", 50),
+			fixedHoliday("Bastille Day", time.July, 14),
+			fixedHoliday("Assumption of Mary", time.August, 15),
+			fixedHoliday("All Saints' Day", time.November, 1),
+			fixedHoliday("Armistice Day", time.November, 11),
+			fixedHoliday("Christmas Day", time.December, 25),
+		},
+	},
+}
+
+// HolidayCountries returns the codes of the countries with bundled holidays
+func HolidayCountries() []string {
+	codes := make([]string, 0, len(holidayCalendars))
+	for code := range holidayCalendars {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func fixedHoliday(name string, month time.Month, day int) holidayRule {
+	return holidayRule{name, func(year int) (time.Time, bool) {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local), true
+	}}
+}
+
+// nthWeekdayHoliday is on the nth weekday of the month, counting from the
+// end when n is negative
+func nthWeekdayHoliday(name string, month time.Month, weekday time.Weekday, n int) holidayRule {
+	return holidayRule{name, func(year int) (time.Time, bool) {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
+			offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -offset+7*(n+1)), true
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(n-1)), true
+	}}
+}
+
+// easterHoliday is the given number of days after Easter Sunday
+func easterHoliday(name string, days int) holidayRule {
+	return holidayRule{name, func(year int) (time.Time, bool) {
+		return easter(year).AddDate(0, 0, days), true
+	}}
+}
+
+// since limits a holiday to the years from first on
+func since(first int, rule holidayRule) holidayRule {
+	return holidayRule{rule.name, func(year int) (time.Time, bool) {
+		if year < first {
+			return time.Time{}, false
+		}
+		return rule.date(year)
+	}}
+}
+
+// easter returns Easter Sunday of the Gregorian calendar
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+// nearestWeekday observes Saturday holidays on Friday and Sunday holidays
+// on Monday, like US federal holidays
+func nearestWeekday(day time.Time, taken map[time.Time]bool) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// nextFreeWeekday observes weekend holidays on the next weekday that isn't
+// a holiday already, like UK substitute days
+func nextFreeWeekday(day time.Time, taken map[time.Time]bool) time.Time {
+	if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+		return day
+	}
+	for {
+		day = day.AddDate(0, 0, 1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday && !taken[day] {
+			return day
+		}
+	}
+}
+
+// HolidaySource is a read-only ReminderSource of the public holidays of
+// one or more countries, as untimed reminders that also shade their day in
+// the calendar
+type HolidaySource struct {
+	name      string
+	Countries []string
+	Shade     *Color // Calendar shade of holidays, nil to not shade them
+}
+
+// NewHolidaySource creates a source called name with the holidays of the
+// given countries
+func NewHolidaySource(name string, countries []string) (*HolidaySource, error) {
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("no country given, one of %s", strings.Join(HolidayCountries(), ", "))
+	}
+	for i, code := range countries {
+		countries[i] = strings.ToUpper(code)
+		if _, ok := holidayCalendars[countries[i]]; !ok {
+			return nil, fmt.Errorf("no holidays for %s, only for %s", code, strings.Join(HolidayCountries(), ", "))
+		}
+	}
+	shade := DefaultHolidayShade
+	return &HolidaySource{name: name, Countries: countries, Shade: &shade}, nil
+}
+
+// Name implements SourceInfo
+func (s *HolidaySource) Name() string {
+	return s.name
+}
+
+// Capabilities implements SourceInfo - holidays are read-only
+func (s *HolidaySource) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+// SetFiles implements ReminderSource - holidays have no files
+func (s *HolidaySource) SetFiles(files []string) {}
+
+// GetEvents implements ReminderSource - returns the holidays between start
+// and end, and a SHADE special for each day with one
+func (s *HolidaySource) GetEvents(start, end time.Time) ([]Event, error) {
+	var events []Event
+	shaded := map[time.Time]bool{}
+	for _, code := range s.Countries {
+		for _, h := range holidaysIn(code, start.Year()-1, end.Year()+1) {
+			if h.date.Before(start) || h.date.After(end) {
+				continue
+			}
+			description := h.name
+			if len(s.Countries) > 1 {
+				description += " (" + code + ")"
+			}
+			events = append(events, Event{
+				ID:          fmt.Sprintf("%s-%s-%s", s.name, code, h.date.Format("2006-01-02")),
+				Date:        h.date,
+				Description: description,
+				Type:        EventNote,
+				Source:      s.name,
+				Tags:        []string{HolidayTag, code},
+			})
+			if s.Shade != nil && !shaded[h.date] {
+				shaded[h.date] = true
+				shade := *s.Shade
+				events = append(events, Event{Date: h.date, Special: SpecialShade, Color: &shade, Source: s.name})
+			}
+		}
+	}
+	return events, nil
+}
+
+// WatchFiles implements ReminderSource - holidays don't change
+func (s *HolidaySource) WatchFiles() (<-chan FileChangeEvent, error) {
+	return nil, nil
+}
+
+// StopWatching implements ReminderSource
+func (s *HolidaySource) StopWatching() error {
+	return nil
+}
+
+// holiday is a holiday on a day
+type holiday struct {
+	name string
+	date time.Time
+}
+
+// holidaysIn returns the holidays of a country in the years from first to
+// last, with an observed holiday added where one falls on a weekend
+func holidaysIn(code string, first, last int) []holiday {
+	calendar := holidayCalendars[code]
+	var holidays []holiday
+	for year := first; year <= last; year++ {
+		taken := map[time.Time]bool{}
+		var actual []holiday
+		for _, rule := range calendar.rules {
+			if date, ok := rule.date(year); ok {
+				actual = append(actual, holiday{rule.name, date})
+				taken[date] = true
+			}
+		}
+		holidays = append(holidays, actual...)
+		if calendar.observed == nil {
+			continue
+		}
+		for _, h := range actual {
+			if observed := calendar.observed(h.date, taken); !observed.Equal(h.date) {
+				taken[observed] = true
+				holidays = append(holidays, holiday{h.name + " (observed)", observed})
+			}
+		}
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].date.Before(holidays[j].date)
+	})
+	return holidays
+}
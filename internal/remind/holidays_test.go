@@ -0,0 +1,97 @@
+package remind
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEaster(t *testing.T) {
+	for year, want := range map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	} {
+		if got := easter(year).Format("2006-01-02"); got != want {
+			t.Errorf("Easter %d: got %s, want %s", year, got, want)
+		}
+	}
+}
+
+func TestHolidaysIn(t *testing.T) {
+	tests := []struct {
+		country string
+		year    int
+		name    string
+		want    string
+	}{
+		{"US", 2025, "Martin Luther King Jr. Day", "2025-01-20"},
+		{"US", 2025, "Memorial Day", "2025-05-26"},
+		{"US", 2025, "Thanksgiving Day", "2025-11-27"},
+		{"US", 2026, "Independence Day (observed)", "2026-07-03"},
+		{"US", 2022, "New Year's Day (observed)", "2021-12-31"},
+		{"GB", 2021, "Christmas Day (observed)", "2021-12-27"},
+		{"GB", 2021, "Boxing Day (observed)", "2021-12-28"},
+		{"GB", 2022, "Christmas Day (observed)", "2022-12-27"},
+		{"GB", 2025, "Summer bank holiday", "2025-08-25"},
+		{"CA", 2025, "Victoria Day", "2025-05-19"},
+		{"DE", 2025, "Ascension Day", "2025-05-29"},
+		{"FR", 2025, "Whit Monday", "2025-06-09"},
+	}
+	for _, tt := range tests {
+		found := false
+		for _, h := range holidaysIn(tt.country, tt.year, tt.year) {
+			if h.name == tt.name {
+				found = true
+				if got := h.date.Format("2006-01-02"); got != tt.want {
+					t.Errorf("%s %s: got %s, want %s", tt.country, tt.name, got, tt.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s %d: no %s", tt.country, tt.year, tt.name)
+		}
+	}
+
+	for _, h := range holidaysIn("US", 2020, 2020) {
+		if h.name == "Juneteenth" {
+			t.Error("Juneteenth wasn't a federal holiday in 2020")
+		}
+	}
+}
+
+func TestHolidaySource(t *testing.T) {
+	source, err := NewSource("holidays", "holidays", "us gb #102030")
+	if err != nil {
+		t.Fatalf("NewSource: %v", err)
+	}
+
+	day := time.Date(2025, 12, 25, 0, 0, 0, 0, time.Local)
+	events, err := source.GetEvents(day, day)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected Christmas in both countries and one shade, got %v", events)
+	}
+	if events[0].Description != "Christmas Day (US)" || !events[0].HasTag(HolidayTag) || events[0].Time != nil {
+		t.Errorf("unexpected holiday %+v", events[0])
+	}
+	shade := events[1]
+	if shade.Special != SpecialShade || shade.Color == nil || *shade.Color != (Color{R: 0x10, G: 0x20, B: 0x30}) {
+		t.Errorf("expected the day shaded with the given color, got %+v", shade)
+	}
+
+	if info, ok := source.(SourceInfo); !ok || info.Capabilities() != (Capabilities{}) {
+		t.Error("expected a read-only source")
+	}
+
+	plain, _ := NewSource("holidays", "holidays", "DE --no-shade")
+	if events, _ := plain.GetEvents(day, day); len(events) != 1 || events[0].Description != "Christmas Day" {
+		t.Errorf("expected only the unshaded holiday, got %v", events)
+	}
+
+	if _, err := NewSource("holidays", "holidays", "XX"); err == nil {
+		t.Error("expected an error for a country without holidays")
+	}
+}
@@ -21,4 +21,87 @@ type ReminderSource interface {
 type FileChangeEvent struct {
 	Path      string
 	Timestamp time.Time
+	Err       error // The watcher failed and may have missed changes, Path is empty
+}
+
+// Capabilities describes the write operations a source supports
+type Capabilities struct {
+	Add    bool // New reminders can be added
+	Remove bool // Existing reminders can be removed
+	Edit   bool // Reminders can be opened in an editor at their file and line
+}
+
+// SourceInfo is implemented by sources that identify themselves and report
+// which operations they support. Events returned by the source carry its
+// name in Event.Source.
+type SourceInfo interface {
+	Name() string
+	Capabilities() Capabilities
+}
+
+// EventWriter is implemented by sources that can modify their reminders
+type EventWriter interface {
+	// AddEventStruct adds an event and returns the line number it was written to
+	AddEventStruct(event Event) (int, error)
+	// RemoveEvent removes an existing event
+	RemoveEvent(event Event) error
+}
+
+// EventUpdater is implemented by sources that can change existing reminders
+// in place
+type EventUpdater interface {
+	// CompleteEvent marks an existing event done at the given time
+	CompleteEvent(event Event, at time.Time) error
+	// RescheduleEvent moves a one-off event to another date, and time when
+	// at is not nil
+	RescheduleEvent(event Event, date time.Time, at *time.Time, duration *time.Duration) error
+	// SetEventStatus tags an event with one of Statuses
+	SetEventStatus(event Event, status string) error
+}
+
+// ToggleableSource is implemented by sources made of several named sources
+// that can be hidden at runtime
+type ToggleableSource interface {
+	SourceNames() []string
+	SetEnabled(name string, enabled bool)
+	Enabled(name string) bool
+}
+
+// WarningSource is implemented by sources that can report problems that
+// didn't stop them returning events, like falling back to a less precise
+// parser
+type WarningSource interface {
+	// Warnings returns the warnings since the last call
+	Warnings() []string
+}
+
+// CachedSource is implemented by sources that cache the results of slow
+// exports
+type CachedSource interface {
+	// Refresh discards cached results so the next GetEvents fetches them anew
+	Refresh()
+	// CacheStates reports how up to date each cache is
+	CacheStates() []CacheState
+}
+
+// CacheState describes the cached results of a source
+type CacheState struct {
+	Name       string    // Source name
+	Updated    time.Time // When the results were fetched, zero if never
+	Stale      bool      // The results are older than the source's TTL
+	Refreshing bool      // New results are being fetched
+	Loading    bool      // The source is loading in the background for the first time
+	Err        error     // Error from the last fetch, if it failed
+}
+
+// SourceCapabilities returns what can be done with an event from source.
+// Sources that don't report their capabilities are treated as read-only.
+func SourceCapabilities(source ReminderSource, event Event) Capabilities {
+	switch s := source.(type) {
+	case *CompositeSource:
+		return s.CapabilitiesFor(event)
+	case SourceInfo:
+		return s.Capabilities()
+	}
+	return Capabilities{}
 }
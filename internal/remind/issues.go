@@ -0,0 +1,384 @@
+package remind
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Issue trackers an IssueSource can read
+const (
+	IssueProviderGitHub = "github"
+	IssueProviderGitLab = "gitlab"
+	IssueProviderJira   = "jira"
+)
+
+// Default API locations of the issue trackers
+const (
+	DefaultGitHubURL = "https://api.github.com"
+	DefaultGitLabURL = "https://gitlab.com"
+)
+
+// maxIssuePages limits how many pages of issues are fetched
+const maxIssuePages = 10
+
+// issue is an assigned issue or pull request with a due date
+type issue struct {
+	Ref    string // e.g. owner/repo#12
+	Title  string
+	URL    string
+	Due    time.Time
+	Labels []string
+}
+
+// IssueSource is a ReminderSource listing the open issues and pull requests
+// assigned to the token's user that have a due date, or a milestone or
+// sprint with one, as untimed todos on that day
+type IssueSource struct {
+	Provider    string        // IssueProviderGitHub, IssueProviderGitLab or IssueProviderJira
+	BaseURL     string        // API location, e.g. https://gitlab.example.com
+	Token       string        // Personal access token
+	User        string        // Jira account email, sent with the token as basic auth; bearer auth when empty
+	JQL         string        // Jira query selecting the issues
+	SprintField string        // Jira custom field holding an issue's sprints
+	CacheTTL    time.Duration // How long fetched issues are shown before fetching them again
+	Client      *http.Client
+
+	name      string
+	eventChan chan FileChangeEvent
+
+	mu         sync.Mutex
+	issues     []issue
+	fetchedAt  time.Time
+	refreshing bool
+	lastErr    error
+}
+
+// NewIssueSource creates a source called name for the given provider. An
+// empty baseURL uses the provider's public instance.
+func NewIssueSource(name, provider, baseURL, token string) (*IssueSource, error) {
+	switch provider {
+	case IssueProviderGitHub:
+		if baseURL == "" {
+			baseURL = DefaultGitHubURL
+		}
+	case IssueProviderGitLab:
+		if baseURL == "" {
+			baseURL = DefaultGitLabURL
+		}
+	case IssueProviderJira:
+		if baseURL == "" {
+			return nil, fmt.Errorf("jira sources need the location of the Jira site")
+		}
+	default:
+		return nil, fmt.Errorf("unknown issue provider: %s", provider)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no %s token configured", provider)
+	}
+
+	return &IssueSource{
+		Provider:    provider,
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		Token:       token,
+		JQL:         DefaultJQL,
+		SprintField: DefaultSprintField,
+		CacheTTL:    5 * time.Minute,
+		Client:      &http.Client{Timeout: 30 * time.Second},
+		name:        name,
+	}, nil
+}
+
+// Name implements SourceInfo
+func (s *IssueSource) Name() string {
+	return s.name
+}
+
+// Capabilities implements SourceInfo - issues are read-only
+func (s *IssueSource) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+// SetFiles implements ReminderSource - issue sources have no files
+func (s *IssueSource) SetFiles(files []string) {}
+
+// GetEvents implements ReminderSource - returns issues due between start and end
+func (s *IssueSource) GetEvents(start, end time.Time) ([]Event, error) {
+	issues, err := s.assignedIssues()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, is := range issues {
+		if is.Due.Before(start) || is.Due.After(end) {
+			continue
+		}
+		events = append(events, Event{
+			ID:          fmt.Sprintf("%s-%s", s.name, is.Ref),
+			Date:        is.Due,
+			Description: fmt.Sprintf("%s %s", is.Ref, is.Title),
+			Body:        is.URL,
+			Type:        EventTodo,
+			Source:      s.name,
+			Tags:        append([]string{}, is.Labels...),
+		})
+	}
+	return events, nil
+}
+
+// assignedIssues returns the cached issues, fetching them when there are
+// none yet and refreshing them in the background once older than CacheTTL
+func (s *IssueSource) assignedIssues() ([]issue, error) {
+	s.mu.Lock()
+	if s.CacheTTL <= 0 || s.fetchedAt.IsZero() {
+		s.mu.Unlock()
+		return s.fetch()
+	}
+
+	issues := s.issues
+	if time.Since(s.fetchedAt) >= s.CacheTTL && !s.refreshing {
+		s.refreshing = true
+		go s.refreshInBackground()
+	}
+	s.mu.Unlock()
+	return issues, nil
+}
+
+// fetch queries the provider and caches the result
+func (s *IssueSource) fetch() ([]issue, error) {
+	issues, err := s.query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	if err != nil {
+		return nil, err
+	}
+	s.issues, s.fetchedAt = issues, time.Now()
+	return issues, nil
+}
+
+// refreshInBackground updates stale issues and, when watching, reports them
+// like a changed file
+func (s *IssueSource) refreshInBackground() {
+	issues, err := s.query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+	s.lastErr = err
+	if err != nil {
+		// Keep showing the previous issues
+		return
+	}
+	s.issues, s.fetchedAt = issues, time.Now()
+
+	if s.eventChan != nil {
+		select {
+		case s.eventChan <- FileChangeEvent{Path: s.name, Timestamp: time.Now()}:
+		default:
+			// Channel full, a reload is already pending
+		}
+	}
+}
+
+// query fetches the assigned issues from the provider
+func (s *IssueSource) query() ([]issue, error) {
+	switch s.Provider {
+	case IssueProviderGitLab:
+		return s.queryGitLab()
+	case IssueProviderJira:
+		return s.queryJira()
+	}
+	return s.queryGitHub()
+}
+
+// githubIssue is an issue or pull request from GitHub's /issues
+type githubIssue struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	HTMLURL    string `json:"html_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Milestone *struct {
+		DueOn *time.Time `json:"due_on"`
+	} `json:"milestone"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// nextLinkRegex finds the next page in a GitHub Link header
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// queryGitHub lists open issues and pull requests assigned to the user.
+// GitHub issues have no due date of their own, so their milestone's is used.
+func (s *IssueSource) queryGitHub() ([]issue, error) {
+	var issues []issue
+	url := s.BaseURL + "/issues?filter=assigned&state=open&per_page=100"
+	for page := 0; url != "" && page < maxIssuePages; page++ {
+		var batch []githubIssue
+		resp, err := s.get(url, &batch, map[string]string{
+			"Authorization": "Bearer " + s.Token,
+			"Accept":        "application/vnd.github+json",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, gi := range batch {
+			if gi.Milestone == nil || gi.Milestone.DueOn == nil {
+				continue
+			}
+			is := issue{
+				Ref:   fmt.Sprintf("%s#%d", gi.Repository.FullName, gi.Number),
+				Title: gi.Title,
+				URL:   gi.HTMLURL,
+				Due:   dueDay(*gi.Milestone.DueOn),
+			}
+			for _, label := range gi.Labels {
+				is.Labels = append(is.Labels, label.Name)
+			}
+			issues = append(issues, is)
+		}
+
+		url = ""
+		if matches := nextLinkRegex.FindStringSubmatch(resp.Header.Get("Link")); matches != nil {
+			url = matches[1]
+		}
+	}
+	return issues, nil
+}
+
+// gitlabIssue is an issue or merge request from GitLab's API
+type gitlabIssue struct {
+	Title      string `json:"title"`
+	WebURL     string `json:"web_url"`
+	DueDate    string `json:"due_date"`
+	References struct {
+		Full string `json:"full"`
+	} `json:"references"`
+	Milestone *struct {
+		DueDate string `json:"due_date"`
+	} `json:"milestone"`
+	Labels []string `json:"labels"`
+}
+
+// queryGitLab lists open issues and merge requests assigned to the user,
+// due on their own due date or else their milestone's
+func (s *IssueSource) queryGitLab() ([]issue, error) {
+	var issues []issue
+	for _, kind := range []string{"issues", "merge_requests"} {
+		next := "1"
+		for page := 0; next != "" && page < maxIssuePages; page++ {
+			var batch []gitlabIssue
+			url := fmt.Sprintf("%s/api/v4/%s?scope=assigned_to_me&state=opened&per_page=100&page=%s", s.BaseURL, kind, next)
+			resp, err := s.get(url, &batch, map[string]string{"PRIVATE-TOKEN": s.Token})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, gi := range batch {
+				due := gi.DueDate
+				if due == "" && gi.Milestone != nil {
+					due = gi.Milestone.DueDate
+				}
+				if due == "" {
+					continue
+				}
+				day, err := time.ParseInLocation("2006-01-02", due, time.Local)
+				if err != nil {
+					continue
+				}
+				issues = append(issues, issue{
+					Ref:    gi.References.Full,
+					Title:  gi.Title,
+					URL:    gi.WebURL,
+					Due:    day,
+					Labels: gi.Labels,
+				})
+			}
+			next = resp.Header.Get("X-Next-Page")
+		}
+	}
+	return issues, nil
+}
+
+// get requests url and decodes the JSON response into v
+func (s *IssueSource) get(url string, v any, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", s.Provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s request failed: %s", s.Provider, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("invalid %s response: %w", s.Provider, err)
+	}
+	return resp, nil
+}
+
+// dueDay returns the local day a due timestamp falls on. GitHub reports
+// milestone due dates as a time of day in UTC.
+func dueDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Refresh implements CachedSource - the next GetEvents fetches the issues again
+func (s *IssueSource) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchedAt = time.Time{}
+}
+
+// CacheStates implements CachedSource
+func (s *IssueSource) CacheStates() []CacheState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return []CacheState{{
+		Name:       s.name,
+		Updated:    s.fetchedAt,
+		Stale:      s.CacheTTL > 0 && !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) >= s.CacheTTL,
+		Refreshing: s.refreshing,
+		Err:        s.lastErr,
+	}}
+}
+
+// WatchFiles implements ReminderSource - reports issues refreshed in the
+// background
+func (s *IssueSource) WatchFiles() (<-chan FileChangeEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.eventChan == nil {
+		s.eventChan = make(chan FileChangeEvent, 10)
+	}
+	return s.eventChan, nil
+}
+
+// StopWatching implements ReminderSource
+func (s *IssueSource) StopWatching() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.eventChan != nil {
+		close(s.eventChan)
+		s.eventChan = nil
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package remind
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIssueSourceGitHub(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/issues?filter=assigned&page=2>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[
+				{"number": 12, "title": "Fix login", "html_url": "https://github.com/acme/app/issues/12",
+				 "repository": {"full_name": "acme/app"},
+				 "milestone": {"due_on": "2025-03-14T07:00:00Z"},
+				 "labels": [{"name": "bug"}]},
+				{"number": 13, "title": "No milestone", "repository": {"full_name": "acme/app"}}
+			]`)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"number": 3, "title": "Review docs", "html_url": "https://github.com/acme/docs/pull/3",
+			 "repository": {"full_name": "acme/docs"},
+			 "milestone": {"due_on": "2025-04-01T07:00:00Z"}}
+		]`)
+	}))
+	defer server.Close()
+
+	source, err := NewIssueSource("gh", IssueProviderGitHub, server.URL, "secret")
+	if err != nil {
+		t.Fatalf("NewIssueSource: %v", err)
+	}
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	events, err := source.GetEvents(start, start.AddDate(0, 1, -1))
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event in March, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.Description != "acme/app#12 Fix login" {
+		t.Errorf("unexpected description %q", event.Description)
+	}
+	if !event.Date.Equal(time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)) || event.Time != nil {
+		t.Errorf("expected an untimed event on March 14, got %v %v", event.Date, event.Time)
+	}
+	if event.Body != "https://github.com/acme/app/issues/12" {
+		t.Errorf("expected the link in the body, got %q", event.Body)
+	}
+	if event.Source != "gh" || event.Type != EventTodo || !event.HasTag("bug") {
+		t.Errorf("unexpected source %q, type %v or tags %v", event.Source, event.Type, event.Tags)
+	}
+
+	// The second page was fetched too
+	events, _ = source.GetEvents(start.AddDate(0, 1, 0), start.AddDate(0, 2, -1))
+	if len(events) != 1 || events[0].Description != "acme/docs#3 Review docs" {
+		t.Errorf("expected the pull request from the second page in April, got %v", events)
+	}
+
+	wrongToken, _ := NewIssueSource("gh", IssueProviderGitHub, server.URL, "wrong")
+	if _, err := wrongToken.GetEvents(start, start); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
+
+func TestIssueSourceGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" || r.URL.Query().Get("scope") != "assigned_to_me" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v4/issues":
+			fmt.Fprint(w, `[
+				{"title": "Outage report", "web_url": "https://gitlab.example.com/ops/infra/-/issues/7",
+				 "due_date": "2025-03-10", "references": {"full": "ops/infra#7"}, "labels": ["ops"]},
+				{"title": "Someday", "references": {"full": "ops/infra#8"}}
+			]`)
+		case "/api/v4/merge_requests":
+			fmt.Fprint(w, `[
+				{"title": "Upgrade", "web_url": "https://gitlab.example.com/ops/infra/-/merge_requests/2",
+				 "references": {"full": "ops/infra!2"}, "milestone": {"due_date": "2025-03-20"}}
+			]`)
+		}
+	}))
+	defer server.Close()
+
+	source, err := NewIssueSource("gl", IssueProviderGitLab, server.URL+"/", "secret")
+	if err != nil {
+		t.Fatalf("NewIssueSource: %v", err)
+	}
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	events, err := source.GetEvents(start, start.AddDate(0, 1, -1))
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected the issue and merge request with due dates, got %d events", len(events))
+	}
+	if events[0].Description != "ops/infra#7 Outage report" || events[0].Date.Day() != 10 {
+		t.Errorf("unexpected issue %q on %v", events[0].Description, events[0].Date)
+	}
+	if events[1].Description != "ops/infra!2 Upgrade" || events[1].Date.Day() != 20 {
+		t.Errorf("expected the merge request on its milestone's due date, got %q on %v", events[1].Description, events[1].Date)
+	}
+
+	if _, err := NewIssueSource("gl", IssueProviderGitLab, "", ""); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
+
+func TestIssueSourceJira(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		if !ok || user != "me@example.com" || token != "secret" || r.URL.Path != "/rest/api/2/search" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if jql := r.URL.Query().Get("jql"); jql != "project = OPS" {
+			t.Errorf("unexpected JQL %q", jql)
+		}
+		if r.URL.Query().Get("startAt") == "0" {
+			fmt.Fprint(w, `{"startAt": 0, "maxResults": 2, "total": 3, "issues": [
+				{"key": "OPS-1", "fields": {"summary": "Rotate keys", "duedate": "2025-03-05",
+				 "project": {"key": "OPS"}, "labels": ["security"]}},
+				{"key": "OPS-2", "fields": {"summary": "Sprint work", "duedate": null, "project": {"key": "OPS"},
+				 "customfield_10020": [
+					{"name": "Sprint 4", "state": "closed", "endDate": "2025-02-28T12:00:00Z"},
+					{"name": "Sprint 5", "state": "active", "endDate": "2025-03-14T12:00:00Z"}]}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt": 2, "maxResults": 2, "total": 3, "issues": [
+			{"key": "OPS-3", "fields": {"summary": "Backlog", "duedate": null, "project": {"key": "OPS"}}}
+		]}`)
+	}))
+	defer server.Close()
+
+	source, err := NewIssueSource("ops", IssueProviderJira, server.URL, "secret")
+	if err != nil {
+		t.Fatalf("NewIssueSource: %v", err)
+	}
+	source.User = "me@example.com"
+	source.JQL = "project = OPS"
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	events, err := source.GetEvents(start, start.AddDate(0, 1, -1))
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected the issues with a due date or open sprint, got %d events", len(events))
+	}
+
+	due := events[0]
+	if due.Description != "OPS-1 Rotate keys" || due.Date.Day() != 5 {
+		t.Errorf("unexpected issue %q on %v", due.Description, due.Date)
+	}
+	if due.Body != server.URL+"/browse/OPS-1" {
+		t.Errorf("expected a link to the issue, got %q", due.Body)
+	}
+	if !due.HasTag("OPS") || !due.HasTag("security") {
+		t.Errorf("expected the project and label tags, got %v", due.Tags)
+	}
+
+	if sprint := events[1]; sprint.Description != "OPS-2 Sprint work" || sprint.Date.Day() != 14 {
+		t.Errorf("expected OPS-2 at the end of the active sprint, got %q on %v", sprint.Description, sprint.Date)
+	}
+
+	if _, err := NewIssueSource("ops", IssueProviderJira, "", "secret"); err == nil {
+		t.Error("expected an error without the Jira site")
+	}
+}
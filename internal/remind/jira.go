@@ -0,0 +1,123 @@
+package remind
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultJQL selects the unresolved Jira issues assigned to the user
+const DefaultJQL = "assignee = currentUser() AND resolution = Unresolved"
+
+// DefaultSprintField is where Jira Cloud keeps an issue's sprints
+const DefaultSprintField = "customfield_10020"
+
+// jiraSearch is a page of Jira search results
+type jiraSearch struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Issues     []struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	} `json:"issues"`
+}
+
+// jiraSprint is one of the sprints an issue is in
+type jiraSprint struct {
+	Name    string     `json:"name"`
+	State   string     `json:"state"`
+	EndDate *time.Time `json:"endDate"`
+}
+
+// queryJira runs the JQL query. Issues are due on their due date or else
+// at the end of their open sprint, and are tagged with their project key.
+func (s *IssueSource) queryJira() ([]issue, error) {
+	headers := map[string]string{"Authorization": "Bearer " + s.Token}
+	if s.User != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.User+":"+s.Token))
+	}
+
+	var issues []issue
+	for page, startAt := 0, 0; page < maxIssuePages; page++ {
+		params := url.Values{}
+		params.Set("jql", s.JQL)
+		params.Set("fields", "summary,duedate,project,labels,"+s.SprintField)
+		params.Set("startAt", strconv.Itoa(startAt))
+		params.Set("maxResults", "100")
+
+		var result jiraSearch
+		if _, err := s.get(s.BaseURL+"/rest/api/2/search?"+params.Encode(), &result, headers); err != nil {
+			return nil, err
+		}
+
+		for _, ji := range result.Issues {
+			var summary, dueDate string
+			var project struct {
+				Key string `json:"key"`
+			}
+			var labels []string
+			json.Unmarshal(ji.Fields["summary"], &summary)
+			json.Unmarshal(ji.Fields["duedate"], &dueDate)
+			json.Unmarshal(ji.Fields["project"], &project)
+			json.Unmarshal(ji.Fields["labels"], &labels)
+
+			var due time.Time
+			if dueDate != "" {
+				day, err := time.ParseInLocation("2006-01-02", dueDate, time.Local)
+				if err != nil {
+					continue
+				}
+				due = day
+			} else if end, ok := sprintEnd(ji.Fields[s.SprintField]); ok {
+				due = end
+			} else {
+				continue
+			}
+
+			is := issue{
+				Ref:   ji.Key,
+				Title: summary,
+				URL:   s.BaseURL + "/browse/" + ji.Key,
+				Due:   due,
+			}
+			if project.Key != "" {
+				is.Labels = append(is.Labels, project.Key)
+			}
+			is.Labels = append(is.Labels, labels...)
+			issues = append(issues, is)
+		}
+
+		startAt = result.StartAt + len(result.Issues)
+		if len(result.Issues) == 0 || startAt >= result.Total {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// sprintEnd returns the day the earliest ending open sprint of an issue
+// ends. Sprints the server doesn't report as objects are ignored.
+func sprintEnd(field json.RawMessage) (time.Time, bool) {
+	var sprints []jiraSprint
+	if len(field) == 0 || json.Unmarshal(field, &sprints) != nil {
+		return time.Time{}, false
+	}
+
+	var end *time.Time
+	for _, sprint := range sprints {
+		if sprint.State == "closed" || sprint.EndDate == nil {
+			continue
+		}
+		if end == nil || sprint.EndDate.Before(*end) {
+			end = sprint.EndDate
+		}
+	}
+	if end == nil {
+		return time.Time{}, false
+	}
+	local := end.Local()
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local), true
+}
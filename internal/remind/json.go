@@ -3,21 +3,30 @@ package remind
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// RemindJSON represents the JSON output from remind -pppq
+// RemindJSON is a month of remind -ppp output. The schema covers every field
+// remind has emitted for it, so that strict decoding in the tests notices
+// new ones.
 type RemindJSON struct {
-	MonthName   string        `json:"monthname"`
-	Year        int           `json:"year"`
-	DaysInMonth int           `json:"daysinmonth"`
-	FirstWkDay  int           `json:"firstwkday"`
-	MondayFirst int           `json:"mondayfirst"`
-	DayNames    []string      `json:"daynames"`
-	Entries     []RemindEntry `json:"entries"`
+	MonthName       string        `json:"monthname"`
+	Year            int           `json:"year"`
+	DaysInMonth     int           `json:"daysinmonth"`
+	FirstWkDay      int           `json:"firstwkday"`
+	MondayFirst     int           `json:"mondayfirst"`
+	DayNames        []string      `json:"daynames"`
+	PrevMonthName   string        `json:"prevmonthname,omitempty"`
+	DaysInPrevMonth int           `json:"daysinprevmonth,omitempty"`
+	NextMonthName   string        `json:"nextmonthname,omitempty"`
+	DaysInNextMonth int           `json:"daysinnextmonth,omitempty"`
+	Entries         []RemindEntry `json:"entries"`
 }
 
-// RemindEntry represents a single reminder entry in the JSON
+// RemindEntry is a single reminder occurrence in remind -ppp output
 type RemindEntry struct {
 	Date          string   `json:"date"`
 	Filename      string   `json:"filename"`
@@ -25,16 +34,64 @@ type RemindEntry struct {
 	Duration      *int     `json:"duration,omitempty"`
 	Time          *int     `json:"time,omitempty"`
 	TDelta        *int     `json:"tdelta,omitempty"`
+	TRep          *int     `json:"trep,omitempty"`
 	EventDuration *int     `json:"eventduration,omitempty"`
 	EventStart    string   `json:"eventstart,omitempty"`
 	Priority      int      `json:"priority"`
 	RawBody       string   `json:"rawbody"`
 	Body          string   `json:"body"`
-	Tags          []string `json:"tags,omitempty"`
-	Skip          string   `json:"skip,omitempty"`
+	CalendarBody  string   `json:"calendar_body,omitempty"` // Body for calendars, from %"...%"
+	PlainBody     string   `json:"plain_body,omitempty"`    // Body without %"...%"
+	Tags          TagList  `json:"tags,omitempty"`
+	Skip          string   `json:"skip,omitempty"` // SKIP, BEFORE or AFTER for omitted days
 	Until         string   `json:"until,omitempty"`
 	From          string   `json:"from,omitempty"`
+	ScanFrom      string   `json:"scanfrom,omitempty"`
+	Once          *int     `json:"once,omitempty"`
+	Back          *int     `json:"back,omitempty"`  // Trigger back, e.g. -3
+	Delta         *int     `json:"delta,omitempty"` // Trigger delta, e.g. +3
+	LocalOmit     []string `json:"localomit,omitempty"`
+	OmitFunc      string   `json:"omitfunc,omitempty"`
+	Sched         string   `json:"sched,omitempty"`
+	Warn          string   `json:"warn,omitempty"`
+	NonConstExpr  *int     `json:"nonconst_expr,omitempty"`
+	IfDepth       *int     `json:"if_depth,omitempty"`
 	PassThru      string   `json:"passthru,omitempty"`
+	D             *int     `json:"d,omitempty"` // Trigger day, month and year when given
+	M             *int     `json:"m,omitempty"`
+	Y             *int     `json:"y,omitempty"`
+	Rep           *int     `json:"rep,omitempty"` // Repeat interval in days
+	WD            []string `json:"wd,omitempty"`  // Trigger weekdays
+	R             *int     `json:"r,omitempty"`   // Set for SPECIAL COLOR entries
+	G             *int     `json:"g,omitempty"`
+	B             *int     `json:"b,omitempty"`
+
+	Info map[string]string `json:"info,omitempty"` // INFO lines, e.g. "location"
+}
+
+// TagList is the tags of a reminder. Some versions of remind report them as
+// a comma separated string, others as an array.
+type TagList []string
+
+// UnmarshalJSON accepts both forms of tags
+func (t *TagList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = list
+		return nil
+	}
+
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		return fmt.Errorf("tags are neither a list nor a string: %s", data)
+	}
+	*t = nil
+	for _, tag := range strings.Split(joined, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
 }
 
 // ParseRemindJSON parses the JSON output from remind
@@ -47,13 +104,23 @@ func ParseRemindJSON(jsonData []byte) ([]RemindJSON, error) {
 	return months, nil
 }
 
+// supportedSpecials lists the SPECIAL types urd understands
+var supportedSpecials = map[string]bool{
+	"COLOR":      true,
+	"COLOUR":     true,
+	SpecialShade: true,
+	SpecialMoon:  true,
+	SpecialWeek:  true,
+}
+
 // ConvertJSONToEvents converts RemindJSON entries to Event structs
 func ConvertJSONToEvents(entries []RemindEntry, timezone *time.Location) []Event {
 	var events []Event
 
 	for _, entry := range entries {
-		// Skip SPECIAL SHADE entries - these are for calendar display, not events
-		if entry.PassThru == "SHADE" {
+		// Skip SPECIALs meant for other back ends (HTML, PostScript, ...)
+		// so they don't show up as garbled events
+		if entry.PassThru != "" && !supportedSpecials[entry.PassThru] {
 			continue
 		}
 
@@ -64,12 +131,42 @@ func ConvertJSONToEvents(entries []RemindEntry, timezone *time.Location) []Event
 		}
 
 		event := Event{
-			ID:          fmt.Sprintf("evt-%s-%d", entry.Date, entry.LineNo),
 			Date:        date,
 			Description: entry.Body,
 			Filename:    entry.Filename,
 			LineNumber:  entry.LineNo,
-			Tags:        entry.Tags,
+			Tags:        []string(entry.Tags),
+			IsRepeating: entry.isRepeating(),
+		}
+
+		switch entry.PassThru {
+		case "COLOR", "COLOUR":
+			// SPECIAL COLOR reminders carry an explicit color
+			event.Color, event.Description = parseSpecialColor(entry)
+		case SpecialShade:
+			// SHADE colors the whole day, MOON and WEEK annotate it; all are
+			// kept as specials for the calendar rather than scheduled events
+			event.Special = SpecialShade
+			event.Color = parseShade(entry.Body)
+			event.Description = ""
+		case SpecialMoon:
+			phase, msg, ok := parseMoon(entry.Body)
+			if !ok {
+				continue
+			}
+			event.Special = SpecialMoon
+			event.MoonPhase, event.Description = phase, msg
+		case SpecialWeek:
+			event.Special = SpecialWeek
+			event.Description = strings.TrimSpace(entry.Body)
+		}
+
+		// Location from INFO "Location: ..." or an @@place in the message
+		event.Description, event.Location = ParseLocation(event.Description)
+		for key, value := range entry.Info {
+			if strings.EqualFold(key, "location") && value != "" {
+				event.Location = value
+			}
 		}
 
 		// Check if it's a timed event
@@ -104,8 +201,95 @@ func ConvertJSONToEvents(entries []RemindEntry, timezone *time.Location) []Event
 			event.Priority = PriorityNone
 		}
 
+		event.ID = EventID(event)
 		events = append(events, event)
 	}
 
 	return events
 }
+
+// isRepeating reports whether the entry's trigger can fire on more than one
+// day, i.e. it doesn't give a full date or it repeats
+func (e RemindEntry) isRepeating() bool {
+	return e.D == nil || e.M == nil || e.Y == nil || e.Rep != nil || len(e.WD) > 0
+}
+
+// locationRegex matches an @@place location; underscores stand for spaces
+var locationRegex = regexp.MustCompile(`(^|\s)@@(\S+)`)
+
+// ParseLocation removes an @@place location from a description, returning
+// the description and the place
+func ParseLocation(desc string) (string, string) {
+	matches := locationRegex.FindStringSubmatch(desc)
+	if matches == nil {
+		return desc, ""
+	}
+	desc = strings.Join(strings.Fields(locationRegex.ReplaceAllString(desc, "$1")), " ")
+	return desc, strings.ReplaceAll(matches[2], "_", " ")
+}
+
+// parseSpecialColor extracts the color of a SPECIAL COLOR entry. Newer
+// versions of remind report it in the r/g/b fields; older ones leave it at
+// the start of the body as "r g b message".
+func parseSpecialColor(entry RemindEntry) (*Color, string) {
+	if entry.R != nil && entry.G != nil && entry.B != nil {
+		return &Color{R: *entry.R, G: *entry.G, B: *entry.B}, entry.Body
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(entry.Body), " ", 4)
+	if len(fields) < 3 {
+		return nil, entry.Body
+	}
+
+	var rgb [3]int
+	for i := 0; i < 3; i++ {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil || v < 0 || v > 255 {
+			return nil, entry.Body
+		}
+		rgb[i] = v
+	}
+
+	desc := ""
+	if len(fields) == 4 {
+		desc = strings.TrimSpace(fields[3])
+	}
+	return &Color{R: rgb[0], G: rgb[1], B: rgb[2]}, desc
+}
+
+// parseShade parses the body of a SPECIAL SHADE entry, which is either
+// "r g b" or a single gray level
+func parseShade(body string) *Color {
+	fields := strings.Fields(body)
+	var values []int
+	for _, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil || v < 0 || v > 255 {
+			return nil
+		}
+		values = append(values, v)
+	}
+
+	switch len(values) {
+	case 1:
+		return &Color{R: values[0], G: values[0], B: values[0]}
+	case 3:
+		return &Color{R: values[0], G: values[1], B: values[2]}
+	}
+	return nil
+}
+
+// parseMoon parses the body of a SPECIAL MOON entry: "phase [size [fontsize [msg]]]"
+func parseMoon(body string) (int, string, bool) {
+	fields := strings.SplitN(strings.TrimSpace(body), " ", 4)
+	phase, err := strconv.Atoi(fields[0])
+	if err != nil || phase < MoonNew || phase > MoonLastQuarter {
+		return 0, "", false
+	}
+
+	msg := ""
+	if len(fields) == 4 {
+		msg = strings.TrimSpace(fields[3])
+	}
+	return phase, msg, true
+}
@@ -0,0 +1,189 @@
+package remind
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	update  = flag.Bool("update", false, "rewrite the golden files")
+	capture = flag.Bool("capture", false, "add the -ppp output of the installed remind as a fixture")
+)
+
+// formatGolden renders events one per line with every field urd reads from
+// remind, so changes to the conversion show up in the golden files
+func formatGolden(events []Event) string {
+	var b strings.Builder
+	for _, e := range events {
+		when := e.Date.Format("2006-01-02")
+		if e.Time != nil {
+			when += " " + e.Time.Format("15:04")
+		}
+		if e.Duration != nil {
+			when += "+" + e.Duration.String()
+		}
+		color := ""
+		if e.Color != nil {
+			color = fmt.Sprintf("%d,%d,%d", e.Color.R, e.Color.G, e.Color.B)
+		}
+		fmt.Fprintf(&b, "%s %s:%d %s type=%d priority=%d repeating=%v tags=%v location=%q color=%s special=%s moon=%d %q\n",
+			when, e.Filename, e.LineNumber, e.ID, e.Type, e.Priority, e.IsRepeating, e.Tags,
+			e.Location, color, e.Special, e.MoonPhase, e.Description)
+	}
+	return b.String()
+}
+
+// TestRemindJSONGolden converts remind -ppp output and compares the events
+// with golden files. basic.json, tags.json and full.json are written by hand
+// after some of the occurrences of testdata/json/sample.rem, not printed by
+// remind, with the sets of entry fields urd has to read: the fields of older
+// versions, then tags and the trigger fields, then every field of newer
+// versions.
+//
+// The output of each installed remind version is added as
+// remind-<version>.json, along with its golden file, by running
+//
+//	go test ./internal/remind -run TestRemindJSONGolden -capture -update
+//
+// which saves what remind -ppp sample.rem 1 Aug 2025 prints in
+// testdata/json. Decoding is strict, so fields missing from RemindEntry fail
+// the test.
+func TestRemindJSONGolden(t *testing.T) {
+	if *capture {
+		captureFixture(t)
+	}
+
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "json", "*.json"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			data, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.DisallowUnknownFields()
+			var months []RemindJSON
+			if err := decoder.Decode(&months); err != nil {
+				t.Fatalf("schema doesn't cover the output: %v", err)
+			}
+
+			var events []Event
+			for _, month := range months {
+				events = append(events, ConvertJSONToEvents(month.Entries, time.Local)...)
+			}
+			got := formatGolden(events)
+
+			golden := strings.TrimSuffix(fixture, ".json") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("events differ from %s:\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// captureFixture saves what the installed remind prints for sample.rem as
+// testdata/json/remind-<version>.json
+func captureFixture(t *testing.T) {
+	t.Helper()
+	dir := filepath.Join("testdata", "json")
+
+	cmd := exec.Command("remind", "-")
+	cmd.Stdin = strings.NewReader("BANNER %\nMSG [version()]%\n")
+	version, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("can't tell the version of remind: %v", err)
+	}
+
+	cmd = exec.Command("remind", "-ppp", "sample.rem", "1", "Aug", "2025")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("remind -ppp: %v", err)
+	}
+	fixture := filepath.Join(dir, "remind-"+strings.TrimSpace(string(version))+".json")
+	if err := os.WriteFile(fixture, output, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTagList(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected []string
+	}{
+		{`["work","urgent"]`, []string{"work", "urgent"}},
+		{`"work, urgent"`, []string{"work", "urgent"}},
+		{`""`, nil},
+	}
+	for _, tt := range tests {
+		var tags TagList
+		if err := json.Unmarshal([]byte(tt.json), &tags); err != nil {
+			t.Errorf("%s: %v", tt.json, err)
+			continue
+		}
+		if fmt.Sprint([]string(tags)) != fmt.Sprint(tt.expected) {
+			t.Errorf("%s: got %v, want %v", tt.json, tags, tt.expected)
+		}
+	}
+
+	var tags TagList
+	if err := json.Unmarshal([]byte(`42`), &tags); err == nil {
+		t.Error("expected an error for a number")
+	}
+}
+
+func TestTextFallbackWarns(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "remind")
+	output := "2025/08/25 * * * * Pay rent\n"
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '"+output+"'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "test.rem")
+	if err := os.WriteFile(file, []byte("REM 25 MSG Pay rent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.RemindPath = script
+	client.SetFiles([]string{file})
+
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	events, err := client.GetEvents(day, day)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Description != "Pay rent" {
+		t.Fatalf("expected the event from the text output, got %v", events)
+	}
+
+	warnings := client.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "JSON") {
+		t.Errorf("expected a warning about the JSON output, got %v", warnings)
+	}
+	if again := client.Warnings(); len(again) != 0 {
+		t.Errorf("expected warnings to be reported once, got %v", again)
+	}
+}
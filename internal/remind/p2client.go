@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/cwarden/urd/internal/config"
 )
 
 // P2WorkPeriod represents a work period from p2 work --json output
@@ -23,11 +27,21 @@ type P2WorkPeriod struct {
 
 // P2Client is a ReminderSource that reads work periods from p2
 type P2Client struct {
-	P2Path    string // Path to p2 binary
-	TasksFile string // Path to tasks.rec file
-	ShowAll   bool   // Show all periods (not currently used with work command)
+	P2Path    string        // Path to p2 binary
+	TasksFile string        // Path to tasks.rec file
+	Command   []string      // Full p2 command line, used instead of P2Path and TasksFile when set
+	Profile   string        // Name of this p2 source when there are several, "" for the default
+	Color     *Color        // Color for the work periods of this source, nil for the default colors
+	ShowAll   bool          // Show all periods (not currently used with work command)
+	CacheTTL  time.Duration // How long an export is used before p2 is run again, 0 to always run it
 	watcher   *FileWatcher
 	eventChan chan FileChangeEvent
+
+	mu         sync.Mutex
+	periods    []P2WorkPeriod // work periods of the last export
+	fetchedAt  time.Time      // when they were exported, zero if never
+	refreshing bool           // a background export is running
+	lastErr    error          // error from the last export
 }
 
 // NewP2Client creates a new P2 client
@@ -39,6 +53,42 @@ func NewP2Client() *P2Client {
 	}
 }
 
+// NewP2Profile creates a p2 client named profile that runs the given
+// command line, e.g. "p2 work --json ~/client-a/tasks.rec"
+func NewP2Profile(profile, command string) (*P2Client, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty p2 command")
+	}
+	for i, arg := range args {
+		args[i] = config.ExpandHome(arg)
+	}
+
+	c := NewP2Client()
+	c.Profile = profile
+	c.Command = args
+	return c, nil
+}
+
+// P2SourceName identifies events read from p2
+const P2SourceName = "p2"
+
+// Name implements SourceInfo - profiles are named after their profile
+func (c *P2Client) Name() string {
+	if c.Profile != "" {
+		return c.Profile
+	}
+	return P2SourceName
+}
+
+// Capabilities implements SourceInfo - p2 work periods are read-only
+func (c *P2Client) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
 // SetFiles sets the tasks file to use (implements ReminderSource)
 func (c *P2Client) SetFiles(files []string) {
 	if len(files) > 0 {
@@ -48,13 +98,99 @@ func (c *P2Client) SetFiles(files []string) {
 
 // GetEvents implements ReminderSource - returns p2 work periods as events
 func (c *P2Client) GetEvents(start, end time.Time) ([]Event, error) {
-	// Build command - use 'work' instead of 'tasks list'
-	args := []string{"work", "--json"}
-	if c.TasksFile != "" && c.TasksFile != "tasks.rec" {
-		args = append(args, c.TasksFile)
+	periods, err := c.workPeriods()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, period := range periods {
+		// Filter by date range
+		// Check if the work period's start date falls within the requested date range
+		periodDate := time.Date(period.Start.Year(), period.Start.Month(), period.Start.Day(), 0, 0, 0, 0, period.Start.Location())
+
+		// Skip if the period's date is outside the requested range
+		if periodDate.Before(start) || periodDate.After(end) {
+			continue
+		}
+
+		events = append(events, c.workPeriodToEvent(period))
+	}
+
+	return events, nil
+}
+
+// workPeriods returns the exported work periods. A cached export is used
+// while it is younger than CacheTTL; once it is older it is still returned
+// but p2 is run again in the background, so a slow export doesn't hold up
+// the caller.
+func (c *P2Client) workPeriods() ([]P2WorkPeriod, error) {
+	c.mu.Lock()
+	if c.CacheTTL <= 0 || c.fetchedAt.IsZero() {
+		c.mu.Unlock()
+		return c.fetch()
+	}
+
+	periods := c.periods
+	if time.Since(c.fetchedAt) >= c.CacheTTL && !c.refreshing {
+		c.refreshing = true
+		go c.refreshInBackground()
+	}
+	c.mu.Unlock()
+	return periods, nil
+}
+
+// fetch runs p2 and caches the result
+func (c *P2Client) fetch() ([]P2WorkPeriod, error) {
+	periods, err := c.export()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastErr = err
+	if err != nil {
+		return nil, err
+	}
+	c.periods, c.fetchedAt = periods, time.Now()
+	return periods, nil
+}
+
+// refreshInBackground updates a stale cache and, when watching, reports the
+// new export like a change to the tasks file
+func (c *P2Client) refreshInBackground() {
+	periods, err := c.export()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	c.lastErr = err
+	if err != nil {
+		// Keep showing the previous export
+		return
 	}
+	c.periods, c.fetchedAt = periods, time.Now()
+
+	if c.eventChan != nil {
+		select {
+		case c.eventChan <- FileChangeEvent{Path: c.TasksFile, Timestamp: time.Now()}:
+		default:
+			// Channel full, a reload is already pending
+		}
+	}
+}
 
-	cmd := exec.Command(c.P2Path, args...)
+// export runs p2 work and parses its output
+func (c *P2Client) export() ([]P2WorkPeriod, error) {
+	var cmd *exec.Cmd
+	if len(c.Command) > 0 {
+		cmd = exec.Command(c.Command[0], c.Command[1:]...)
+	} else {
+		// Build command - use 'work' instead of 'tasks list'
+		args := []string{"work", "--json"}
+		if c.TasksFile != "" && c.TasksFile != "tasks.rec" {
+			args = append(args, c.TasksFile)
+		}
+		cmd = exec.Command(c.P2Path, args...)
+	}
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
@@ -64,7 +200,7 @@ func (c *P2Client) GetEvents(start, end time.Time) ([]Event, error) {
 		return nil, fmt.Errorf("failed to start p2: %w", err)
 	}
 
-	var events []Event
+	var periods []P2WorkPeriod
 	scanner := bufio.NewScanner(stdout)
 
 	for scanner.Scan() {
@@ -72,33 +208,43 @@ func (c *P2Client) GetEvents(start, end time.Time) ([]Event, error) {
 		if err := json.Unmarshal(scanner.Bytes(), &period); err != nil {
 			continue // Skip malformed lines
 		}
-
-		// Convert P2 work period to Event
-		event := c.workPeriodToEvent(period)
-
-		// Filter by date range
-		// Check if the work period's start date falls within the requested date range
-		periodDate := time.Date(period.Start.Year(), period.Start.Month(), period.Start.Day(), 0, 0, 0, 0, period.Start.Location())
-
-		// Skip if the period's date is outside the requested range
-		if periodDate.Before(start) || periodDate.After(end) {
-			continue
-		}
-
-		events = append(events, event)
+		periods = append(periods, period)
 	}
 
 	if err := cmd.Wait(); err != nil {
 		return nil, fmt.Errorf("p2 command failed: %w", err)
 	}
 
-	return events, scanner.Err()
+	return periods, scanner.Err()
+}
+
+// Refresh implements CachedSource - the next GetEvents runs p2 again
+func (c *P2Client) Refresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetchedAt = time.Time{}
+}
+
+// CacheStates implements CachedSource
+func (c *P2Client) CacheStates() []CacheState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return []CacheState{{
+		Name:       c.Name(),
+		Updated:    c.fetchedAt,
+		Stale:      c.CacheTTL > 0 && !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) >= c.CacheTTL,
+		Refreshing: c.refreshing,
+		Err:        c.lastErr,
+	}}
 }
 
 // workPeriodToEvent converts a P2WorkPeriod to a remind Event
 func (c *P2Client) workPeriodToEvent(period P2WorkPeriod) Event {
 	// Create a unique ID for this work period
 	periodID := fmt.Sprintf("p2-%s-%s", period.TaskID, period.Start.Format("20060102-150405"))
+	if c.Profile != "" {
+		periodID = fmt.Sprintf("p2-%s-%s-%s", c.Profile, period.TaskID, period.Start.Format("20060102-150405"))
+	}
 
 	// Build description with partial indicator if needed
 	description := period.TaskName
@@ -108,6 +254,8 @@ func (c *P2Client) workPeriodToEvent(period P2WorkPeriod) Event {
 
 	event := Event{
 		ID:          periodID,
+		Source:      c.Name(),
+		Color:       c.Color,
 		Description: description,
 		Body:        "", // Work periods don't have descriptions
 		Type:        EventTodo,
@@ -123,6 +271,11 @@ func (c *P2Client) workPeriodToEvent(period P2WorkPeriod) Event {
 	duration := period.End.Sub(period.Start)
 	event.Duration = &duration
 
+	// Tell profiles apart
+	if c.Profile != "" {
+		event.Tags = append(event.Tags, c.Profile)
+	}
+
 	// Add package as a tag
 	if period.PackageID != "" && period.PackageID != "default" {
 		event.Tags = append(event.Tags, period.PackageID)
@@ -147,14 +300,28 @@ func (c *P2Client) WatchFiles() (<-chan FileChangeEvent, error) {
 		return c.eventChan, nil // Already watching
 	}
 
+	c.mu.Lock()
 	c.eventChan = make(chan FileChangeEvent, 10)
+	c.mu.Unlock()
 
-	watcher, err := NewFileWatcher(func(path string) {
+	send := func(event FileChangeEvent) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.eventChan == nil {
+			return
+		}
 		select {
-		case c.eventChan <- FileChangeEvent{Path: path, Timestamp: time.Now()}:
+		case c.eventChan <- event:
 		default:
 			// Channel full, drop event
 		}
+	}
+	watcher, err := NewFileWatcher(func(path string) {
+		// The tasks changed, so the cached export is out of date
+		c.Refresh()
+		send(FileChangeEvent{Path: path, Timestamp: time.Now()})
+	}, func(err error) {
+		send(FileChangeEvent{Timestamp: time.Now(), Err: err})
 	})
 	if err != nil {
 		return nil, err
@@ -163,8 +330,8 @@ func (c *P2Client) WatchFiles() (<-chan FileChangeEvent, error) {
 	c.watcher = watcher
 
 	// Watch the tasks file
-	if c.TasksFile != "" {
-		if err := c.watcher.AddFile(c.TasksFile); err != nil {
+	for _, file := range c.tasksFiles() {
+		if err := c.watcher.AddFile(file); err != nil {
 			// Non-fatal, continue without watching
 		}
 	}
@@ -172,6 +339,25 @@ func (c *P2Client) WatchFiles() (<-chan FileChangeEvent, error) {
 	return c.eventChan, nil
 }
 
+// tasksFiles returns the rec files p2 reads: those named in the command
+// line, or TasksFile
+func (c *P2Client) tasksFiles() []string {
+	if len(c.Command) == 0 {
+		if c.TasksFile == "" {
+			return nil
+		}
+		return []string{c.TasksFile}
+	}
+
+	var files []string
+	for _, arg := range c.Command[1:] {
+		if strings.HasSuffix(arg, ".rec") {
+			files = append(files, arg)
+		}
+	}
+	return files
+}
+
 // StopWatching implements ReminderSource - stops file watching
 func (c *P2Client) StopWatching() error {
 	if c.watcher == nil {
@@ -181,10 +367,12 @@ func (c *P2Client) StopWatching() error {
 	err := c.watcher.Close()
 	c.watcher = nil
 
+	c.mu.Lock()
 	if c.eventChan != nil {
 		close(c.eventChan)
 		c.eventChan = nil
 	}
+	c.mu.Unlock()
 
 	return err
 }
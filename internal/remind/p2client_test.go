@@ -2,8 +2,12 @@ package remind
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -214,6 +218,168 @@ EOF
 	}
 }
 
+func TestP2ClientCache(t *testing.T) {
+	// The mock counts its runs in a file next to it
+	dir := t.TempDir()
+	mockScript := filepath.Join(dir, "mock_p2")
+	mockContent := `#!/bin/sh
+echo run >> "$(dirname "$0")/runs"
+echo '{"task_id":"1","task_name":"Task","start":"2025-08-21T10:00:00-05:00","end":"2025-08-21T12:00:00-05:00","hours":2.0,"is_complete":true,"total_hours":2.0}'
+`
+	if err := os.WriteFile(mockScript, []byte(mockContent), 0755); err != nil {
+		t.Fatalf("Failed to create mock script: %v", err)
+	}
+	runs := func() int {
+		data, _ := os.ReadFile(filepath.Join(dir, "runs"))
+		return strings.Count(string(data), "run")
+	}
+
+	client := NewP2Client()
+	client.P2Path = mockScript
+	client.CacheTTL = time.Hour
+
+	start := time.Date(2025, 8, 21, 0, 0, 0, 0, time.Local)
+	end := time.Date(2025, 8, 21, 23, 59, 59, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		if events, err := client.GetEvents(start, end); err != nil || len(events) != 1 {
+			t.Fatalf("GetEvents() = %d events, %v", len(events), err)
+		}
+	}
+	if runs() != 1 {
+		t.Errorf("Expected p2 to run once while cached, ran %d times", runs())
+	}
+
+	client.Refresh()
+	client.GetEvents(start, end)
+	if runs() != 2 {
+		t.Errorf("Expected Refresh to run p2 again, ran %d times", runs())
+	}
+
+	// Stale results are returned at once while p2 runs in the background
+	client.CacheTTL = time.Nanosecond
+	if states := client.CacheStates(); len(states) != 1 || !states[0].Stale || states[0].Updated.IsZero() {
+		t.Fatalf("Expected a stale cache, got %+v", states)
+	}
+	if events, err := client.GetEvents(start, end); err != nil || len(events) != 1 {
+		t.Fatalf("GetEvents() = %d events, %v", len(events), err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for client.CacheStates()[0].Refreshing && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if runs() != 3 {
+		t.Errorf("Expected a background run, ran %d times", runs())
+	}
+}
+
+func TestP2Profile(t *testing.T) {
+	// The mock reports the arguments it was run with as the task name
+	mockScript := filepath.Join(t.TempDir(), "mock_p2")
+	mockContent := `#!/bin/sh
+echo "{\"task_id\":\"7\",\"task_name\":\"$*\",\"start\":\"2025-08-21T10:00:00-05:00\",\"end\":\"2025-08-21T11:00:00-05:00\",\"is_complete\":true}"
+`
+	if err := os.WriteFile(mockScript, []byte(mockContent), 0755); err != nil {
+		t.Fatalf("Failed to create mock script: %v", err)
+	}
+
+	client, err := NewP2Profile("clientA", mockScript+` --user "Jane Doe" work --json a.rec`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Color = &Color{R: 0, G: 128, B: 255}
+
+	start := time.Date(2025, 8, 21, 0, 0, 0, 0, time.Local)
+	events, err := client.GetEvents(start, start.AddDate(0, 0, 1))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("GetEvents() = %d events, %v", len(events), err)
+	}
+
+	event := events[0]
+	if event.Description != "--user Jane Doe work --json a.rec" {
+		t.Errorf("p2 ran with unexpected arguments: %q", event.Description)
+	}
+	if event.Source != "clientA" || !event.HasTag("clientA") || event.Color != client.Color {
+		t.Errorf("Expected the event to carry its profile, got %+v", event)
+	}
+	if event.ID != "p2-clientA-7-20250821-100000" {
+		t.Errorf("Unexpected ID %q", event.ID)
+	}
+	if files := client.tasksFiles(); len(files) != 1 || files[0] != "a.rec" {
+		t.Errorf("Expected to watch a.rec, got %v", files)
+	}
+
+	if _, err := NewP2Profile("broken", `p2 "work`); err == nil {
+		t.Error("Expected error for an unclosed quote")
+	}
+}
+
+func TestCompositeSourceToggle(t *testing.T) {
+	now := time.Now()
+	a := &mockWritableSource{name: "a", mockSource: mockSource{events: []Event{{ID: "a-1", Date: now}}}}
+	b := &mockWritableSource{name: "b", mockSource: mockSource{events: []Event{{ID: "b-1", Date: now}}}}
+	composite := NewCompositeSource(a, b)
+
+	if names := composite.SourceNames(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("SourceNames() = %v", names)
+	}
+
+	composite.SetEnabled("a", false)
+	if composite.Enabled("a") || !composite.Enabled("b") {
+		t.Error("Expected only a to be disabled")
+	}
+	events, _ := composite.GetEvents(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if len(events) != 1 || events[0].ID != "b-1" {
+		t.Errorf("Expected only b's events, got %+v", events)
+	}
+
+	composite.SetEnabled("a", true)
+	events, _ = composite.GetEvents(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if len(events) != 2 {
+		t.Errorf("Expected both sources' events, got %d", len(events))
+	}
+}
+
+func TestCompositeSourceRefreshRate(t *testing.T) {
+	now := time.Now()
+	slow := &mockWritableSource{name: "slow", caps: Capabilities{Add: true}, mockSource: mockSource{events: []Event{{ID: "slow-1", Date: now}}}}
+	fast := &mockWritableSource{name: "fast", mockSource: mockSource{events: []Event{{ID: "fast-1", Date: now}}}}
+	composite := NewCompositeSource(slow, fast)
+	composite.SetRefreshRate("slow", time.Hour)
+
+	start, end := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	count := func() int {
+		events, _ := composite.GetEvents(start, end)
+		return len(events)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("Expected 2 events, got %d", n)
+	}
+
+	// Within its refresh rate, slow's events are reused
+	slow.events = append(slow.events, Event{ID: "slow-2", Date: now})
+	fast.events = append(fast.events, Event{ID: "fast-2", Date: now})
+	if n := count(); n != 3 {
+		t.Errorf("Expected slow's events to be reused, got %d events", n)
+	}
+
+	// Other days, writes and Refresh fetch them again
+	if events, _ := composite.GetEvents(start, end.AddDate(0, 0, 1)); len(events) != 4 {
+		t.Errorf("Expected slow's events to be fetched for other days, got %d events", len(events))
+	}
+	slow.events = append(slow.events, Event{ID: "slow-3", Date: now})
+	if _, err := composite.AddEventStruct(Event{Source: "slow"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 5 {
+		t.Errorf("Expected slow's events to be fetched after a write, got %d events", n)
+	}
+	slow.events = append(slow.events, Event{ID: "slow-4", Date: now})
+	composite.Refresh()
+	if n := count(); n != 6 {
+		t.Errorf("Expected slow's events to be fetched after Refresh, got %d events", n)
+	}
+}
+
 func TestP2ClientJSONParsing(t *testing.T) {
 	// Test parsing of JSON lines for work periods
 	jsonLines := []string{
@@ -276,6 +442,42 @@ EOF
 	}
 }
 
+func TestCompositeSourceLazy(t *testing.T) {
+	now := time.Now()
+	slow := &blockingSource{
+		mockWritableSource: mockWritableSource{name: "slow", mockSource: mockSource{events: []Event{{ID: "slow-1", Date: now}}}},
+		release:            make(chan struct{}),
+	}
+	fast := &mockWritableSource{name: "fast", mockSource: mockSource{events: []Event{{ID: "fast-1", Date: now}}}}
+	composite := NewCompositeSource(fast, slow)
+	composite.SetLazy("slow")
+	changes, _ := composite.WatchFiles()
+	defer composite.StopWatching()
+
+	// The slow source doesn't keep the first load waiting
+	start, end := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	events, _ := composite.GetEvents(start, end)
+	if len(events) != 1 || events[0].ID != "fast-1" {
+		t.Fatalf("Expected only fast's events while slow loads, got %+v", events)
+	}
+	if states := composite.CacheStates(); len(states) != 1 || states[0].Name != "slow" || !states[0].Loading {
+		t.Errorf("Expected slow to be reported loading, got %+v", states)
+	}
+
+	close(slow.release)
+	select {
+	case <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a change once slow loaded")
+	}
+	if events, _ := composite.GetEvents(start, end); len(events) != 2 {
+		t.Errorf("Expected both sources' events once slow loaded, got %d", len(events))
+	}
+	if states := composite.CacheStates(); len(states) != 0 {
+		t.Errorf("Expected nothing loading, got %+v", states)
+	}
+}
+
 func TestCompositeSource(t *testing.T) {
 	// Create mock sources
 	source1 := &mockSource{
@@ -360,6 +562,126 @@ func TestCompositeSourceDeduplication(t *testing.T) {
 	}
 }
 
+func TestCompositeSourceDedupPolicy(t *testing.T) {
+	now := time.Now()
+	caldav := &mockSource{events: []Event{{ID: "standup", Description: "Standup (caldav)", Date: now, Source: "caldav", Tags: []string{"work"}}}}
+	rem := &mockSource{events: []Event{{ID: "standup", Description: "Standup (remind)", Date: now, Source: RemindSourceName, Tags: []string{"daily", "work"}}}}
+	start, end := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+
+	tests := []struct {
+		policy      string
+		description string
+		tags        []string
+		alsoFrom    string
+	}{
+		{DedupFirst, "Standup (caldav)", []string{"work"}, RemindSourceName},
+		{DedupPreferRemind, "Standup (remind)", []string{"daily", "work"}, "caldav"},
+		{DedupMergeTags, "Standup (caldav)", []string{"work", "daily"}, RemindSourceName},
+	}
+	for _, tt := range tests {
+		composite := NewCompositeSource(caldav, rem)
+		composite.SetDedupPolicy(tt.policy)
+		for _, get := range []func() ([]Event, error){
+			func() ([]Event, error) { return composite.GetEvents(start, end) },
+			func() ([]Event, error) { return composite.Upcoming(start, 10) },
+		} {
+			events, _ := get()
+			if len(events) != 1 {
+				t.Fatalf("%s: expected one event, got %+v", tt.policy, events)
+			}
+			event := events[0]
+			if event.Description != tt.description || !slices.Equal(event.Tags, tt.tags) || !slices.Equal(event.AlsoFrom, []string{tt.alsoFrom}) {
+				t.Errorf("%s: got %q tags %v also from %v", tt.policy, event.Description, event.Tags, event.AlsoFrom)
+			}
+		}
+	}
+	if len(caldav.events[0].Tags) != 1 || caldav.events[0].AlsoFrom != nil {
+		t.Error("combining changed the events of a source")
+	}
+}
+
+func TestCompositeSourceIgnore(t *testing.T) {
+	now := time.Now()
+	source := &mockSource{events: []Event{
+		{ID: "1", Date: now, Description: "Backup done"},
+		{ID: "2", Date: now, Description: "Standup", Tags: []string{"@bot"}},
+		{ID: "3", Date: now, Description: "Deploy", Filename: "/home/me/shared/ci.rem"},
+		{ID: "4", Date: now, Description: "Lunch", Filename: "/home/me/reminders.rem"},
+	}}
+	composite := NewCompositeSource(source)
+	composite.SetIgnore([]IgnoreRule{
+		{Field: IgnoreDescription, Pattern: regexp.MustCompile(`^Backup`)},
+		{Field: IgnoreTag, Pattern: regexp.MustCompile(`^bot$`)},
+		{Field: IgnoreFile, Pattern: regexp.MustCompile(`/shared/`)},
+	})
+
+	events, _ := composite.GetEvents(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if len(events) != 1 || events[0].ID != "4" {
+		t.Errorf("Expected only lunch, got %+v", events)
+	}
+	upcoming, _ := composite.Upcoming(now.AddDate(0, 0, -1), 10)
+	if len(upcoming) != 1 || upcoming[0].ID != "4" {
+		t.Errorf("Expected only lunch upcoming, got %+v", upcoming)
+	}
+}
+
+func TestCompositeSourceWriteRouting(t *testing.T) {
+	writable := &mockWritableSource{name: "remind", caps: Capabilities{Add: true, Remove: true, Edit: true}}
+	readOnly := &mockWritableSource{name: "caldav"}
+	composite := NewCompositeSource(readOnly, writable, &mockSource{})
+
+	remindEvent := Event{ID: "evt-1", Source: "remind"}
+	caldavEvent := Event{ID: "cal-1", Source: "caldav"}
+
+	if caps := composite.CapabilitiesFor(remindEvent); !caps.Remove || !caps.Edit {
+		t.Errorf("Expected remind event to be writable, got %+v", caps)
+	}
+	if caps := composite.CapabilitiesFor(caldavEvent); caps != (Capabilities{}) {
+		t.Errorf("Expected caldav event to be read-only, got %+v", caps)
+	}
+	if caps := composite.CapabilitiesFor(Event{ID: "x"}); caps != (Capabilities{}) {
+		t.Errorf("Expected event without source to be read-only, got %+v", caps)
+	}
+
+	if err := composite.RemoveEvent(remindEvent); err != nil {
+		t.Errorf("Unexpected error removing remind event: %v", err)
+	}
+	if len(writable.removed) != 1 || writable.removed[0].ID != "evt-1" {
+		t.Errorf("Expected remove to be routed to remind source, got %v", writable.removed)
+	}
+
+	if err := composite.RemoveEvent(caldavEvent); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly removing caldav event, got %v", err)
+	}
+	if len(readOnly.removed) != 0 {
+		t.Error("Read-only source should not receive removes")
+	}
+
+	// Events without a source go to the first source that can add
+	if _, err := composite.AddEventStruct(Event{Description: "New"}); err != nil {
+		t.Errorf("Unexpected error adding event: %v", err)
+	}
+	if len(writable.added) != 1 {
+		t.Errorf("Expected add to be routed to remind source, got %d adds", len(writable.added))
+	}
+
+	if _, err := composite.AddEventStruct(Event{Description: "New", Source: "caldav"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly adding to caldav, got %v", err)
+	}
+}
+
+func TestSourceCapabilities(t *testing.T) {
+	if caps := SourceCapabilities(NewClient(), Event{}); !caps.Add || !caps.Remove || !caps.Edit {
+		t.Errorf("Expected remind client to be writable, got %+v", caps)
+	}
+	if caps := SourceCapabilities(NewP2Client(), Event{}); caps != (Capabilities{}) {
+		t.Errorf("Expected p2 client to be read-only, got %+v", caps)
+	}
+	if caps := SourceCapabilities(&mockSource{}, Event{}); caps != (Capabilities{}) {
+		t.Errorf("Expected unknown source to be read-only, got %+v", caps)
+	}
+}
+
 // Helper functions
 func timePtr(t time.Time) *time.Time {
 	return &t
@@ -402,3 +724,41 @@ func (m *mockSource) WatchFiles() (<-chan FileChangeEvent, error) {
 func (m *mockSource) StopWatching() error {
 	return nil
 }
+
+// Mock source with a name, capabilities and write support
+type mockWritableSource struct {
+	mockSource
+	name    string
+	caps    Capabilities
+	added   []Event
+	removed []Event
+}
+
+// Mock source whose events are only returned once released
+type blockingSource struct {
+	mockWritableSource
+	release chan struct{}
+}
+
+func (b *blockingSource) GetEvents(start, end time.Time) ([]Event, error) {
+	<-b.release
+	return b.mockWritableSource.GetEvents(start, end)
+}
+
+func (m *mockWritableSource) Name() string {
+	return m.name
+}
+
+func (m *mockWritableSource) Capabilities() Capabilities {
+	return m.caps
+}
+
+func (m *mockWritableSource) AddEventStruct(event Event) (int, error) {
+	m.added = append(m.added, event)
+	return len(m.added), nil
+}
+
+func (m *mockWritableSource) RemoveEvent(event Event) error {
+	m.removed = append(m.removed, event)
+	return nil
+}
@@ -0,0 +1,430 @@
+package remind
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// PluginProtocolVersion is the version of the source plugin protocol urd
+// speaks, documented under Source Plugins in the README
+const PluginProtocolVersion = 1
+
+// maxPluginFrame limits the size of a single plugin message
+const maxPluginFrame = 16 << 20
+
+// errBadPluginMessage is returned for a frame that isn't a valid message.
+// Unlike other read errors, the frames after it can still be read.
+var errBadPluginMessage = errors.New("invalid plugin message")
+
+// defaultPluginTimeout is how long urd waits for a plugin to answer
+const defaultPluginTimeout = 30 * time.Second
+
+// pluginExitGrace is how long a plugin has to exit once its stdin is closed
+// before it is killed
+const pluginExitGrace = time.Second
+
+// pluginMessage is a request, response or notification exchanged with a
+// plugin. Requests carry an ID and method, responses the same ID and a
+// result or error, and notifications a method but no ID.
+type pluginMessage struct {
+	ID     int             `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// pluginHandshake is exchanged when a plugin starts
+type pluginHandshake struct {
+	Protocol     int      `json:"protocol"`
+	Name         string   `json:"name"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// pluginRange is the date range of a get_events request
+type pluginRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// pluginChange is the params of a changed notification
+type pluginChange struct {
+	Path string `json:"path,omitempty"`
+}
+
+// PluginEvent is an event as sent by a plugin
+type PluginEvent struct {
+	ID          string   `json:"id"`
+	Date        string   `json:"date"`               // 2006-01-02
+	Time        string   `json:"time,omitempty"`     // 15:04, untimed when empty
+	Duration    int      `json:"duration,omitempty"` // minutes
+	Description string   `json:"description"`
+	Body        string   `json:"body,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	Priority    int      `json:"priority,omitempty"` // 0 none to 3 high
+	Todo        bool     `json:"todo,omitempty"`
+	Color       string   `json:"color,omitempty"` // #rrggbb
+	Tags        []string `json:"tags,omitempty"`
+	Repeating   bool     `json:"repeating,omitempty"`
+}
+
+// writePluginFrame writes msg as a big-endian uint32 length followed by
+// that many bytes of JSON
+func writePluginFrame(w io.Writer, msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readPluginFrame reads a message written by writePluginFrame
+func readPluginFrame(r io.Reader, msg any) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxPluginFrame {
+		return fmt.Errorf("plugin message of %d bytes is too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("%w: %v", errBadPluginMessage, err)
+	}
+	return nil
+}
+
+// PluginSource is a ReminderSource served by a separate program speaking
+// the plugin protocol on its stdin and stdout
+type PluginSource struct {
+	Timeout time.Duration // How long to wait for the plugin to answer
+
+	name         string
+	capabilities map[string]bool
+
+	cmd       *exec.Cmd
+	writeMu   sync.Mutex // serializes frames written to stdin
+	stdin     io.WriteCloser
+	mu        sync.Mutex
+	nextID    int
+	pending   map[int]chan pluginMessage
+	exitErr   error         // why the plugin stopped, nil while it runs
+	exited    chan struct{} // closed once the plugin exited and was waited for
+	eventChan chan FileChangeEvent
+}
+
+// NewPluginSource starts the plugin command and performs the handshake
+func NewPluginSource(name string, command []string) (*PluginSource, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("empty plugin command")
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", name, err)
+	}
+
+	p := &PluginSource{
+		Timeout:      defaultPluginTimeout,
+		name:         name,
+		capabilities: make(map[string]bool),
+		cmd:          cmd,
+		stdin:        stdin,
+		pending:      make(map[int]chan pluginMessage),
+		exited:       make(chan struct{}),
+	}
+	go p.readLoop(bufio.NewReader(stdout))
+
+	var hello pluginHandshake
+	if err := p.call("handshake", pluginHandshake{Protocol: PluginProtocolVersion, Name: name}, &hello); err != nil {
+		p.Close()
+		return nil, fmt.Errorf("plugin %s handshake failed: %w", name, err)
+	}
+	if hello.Protocol != PluginProtocolVersion {
+		p.Close()
+		return nil, fmt.Errorf("plugin %s speaks protocol %d, urd speaks %d", name, hello.Protocol, PluginProtocolVersion)
+	}
+	for _, capability := range hello.Capabilities {
+		p.capabilities[capability] = true
+	}
+	return p, nil
+}
+
+// readLoop hands responses to their callers and turns changed
+// notifications into file change events until the plugin exits
+func (p *PluginSource) readLoop(r io.Reader) {
+	for {
+		var msg pluginMessage
+		if err := readPluginFrame(r, &msg); errors.Is(err, errBadPluginMessage) {
+			continue // Skip it, whoever waits for it times out
+		} else if err != nil {
+			p.stop(err)
+			return
+		}
+
+		p.mu.Lock()
+		if msg.ID != 0 {
+			if reply, ok := p.pending[msg.ID]; ok {
+				delete(p.pending, msg.ID)
+				reply <- msg
+			}
+		} else if msg.Method == "changed" && p.eventChan != nil {
+			var change pluginChange
+			json.Unmarshal(msg.Params, &change)
+			if change.Path == "" {
+				change.Path = p.name
+			}
+			select {
+			case p.eventChan <- FileChangeEvent{Path: change.Path, Timestamp: time.Now()}:
+			default:
+				// Channel full, a reload is already pending
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+// stop records why the plugin went away, fails outstanding requests and
+// waits for the plugin to exit. A plugin whose output can't be read any more
+// is killed, as it would otherwise block writing to it.
+func (p *PluginSource) stop(err error) {
+	defer close(p.exited)
+
+	exited := err == io.EOF
+	if exited {
+		err = fmt.Errorf("plugin %s exited", p.name)
+	} else {
+		err = fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	p.fail(err)
+
+	if !exited {
+		p.cmd.Process.Kill()
+	}
+	if waitErr := p.cmd.Wait(); exited && waitErr != nil {
+		p.fail(fmt.Errorf("plugin %s exited: %w", p.name, waitErr))
+	}
+}
+
+// fail makes err the answer to outstanding and later requests
+func (p *PluginSource) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exitErr = err
+	for id, reply := range p.pending {
+		delete(p.pending, id)
+		reply <- pluginMessage{ID: id, Error: err.Error()}
+	}
+}
+
+// call sends a request and decodes its result into result
+func (p *PluginSource) call(method string, params, result any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.exitErr != nil {
+		p.mu.Unlock()
+		return p.exitErr
+	}
+	p.nextID++
+	id := p.nextID
+	reply := make(chan pluginMessage, 1)
+	p.pending[id] = reply
+	p.mu.Unlock()
+
+	p.writeMu.Lock()
+	err = writePluginFrame(p.stdin, pluginMessage{ID: id, Method: method, Params: data})
+	p.writeMu.Unlock()
+	if err != nil {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return fmt.Errorf("failed to write to plugin %s: %w", p.name, err)
+	}
+
+	select {
+	case msg := <-reply:
+		if msg.Error != "" {
+			return fmt.Errorf("%s: %s", method, msg.Error)
+		}
+		if result != nil && len(msg.Result) > 0 {
+			return json.Unmarshal(msg.Result, result)
+		}
+		return nil
+	case <-time.After(p.Timeout):
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return fmt.Errorf("plugin %s didn't answer %s within %s", p.name, method, p.Timeout)
+	}
+}
+
+// Name implements SourceInfo
+func (p *PluginSource) Name() string {
+	return p.name
+}
+
+// Capabilities implements SourceInfo - plugin events are read-only
+func (p *PluginSource) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+// HasCapability reports whether the plugin announced a capability in its
+// handshake, e.g. "watch"
+func (p *PluginSource) HasCapability(capability string) bool {
+	return p.capabilities[capability]
+}
+
+// SetFiles implements ReminderSource - plugins are configured by their
+// command line
+func (p *PluginSource) SetFiles(files []string) {}
+
+// GetEvents implements ReminderSource
+func (p *PluginSource) GetEvents(start, end time.Time) ([]Event, error) {
+	var result struct {
+		Events []PluginEvent `json:"events"`
+	}
+	params := pluginRange{Start: start.Format("2006-01-02"), End: end.Format("2006-01-02")}
+	if err := p.call("get_events", params, &result); err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(result.Events))
+	for _, pe := range result.Events {
+		event, err := p.toEvent(pe)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// toEvent converts an event sent by the plugin
+func (p *PluginSource) toEvent(pe PluginEvent) (Event, error) {
+	date, err := time.ParseInLocation("2006-01-02", pe.Date, time.Local)
+	if err != nil {
+		return Event{}, fmt.Errorf("event %q has invalid date %q", pe.ID, pe.Date)
+	}
+
+	event := Event{
+		ID:          fmt.Sprintf("%s-%s", p.name, pe.ID),
+		Date:        date,
+		Description: pe.Description,
+		Body:        pe.Body,
+		Location:    pe.Location,
+		Priority:    Priority(min(max(pe.Priority, 0), int(PriorityHigh))),
+		Type:        EventReminder,
+		Source:      p.name,
+		Tags:        pe.Tags,
+		IsRepeating: pe.Repeating,
+	}
+	if pe.Todo {
+		event.Type = EventTodo
+	}
+	if pe.URL != "" {
+		// Links are found in the body like any other URL
+		if event.Body != "" {
+			event.Body += "\n"
+		}
+		event.Body += pe.URL
+	}
+	if pe.Time != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04", pe.Date+" "+pe.Time, time.Local)
+		if err != nil {
+			return Event{}, fmt.Errorf("event %q has invalid time %q", pe.ID, pe.Time)
+		}
+		event.Time = &t
+		if pe.Duration > 0 {
+			duration := time.Duration(pe.Duration) * time.Minute
+			event.Duration = &duration
+		}
+	}
+	if pe.Color != "" {
+		var color Color
+		if _, err := fmt.Sscanf(pe.Color, "#%02x%02x%02x", &color.R, &color.G, &color.B); err == nil {
+			event.Color = &color
+		}
+	}
+	return event, nil
+}
+
+// WatchFiles implements ReminderSource - asks plugins with the watch
+// capability to report changes. Returns nil for plugins without it.
+func (p *PluginSource) WatchFiles() (<-chan FileChangeEvent, error) {
+	if !p.capabilities["watch"] {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	if p.eventChan != nil {
+		p.mu.Unlock()
+		return p.eventChan, nil // Already watching
+	}
+	p.eventChan = make(chan FileChangeEvent, 10)
+	eventChan := p.eventChan
+	p.mu.Unlock()
+
+	if err := p.call("watch", struct{}{}, nil); err != nil {
+		p.StopWatching()
+		return nil, err
+	}
+	return eventChan, nil
+}
+
+// StopWatching implements ReminderSource
+func (p *PluginSource) StopWatching() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.eventChan != nil {
+		close(p.eventChan)
+		p.eventChan = nil
+	}
+	return nil
+}
+
+// Close ends the plugin by closing its stdin, killing it if it doesn't exit
+// within pluginExitGrace, and waits for it to be gone
+func (p *PluginSource) Close() error {
+	p.stdin.Close()
+	select {
+	case <-p.exited:
+		return nil
+	case <-time.After(pluginExitGrace):
+	}
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-p.exited
+	return nil
+}
@@ -0,0 +1,202 @@
+package remind
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestHelperPlugin isn't a real test: it is run as the plugin by the tests
+// below, serving two events and a change notification once watched
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("URD_TEST_PLUGIN") != "1" {
+		t.Skip("only run as a plugin")
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	for {
+		var req pluginMessage
+		if err := readPluginFrame(in, &req); err != nil {
+			if os.Getenv("URD_TEST_PLUGIN_STAYS") == "1" {
+				time.Sleep(time.Hour) // Ignore the end of stdin, until killed
+			}
+			os.Exit(0)
+		}
+
+		var result any
+		switch req.Method {
+		case "handshake":
+			result = pluginHandshake{Protocol: PluginProtocolVersion, Name: "helper", Capabilities: []string{"get_events", "watch"}}
+		case "get_events":
+			var r pluginRange
+			json.Unmarshal(req.Params, &r)
+			switch r.Start {
+			case "2000-01-01":
+				writePluginFrame(os.Stdout, pluginMessage{ID: req.ID, Error: "no such project"})
+				continue
+			case "2000-01-02":
+				os.Stdout.Write([]byte{0, 0, 0, 8})
+				os.Stdout.Write([]byte("not json"))
+			case "2000-01-03":
+				os.Stdout.Write([]byte{0xff, 0xff, 0xff, 0xff})
+				time.Sleep(time.Hour) // Stuck, until killed
+			}
+			result = map[string]any{"events": []PluginEvent{
+				{ID: "PROJ-1", Date: r.Start, Description: "Fix login", URL: "https://example.com/PROJ-1", Priority: 3, Todo: true},
+				{ID: "PROJ-2", Date: r.Start, Time: "14:30", Duration: 45, Description: "Demo", Color: "#ff8000", Tags: []string{"demo"}},
+			}}
+		case "watch":
+			result = struct{}{}
+		}
+		data, _ := json.Marshal(result)
+		writePluginFrame(os.Stdout, pluginMessage{ID: req.ID, Result: data})
+		if req.Method == "watch" {
+			writePluginFrame(os.Stdout, pluginMessage{Method: "changed", Params: json.RawMessage(`{"path":"PROJ"}`)})
+		}
+	}
+}
+
+func startHelperPlugin(t *testing.T) *PluginSource {
+	t.Helper()
+	t.Setenv("URD_TEST_PLUGIN", "1")
+	p, err := NewPluginSource("jira", []string{os.Args[0], "-test.run=^TestHelperPlugin$"})
+	if err != nil {
+		t.Fatalf("NewPluginSource: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestPluginSource(t *testing.T) {
+	p := startHelperPlugin(t)
+
+	if !p.HasCapability("watch") {
+		t.Error("expected the watch capability from the handshake")
+	}
+
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	events, err := p.GetEvents(day, day.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	todo := events[0]
+	if todo.ID != "jira-PROJ-1" || todo.Source != "jira" {
+		t.Errorf("unexpected ID %q or source %q", todo.ID, todo.Source)
+	}
+	if !todo.Date.Equal(day) || todo.Time != nil {
+		t.Errorf("expected an untimed event on %v, got %v %v", day, todo.Date, todo.Time)
+	}
+	if todo.Type != EventTodo || todo.Priority != PriorityHigh {
+		t.Errorf("expected a high priority todo, got type %v priority %v", todo.Type, todo.Priority)
+	}
+	if todo.Body != "https://example.com/PROJ-1" {
+		t.Errorf("expected the URL in the body, got %q", todo.Body)
+	}
+
+	demo := events[1]
+	if demo.Time == nil || demo.Time.Format("15:04") != "14:30" {
+		t.Errorf("expected the demo at 14:30, got %v", demo.Time)
+	}
+	if demo.Duration == nil || *demo.Duration != 45*time.Minute {
+		t.Errorf("expected a 45m duration, got %v", demo.Duration)
+	}
+	if demo.Color == nil || *demo.Color != (Color{255, 128, 0}) {
+		t.Errorf("expected color #ff8000, got %v", demo.Color)
+	}
+
+	if _, err := p.GetEvents(time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local), day); err == nil {
+		t.Error("expected the plugin's error to be returned")
+	}
+
+	changes, err := p.WatchFiles()
+	if err != nil || changes == nil {
+		t.Fatalf("WatchFiles: %v", err)
+	}
+	select {
+	case change := <-changes:
+		if change.Path != "PROJ" {
+			t.Errorf("expected a change to PROJ, got %q", change.Path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+	}
+	p.StopWatching()
+
+	// Once the plugin is gone requests fail instead of hanging
+	p.Close()
+	p.Timeout = 5 * time.Second
+	if _, err := p.GetEvents(day, day); err == nil {
+		t.Error("expected an error after the plugin exited")
+	}
+}
+
+func TestPluginGarbledOutput(t *testing.T) {
+	p := startHelperPlugin(t)
+
+	// An invalid message is skipped
+	if _, err := p.GetEvents(time.Date(2000, 1, 2, 0, 0, 0, 0, time.Local), time.Now()); err != nil {
+		t.Fatalf("expected the message after an invalid one read, got %v", err)
+	}
+
+	// A bad frame header leaves the rest unreadable, so requests fail at
+	// once and the plugin is killed instead of waited for
+	start := time.Now()
+	if _, err := p.GetEvents(time.Date(2000, 1, 3, 0, 0, 0, 0, time.Local), time.Now()); err == nil {
+		t.Fatal("expected an error for a bad frame")
+	}
+	if elapsed := time.Since(start); elapsed > p.Timeout/2 {
+		t.Errorf("the request took %s to fail", elapsed)
+	}
+	select {
+	case <-p.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the plugin killed")
+	}
+	if _, err := p.GetEvents(time.Now(), time.Now()); err == nil {
+		t.Error("expected later requests to fail")
+	}
+}
+
+func TestPluginCloseKillsStuckPlugin(t *testing.T) {
+	t.Setenv("URD_TEST_PLUGIN_STAYS", "1")
+	p := startHelperPlugin(t)
+
+	p.Close()
+	if p.cmd.ProcessState == nil {
+		t.Error("expected the plugin killed and waited for")
+	}
+}
+
+func TestCompositeClosesPlugins(t *testing.T) {
+	p := startHelperPlugin(t)
+
+	if err := NewCompositeSource(NewClient(), p).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if p.cmd.ProcessState == nil {
+		t.Error("expected the plugin ended with the composite")
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	t.Setenv("URD_TEST_PLUGIN", "1")
+
+	source, err := NewSource("helper", "plugin", os.Args[0]+" -test.run=^TestHelperPlugin$")
+	if err != nil {
+		t.Fatalf("NewSource: %v", err)
+	}
+	if info, ok := source.(SourceInfo); !ok || info.Name() != "helper" {
+		t.Errorf("expected a source named helper")
+	}
+	source.(*PluginSource).Close()
+
+	if _, err := NewSource("nope", "no-such-kind", ""); err == nil {
+		t.Error("expected an error for an unknown kind without a plugin binary")
+	}
+}
@@ -0,0 +1,113 @@
+package remind
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// SourceResponse is what GetEvents returned for a range of days, as
+// recorded in a session to replay it
+type SourceResponse struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Events []Event   `json:"events"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Recorder receives each response of a source's GetEvents
+type Recorder func(SourceResponse)
+
+// RecordingSource is implemented by sources that report what GetEvents
+// returns, for recording a session
+type RecordingSource interface {
+	SetRecorder(record Recorder)
+}
+
+// newResponse describes what GetEvents returned
+func newResponse(start, end time.Time, events []Event, err error) SourceResponse {
+	response := SourceResponse{Start: start, End: end, Events: events}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	return response
+}
+
+// ReplaySource returns the events recorded in a session instead of reading
+// any file, so a session replays the same wherever it's replayed
+type ReplaySource struct {
+	mu        sync.Mutex
+	responses []SourceResponse
+	replayed  map[string]int // Responses returned so far by days, see daysOf
+}
+
+// NewReplaySource returns a source answering with responses, in the order
+// they were recorded
+func NewReplaySource(responses []SourceResponse) *ReplaySource {
+	return &ReplaySource{
+		responses: responses,
+		replayed:  make(map[string]int),
+	}
+}
+
+// GetEvents implements ReminderSource - the next response recorded for the
+// same days, the last one again once they were all returned. Days never
+// recorded get the recorded events that fall in them.
+func (r *ReplaySource) GetEvents(start, end time.Time) ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := daysOf(start, end)
+	var last *SourceResponse
+	seen := 0
+	for i, response := range r.responses {
+		if daysOf(response.Start, response.End) != key {
+			continue
+		}
+		last = &r.responses[i]
+		if seen == r.replayed[key] {
+			break
+		}
+		seen++
+	}
+	if last != nil {
+		r.replayed[key]++
+		if last.Error != "" {
+			return nil, errors.New(last.Error)
+		}
+		return last.Events, nil
+	}
+
+	var events []Event
+	found := make(map[string]bool)
+	for _, response := range r.responses {
+		for _, event := range response.Events {
+			key := event.ID + event.Date.String()
+			if event.Date.Before(start) || event.Date.After(end) || found[key] {
+				continue
+			}
+			found[key] = true
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// daysOf identifies the days from start to end, as the time of day of
+// the ranges asked for depends on when they were
+func daysOf(start, end time.Time) string {
+	return start.Format(time.DateOnly) + "/" + end.Format(time.DateOnly)
+}
+
+// SetFiles implements ReminderSource - there are no files to read
+func (r *ReplaySource) SetFiles(files []string) {}
+
+// WatchFiles implements ReminderSource - recorded events never change
+func (r *ReplaySource) WatchFiles() (<-chan FileChangeEvent, error) {
+	return nil, nil
+}
+
+// StopWatching implements ReminderSource
+func (r *ReplaySource) StopWatching() error {
+	return nil
+}
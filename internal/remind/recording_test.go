@@ -0,0 +1,50 @@
+package remind
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplaySource(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	before := Event{ID: "1", Date: day, Description: "Before"}
+	after := Event{ID: "1", Date: day, Description: "After"}
+	source := NewReplaySource([]SourceResponse{
+		{Start: day.Add(8 * time.Hour), End: day.AddDate(0, 0, 1), Events: []Event{before}},
+		{Start: day.Add(9 * time.Hour), End: day.AddDate(0, 0, 1), Events: []Event{after}},
+		{Start: day.AddDate(0, 0, 7), End: day.AddDate(0, 0, 8), Error: "remind failed"},
+	})
+
+	// The same days give the responses in order, whatever the time of day
+	for _, expected := range []string{"Before", "After", "After"} {
+		events, err := source.GetEvents(day.Add(10*time.Hour), day.AddDate(0, 0, 1))
+		if err != nil || len(events) != 1 || events[0].Description != expected {
+			t.Errorf("expected %s, got %+v, %v", expected, events, err)
+		}
+	}
+	if _, err := source.GetEvents(day.AddDate(0, 0, 7), day.AddDate(0, 0, 8)); err == nil {
+		t.Error("expected the recorded error")
+	}
+
+	// Other days get the events recorded in them
+	events, err := source.GetEvents(day.AddDate(0, 0, -1), day)
+	if err != nil || len(events) != 1 {
+		t.Errorf("expected the event of the day, got %+v, %v", events, err)
+	}
+}
+
+func TestClientRecorder(t *testing.T) {
+	client := NewClient()
+	var responses []SourceResponse
+	client.SetRecorder(func(response SourceResponse) {
+		responses = append(responses, response)
+	})
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	if _, err := client.GetEvents(day, day); !errors.Is(err, ErrNoFiles) {
+		t.Fatalf("expected ErrNoFiles, got %v", err)
+	}
+	if len(responses) != 1 || responses[0].Error != ErrNoFiles.Error() {
+		t.Errorf("expected the error recorded, got %+v", responses)
+	}
+}
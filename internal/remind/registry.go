@@ -0,0 +1,106 @@
+package remind
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+// PluginPrefix is prepended to the kind of a source that isn't built in to
+// find the plugin binary serving it, e.g. urd-source-jira
+const PluginPrefix = "urd-source-"
+
+// SourceFactory creates a source called name from the arguments given in
+// the configuration
+type SourceFactory func(name string, args []string) (ReminderSource, error)
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]SourceFactory{
+		"plugin": func(name string, args []string) (ReminderSource, error) {
+			return NewPluginSource(name, args)
+		},
+		"holidays": newHolidaySourceFromArgs,
+	}
+)
+
+// newHolidaySourceFromArgs creates a holiday source from country codes, a
+// #rrggbb shade color and --no-shade
+func newHolidaySourceFromArgs(name string, args []string) (ReminderSource, error) {
+	var countries []string
+	var shade *Color
+	noShade := false
+	for _, arg := range args {
+		switch {
+		case arg == "--no-shade":
+			noShade = true
+		case strings.HasPrefix(arg, "#"):
+			var color Color
+			if _, err := fmt.Sscanf(arg, "#%02x%02x%02x", &color.R, &color.G, &color.B); err != nil {
+				return nil, fmt.Errorf("invalid shade color: %s", arg)
+			}
+			shade = &color
+		default:
+			countries = append(countries, arg)
+		}
+	}
+
+	source, err := NewHolidaySource(name, countries)
+	if err != nil {
+		return nil, err
+	}
+	if noShade {
+		source.Shade = nil
+	} else if shade != nil {
+		source.Shade = shade
+	}
+	return source, nil
+}
+
+// RegisterSource makes a kind of source available to NewSource
+func RegisterSource(kind string, factory SourceFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[kind] = factory
+}
+
+// SourceKinds returns the kinds of source that are built in
+func SourceKinds() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewSource creates a source of the given kind, passing it args split like
+// a command line. Kinds that aren't registered are served by a plugin: the
+// urd-source-<kind> binary on the PATH, run with args.
+func NewSource(name, kind, args string) (ReminderSource, error) {
+	argv, err := splitCommandLine(args)
+	if err != nil {
+		return nil, err
+	}
+	for i, arg := range argv {
+		argv[i] = config.ExpandHome(arg)
+	}
+
+	registryMu.Lock()
+	factory, ok := registry[kind]
+	registryMu.Unlock()
+	if ok {
+		return factory(name, argv)
+	}
+
+	source, err := NewPluginSource(name, append([]string{PluginPrefix + kind}, argv...))
+	if err != nil {
+		return nil, fmt.Errorf("unknown source kind %s: %w", kind, err)
+	}
+	return source, nil
+}
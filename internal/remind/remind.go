@@ -2,13 +2,15 @@ package remind
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
-	"sort"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -26,12 +28,24 @@ func (e *RemindSyntaxError) Error() string {
 	return fmt.Sprintf("%s: %s", e.File, e.Message)
 }
 
+// RemindSourceName identifies events read from remind files
+const RemindSourceName = "remind"
+
 type Client struct {
 	RemindPath string
-	Files      []string
+	Files      []string // Expanded remind files
 	Timezone   *time.Location
+	Clock      Clock    // Current time for quick adds, SystemClock when nil
+	DayFirst   bool     // Quick adds read 03/04 as April 3, see TimeParser
+	ReadOnly   bool     // Refuse every change to the files, as when replaying a session
+	entries    []string // Configured entries (files, directories or globs)
 	watcher    *FileWatcher
 	eventChan  chan FileChangeEvent
+
+	mu            sync.Mutex
+	warnings      []string // reported since Warnings was last called
+	builtinWarned bool     // Skipped lines were reported, see builtinReminders
+	recorder      Recorder // Receives what GetEvents returns, while recording
 }
 
 func NewClient() *Client {
@@ -39,16 +53,82 @@ func NewClient() *Client {
 		RemindPath: "remind",
 		Files:      []string{},
 		Timezone:   time.Local,
+		Clock:      SystemClock,
+	}
+}
+
+// now returns the current time of the client's clock
+func (c *Client) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
 	}
+	return c.Clock.Now()
 }
 
+// SetFiles sets the remind files to read. Entries may be files, directories
+// (searched recursively for *.rem files) or glob patterns.
 func (c *Client) SetFiles(files []string) {
-	c.Files = files
+	c.entries = files
+	c.Files = ExpandFiles(files)
+}
+
+// Name implements SourceInfo
+func (c *Client) Name() string {
+	return RemindSourceName
+}
+
+// Capabilities implements SourceInfo - remind files are fully writable,
+// unless the client is read-only
+func (c *Client) Capabilities() Capabilities {
+	if c.ReadOnly {
+		return Capabilities{}
+	}
+	return Capabilities{Add: true, Remove: true, Edit: true}
+}
+
+// writable returns ErrReadOnly when the client must not change any file
+func (c *Client) writable() error {
+	if c.ReadOnly {
+		return fmt.Errorf("%s: %w", c.Name(), ErrReadOnly)
+	}
+	return nil
+}
+
+// PrimaryFile returns the file new reminders are written to
+func (c *Client) PrimaryFile() string {
+	if len(c.Files) == 0 {
+		return ""
+	}
+	return c.Files[0]
 }
 
 func (c *Client) GetEvents(start, end time.Time) ([]Event, error) {
+	events, err := c.getEvents(start, end)
+	c.mu.Lock()
+	record := c.recorder
+	c.mu.Unlock()
+	if record != nil {
+		record(newResponse(start, end, events, err))
+	}
+	return events, err
+}
+
+// SetRecorder implements RecordingSource
+func (c *Client) SetRecorder(record Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = record
+}
+
+// getEvents returns the events from start to end, see GetEvents
+func (c *Client) getEvents(start, end time.Time) ([]Event, error) {
+	// Re-expand directories and globs to pick up newly created files
+	if c.entries != nil {
+		c.Files = ExpandFiles(c.entries)
+	}
+
 	if len(c.Files) == 0 {
-		return nil, fmt.Errorf("no remind files configured")
+		return nil, ErrNoFiles
 	}
 
 	// Simply call getEventsForMonth for a single month if the date range is within one month
@@ -87,8 +167,8 @@ func (c *Client) GetEvents(start, end time.Time) ([]Event, error) {
 		// Filter events to the requested date range and deduplicate
 		for _, event := range events {
 			if !event.Date.Before(start) && !event.Date.After(end) {
-				// Use the event ID as the deduplication key
-				// The ID already includes date and line number which makes it unique
+				// Use the event ID as the deduplication key; it covers the
+				// file, line, date, time and description of each occurrence
 				if _, exists := eventMap[event.ID]; !exists {
 					eventMap[event.ID] = event
 				}
@@ -115,6 +195,11 @@ func (c *Client) GetEvents(start, end time.Time) ([]Event, error) {
 
 // getEventsForMonth gets events for a specific month
 func (c *Client) getEventsForMonth(monthStart time.Time) ([]Event, error) {
+	// Without remind, read what the built-in parser understands
+	if c.Degraded() {
+		return c.builtinEvents(monthStart, monthStart.AddDate(0, 1, -1)), nil
+	}
+
 	args := []string{
 		"-pppq", // rem2ps format with preprocessing, quiet
 		"-l",    // include file and line number
@@ -148,6 +233,9 @@ func (c *Client) getEventsForMonth(monthStart time.Time) ([]Event, error) {
 	}
 
 	// If command failed and no stdout, return error
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrRemindNotFound, c.RemindPath)
+	}
 	if err != nil && stdout.Len() == 0 {
 		return nil, fmt.Errorf("remind command failed: %w", err)
 	}
@@ -157,20 +245,48 @@ func (c *Client) getEventsForMonth(monthStart time.Time) ([]Event, error) {
 	// Parse JSON output
 	months, parseErr := ParseRemindJSON(output)
 	if parseErr != nil {
-		// Fall back to text parsing if JSON fails
-		return c.parseRemindOutput(string(output))
+		// Fall back to text parsing if JSON fails. Events parsed from text
+		// have no file and line, so they can't be edited.
+		c.warn("Couldn't read remind's JSON output, events can't be edited: %v", parseErr)
+		events, err := c.parseRemindOutput(string(output))
+		for i := range events {
+			events[i].Source = RemindSourceName
+		}
+		return events, err
 	}
 
 	// Convert JSON entries to events
 	var events []Event
 	for _, month := range months {
 		monthEvents := ConvertJSONToEvents(month.Entries, c.Timezone)
+		for i := range monthEvents {
+			// Track the file each event really came from, which may be
+			// an included file rather than one passed on the command line
+			monthEvents[i].Filename = resolveSourcePath(monthEvents[i].Filename)
+			monthEvents[i].Source = RemindSourceName
+		}
 		events = append(events, monthEvents...)
 	}
 
 	return events, nil
 }
 
+// warn records a problem for Warnings
+func (c *Client) warn(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// Warnings implements WarningSource
+func (c *Client) Warnings() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	warnings := c.warnings
+	c.warnings = nil
+	return warnings
+}
+
 func monthName(m time.Month) string {
 	return []string{
 		"", "Jan", "Feb", "Mar", "Apr", "May", "Jun",
@@ -188,12 +304,85 @@ func (c *Client) GetEventsForDate(date time.Time) ([]Event, error) {
 // FindNext finds the next occurrence of events matching the search term after the given time
 // This uses 'remind -n' which searches forward indefinitely
 func (c *Client) FindNext(searchTerm string, afterTime time.Time) (*Event, error) {
-	if len(c.Files) == 0 {
-		return nil, fmt.Errorf("no remind files configured")
+	results, err := c.nextOccurrences(afterTime)
+	if err != nil {
+		return nil, err
 	}
 
 	searchLower := strings.ToLower(searchTerm)
 
+	// Find first matching event after afterTime
+	for _, event := range results {
+		if eventStart(event).After(afterTime) {
+			// Check if description matches
+			if strings.Contains(strings.ToLower(event.Description), searchLower) {
+				return &event, nil
+			}
+			// Check tags
+			for _, tag := range event.Tags {
+				if strings.Contains(strings.ToLower(tag), searchLower) {
+					return &event, nil
+				}
+			}
+		}
+	}
+
+	return nil, nil // No match found
+}
+
+// Upcoming implements UpcomingSource - returns the next n events from now on,
+// however far ahead they are. remind -n reports only the next occurrence of
+// each reminder, so it is used to find how far ahead to look, and the events
+// themselves come from scanning up to there; that way a recurring reminder
+// appears as often as it occurs.
+func (c *Client) Upcoming(after time.Time, n int) ([]Event, error) {
+	results, err := c.nextOccurrences(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var next []Event
+	seen := make(map[string]bool)
+	for _, event := range results {
+		// Both remind runs report reminders that recur daily
+		if seen[event.ID] || !isUpcoming(event, after) {
+			continue
+		}
+		seen[event.ID] = true
+		next = append(next, event)
+	}
+
+	// n next occurrences are n occurrences, so the first n events all fall
+	// on or before the day of the nth; with fewer, look at least as far as
+	// other sources do
+	dayStart := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	end := after.Add(upcomingWindow)
+	if n > 0 && len(next) >= n {
+		end = next[n-1].Date
+	} else if len(next) > 0 && next[len(next)-1].Date.After(end) {
+		end = next[len(next)-1].Date
+	}
+
+	events, err := c.GetEvents(dayStart, end)
+	if err != nil {
+		return nil, err
+	}
+	return firstUpcoming(events, after, n), nil
+}
+
+// nextOccurrences returns the next occurrence of every reminder on or after
+// the given time, sorted chronologically
+func (c *Client) nextOccurrences(afterTime time.Time) ([]Event, error) {
+	if len(c.Files) == 0 {
+		return nil, ErrNoFiles
+	}
+
+	if c.Degraded() {
+		results := c.builtinNextOccurrences(afterTime)
+		sortByStart(results)
+		return results, nil
+	}
+
 	// Use remind -n to get next occurrences of all reminders from the given date
 	// We need to run it twice: once from the current date, once from the next day
 	// to avoid missing recurring events that fall today but before afterTime
@@ -228,47 +417,8 @@ func (c *Client) FindNext(searchTerm string, afterTime time.Time) (*Event, error
 		results = append(results, events...)
 	}
 
-	// Sort by date/time and find first match after afterTime
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].Date.Equal(results[j].Date) {
-			if results[i].Time == nil && results[j].Time == nil {
-				return false
-			}
-			if results[i].Time == nil {
-				return false
-			}
-			if results[j].Time == nil {
-				return true
-			}
-			return results[i].Time.Before(*results[j].Time)
-		}
-		return results[i].Date.Before(results[j].Date)
-	})
-
-	// Find first matching event after afterTime
-	for _, event := range results {
-		// Check if event is after the search start time
-		eventTime := event.Date
-		if event.Time != nil {
-			eventTime = time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
-				event.Time.Hour(), event.Time.Minute(), 0, 0, event.Date.Location())
-		}
-
-		if eventTime.After(afterTime) {
-			// Check if description matches
-			if strings.Contains(strings.ToLower(event.Description), searchLower) {
-				return &event, nil
-			}
-			// Check tags
-			for _, tag := range event.Tags {
-				if strings.Contains(strings.ToLower(tag), searchLower) {
-					return &event, nil
-				}
-			}
-		}
-	}
-
-	return nil, nil // No match found
+	sortByStart(results)
+	return results, nil
 }
 
 // parseRemindNextOutput parses the output of 'remind -n'
@@ -308,7 +458,7 @@ func (c *Client) parseRemindNextOutput(output string) ([]Event, error) {
 
 			// Parse priority and tags
 			event.Description, event.Priority, event.Tags = c.parseEventDetails(desc)
-			event.ID = c.generateEventID(event)
+			event.ID = EventID(event)
 
 			events = append(events, event)
 		} else if matches := untimedLineRe.FindStringSubmatch(line); matches != nil {
@@ -327,7 +477,7 @@ func (c *Client) parseRemindNextOutput(output string) ([]Event, error) {
 
 			// Parse priority and tags
 			event.Description, event.Priority, event.Tags = c.parseEventDetails(desc)
-			event.ID = c.generateEventID(event)
+			event.ID = EventID(event)
 
 			events = append(events, event)
 		}
@@ -428,7 +578,7 @@ func (c *Client) parseRemindOutput(output string) ([]Event, error) {
 
 		// Parse priority and tags from description
 		event.Description, event.Priority, event.Tags = c.parseEventDetails(event.Description)
-		event.ID = c.generateEventID(event)
+		event.ID = EventID(event)
 
 		events = append(events, event)
 	}
@@ -463,21 +613,6 @@ func (c *Client) parseEventDetails(desc string) (string, Priority, []string) {
 	return strings.TrimSpace(desc), priority, tags
 }
 
-func (c *Client) generateEventID(event Event) string {
-	hash := fmt.Sprintf("%s-%s-%d",
-		event.Date.Format("2006-01-02"),
-		event.Description,
-		len(event.Description))
-
-	// Simple hash for ID
-	sum := 0
-	for _, b := range hash {
-		sum += int(b)
-	}
-
-	return fmt.Sprintf("evt-%d", sum)
-}
-
 // WatchFiles implements ReminderSource interface - watches remind files for changes
 func (c *Client) WatchFiles() (<-chan FileChangeEvent, error) {
 	if c.watcher != nil {
@@ -486,12 +621,17 @@ func (c *Client) WatchFiles() (<-chan FileChangeEvent, error) {
 
 	c.eventChan = make(chan FileChangeEvent, 10)
 
-	watcher, err := NewFileWatcher(func(path string) {
+	send := func(event FileChangeEvent) {
 		select {
-		case c.eventChan <- FileChangeEvent{Path: path, Timestamp: time.Now()}:
+		case c.eventChan <- event:
 		default:
 			// Channel full, drop event
 		}
+	}
+	watcher, err := NewFileWatcher(func(path string) {
+		send(FileChangeEvent{Path: path, Timestamp: time.Now()})
+	}, func(err error) {
+		send(FileChangeEvent{Timestamp: time.Now(), Err: err})
 	})
 	if err != nil {
 		return nil, err
@@ -499,11 +639,18 @@ func (c *Client) WatchFiles() (<-chan FileChangeEvent, error) {
 
 	c.watcher = watcher
 
-	// Add all configured files to the watcher
-	for _, file := range c.Files {
+	// Add all configured files, and any files they include, to the watcher.
+	// Files that can't be watched are reported with the next events.
+	for _, file := range append(append([]string{}, c.Files...), IncludedFiles(c.Files)...) {
 		if err := c.watcher.AddFile(file); err != nil {
-			// Log error but continue with other files
-			continue
+			c.warn("changes to %s won't be noticed: %v", file, err)
+		}
+	}
+
+	// Watch directories and glob parents so newly created files are picked up
+	for dir, match := range watchDirs(c.entries) {
+		if err := c.watcher.AddDir(dir, match); err != nil {
+			c.warn("new files in %s won't be noticed: %v", dir, err)
 		}
 	}
 
@@ -528,8 +675,11 @@ func (c *Client) StopWatching() error {
 }
 
 func (c *Client) AddEvent(desc, dateStr, timeStr string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
 	if len(c.Files) == 0 {
-		return fmt.Errorf("no remind files configured")
+		return ErrNoFiles
 	}
 
 	// Use first file for new events
@@ -562,12 +712,25 @@ func (c *Client) AddEvent(desc, dateStr, timeStr string) error {
 // and appends it to the remind file
 func (c *Client) AddEventFromTemplate(template, dateStr, timeStr string) (int, error) {
 	if len(c.Files) == 0 {
-		return 0, fmt.Errorf("no remind files configured")
+		return 0, ErrNoFiles
 	}
-
 	// Use first file for new events
-	file := c.Files[0]
+	return c.AddEventFromTemplateTo(c.Files[0], nil, template, dateStr, timeStr)
+}
+
+// AddEventFromTemplateTo creates a new reminder using the provided template,
+// tagged with tags, and appends it to file. It returns the line the reminder
+// is on.
+func (c *Client) AddEventFromTemplateTo(file string, tags []string, template, dateStr, timeStr string) (int, error) {
+	return c.AddSpanFromTemplateTo(file, tags, template, dateStr, timeStr, 0)
+}
 
+// AddSpanFromTemplateTo is AddEventFromTemplateTo for a reminder lasting
+// length, which %dura% is expanded to instead of an hour when it's not zero
+func (c *Client) AddSpanFromTemplateTo(file string, tags []string, template, dateStr, timeStr string, length time.Duration) (int, error) {
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
 	// Get current line count to know where we're adding the new entry
 	existingContent, err := os.ReadFile(file)
 	if err != nil && !os.IsNotExist(err) {
@@ -576,10 +739,12 @@ func (c *Client) AddEventFromTemplate(template, dateStr, timeStr string) (int, e
 	lineNumber := strings.Count(string(existingContent), "\n") + 1
 
 	// Build the remind line
-	remindLine := c.expandTemplate(template, dateStr, timeStr)
-	if !strings.HasSuffix(remindLine, "\n") {
-		remindLine = remindLine + "\n"
+	remindLine := c.expandTemplate(template, dateStr, timeStr, length)
+	if remindLine == "" && timeStr != "" {
+		// Fallback to simple format
+		remindLine = fmt.Sprintf("REM %s AT %s MSG New reminder", dateStr, timeStr)
 	}
+	remindLine = WithTags(strings.TrimSuffix(remindLine, "\n"), tags) + "\n"
 
 	// Append to file
 	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
@@ -596,45 +761,25 @@ func (c *Client) AddEventFromTemplate(template, dateStr, timeStr string) (int, e
 	return lineNumber, nil
 }
 
-// AddTimedEventFromTemplate creates a new timed reminder using the provided template
-// and appends it to the remind file at the current time slot
-func (c *Client) AddTimedEventFromTemplate(template, dateStr, timeStr string) (int, error) {
-	if len(c.Files) == 0 {
-		return 0, fmt.Errorf("no remind files configured")
-	}
+// bodyKeywordRe matches the keyword a REM line's body starts with
+var bodyKeywordRe = regexp.MustCompile(`(?i)\s(MSG|MSF|RUN|CAL|SPECIAL|PS|PSFILE|SATISFY)\b`)
 
-	// Use first file for new events
-	file := c.Files[0]
-
-	// Get current line count to know where we're adding the new entry
-	existingContent, err := os.ReadFile(file)
-	if err != nil && !os.IsNotExist(err) {
-		return 0, fmt.Errorf("failed to read remind file: %w", err)
+// WithTags adds a TAG clause for each of tags to a REM line, before its
+// body, leaving off any @ they're written with
+func WithTags(line string, tags []string) string {
+	var clauses string
+	for _, tag := range tags {
+		if tag = strings.TrimPrefix(tag, "@"); tag != "" {
+			clauses += " TAG " + tag
+		}
 	}
-	lineNumber := strings.Count(string(existingContent), "\n") + 1
-
-	// Build the remind line
-	remindLine := c.expandTemplate(template, dateStr, timeStr)
-	if remindLine == "" {
-		// Fallback to simple format
-		remindLine = fmt.Sprintf("REM %s AT %s MSG New reminder\n", dateStr, timeStr)
-	} else if !strings.HasSuffix(remindLine, "\n") {
-		remindLine = remindLine + "\n"
+	if clauses == "" {
+		return line
 	}
-
-	// Append to file
-	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return 0, fmt.Errorf("failed to open remind file: %w", err)
+	if loc := bodyKeywordRe.FindStringIndex(line); loc != nil {
+		return line[:loc[0]] + clauses + line[loc[0]:]
 	}
-	defer f.Close()
-
-	_, err = f.WriteString(remindLine)
-	if err != nil {
-		return 0, fmt.Errorf("failed to write to remind file: %w", err)
-	}
-
-	return lineNumber, nil
+	return line + clauses
 }
 
 // parseRemindError parses remind error output to extract file, line number, and error message
@@ -685,6 +830,42 @@ func (c *Client) parseRemindError(output string) error {
 	return nil
 }
 
+// CheckFile runs remind on the remind files, which file was edited in, and
+// returns the error it reports in file, or else the first error it reports
+// elsewhere. Without remind, nothing is checked.
+func (c *Client) CheckFile(file string) error {
+	if c.Degraded() {
+		return nil
+	}
+	files := c.Files
+	if len(files) == 0 {
+		files = []string{file}
+	}
+	cmd := exec.Command(c.RemindPath, append([]string{"-n"}, files...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	_ = cmd.Run() // remind exits non-zero for some errors, they are on stderr
+
+	file = resolveSourcePath(file)
+	var first error
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		err := c.parseRemindError(line)
+		if err == nil {
+			continue
+		}
+		if syntaxErr, ok := err.(*RemindSyntaxError); ok && syntaxErr.File != "" {
+			syntaxErr.File = resolveSourcePath(syntaxErr.File)
+			if syntaxErr.File == file {
+				return syntaxErr
+			}
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
 func (c *Client) TestConnection() error {
 	// Test with a simple remind command that should always work
 	cmd := exec.Command(c.RemindPath, "-n")
@@ -695,13 +876,19 @@ func (c *Client) TestConnection() error {
 		if len(output) > 0 && (strings.Contains(string(output), "No reminders") || strings.Contains(string(output), "REM")) {
 			return nil
 		}
-		return fmt.Errorf("remind command not found or not working: %w", err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrRemindNotFound, c.RemindPath)
+		}
+		return fmt.Errorf("remind command not working: %w", err)
 	}
 	return nil
 }
 
 // EditEvent opens the remind file for editing at a specific line number
 func (c *Client) EditEvent(event Event, editCommand string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
 	if editCommand == "" {
 		return fmt.Errorf("no edit command specified")
 	}
@@ -717,6 +904,9 @@ func (c *Client) EditEvent(event Event, editCommand string) error {
 
 // EditFile opens a remind file for editing (for new events)
 func (c *Client) EditFile(filePath string, editCommand string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
 	if editCommand == "" {
 		return fmt.Errorf("no edit command specified")
 	}
@@ -727,28 +917,23 @@ func (c *Client) EditFile(filePath string, editCommand string) error {
 // findEventFile attempts to locate which remind file contains the given event
 func (c *Client) findEventFile(event Event) (string, error) {
 	if len(c.Files) == 0 {
-		return "", fmt.Errorf("no remind files configured")
+		return "", ErrNoFiles
+	}
+
+	// Events loaded from remind carry the file they came from, which may be
+	// an included file rather than one of the configured files
+	if event.Filename != "" {
+		return event.Filename, nil
 	}
 
-	// For now, use the first file as default
-	// In a more sophisticated implementation, we could parse the event ID
-	// or search through files to find the exact match
 	return c.Files[0], nil
 }
 
 // executeEditCommand runs the editor command with proper variable substitution
 func (c *Client) executeEditCommand(command, filePath string, lineNumber int) error {
-	// Expand variables in the command
-	expandedCommand := c.expandCommandVariables(command, filePath, lineNumber)
-
-	// Parse the command into program and arguments
-	parts, err := c.parseCommand(expandedCommand)
+	parts, err := EditorCommand(command, filePath, lineNumber)
 	if err != nil {
-		return fmt.Errorf("failed to parse edit command: %w", err)
-	}
-
-	if len(parts) == 0 {
-		return fmt.Errorf("empty edit command")
+		return err
 	}
 
 	// Execute the editor
@@ -770,22 +955,42 @@ func (c *Client) executeEditCommand(command, filePath string, lineNumber int) er
 	return err
 }
 
-// expandCommandVariables replaces template variables in the command string
-func (c *Client) expandCommandVariables(command, filePath string, lineNumber int) string {
-	result := command
-	result = strings.ReplaceAll(result, "%file%", filePath)
+// EditorCommand splits an edit command into the program and its arguments,
+// then replaces %file% and %line% in each. Replacing them after splitting
+// keeps paths with spaces, common on Windows, in one argument.
+func EditorCommand(command, filePath string, lineNumber int) ([]string, error) {
+	parts, err := splitCommandLine(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse edit command: %w", err)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty edit command")
+	}
+
+	line := "999999" // For new events, go to end of file
 	if lineNumber > 0 {
-		result = strings.ReplaceAll(result, "%line%", fmt.Sprintf("%d", lineNumber))
-	} else {
-		// For new events, go to end of file
-		result = strings.ReplaceAll(result, "%line%", "999999")
+		line = strconv.Itoa(lineNumber)
 	}
-	return result
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, "%file%", filePath)
+		parts[i] = strings.ReplaceAll(part, "%line%", line)
+	}
+	return parts, nil
 }
 
-// parseCommand splits a command string into program and arguments
-// Handles quoted arguments properly
-func (c *Client) parseCommand(command string) ([]string, error) {
+// commandQuotes are the quotes that keep arguments together in command
+// lines from urdrc. On Windows, where names can have apostrophes, only
+// double quotes do, as with cmd.exe.
+var commandQuotes = func() string {
+	if runtime.GOOS == "windows" {
+		return `"`
+	}
+	return `"'`
+}()
+
+// splitCommandLine splits a command line into arguments at spaces, keeping
+// quoted strings together
+func splitCommandLine(command string) ([]string, error) {
 	var parts []string
 	var current string
 	var inQuotes bool
@@ -793,7 +998,7 @@ func (c *Client) parseCommand(command string) ([]string, error) {
 
 	for _, r := range command {
 		switch {
-		case !inQuotes && (r == '"' || r == '\''):
+		case !inQuotes && strings.ContainsRune(commandQuotes, r):
 			inQuotes = true
 			quoteChar = r
 		case inQuotes && r == quoteChar:
@@ -819,8 +1024,10 @@ func (c *Client) parseCommand(command string) ([]string, error) {
 	return parts, nil
 }
 
-// expandTemplate replaces template placeholders with actual values
-func (c *Client) expandTemplate(template, dateStr, timeStr string) string {
+// expandTemplate replaces template placeholders with actual values. %dura%
+// is the length in hours, an hour when length is zero, except in
+// %dura%:00, which becomes the exact length as DURATION writes it.
+func (c *Client) expandTemplate(template, dateStr, timeStr string, length time.Duration) string {
 	if template == "" {
 		return ""
 	}
@@ -864,7 +1071,12 @@ func (c *Client) expandTemplate(template, dateStr, timeStr string) string {
 	remindLine = strings.ReplaceAll(remindLine, "%min%", minStr)
 	remindLine = strings.ReplaceAll(remindLine, "%wdayname%", weekdayName)
 	remindLine = strings.ReplaceAll(remindLine, "%wday%", fmt.Sprintf("%d", getWeekdayNum(weekdayName)))
-	remindLine = strings.ReplaceAll(remindLine, "%dura%", "1") // Default 1 hour duration
+	if length <= 0 {
+		length = time.Hour // Default 1 hour duration
+	}
+	minutes := int(length / time.Minute)
+	remindLine = strings.ReplaceAll(remindLine, "%dura%:00", fmt.Sprintf("%d:%.2d", minutes/60, minutes%60))
+	remindLine = strings.ReplaceAll(remindLine, "%dura%", strconv.Itoa(max(1, (minutes+59)/60)))
 
 	// Remove the trailing % if present
 	if strings.HasSuffix(remindLine, "%") {
@@ -898,8 +1110,11 @@ func getWeekdayNum(weekdayName string) int {
 
 // AddEventStruct adds a remind.Event to the remind file and returns the line number
 func (c *Client) AddEventStruct(event Event) (int, error) {
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
 	if len(c.Files) == 0 {
-		return 0, fmt.Errorf("no remind files configured")
+		return 0, ErrNoFiles
 	}
 
 	// Use first file for new events
@@ -941,8 +1156,11 @@ func (c *Client) AddEventStruct(event Event) (int, error) {
 // RemoveEvent removes an event from the remind file
 // This is a simplified implementation that removes by matching description and date
 func (c *Client) RemoveEvent(event Event) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
 	if len(c.Files) == 0 {
-		return fmt.Errorf("no remind files configured")
+		return ErrNoFiles
 	}
 
 	// If we have a line number, use it directly
@@ -963,7 +1181,7 @@ func (c *Client) RemoveEvent(event Event) error {
 
 		// Check if line number is valid
 		if event.LineNumber > len(lines) {
-			return fmt.Errorf("line number %d exceeds file length", event.LineNumber)
+			return fmt.Errorf("%w: line %d is past the end of %s", ErrWriteConflict, event.LineNumber, event.Filename)
 		}
 
 		// Remove the line at the specified line number (1-indexed)
@@ -1024,7 +1242,7 @@ func (c *Client) RemoveEvent(event Event) error {
 	}
 
 	if !removed {
-		return fmt.Errorf("event not found in remind file")
+		return fmt.Errorf("%w: the event is no longer in %s", ErrWriteConflict, file)
 	}
 
 	// Write the updated content back to file
@@ -1037,17 +1255,44 @@ func (c *Client) RemoveEvent(event Event) error {
 	return nil
 }
 
+// CompleteEvent marks a one-off reminder done by commenting it out, keeping
+// it in the file as a record of when it was done
+func (c *Client) CompleteEvent(event Event, at time.Time) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if event.IsRepeating {
+		return fmt.Errorf("repeating reminders can't be marked done for one occurrence")
+	}
+	lines, _, end, err := readReminder(event)
+	if err != nil {
+		return err
+	}
+
+	// Comment out the reminder along with any continuation lines
+	lines[event.LineNumber-1] = fmt.Sprintf("# DONE %s: %s", at.Format("2006-01-02"), lines[event.LineNumber-1])
+	for i := event.LineNumber; i <= end; i++ {
+		lines[i] = "# " + lines[i]
+	}
+
+	if err := os.WriteFile(event.Filename, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write updated remind file: %w", err)
+	}
+	return nil
+}
+
 // AddQuickEvent parses natural language event description and adds it to remind file
 func (c *Client) AddQuickEvent(eventDesc string) (int, error) {
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
 	if len(c.Files) == 0 {
-		return 0, fmt.Errorf("no remind files configured")
+		return 0, ErrNoFiles
 	}
 
-	// Parse the natural language description using the time parser
-	parser := &TimeParser{Now: time.Now(), Location: time.Local}
-	parsed, err := parser.Parse(eventDesc)
+	_, remindLine, err := QuickEventLine(eventDesc, c.now(), c.DayFirst)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse event description: %w", err)
+		return 0, err
 	}
 
 	// Use first file for new events
@@ -1060,30 +1305,6 @@ func (c *Client) AddQuickEvent(eventDesc string) (int, error) {
 	}
 	lineNumber := strings.Count(string(existingContent), "\n") + 1
 
-	// Format the remind line based on parsing results
-	var remindLine string
-	dateStr := parsed.Date.Format("Jan 2 2006")
-	description := strings.TrimSpace(parsed.Text)
-	if description == "" {
-		description = "New reminder"
-	}
-
-	if parsed.HasTime {
-		timeStr := parsed.Time.Format("15:04")
-		if parsed.Duration > 0 {
-			// Calculate duration in hours and minutes
-			totalMin := int(parsed.Duration.Minutes())
-			hours := totalMin / 60
-			minutes := totalMin % 60
-			remindLine = fmt.Sprintf("REM %s AT %s DURATION %d:%.2d MSG %s\n",
-				dateStr, timeStr, hours, minutes, description)
-		} else {
-			remindLine = fmt.Sprintf("REM %s AT %s MSG %s\n", dateStr, timeStr, description)
-		}
-	} else {
-		remindLine = fmt.Sprintf("REM %s MSG %s\n", dateStr, description)
-	}
-
 	// Append to file
 	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
 	if err != nil {
@@ -1091,10 +1312,44 @@ func (c *Client) AddQuickEvent(eventDesc string) (int, error) {
 	}
 	defer f.Close()
 
-	_, err = f.WriteString(remindLine)
+	_, err = f.WriteString(remindLine + "\n")
 	if err != nil {
 		return 0, fmt.Errorf("failed to write to remind file: %w", err)
 	}
 
 	return lineNumber, nil
 }
+
+// QuickEventLine parses a natural language event description and returns the
+// parsed event along with the REM line AddQuickEvent writes for it.
+// Numeric dates are read day first with dayFirst.
+func QuickEventLine(eventDesc string, now time.Time, dayFirst bool) (*ParsedEvent, string, error) {
+	// Parse the natural language description using the time parser
+	parser := &TimeParser{Now: now, Location: time.Local, DayFirst: dayFirst}
+	parsed, err := parser.Parse(eventDesc)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: event description: %w", ErrParse, err)
+	}
+
+	// Format the remind line based on parsing results
+	dateStr := parsed.Date.Format("Jan 2 2006")
+	description := strings.TrimSpace(parsed.Text)
+	if description == "" {
+		description = "New reminder"
+	}
+
+	if !parsed.HasTime {
+		return parsed, fmt.Sprintf("REM %s MSG %s", dateStr, description), nil
+	}
+
+	timeStr := parsed.Time.Format("15:04")
+	if parsed.Duration > 0 {
+		// Calculate duration in hours and minutes
+		totalMin := int(parsed.Duration.Minutes())
+		hours := totalMin / 60
+		minutes := totalMin % 60
+		return parsed, fmt.Sprintf("REM %s AT %s DURATION %d:%.2d MSG %s",
+			dateStr, timeStr, hours, minutes, description), nil
+	}
+	return parsed, fmt.Sprintf("REM %s AT %s MSG %s", dateStr, timeStr, description), nil
+}
@@ -1,6 +1,10 @@
 package remind
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -173,9 +177,7 @@ func TestParseDifferentDateFormats(t *testing.T) {
 	}
 }
 
-func TestGenerateEventID(t *testing.T) {
-	client := NewClient()
-
+func TestEventID(t *testing.T) {
 	event1 := Event{
 		Date:        time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local),
 		Description: "Test event",
@@ -191,9 +193,9 @@ func TestGenerateEventID(t *testing.T) {
 		Description: "Different event",
 	}
 
-	id1 := client.generateEventID(event1)
-	id2 := client.generateEventID(event2)
-	id3 := client.generateEventID(event3)
+	id1 := EventID(event1)
+	id2 := EventID(event2)
+	id3 := EventID(event3)
 
 	// Same events should generate same ID
 	if id1 != id2 {
@@ -209,6 +211,19 @@ func TestGenerateEventID(t *testing.T) {
 	if !strings.HasPrefix(id1, "evt-") {
 		t.Errorf("ID doesn't have expected prefix: %s", id1)
 	}
+
+	// Anagrams, other files, lines and times are told apart
+	at := time.Date(2024, 3, 15, 9, 0, 0, 0, time.Local)
+	for name, other := range map[string]Event{
+		"anagram": {Date: event1.Date, Description: "Test evnet"},
+		"file":    {Date: event1.Date, Description: "Test event", Filename: "/r/work.rem", LineNumber: 1},
+		"line":    {Date: event1.Date, Description: "Test event", Filename: "/r/work.rem", LineNumber: 2},
+		"time":    {Date: event1.Date, Time: &at, Description: "Test event"},
+	} {
+		if EventID(other) == id1 {
+			t.Errorf("%s: expected a different ID than %s", name, id1)
+		}
+	}
 }
 
 func TestParseRemindNextOutput(t *testing.T) {
@@ -475,3 +490,412 @@ func TestRemindSyntaxErrorString(t *testing.T) {
 		})
 	}
 }
+
+func TestEventPrivacy(t *testing.T) {
+	private := Event{
+		Description: "Doctor appointment",
+		Body:        "Bring insurance card",
+		Tags:        []string{"health", "private"},
+	}
+	public := Event{
+		Description: "Team standup",
+		Tags:        []string{"work"},
+	}
+
+	if !private.IsPrivate() {
+		t.Error("Event tagged private should be private")
+	}
+	if public.IsPrivate() {
+		t.Error("Event without PRIVATE tag should not be private")
+	}
+	if !(Event{Tags: []string{"@PRIVATE"}}).IsPrivate() {
+		t.Error("@-prefixed PRIVATE tag should be recognized")
+	}
+
+	redacted := private.Redacted()
+	if redacted.Description != RedactedDescription {
+		t.Errorf("Redacted description = %q, want %q", redacted.Description, RedactedDescription)
+	}
+	if redacted.Body != "" || len(redacted.Tags) != 0 {
+		t.Errorf("Redacted event leaks details: body=%q tags=%v", redacted.Body, redacted.Tags)
+	}
+	if private.Description != "Doctor appointment" {
+		t.Error("Redacted must not modify the original event")
+	}
+}
+
+func TestEventSourceLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{"unknown source", Event{}, ""},
+		{"file and line", Event{Filename: "/home/user/reminders/work.rem", LineNumber: 12}, "work.rem:12"},
+		{"file only", Event{Filename: "/home/user/reminders/work.rem"}, "work.rem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.SourceLocation(); got != tt.expected {
+				t.Errorf("SourceLocation() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertJSONSpecialColor(t *testing.T) {
+	r, g, b := 255, 128, 0
+	entries := []RemindEntry{
+		{Date: "2025-08-25", LineNo: 1, PassThru: "COLOR", Body: "Orange event", R: &r, G: &g, B: &b},
+		{Date: "2025-08-25", LineNo: 2, PassThru: "COLOUR", Body: "0 0 255 Blue event"},
+		{Date: "2025-08-25", LineNo: 3, PassThru: "COLOR", Body: "not a color"},
+		{Date: "2025-08-25", LineNo: 4, Body: "Plain event"},
+	}
+
+	events := ConvertJSONToEvents(entries, time.Local)
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(events))
+	}
+
+	expected := []struct {
+		color *Color
+		desc  string
+	}{
+		{&Color{R: 255, G: 128, B: 0}, "Orange event"},
+		{&Color{R: 0, G: 0, B: 255}, "Blue event"},
+		{nil, "not a color"},
+		{nil, "Plain event"},
+	}
+
+	for i, exp := range expected {
+		event := events[i]
+		if event.Description != exp.desc {
+			t.Errorf("Event %d: expected description %q, got %q", i, exp.desc, event.Description)
+		}
+		if (event.Color == nil) != (exp.color == nil) || (exp.color != nil && *event.Color != *exp.color) {
+			t.Errorf("Event %d: expected color %v, got %v", i, exp.color, event.Color)
+		}
+	}
+}
+
+func TestConvertJSONSpecials(t *testing.T) {
+	entries := []RemindEntry{
+		{Date: "2025-08-09", LineNo: 1, PassThru: "MOON", Body: "2 -1 -1 Full moon"},
+		{Date: "2025-08-10", LineNo: 2, PassThru: "SHADE", Body: "200 220 255"},
+		{Date: "2025-08-11", LineNo: 3, PassThru: "SHADE", Body: "128"},
+		{Date: "2025-08-11", LineNo: 4, PassThru: "WEEK", Body: "(W33)"},
+		{Date: "2025-08-12", LineNo: 5, PassThru: "HTML", Body: "<b>html only</b>"},
+		{Date: "2025-08-12", LineNo: 6, PassThru: "MOON", Body: "garbage"},
+	}
+
+	events := ConvertJSONToEvents(entries, time.Local)
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events (unsupported and malformed specials dropped), got %d", len(events))
+	}
+
+	if events[0].Special != SpecialMoon || events[0].MoonPhase != MoonFull || events[0].Description != "Full moon" {
+		t.Errorf("Unexpected MOON event: %+v", events[0])
+	}
+	if events[1].Special != SpecialShade || events[1].Color == nil || *events[1].Color != (Color{R: 200, G: 220, B: 255}) {
+		t.Errorf("Unexpected SHADE event: %+v", events[1])
+	}
+	if events[2].Color == nil || *events[2].Color != (Color{R: 128, G: 128, B: 128}) {
+		t.Errorf("Expected gray SHADE, got %+v", events[2].Color)
+	}
+	if events[3].Special != SpecialWeek || events[3].Description != "(W33)" {
+		t.Errorf("Unexpected WEEK event: %+v", events[3])
+	}
+	for _, event := range events {
+		if !event.IsSpecial() {
+			t.Errorf("Expected %s to be special", event.ID)
+		}
+	}
+}
+
+func TestConvertJSONLocation(t *testing.T) {
+	entries := []RemindEntry{
+		{Date: "2025-08-25", LineNo: 1, Body: "Dentist @@Main_Street_Clinic checkup"},
+		{Date: "2025-08-25", LineNo: 2, Body: "Board meeting", Info: map[string]string{"Location": "HQ"}},
+		{Date: "2025-08-25", LineNo: 3, Body: "Email me@@example.com"},
+	}
+
+	events := ConvertJSONToEvents(entries, time.Local)
+	if events[0].Description != "Dentist checkup" || events[0].Location != "Main Street Clinic" {
+		t.Errorf("Unexpected @@ location: %q at %q", events[0].Description, events[0].Location)
+	}
+	if events[1].Location != "HQ" {
+		t.Errorf("Expected INFO location HQ, got %q", events[1].Location)
+	}
+	if events[2].Location != "" || events[2].Description != "Email me@@example.com" {
+		t.Errorf("@@ inside a word shouldn't be a location: %+v", events[2])
+	}
+}
+
+func TestConvertJSONRepeating(t *testing.T) {
+	data := `[{"monthname":"August","year":2025,"entries":[
+		{"date":"2025-08-25","lineno":1,"body":"Dentist","d":25,"m":8,"y":2025},
+		{"date":"2025-08-25","lineno":2,"body":"Standup","wd":["Monday"]},
+		{"date":"2025-08-25","lineno":3,"body":"Rent","d":25},
+		{"date":"2025-08-25","lineno":4,"body":"Water plants","d":18,"m":8,"y":2025,"rep":7}
+	]}]`
+
+	months, err := ParseRemindJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := ConvertJSONToEvents(months[0].Entries, time.Local)
+
+	expected := []bool{false, true, true, true}
+	for i, event := range events {
+		if event.IsRepeating != expected[i] {
+			t.Errorf("%s: IsRepeating = %v, want %v", event.Description, event.IsRepeating, expected[i])
+		}
+	}
+}
+
+func TestCompleteEvent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	content := "REM Aug 24 2025 MSG Call plumber\nREM Aug 24 2025 MSG Long \\\n  todo\nREM Mon MSG Standup\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.SetFiles([]string{file})
+	done := time.Date(2025, 8, 25, 9, 0, 0, 0, time.Local)
+	if err := client.CompleteEvent(Event{Filename: file, LineNumber: 3, Description: "Standup", IsRepeating: true}, done); err == nil {
+		t.Error("Expected error completing a repeating reminder")
+	}
+	if err := client.CompleteEvent(Event{Filename: file, LineNumber: 2, Description: "Long todo"}, done); err != nil {
+		t.Fatalf("CompleteEvent() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 24 2025 MSG Call plumber\n# DONE 2025-08-25: REM Aug 24 2025 MSG Long \\\n#   todo\nREM Mon MSG Standup\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+
+	if err := client.CompleteEvent(Event{Filename: file}, done); err == nil {
+		t.Error("Expected error for an event without a line number")
+	}
+}
+
+func TestQuickEventLine(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local) // Monday
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"tomorrow at 2pm Meeting", "REM Aug 26 2025 AT 14:00 MSG Meeting"},
+		{"Lunch next friday", "REM Sep 5 2025 MSG Lunch"},
+		{"at 9am", "REM Aug 25 2025 AT 09:00 MSG New reminder"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, line, err := QuickEventLine(tt.input, now, false)
+			if err != nil {
+				t.Fatalf("QuickEventLine() error: %v", err)
+			}
+			if line != tt.expected {
+				t.Errorf("QuickEventLine(%q) = %q, want %q", tt.input, line, tt.expected)
+			}
+		})
+	}
+
+	if _, _, err := QuickEventLine("   ", now, false); err == nil {
+		t.Error("Expected error for empty input")
+	}
+}
+
+func TestAddQuickEventUsesClock(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	client := NewClient()
+	client.Files = []string{file}
+	client.Clock = FixedClock(time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local))
+
+	if _, err := client.AddQuickEvent("tomorrow at 2pm Meeting"); err != nil {
+		t.Fatalf("AddQuickEvent() error: %v", err)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "REM Aug 26 2025 AT 14:00 MSG Meeting"; !strings.Contains(string(content), want) {
+		t.Errorf("file = %q, want it to contain %q", content, want)
+	}
+}
+
+func TestAddEventFromTemplateTo(t *testing.T) {
+	dir := t.TempDir()
+	client := NewClient()
+	client.Files = []string{filepath.Join(dir, "reminders.rem")}
+
+	work := filepath.Join(dir, "work.rem")
+	if err := os.WriteFile(work, []byte("REM Mon MSG Standup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	line, err := client.AddEventFromTemplateTo(work, []string{"@work", "review"}, `REM %mday% AT %hour%:%min% DURATION 1:00 MSG`, "Aug 25 2025", "14:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != 2 {
+		t.Errorf("line = %d, want 2", line)
+	}
+	data, _ := os.ReadFile(work)
+	expected := "REM Mon MSG Standup\nREM 25 AT 14:00 DURATION 1:00 TAG work TAG review MSG\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+	if _, err := os.Stat(client.Files[0]); !os.IsNotExist(err) {
+		t.Error("expected nothing written to the first remind file")
+	}
+}
+
+func TestAddSpanFromTemplateTo(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	client := NewClient()
+	template := `REM %monname% %mday% %year% AT %hour%:%min% +%dura% DURATION %dura%:00 MSG`
+	for _, length := range []time.Duration{105 * time.Minute, 15 * time.Minute, 0} {
+		if _, err := client.AddSpanFromTemplateTo(file, nil, template, "Aug 25 2025", "09:15", length); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 25 2025 AT 09:15 +2 DURATION 1:45 MSG\n" +
+		"REM Aug 25 2025 AT 09:15 +1 DURATION 0:15 MSG\n" +
+		"REM Aug 25 2025 AT 09:15 +1 DURATION 1:00 MSG\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	content := "REM Aug 25 2025 MSG Dentist\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient()
+	client.RemindPath = "urd-test-no-remind"
+	client.SetFiles([]string{file})
+	client.ReadOnly = true
+
+	event := Event{Date: time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local), Description: "Dentist", Filename: file, LineNumber: 1}
+	if _, err := client.AddQuickEvent("tomorrow Meeting"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddQuickEvent() error = %v, want ErrReadOnly", err)
+	}
+	if _, err := client.AddSpanFromTemplateTo(file, nil, "REM %monname% %mday% %year% MSG", "Aug 26 2025", "", 0); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddSpanFromTemplateTo() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.RemoveEvent(event); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RemoveEvent() error = %v, want ErrReadOnly", err)
+	}
+	if err := client.SetEventStatus(event, "done"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SetEventStatus() error = %v, want ErrReadOnly", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != content {
+		t.Errorf("expected the file unchanged, got %q", data)
+	}
+	if client.Capabilities() != (Capabilities{}) {
+		t.Errorf("expected no capabilities, got %+v", client.Capabilities())
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	tests := []struct {
+		line     string
+		tags     []string
+		expected string
+	}{
+		{`REM Aug 25 2025 MSG %"Lunch%"%`, []string{"food"}, `REM Aug 25 2025 TAG food MSG %"Lunch%"%`},
+		{"REM Mon RUN backup", []string{"@ops"}, "REM Mon TAG ops RUN backup"},
+		{"REM Mon", []string{"a", "b"}, "REM Mon TAG a TAG b"},
+		{"REM Mon MSG Standup", nil, "REM Mon MSG Standup"},
+	}
+	for _, tt := range tests {
+		if got := WithTags(tt.line, tt.tags); got != tt.expected {
+			t.Errorf("WithTags(%q, %q) = %q, want %q", tt.line, tt.tags, got, tt.expected)
+		}
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		file     string
+		line     int
+		quotes   string
+		expected []string
+	}{
+		{"vim", "vim +%line% %file%", "/home/me/.reminders", 12, `"'`, []string{"vim", "+12", "/home/me/.reminders"}},
+		{"new reminder", "vim +%line% %file%", "/home/me/.reminders", 0, `"'`, []string{"vim", "+999999", "/home/me/.reminders"}},
+		{"path with spaces", "code --wait -g %file%:%line%", `C:\Users\Jo Smith\reminders.rem`, 3, `"`, []string{"code", "--wait", "-g", `C:\Users\Jo Smith\reminders.rem:3`}},
+		{"quoted program", `"C:\Program Files\Vim\vim.exe" +%line% %file%`, `C:\rem\it's.rem`, 5, `"`, []string{`C:\Program Files\Vim\vim.exe`, "+5", `C:\rem\it's.rem`}},
+		{"apostrophe on windows", `notepad it's.rem`, "", 0, `"`, []string{"notepad", "it's.rem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := commandQuotes
+			commandQuotes = tt.quotes
+			defer func() { commandQuotes = saved }()
+
+			parts, err := EditorCommand(tt.command, tt.file, tt.line)
+			if err != nil {
+				t.Fatalf("EditorCommand() error: %v", err)
+			}
+			if !slices.Equal(parts, tt.expected) {
+				t.Errorf("EditorCommand(%q) = %q, want %q", tt.command, parts, tt.expected)
+			}
+		})
+	}
+
+	if _, err := EditorCommand("  ", "file", 1); err == nil {
+		t.Error("Expected error for empty command")
+	}
+}
+
+func TestClockFrom(t *testing.T) {
+	start := time.Date(2025, 8, 25, 9, 40, 0, 0, time.Local)
+	clock := ClockFrom(start)
+	if now := clock.Now(); now.Before(start) || now.After(start.Add(time.Minute)) {
+		t.Errorf("Now() = %v, want just after %v", now, start)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.rem")
+	work := filepath.Join(dir, "work.rem")
+	script := filepath.Join(dir, "remind")
+	stderr := main + "(3): Expecting number\\n" + work + "(7): Unknown token\\n"
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '"+stderr+"' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.RemindPath = script
+	client.SetFiles([]string{main})
+
+	// The error in the edited file wins
+	err := client.CheckFile(work)
+	var syntaxErr *RemindSyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.File != work || syntaxErr.Line != 7 {
+		t.Fatalf("expected the error in work.rem, got %v", err)
+	}
+	if err := client.CheckFile(filepath.Join(dir, "other.rem")); !errors.As(err, &syntaxErr) || syntaxErr.File != main {
+		t.Errorf("expected the first error otherwise, got %v", err)
+	}
+
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'No reminders.'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CheckFile(main); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
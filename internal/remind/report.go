@@ -0,0 +1,298 @@
+package remind
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WeekReport summarizes a week for weekly reviews: the events of the week
+// past, the reminders done in it and the highlights of the week to come
+type WeekReport struct {
+	Start      time.Time     // First day of the week past
+	Events     int           // Timed events of the week past, cancelled ones left out
+	Scheduled  time.Duration // How long they lasted together
+	Tags       []TagHours    // The same by tag, longest first
+	Done       []DoneItem    // Reminders marked done in the week past
+	Highlights []Event       // One-off and prioritized events of the week to come
+}
+
+// TagHours is how many timed events had a tag and how long they lasted
+type TagHours struct {
+	Tag       string // Empty for the events without tags
+	Events    int
+	Scheduled time.Duration
+}
+
+// DoneItem is a reminder commented out as done, see Client.CompleteEvent
+type DoneItem struct {
+	Date        time.Time
+	Description string
+	Private     bool // Tagged PRIVATE
+}
+
+// End returns the first day of the week to come
+func (r WeekReport) End() time.Time {
+	return r.Start.AddDate(0, 0, 7)
+}
+
+// NewWeekReport summarizes the week from start, with the events of the
+// week past and of the week to come, and the reminders done
+func NewWeekReport(start time.Time, past, coming []Event, done []DoneItem) WeekReport {
+	report := WeekReport{Start: start}
+	byTag := make(map[string]*TagHours)
+	for _, event := range past {
+		if event.IsSpecial() || event.IsCancelled() || event.Time == nil {
+			continue
+		}
+		var length time.Duration
+		if event.Duration != nil {
+			length = *event.Duration
+		}
+		report.Events++
+		report.Scheduled += length
+
+		tags := reportTags(event)
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+		for _, tag := range tags {
+			hours, ok := byTag[tag]
+			if !ok {
+				hours = &TagHours{Tag: tag}
+				byTag[tag] = hours
+			}
+			hours.Events++
+			hours.Scheduled += length
+		}
+	}
+	for _, hours := range byTag {
+		report.Tags = append(report.Tags, *hours)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		a, b := report.Tags[i], report.Tags[j]
+		if a.Scheduled != b.Scheduled {
+			return a.Scheduled > b.Scheduled
+		}
+		return a.Tag < b.Tag
+	})
+
+	end := report.End()
+	for _, item := range done {
+		if !item.Date.Before(start) && item.Date.Before(end) {
+			report.Done = append(report.Done, item)
+		}
+	}
+	sort.SliceStable(report.Done, func(i, j int) bool {
+		return report.Done[i].Date.Before(report.Done[j].Date)
+	})
+
+	for _, event := range coming {
+		if event.IsSpecial() || event.IsCancelled() || (event.IsRepeating && event.Priority == PriorityNone) {
+			continue
+		}
+		report.Highlights = append(report.Highlights, event)
+	}
+	sortByStart(report.Highlights)
+	return report
+}
+
+// reportTags returns the tags events count towards, without the status
+func reportTags(event Event) []string {
+	var tags []string
+	for _, tag := range event.Tags {
+		tag = strings.TrimPrefix(tag, "@")
+		if !strings.HasPrefix(strings.ToLower(tag), statusTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// doneLine matches a reminder commented out as done, see CompleteEvent
+var doneLine = regexp.MustCompile(`^# DONE (\d{4}-\d{2}-\d{2}): (.*)$`)
+
+// ReadDone returns the reminders marked done in files
+func ReadDone(files []string) ([]DoneItem, error) {
+	var done []DoneItem
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		var item *DoneItem
+		var line string
+		for scanner.Scan() {
+			text := strings.TrimRight(scanner.Text(), "\r")
+			if item != nil {
+				// A continuation line, commented out along with the reminder
+				line += " " + strings.TrimPrefix(text, "# ")
+			} else if matches := doneLine.FindStringSubmatch(text); matches != nil {
+				date, err := time.ParseInLocation(time.DateOnly, matches[1], time.Local)
+				if err != nil {
+					continue
+				}
+				item, line = &DoneItem{Date: date}, matches[2]
+			} else {
+				continue
+			}
+
+			if strings.HasSuffix(line, "\\") {
+				line = strings.TrimSuffix(line, "\\")
+				continue
+			}
+			item.Description, item.Private = doneDescription(line)
+			done = append(done, *item)
+			item = nil
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return done, nil
+}
+
+// doneDescription returns the message of a reminder marked done, the
+// whole line when it has none, and whether it's private
+func doneDescription(line string) (string, bool) {
+	tokens := tokenizeTrigger(line)
+	private := false
+	for i := 0; i+1 < len(tokens); i++ {
+		private = private || strings.EqualFold(tokens[i].text, "TAG") && strings.EqualFold(tokens[i+1].text, PrivateTag)
+	}
+	if n := len(tokens); n > 0 && bodyKeywords[strings.ToUpper(tokens[n-1].text)] {
+		last := tokens[n-1]
+		return strings.TrimSpace(line[last.offset+len(last.text):]), private
+	}
+	return strings.TrimSpace(line), private
+}
+
+// WriteMarkdown writes the report as Markdown
+func (r WeekReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Week of %s\n\n", r.Start.Format("Jan 2, 2006"))
+
+	fmt.Fprintf(&b, "## %s - %s\n\n", r.Start.Format("Mon Jan 2"), r.End().AddDate(0, 0, -1).Format("Mon Jan 2"))
+	fmt.Fprintf(&b, "%d events, %s scheduled\n\n", r.Events, reportDuration(r.Scheduled))
+	if len(r.Tags) > 0 {
+		b.WriteString("| Tag | Events | Time |\n|---|---:|---:|\n")
+		for _, tag := range r.Tags {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", markdownEscape(tag.Label()), tag.Events, reportDuration(tag.Scheduled))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Done\n\n")
+	if len(r.Done) == 0 {
+		b.WriteString("Nothing marked done.\n")
+	}
+	for _, item := range r.Done {
+		fmt.Fprintf(&b, "- [x] %s %s\n", item.Date.Format("Mon Jan 2"), markdownEscape(item.Description))
+	}
+
+	b.WriteString("\n## Coming up\n\n")
+	if len(r.Highlights) == 0 {
+		b.WriteString("Nothing planned.\n")
+	}
+	for _, event := range r.Highlights {
+		fmt.Fprintf(&b, "- %s %s\n", reportWhen(event), markdownEscape(event.Description))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// reportHTML is the page WriteHTML writes
+var reportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": reportDuration,
+	"when":     reportWhen,
+	"day":      func(t time.Time) string { return t.Format("Mon Jan 2") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Week of {{.Start.Format "Jan 2, 2006"}}</title>
+</head>
+<body>
+<h1>Week of {{.Start.Format "Jan 2, 2006"}}</h1>
+<h2>{{day .Start}} - {{day (.End.AddDate 0 0 -1)}}</h2>
+<p>{{.Events}} events, {{duration .Scheduled}} scheduled</p>
+{{- if .Tags}}
+<table>
+<tr><th>Tag</th><th>Events</th><th>Time</th></tr>
+{{- range .Tags}}
+<tr><td>{{.Label}}</td><td>{{.Events}}</td><td>{{duration .Scheduled}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Done</h2>
+{{- if .Done}}
+<ul>
+{{- range .Done}}
+<li>{{day .Date}} {{.Description}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>Nothing marked done.</p>
+{{- end}}
+<h2>Coming up</h2>
+{{- if .Highlights}}
+<ul>
+{{- range .Highlights}}
+<li>{{when .}} {{.Description}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>Nothing planned.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as an HTML page, for email
+func (r WeekReport) WriteHTML(w io.Writer) error {
+	return reportHTML.Execute(w, r)
+}
+
+// Label names the tag in reports
+func (t TagHours) Label() string {
+	if t.Tag == "" {
+		return "(untagged)"
+	}
+	return t.Tag
+}
+
+// reportDuration formats a duration in hours and minutes, like 12h 30m
+func reportDuration(d time.Duration) string {
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// reportWhen formats when an event takes place in reports
+func reportWhen(event Event) string {
+	when := event.Date.Format("Mon Jan 2")
+	if event.Time != nil {
+		when += " " + event.Time.Format("15:04")
+	}
+	return when
+}
+
+// markdownEscape keeps text from being read as Markdown
+func markdownEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;").Replace(text)
+}
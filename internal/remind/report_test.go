@@ -0,0 +1,102 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeekReport(t *testing.T) {
+	start := time.Date(2025, 8, 18, 0, 0, 0, 0, time.Local)
+	at := func(day, hour int, minutes time.Duration, description string, tags ...string) Event {
+		date := start.AddDate(0, 0, day)
+		when := date.Add(time.Duration(hour) * time.Hour)
+		length := minutes * time.Minute
+		return Event{Date: date, Time: &when, Duration: &length, Description: description, Tags: tags}
+	}
+	past := []Event{
+		at(0, 9, 90, "Planning", "work"),
+		at(1, 9, 30, "Standup", "work", "status:tentative"),
+		at(2, 18, 60, "Run"),
+		at(3, 14, 60, "Cancelled sync", "work", "status:cancelled"),
+		{Date: start, Description: "Pay rent"},
+	}
+	standup := at(7, 9, 15, "Standup", "work")
+	standup.IsRepeating = true
+	coming := []Event{
+		standup,
+		at(8, 11, 60, "Offsite"),
+		{Date: start.AddDate(0, 0, 9), Description: "Proposal due", IsRepeating: true, Priority: PriorityHigh},
+	}
+	done := []DoneItem{
+		{Date: start.AddDate(0, 0, 2), Description: "File taxes"},
+		{Date: start.AddDate(0, 0, -1), Description: "Done before the week"},
+	}
+
+	report := NewWeekReport(start, past, coming, done)
+	if report.Events != 3 || report.Scheduled != 3*time.Hour {
+		t.Errorf("expected 3 events and 3h, got %d and %s", report.Events, report.Scheduled)
+	}
+	if len(report.Tags) != 2 || report.Tags[0].Tag != "work" || report.Tags[0].Scheduled != 2*time.Hour || report.Tags[1].Label() != "(untagged)" {
+		t.Errorf("unexpected tags %+v", report.Tags)
+	}
+	if len(report.Done) != 1 || report.Done[0].Description != "File taxes" {
+		t.Errorf("unexpected done %+v", report.Done)
+	}
+	if len(report.Highlights) != 2 || report.Highlights[0].Description != "Offsite" || report.Highlights[1].Description != "Proposal due" {
+		t.Errorf("unexpected highlights %+v", report.Highlights)
+	}
+
+	var markdown strings.Builder
+	if err := report.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"# Week of Aug 18, 2025", "3 events, 3h scheduled", "| work | 2 | 2h |", "- [x] Wed Aug 20 File taxes", "- Tue Aug 26 11:00 Offsite"} {
+		if !strings.Contains(markdown.String(), expected) {
+			t.Errorf("expected %q in:\n%s", expected, markdown.String())
+		}
+	}
+
+	report.Highlights[0].Description = "<b>Offsite</b>"
+	var html strings.Builder
+	if err := report.WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<td>work</td><td>2</td><td>2h</td>") || !strings.Contains(html.String(), "&lt;b&gt;Offsite") {
+		t.Errorf("unexpected HTML:\n%s", html.String())
+	}
+}
+
+func TestReadDone(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	content := `REM Aug 25 2025 MSG Still to do
+# DONE 2025-08-20: REM Aug 20 2025 TAG PRIVATE MSG See the doctor
+# DONE 2025-08-21: REM Aug 21 2025 AT 10:00 \
+#   MSG File taxes
+# A comment
+# DONE 2025-08-22: OMIT Aug 22 2025
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := ReadDone([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DoneItem{
+		{Date: time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local), Description: "See the doctor", Private: true},
+		{Date: time.Date(2025, 8, 21, 0, 0, 0, 0, time.Local), Description: "File taxes"},
+		{Date: time.Date(2025, 8, 22, 0, 0, 0, 0, time.Local), Description: "OMIT Aug 22 2025"},
+	}
+	if len(done) != len(expected) {
+		t.Fatalf("expected %d items, got %+v", len(expected), done)
+	}
+	for i := range expected {
+		if !done[i].Date.Equal(expected[i].Date) || done[i].Description != expected[i].Description || done[i].Private != expected[i].Private {
+			t.Errorf("item %d = %+v, want %+v", i, done[i], expected[i])
+		}
+	}
+}
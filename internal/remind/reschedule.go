@@ -0,0 +1,219 @@
+package remind
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bodyKeywords start the body of a reminder, after its trigger
+var bodyKeywords = map[string]bool{
+	"MSG": true, "MSF": true, "RUN": true, "CAL": true,
+	"SPECIAL": true, "PS": true, "PSFILE": true,
+}
+
+// triggerArgs are trigger keywords followed by one argument
+var triggerArgs = map[string]bool{
+	"PRIORITY": true, "TAG": true, "INFO": true, "SCHED": true, "WARN": true,
+	"UNTIL": true, "FROM": true, "SCANFROM": true, "OMITFUNC": true,
+}
+
+var (
+	isoDateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(@\d{1,2}:\d{2})?$`)
+	timeRegex    = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+	deltaRegex   = regexp.MustCompile(`^(\+\+?|\*)\d+$`)
+)
+
+// isDateToken reports whether a trigger word is part of a date: a month or
+// weekday, abbreviated to at least three letters, a day of month, year or
+// ISO date
+func isDateToken(word string) bool {
+	if isoDateRegex.MatchString(word) {
+		return true
+	}
+	if n, err := strconv.Atoi(word); err == nil && word[0] != '+' && word[0] != '-' {
+		return n >= 1 && n <= 31 || n >= 1990 && n <= 9999
+	}
+	word = strings.ToUpper(word)
+	if _, ok := parseMonth(word); ok {
+		return true
+	}
+	_, ok := parseWeekday(word)
+	return ok
+}
+
+// matchesEvent reports whether reminder, with its continuation lines
+// joined, still is the REM line event was generated from: its body has the
+// words of the event's description, ignoring tags and @@places, up to the
+// first substitution, which changes the words that follow
+func matchesEvent(reminder string, event Event) bool {
+	tokens := tokenizeTrigger(reminder)
+	if len(tokens) < 2 || !strings.EqualFold(tokens[0].text, "REM") {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	if !bodyKeywords[strings.ToUpper(last.text)] {
+		return false
+	}
+
+	body := reminder[last.offset+len(last.text):]
+	substituted := false
+	if i := strings.IndexByte(body, '%'); i >= 0 {
+		body, substituted = body[:i], true
+	}
+	bodyWords, descWords := messageWords(body), messageWords(event.Description)
+	if substituted {
+		return len(bodyWords) <= len(descWords) && slices.Equal(bodyWords, descWords[:len(bodyWords)])
+	}
+	return slices.Equal(bodyWords, descWords)
+}
+
+// messageWords returns the words of a reminder message as compared by
+// matchesEvent, without @tags and @@places
+func messageWords(message string) []string {
+	var kept []string
+	for _, word := range strings.Fields(message) {
+		if !strings.HasPrefix(word, "@") {
+			kept = append(kept, word)
+		}
+	}
+	return descriptionWords(strings.Join(kept, " "))
+}
+
+// remToken is a word of a REM line and where it starts
+type remToken struct {
+	text   string
+	offset int
+}
+
+// tokenizeTrigger splits a REM line into words, keeping quoted strings
+// together, up to and including the keyword that starts its body
+func tokenizeTrigger(line string) []remToken {
+	var tokens []remToken
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		inQuote := false
+		for i < len(line) && (inQuote || line[i] != ' ' && line[i] != '\t') {
+			if line[i] == '"' {
+				inQuote = !inQuote
+			}
+			i++
+		}
+		tokens = append(tokens, remToken{line[start:i], start})
+		if bodyKeywords[strings.ToUpper(line[start:i])] {
+			break
+		}
+	}
+	return tokens
+}
+
+// RescheduleLine rewrites the trigger of a one-off REM line to fire on date,
+// at the given time and for the given duration if not nil. The date it
+// starts with is replaced; everything else in the trigger, like PRIORITY,
+// TAG and the dates of OMIT and UNTIL, and the body are kept.
+func RescheduleLine(line string, date time.Time, at *time.Time, duration *time.Duration) (string, error) {
+	tokens := tokenizeTrigger(line)
+	if len(tokens) < 2 || !strings.EqualFold(tokens[0].text, "REM") {
+		return "", fmt.Errorf("%w: not a REM line", ErrParse)
+	}
+	last := tokens[len(tokens)-1]
+	if !bodyKeywords[strings.ToUpper(last.text)] {
+		return "", fmt.Errorf("%w: REM line has no MSG", ErrParse)
+	}
+
+	var kept []string
+	trigger := tokens[1 : len(tokens)-1]
+	for i := dateRun(trigger); i < len(trigger); i++ {
+		word := trigger[i].text
+		switch keyword := strings.ToUpper(word); {
+		case keyword == "AT":
+			// Drop the time along with its delta and repeat
+			for i+1 < len(trigger) && (timeRegex.MatchString(trigger[i+1].text) || deltaRegex.MatchString(trigger[i+1].text)) {
+				i++
+			}
+		case keyword == "DURATION":
+			i++
+		case triggerArgs[keyword]:
+			kept = append(kept, word)
+			if i+1 < len(trigger) {
+				i++
+				kept = append(kept, trigger[i].text)
+			}
+		default:
+			kept = append(kept, word)
+		}
+	}
+
+	parts := []string{tokens[0].text, date.Format("Jan 2 2006")}
+	if at != nil {
+		parts = append(parts, "AT", at.Format("15:04"))
+		if duration != nil && *duration > 0 {
+			totalMin := int(duration.Minutes())
+			parts = append(parts, "DURATION", fmt.Sprintf("%d:%.2d", totalMin/60, totalMin%60))
+		}
+	}
+	parts = append(parts, kept...)
+	return strings.Join(parts, " ") + " " + line[last.offset:], nil
+}
+
+// RescheduleEvent moves a one-off reminder to another date, and time if at
+// is not nil, by rewriting its trigger in place
+func (c *Client) RescheduleEvent(event Event, date time.Time, at *time.Time, duration *time.Duration) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if event.IsRepeating {
+		return fmt.Errorf("repeating reminders can't be rescheduled")
+	}
+	return rewriteReminder(event, func(line string) (string, error) {
+		return RescheduleLine(line, date, at, duration)
+	})
+}
+
+// readReminder reads the file of event and returns its lines, the reminder
+// the event was generated from with continuation lines joined, and the index
+// of its last line. ErrWriteConflict is returned when the file changed and
+// another reminder is at the event's line.
+func readReminder(event Event) (lines []string, reminder string, end int, err error) {
+	if lines, err = sourceLines(event); err != nil {
+		return nil, "", 0, err
+	}
+	reminder, end = reminderAt(lines, event.LineNumber)
+	if !matchesEvent(reminder, event) {
+		return nil, "", 0, fmt.Errorf("%w: line %d of %s is no longer %q", ErrWriteConflict, event.LineNumber, event.Filename, event.Description)
+	}
+	return lines, reminder, end, nil
+}
+
+// rewriteReminder replaces the reminder an event was generated from with
+// what rewrite makes of it
+func rewriteReminder(event Event, rewrite func(line string) (string, error)) error {
+	lines, reminder, end, err := readReminder(event)
+	if err != nil {
+		return err
+	}
+
+	// The rewritten reminder takes a single line
+	rewritten, err := rewrite(reminder)
+	if err != nil {
+		return err
+	}
+
+	// Continuation lines are joined, moving the reminders after them up,
+	// see SourceLineCount
+	lines = append(lines[:event.LineNumber-1], append([]string{rewritten}, lines[end+1:]...)...)
+	if err := os.WriteFile(event.Filename, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write updated remind file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRescheduleLine(t *testing.T) {
+	date := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := time.Date(2025, 8, 25, 14, 30, 0, 0, time.Local)
+	hour := time.Hour
+
+	tests := []struct {
+		line     string
+		at       *time.Time
+		duration *time.Duration
+		expected string
+	}{
+		{"REM Aug 24 2025 MSG Call plumber", nil, nil, "REM Aug 25 2025 MSG Call plumber"},
+		{"REM 24 Aug 2025 PRIORITY 9000 TAG work MSG Report %b", &at, &hour, "REM Aug 25 2025 AT 14:30 DURATION 1:00 PRIORITY 9000 TAG work MSG Report %b"},
+		{"REM 2025-08-24 AT 9:00 +15 DURATION 0:30 MSG Sync", nil, nil, "REM Aug 25 2025 MSG Sync"},
+		{`REM Sun Aug 24 2025 +2 INFO "Location: HQ 2" MSG Visit`, &at, nil, `REM Aug 25 2025 AT 14:30 +2 INFO "Location: HQ 2" MSG Visit`},
+		// Only the leading date is replaced
+		{"REM Aug 24 2025 OMIT Sat Sun SATISFY 1 MSG Sync", nil, nil, "REM Aug 25 2025 OMIT Sat Sun SATISFY 1 MSG Sync"},
+		{"REM Aug 24 2025 UNTIL Aug 30 2025 MAYBE-UNCOMPUTABLE MSG Sync", nil, nil, "REM Aug 25 2025 UNTIL Aug 30 2025 MAYBE-UNCOMPUTABLE MSG Sync"},
+	}
+
+	for _, tt := range tests {
+		got, err := RescheduleLine(tt.line, date, tt.at, tt.duration)
+		if err != nil {
+			t.Errorf("RescheduleLine(%q) error: %v", tt.line, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("RescheduleLine(%q) = %q, want %q", tt.line, got, tt.expected)
+		}
+	}
+
+	for _, line := range []string{"OMIT Dec 25", "REM Aug 24 2025"} {
+		if _, err := RescheduleLine(line, date, nil, nil); err == nil {
+			t.Errorf("RescheduleLine(%q) expected error", line)
+		}
+	}
+}
+
+func TestMatchesEvent(t *testing.T) {
+	tests := []struct {
+		line        string
+		description string
+		want        bool
+	}{
+		{"REM Aug 24 2025 MSG Dentist", "Dentist", true},
+		{"rem Mon at 9:00 msg  Team   standup", "Team standup", true},
+		{"REM Mon MSG Standup @work @@Room_4", "Standup", true},
+		{"REM Aug 24 2025 MSG Urgent task!!", "Urgent task", true},
+		{"REM Aug 24 2025 MSG Call %\"Bob%\" back", "Call Bob back", true},
+		{"REM Aug 24 2025 MSG %b: dentist", "in 2 days: dentist", true},
+		{"REM Aug 24 2025 MSG Dentist", "Dentist appointment", false},
+		{"REM Aug 24 2025 MSG Call %\"Bob%\"", "Email Bob", false},
+		{"REM Aug 24 2025 MSG Lunch", "Dentist", false},
+		{"OMIT Dec 25 MSG Christmas", "Christmas", false},
+		{"# REM Aug 24 2025 MSG Dentist", "Dentist", false},
+	}
+	for _, tt := range tests {
+		if got := matchesEvent(tt.line, Event{Description: tt.description}); got != tt.want {
+			t.Errorf("matchesEvent(%q, %q) = %v, want %v", tt.line, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestRescheduleEvent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	content := "REM Aug 24 2025 MSG Long \\\n  todo\nREM Mon MSG Standup\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.SetFiles([]string{file})
+	date := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	if err := client.RescheduleEvent(Event{Filename: file, LineNumber: 1, Description: "Long todo"}, date, nil, nil); err != nil {
+		t.Fatalf("RescheduleEvent() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if expected := "REM Aug 25 2025 MSG Long   todo\nREM Mon MSG Standup\n"; string(data) != expected {
+		t.Errorf("got %q, want %q", data, expected)
+	}
+
+	if err := client.RescheduleEvent(Event{Filename: file, LineNumber: 2, IsRepeating: true}, date, nil, nil); err == nil {
+		t.Error("Expected error rescheduling a repeating reminder")
+	}
+}
@@ -0,0 +1,94 @@
+package remind
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statuses an event can be given with a status: tag, besides the confirmed
+// events that have none
+const (
+	StatusConfirmed = ""
+	StatusTentative = "tentative"
+	StatusCancelled = "cancelled"
+)
+
+// Statuses are the statuses cycle_status goes through, in order
+var Statuses = []string{StatusConfirmed, StatusTentative, StatusCancelled}
+
+// statusTagPrefix starts the tag giving an event its status, as in
+// TAG status:tentative
+const statusTagPrefix = "status:"
+
+// Status returns the status an event is tagged with, lowercased, or
+// StatusConfirmed when it has none
+func (e Event) Status() string {
+	for _, tag := range e.Tags {
+		if value, ok := strings.CutPrefix(strings.ToLower(strings.TrimPrefix(tag, "@")), statusTagPrefix); ok {
+			return value
+		}
+	}
+	return StatusConfirmed
+}
+
+// IsCancelled reports whether the event is tagged status:cancelled
+func (e Event) IsCancelled() bool {
+	return e.Status() == StatusCancelled
+}
+
+// NextStatus returns the status after status in Statuses, going back to
+// confirmed after the last or from one it doesn't know
+func NextStatus(status string) string {
+	for i, s := range Statuses {
+		if s == status && i+1 < len(Statuses) {
+			return Statuses[i+1]
+		}
+	}
+	return StatusConfirmed
+}
+
+// StatusLine rewrites the status tag of a REM line, removing it for
+// StatusConfirmed. Its other tags and the body are kept.
+func StatusLine(line, status string) (string, error) {
+	tokens := tokenizeTrigger(line)
+	if len(tokens) < 2 || !strings.EqualFold(tokens[0].text, "REM") {
+		return "", fmt.Errorf("%w: not a REM line", ErrParse)
+	}
+
+	// Cut the TAG status:... clauses along with the space before them
+	var b strings.Builder
+	end := 0
+	for i := 0; i+1 < len(tokens); i++ {
+		value := strings.ToLower(strings.TrimPrefix(tokens[i+1].text, "@"))
+		if !strings.EqualFold(tokens[i].text, "TAG") || !strings.HasPrefix(value, statusTagPrefix) {
+			continue
+		}
+		start := tokens[i].offset
+		for start > end && (line[start-1] == ' ' || line[start-1] == '\t') {
+			start--
+		}
+		b.WriteString(line[end:start])
+		end = tokens[i+1].offset + len(tokens[i+1].text)
+		i++
+	}
+	b.WriteString(line[end:])
+
+	if status == StatusConfirmed {
+		return b.String(), nil
+	}
+	return WithTags(b.String(), []string{statusTagPrefix + status}), nil
+}
+
+// SetEventStatus tags the one-off reminder an event comes from with status.
+// Repeating reminders are refused, as every occurrence would get it.
+func (c *Client) SetEventStatus(event Event, status string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if event.IsRepeating {
+		return fmt.Errorf("repeating reminders can't change status for one occurrence")
+	}
+	return rewriteReminder(event, func(line string) (string, error) {
+		return StatusLine(line, status)
+	})
+}
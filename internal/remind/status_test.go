@@ -0,0 +1,71 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		status   string
+		expected string
+	}{
+		{"REM Aug 25 2025 AT 10:00 MSG Sync", StatusTentative, "REM Aug 25 2025 AT 10:00 TAG status:tentative MSG Sync"},
+		{"REM Aug 25 2025 TAG work TAG status:tentative MSG Sync", StatusCancelled, "REM Aug 25 2025 TAG work TAG status:cancelled MSG Sync"},
+		{"REM Mon TAG STATUS:Cancelled TAG work MSG Standup", StatusConfirmed, "REM Mon TAG work MSG Standup"},
+		{"REM Mon MSG status: TAG status:x is only a message", StatusConfirmed, "REM Mon MSG status: TAG status:x is only a message"},
+	}
+	for _, tt := range tests {
+		got, err := StatusLine(tt.line, tt.status)
+		if err != nil {
+			t.Errorf("StatusLine(%q) error: %v", tt.line, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("StatusLine(%q, %q) = %q, want %q", tt.line, tt.status, got, tt.expected)
+		}
+	}
+
+	if _, err := StatusLine("OMIT Dec 25", StatusTentative); err == nil {
+		t.Error("expected an error for a line that isn't a REM")
+	}
+}
+
+func TestEventStatus(t *testing.T) {
+	event := Event{Tags: []string{"work", "Status:Tentative"}}
+	if event.Status() != StatusTentative || event.IsCancelled() {
+		t.Errorf("Status() = %q", event.Status())
+	}
+	if (Event{}).Status() != StatusConfirmed {
+		t.Error("expected an event without a status tag to be confirmed")
+	}
+
+	status := StatusConfirmed
+	for _, expected := range []string{StatusTentative, StatusCancelled, StatusConfirmed} {
+		if status = NextStatus(status); status != expected {
+			t.Errorf("NextStatus = %q, want %q", status, expected)
+		}
+	}
+}
+
+func TestSetEventStatus(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	if err := os.WriteFile(file, []byte("REM Mon AT 9:00 \\\n  MSG Standup\nREM Tue MSG Other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient()
+	client.SetFiles([]string{file})
+	if err := client.SetEventStatus(Event{Filename: file, LineNumber: 1, IsRepeating: true}, StatusCancelled); err == nil {
+		t.Error("expected the status of a repeating reminder left alone")
+	}
+	if err := client.SetEventStatus(Event{Filename: file, LineNumber: 1, Description: "Standup"}, StatusCancelled); err != nil {
+		t.Fatalf("SetEventStatus() error: %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	if expected := "REM Mon AT 9:00   TAG status:cancelled MSG Standup\nREM Tue MSG Other\n"; string(data) != expected {
+		t.Errorf("file = %q, want %q", data, expected)
+	}
+}
@@ -12,6 +12,7 @@ import (
 type TimeParser struct {
 	Now      time.Time
 	Location *time.Location
+	DayFirst bool // Read 03/04 as April 3 rather than March 4
 }
 
 type ParsedEvent struct {
@@ -68,6 +69,11 @@ func (p *TimeParser) ParseDateOnly(input string) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("empty input")
 	}
 
+	// Offsets like +3w only as the whole input, in text "+30m" is no date
+	if matches := relativeDateRe.FindStringSubmatch(input); matches != nil {
+		return p.relativeDate(matches[1], matches[2]), nil
+	}
+
 	// Extract time first (can appear anywhere) but ignore it for date-only parsing
 	_, _, _, remaining := p.extractTime(input)
 
@@ -81,16 +87,53 @@ func (p *TimeParser) ParseDateOnly(input string) (time.Time, error) {
 	return date, nil
 }
 
+// relativeDateRe matches offsets from today like "+3w", "-2d", "+1m", "+1y"
+var relativeDateRe = regexp.MustCompile(`(?i)^([+-]\d+)\s*([dwmy])$`)
+
+// relativeDate returns today moved by n days, weeks, months or years
+func (p *TimeParser) relativeDate(n, unit string) time.Time {
+	today := time.Date(p.Now.Year(), p.Now.Month(), p.Now.Day(), 0, 0, 0, 0, p.Location)
+	count, _ := strconv.Atoi(n)
+	switch strings.ToLower(unit) {
+	case "w":
+		return today.AddDate(0, 0, 7*count)
+	case "m":
+		return addMonths(today, count)
+	case "y":
+		return addMonths(today, 12*count)
+	default:
+		return today.AddDate(0, 0, count)
+	}
+}
+
+// atTimeRe and timeRe match times with and without "at", see extractTime
+var (
+	atTimeRe = regexp.MustCompile(`\bat\s+(\d{1,2}):?(\d{2})?\s*(am|pm)?\b`)
+	timeRe   = regexp.MustCompile(`\b(\d{1,2}):(\d{2})\s*(am|pm)?\b|\b(\d{1,2})\s*(am|pm)\b`)
+)
+
+// WithTime returns input with its time changed to hour:minute, or the time
+// added when it has none
+func WithTime(input string, hour, minute int) string {
+	at := fmt.Sprintf("%02d:%02d", hour, minute)
+	lower := strings.ToLower(input)
+	for _, re := range []*regexp.Regexp{atTimeRe, timeRe} {
+		if loc := re.FindStringIndex(lower); loc != nil {
+			end := loc[0] + len(strings.TrimRight(lower[loc[0]:loc[1]], " \t"))
+			return input[:loc[0]] + at + input[end:]
+		}
+	}
+	return strings.TrimSpace(input) + " " + at
+}
+
 // extractTime looks for time patterns anywhere in the input and returns the time and remaining text
 func (p *TimeParser) extractTime(input string) (found bool, hour int, minute int, remaining string) {
 	// Look for patterns like "at 2pm", "at 14:30", "at 2:30pm", "2pm", "14:30"
 	// Try "at TIME" pattern first
-	atTimeRe := regexp.MustCompile(`\bat\s+(\d{1,2}):?(\d{2})?\s*(am|pm)?\b`)
 	matches := atTimeRe.FindStringSubmatch(strings.ToLower(input))
 
 	if matches == nil {
 		// Try just TIME pattern without "at"
-		timeRe := regexp.MustCompile(`\b(\d{1,2}):(\d{2})\s*(am|pm)?\b|\b(\d{1,2})\s*(am|pm)\b`)
 		matches = timeRe.FindStringSubmatch(strings.ToLower(input))
 		if matches != nil {
 			// Adjust match indices for different regex groups
@@ -156,11 +199,38 @@ func (p *TimeParser) extractTime(input string) (found bool, hour int, minute int
 func (p *TimeParser) ExtractDate(input string) (found bool, date time.Time, remaining string) {
 	today := time.Date(p.Now.Year(), p.Now.Month(), p.Now.Day(), 0, 0, 0, 0, p.Location)
 
-	// Try each date pattern and find which one matches
+	// Try each date pattern and find which one matches. A handler returns
+	// the zero time when what matched isn't a date.
 	patterns := []struct {
 		regex   *regexp.Regexp
 		handler func([]string) time.Time
 	}{
+		{
+			// ISO week "2025-W40", optionally with weekday "2025-W40-3"
+			regex: regexp.MustCompile(`(?i)\b(\d{4})-W(\d{1,2})(?:-([1-7]))?\b`),
+			handler: func(m []string) time.Time {
+				year, _ := strconv.Atoi(m[1])
+				week, _ := strconv.Atoi(m[2])
+				weekday := 1
+				if m[3] != "" {
+					weekday, _ = strconv.Atoi(m[3])
+				}
+				if week < 1 || week > isoWeeks(year) {
+					return time.Time{}
+				}
+				return isoWeekDate(year, week, weekday, p.Location)
+			},
+		},
+		{
+			// "eom" and "eoy" - end of month and end of year
+			regex: regexp.MustCompile(`(?i)\b(eom|eoy)\b`),
+			handler: func(m []string) time.Time {
+				if strings.ToLower(m[1]) == "eoy" {
+					return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, p.Location)
+				}
+				return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, p.Location)
+			},
+		},
 		{
 			// "tomorrow"
 			regex: regexp.MustCompile(`(?i)\btomorrow\b`),
@@ -183,8 +253,8 @@ func (p *TimeParser) ExtractDate(input string) (found bool, date time.Time, rema
 			},
 		},
 		{
-			// "next monday", "this friday", etc
-			regex: regexp.MustCompile(`(?i)\b(next|this)\s+(monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`),
+			// "next monday", "this friday", "next fri", etc
+			regex: regexp.MustCompile(`(?i)\b(next|this)\s+(monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tues|tue|wed|thurs|thur|thu|fri|sat|sun)\b`),
 			handler: func(m []string) time.Time {
 				isNext := strings.ToLower(m[1]) == "next"
 				weekday := p.parseWeekday(m[2])
@@ -210,21 +280,19 @@ func (p *TimeParser) ExtractDate(input string) (found bool, date time.Time, rema
 			},
 		},
 		{
-			// MM/DD/YYYY format
+			// MM/DD/YYYY format, DD/MM/YYYY when DayFirst
 			regex: regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4})\b`),
 			handler: func(m []string) time.Time {
-				month, _ := strconv.Atoi(m[1])
-				day, _ := strconv.Atoi(m[2])
+				month, day := p.monthDay(m[1], m[2])
 				year, _ := strconv.Atoi(m[3])
 				return time.Date(year, time.Month(month), day, 0, 0, 0, 0, p.Location)
 			},
 		},
 		{
-			// MM/DD format (current year)
+			// MM/DD format (current year), DD/MM when DayFirst
 			regex: regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})\b`),
 			handler: func(m []string) time.Time {
-				month, _ := strconv.Atoi(m[1])
-				day, _ := strconv.Atoi(m[2])
+				month, day := p.monthDay(m[1], m[2])
 				return time.Date(p.Now.Year(), time.Month(month), day, 0, 0, 0, 0, p.Location)
 			},
 		},
@@ -232,7 +300,9 @@ func (p *TimeParser) ExtractDate(input string) (found bool, date time.Time, rema
 
 	for _, pattern := range patterns {
 		if matches := pattern.regex.FindStringSubmatch(input); matches != nil {
-			date = pattern.handler(matches)
+			if date = pattern.handler(matches); date.IsZero() {
+				continue
+			}
 			// Remove the matched date from input and clean up extra spaces
 			remaining = pattern.regex.ReplaceAllString(input, " ")
 			remaining = regexp.MustCompile(`\s+`).ReplaceAllString(remaining, " ")
@@ -245,6 +315,44 @@ func (p *TimeParser) ExtractDate(input string) (found bool, date time.Time, rema
 	return false, time.Time{}, input
 }
 
+// monthDay returns the month and day of the two numbers of a numeric date
+func (p *TimeParser) monthDay(first, second string) (int, int) {
+	a, _ := strconv.Atoi(first)
+	b, _ := strconv.Atoi(second)
+	if p.DayFirst {
+		return b, a
+	}
+	return a, b
+}
+
+// isoWeekDate returns the given weekday (1 = Monday) of an ISO 8601 week
+func isoWeekDate(year, week, weekday int, loc *time.Location) time.Time {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7 // Days since Monday
+	week1Monday := jan4.AddDate(0, 0, -offset)
+	return week1Monday.AddDate(0, 0, (week-1)*7+weekday-1)
+}
+
+// isoWeeks returns how many ISO 8601 weeks a year has, 52 or 53. December
+// 28th is always in the last week.
+func isoWeeks(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// addMonths adds n months, clamping to the end of shorter months so that
+// Jan 31 + 1 month is Feb 28 rather than Mar 3
+func addMonths(date time.Time, n int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(n), 1, 0, 0, 0, 0, date.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := date.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, date.Location())
+}
+
 func (p *TimeParser) parseWeekday(weekdayStr string) time.Weekday {
 	switch strings.ToLower(weekdayStr) {
 	case "sun", "sunday":
@@ -400,6 +400,12 @@ func TestTimeParser_ExtractDate(t *testing.T) {
 			wantDate:      time.Date(2024, time.January, 19, 0, 0, 0, 0, time.Local),
 			wantRemaining: "deadline",
 		},
+		{
+			name:          "offset isn't a date in text",
+			input:         "Call back +30m",
+			wantFound:     false,
+			wantRemaining: "Call back +30m",
+		},
 		{
 			name:          "no date",
 			input:         "just a reminder",
@@ -424,3 +430,50 @@ func TestTimeParser_ExtractDate(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeParser_DayFirst(t *testing.T) {
+	now := time.Date(2025, time.August, 25, 10, 0, 0, 0, time.Local)
+	for _, dayFirst := range []bool{false, true} {
+		parser := &TimeParser{Now: now, Location: time.Local, DayFirst: dayFirst}
+		want := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.Local)
+		if dayFirst {
+			want = time.Date(2025, time.April, 3, 0, 0, 0, 0, time.Local)
+		}
+		for _, input := range []string{"Dentist 03/04", "Dentist 3/4/2025"} {
+			if _, date, _ := parser.ExtractDate(input); !date.Equal(want) {
+				t.Errorf("DayFirst %v: ExtractDate(%q) = %v, want %v", dayFirst, input, date, want)
+			}
+		}
+	}
+}
+
+func TestAddMonthsClamps(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		months   int
+		expected time.Time
+	}{
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local), 1, time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local)},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local), -1, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)},
+		{time.Date(2025, 11, 15, 0, 0, 0, 0, time.Local), 3, time.Date(2026, 2, 15, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		if got := addMonths(tt.date, tt.months); !got.Equal(tt.expected) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.date.Format("2006-01-02"), tt.months,
+				got.Format("2006-01-02"), tt.expected.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestWithTime(t *testing.T) {
+	for input, want := range map[string]string{
+		"tomorrow at 2pm Meeting": "tomorrow 16:30 Meeting",
+		"Lunch 12:00 friday":      "Lunch 16:30 friday",
+		"Call Sam":                "Call Sam 16:30",
+	} {
+		if got := WithTime(input, 16, 30); got != want {
+			t.Errorf("WithTime(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package remind
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -32,11 +37,109 @@ type Event struct {
 	Type        EventType
 	Filename    string
 	LineNumber  int
+	Source      string // Name of the source the event came from
+	Location    string // Where the event takes place, from INFO "Location" or @@place
+	Color       *Color // Explicit color from SPECIAL COLOR, nil if unset
+	Special     string // SPECIAL type for calendar annotations (MOON, SHADE, WEEK)
+	MoonPhase   int    // Phase for MOON specials: 0 new, 1 first quarter, 2 full, 3 last quarter
 	Tags        []string
+	AlsoFrom    []string // Other sources that had the same event, left out as duplicates
 	IsRepeating bool
 	RepeatSpec  string
 }
 
+// SPECIAL reminder types that annotate the calendar rather than describe events
+const (
+	SpecialMoon  = "MOON"
+	SpecialShade = "SHADE"
+	SpecialWeek  = "WEEK"
+)
+
+// Moon phases as reported by SPECIAL MOON
+const (
+	MoonNew = iota
+	MoonFirstQuarter
+	MoonFull
+	MoonLastQuarter
+)
+
+// IsSpecial reports whether the event is a calendar annotation rather than
+// something that belongs in the schedule
+func (e Event) IsSpecial() bool {
+	return e.Special != ""
+}
+
+// PrivateTag marks an event whose details should be hidden in presentation mode
+const PrivateTag = "PRIVATE"
+
+// SilentTag marks an event that doesn't ring the alarm
+const SilentTag = "SILENT"
+
+// CountdownTag marks a deadline whose days left are counted down
+const CountdownTag = "COUNTDOWN"
+
+// RedactedDescription replaces the description of private events when redacted
+const RedactedDescription = "Busy"
+
+// HasTag reports whether the event carries the given tag (case-insensitive,
+// ignoring any leading @)
+func (e Event) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "@")
+	for _, t := range e.Tags {
+		if strings.EqualFold(strings.TrimPrefix(t, "@"), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivate reports whether the event is tagged PRIVATE
+func (e Event) IsPrivate() bool {
+	return e.HasTag(PrivateTag)
+}
+
+// Redacted returns a copy of the event with its identifying details removed.
+// Date, time and duration are kept so the slot still shows as busy.
+func (e Event) Redacted() Event {
+	e.Description = RedactedDescription
+	e.Body = ""
+	e.Tags = nil
+	e.RepeatSpec = ""
+	e.Location = ""
+	return e
+}
+
+// SourceLocation returns the file name and line the event was defined at,
+// e.g. "work.rem:12", or "" when the source is unknown
+func (e Event) SourceLocation() string {
+	if e.Filename == "" {
+		return ""
+	}
+	if e.LineNumber > 0 {
+		return fmt.Sprintf("%s:%d", filepath.Base(e.Filename), e.LineNumber)
+	}
+	return filepath.Base(e.Filename)
+}
+
+// EventID returns a stable ID for an occurrence of a reminder: a hash of
+// where it is defined, when it occurs and its description. Occurrences from
+// different files, lines, days or times get different IDs, while the same
+// occurrence keeps its ID across reloads.
+func EventID(event Event) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s\x00", event.Filename, event.LineNumber, event.Date.Format("2006-01-02"))
+	if event.Time != nil {
+		fmt.Fprint(h, event.Time.Format("15:04"))
+	}
+	fmt.Fprintf(h, "\x00%s", event.Description)
+	return "evt-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Color is an RGB color given explicitly in a remind file
+type Color struct {
+	R, G, B int
+}
+
 type Calendar struct {
 	Events []Event
 	Date   time.Time
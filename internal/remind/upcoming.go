@@ -0,0 +1,84 @@
+package remind
+
+import (
+	"sort"
+	"time"
+)
+
+// upcomingWindow is how far ahead sources without native support for
+// upcoming events are searched
+const upcomingWindow = 90 * 24 * time.Hour
+
+// UpcomingSource is implemented by sources that can list upcoming events
+// without a fixed date range
+type UpcomingSource interface {
+	// Upcoming returns up to n events starting after the given time, in order
+	Upcoming(after time.Time, n int) ([]Event, error)
+}
+
+// Upcoming returns the next n events from source starting after the given
+// time. Untimed events count as upcoming for the whole of their day.
+func Upcoming(source ReminderSource, after time.Time, n int) ([]Event, error) {
+	if upcoming, ok := source.(UpcomingSource); ok {
+		return upcoming.Upcoming(after, n)
+	}
+
+	// Fall back to scanning a fixed window ahead
+	dayStart := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	events, err := source.GetEvents(dayStart, after.Add(upcomingWindow))
+	if err != nil {
+		return nil, err
+	}
+	return firstUpcoming(events, after, n), nil
+}
+
+// firstUpcoming sorts events and returns the first n that are upcoming
+func firstUpcoming(events []Event, after time.Time, n int) []Event {
+	sortByStart(events)
+
+	var upcoming []Event
+	for _, event := range events {
+		if !isUpcoming(event, after) {
+			continue
+		}
+		upcoming = append(upcoming, event)
+		if len(upcoming) == n {
+			break
+		}
+	}
+	return upcoming
+}
+
+// isUpcoming reports whether the event starts after the given time, treating
+// untimed events as lasting the whole day
+func isUpcoming(event Event, after time.Time) bool {
+	if event.Time == nil {
+		dayEnd := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day()+1, 0, 0, 0, 0, event.Date.Location())
+		return dayEnd.After(after)
+	}
+	return eventStart(event).After(after)
+}
+
+// eventStart returns when the event starts; midnight for untimed events
+func eventStart(event Event) time.Time {
+	if event.Time != nil {
+		return time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+			event.Time.Hour(), event.Time.Minute(), 0, 0, event.Date.Location())
+	}
+	return event.Date
+}
+
+// sortByStart sorts events by day, timed events in time order before
+// untimed ones
+func sortByStart(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.Time == nil || b.Time == nil {
+			return a.Time != nil && b.Time == nil
+		}
+		return a.Time.Before(*b.Time)
+	})
+}
@@ -0,0 +1,109 @@
+package remind
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUpcoming(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 30, 0, 0, time.Local)
+	day := func(offset int) time.Time {
+		return time.Date(2025, 8, 25+offset, 0, 0, 0, 0, time.Local)
+	}
+	at := func(offset, hour int) *time.Time {
+		return timePtr(time.Date(2025, 8, 25+offset, hour, 0, 0, 0, time.Local))
+	}
+
+	source := &mockSource{
+		events: []Event{
+			{ID: "past", Date: day(0), Time: at(0, 9), Description: "Earlier today"},
+			{ID: "untimed-today", Date: day(0), Description: "Untimed today"},
+			{ID: "later", Date: day(0), Time: at(0, 14), Description: "Later today"},
+			{ID: "far", Date: day(60), Time: at(60, 9), Description: "Two months out"},
+			{ID: "tomorrow", Date: day(1), Time: at(1, 8), Description: "Tomorrow"},
+		},
+	}
+
+	events, err := Upcoming(source, now, 3)
+	if err != nil {
+		t.Fatalf("Upcoming() error: %v", err)
+	}
+
+	expected := []string{"later", "untimed-today", "tomorrow"}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, id := range expected {
+		if events[i].ID != id {
+			t.Errorf("events[%d] = %s, want %s", i, events[i].ID, id)
+		}
+	}
+
+	// Without a limit on count the far event is found too
+	events, _ = Upcoming(source, now, 10)
+	if len(events) != 4 || events[3].ID != "far" {
+		t.Errorf("Expected 4 events ending with far, got %v", events)
+	}
+}
+
+func TestClientUpcomingRecurring(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	content := "REM Aug 25 2025 *1 AT 09:00 MSG Standup\nREM Aug 27 2025 AT 14:00 MSG Dentist\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient()
+	client.RemindPath = "urd-test-no-remind"
+	client.SetFiles([]string{file})
+
+	now := time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local)
+	events, err := client.Upcoming(now, 5)
+	if err != nil {
+		t.Fatalf("Upcoming() error: %v", err)
+	}
+
+	// Every standup counts, not just the next one
+	expected := []string{"26 Standup", "27 Standup", "27 Dentist", "28 Standup", "29 Standup"}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		if got := events[i].Date.Format("2") + " " + events[i].Description; got != want {
+			t.Errorf("events[%d] = %s, want %s", i, got, want)
+		}
+		if events[i].Source != RemindSourceName {
+			t.Errorf("events[%d].Source = %q, want %q", i, events[i].Source, RemindSourceName)
+		}
+	}
+}
+
+func TestCompositeUpcoming(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local)
+	date := time.Date(2025, 8, 26, 0, 0, 0, 0, time.Local)
+
+	source1 := &mockSource{events: []Event{
+		{ID: "evt-1", Date: date, Time: timePtr(date.Add(15 * time.Hour))},
+		{ID: "shared", Date: date, Time: timePtr(date.Add(9 * time.Hour))},
+	}}
+	source2 := &mockSource{events: []Event{
+		{ID: "p2-1", Date: date, Time: timePtr(date.Add(11 * time.Hour))},
+		{ID: "shared", Date: date, Time: timePtr(date.Add(9 * time.Hour))},
+	}}
+
+	events, err := NewCompositeSource(source1, source2).Upcoming(now, 10)
+	if err != nil {
+		t.Fatalf("Upcoming() error: %v", err)
+	}
+
+	expected := []string{"shared", "p2-1", "evt-1"}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, id := range expected {
+		if events[i].ID != id {
+			t.Errorf("events[%d] = %s, want %s", i, events[i].ID, id)
+		}
+	}
+}
@@ -8,15 +8,32 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
+// watchDebounce is how long a file has to stay quiet before a change is
+// reported, so an editor's save is reported once
+const watchDebounce = 100 * time.Millisecond
+
+// FileWatcher reports changes to files. It watches the directories the
+// files are in rather than the files themselves: editors that save by
+// writing a new file and renaming it over the old one replace the file a
+// watch on it would follow, so later saves would go unnoticed. Files that
+// are symlinks are watched in the directory of the file they point to too,
+// and reported by the path they were added with.
 type FileWatcher struct {
 	watcher  *fsnotify.Watcher
 	files    map[string]time.Time
+	targets  map[string]string            // Symlink target -> file added as a symlink to it
+	dirs     map[string]func(string) bool // directory -> matcher for new files
+	watched  map[string]bool              // Directories added to the fsnotify watcher
 	onChange func(string)
+	onError  func(error)
 	mu       sync.RWMutex
 	done     chan struct{}
 }
 
-func NewFileWatcher(onChange func(string)) (*FileWatcher, error) {
+// NewFileWatcher starts watching for changes, reported to onChange with the
+// path of the changed file. Errors of the watcher, after which changes may
+// have been missed, are reported to onError, which may be nil.
+func NewFileWatcher(onChange func(string), onError func(error)) (*FileWatcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
@@ -25,7 +42,11 @@ func NewFileWatcher(onChange func(string)) (*FileWatcher, error) {
 	fw := &FileWatcher{
 		watcher:  watcher,
 		files:    make(map[string]time.Time),
+		targets:  make(map[string]string),
+		dirs:     make(map[string]func(string) bool),
+		watched:  make(map[string]bool),
 		onChange: onChange,
+		onError:  onError,
 		done:     make(chan struct{}),
 	}
 
@@ -33,6 +54,19 @@ func NewFileWatcher(onChange func(string)) (*FileWatcher, error) {
 	return fw, nil
 }
 
+// watchDir adds a directory to the fsnotify watcher once. Callers must hold
+// fw.mu.
+func (fw *FileWatcher) watchDir(dir string) error {
+	if fw.watched[dir] {
+		return nil
+	}
+	if err := fw.watcher.Add(dir); err != nil {
+		return err
+	}
+	fw.watched[dir] = true
+	return nil
+}
+
 func (fw *FileWatcher) AddFile(path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -46,16 +80,25 @@ func (fw *FileWatcher) AddFile(path string) error {
 		return nil // Already watching
 	}
 
-	err = fw.watcher.Add(absPath)
-	if err != nil {
+	if err := fw.watchDir(filepath.Dir(absPath)); err != nil {
 		return err
 	}
+	// Saving a symlinked file changes the file it points to, which is
+	// usually in another directory
+	if target, err := filepath.EvalSymlinks(absPath); err == nil && target != absPath {
+		if err := fw.watchDir(filepath.Dir(target)); err != nil {
+			return err
+		}
+		fw.targets[target] = absPath
+	}
 
 	fw.files[absPath] = time.Now()
 	return nil
 }
 
-func (fw *FileWatcher) RemoveFile(path string) error {
+// AddDir watches a directory for newly created files. Files for which match
+// returns true are added to the watch list and reported as changed.
+func (fw *FileWatcher) AddDir(path string, match func(string) bool) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return err
@@ -64,21 +107,110 @@ func (fw *FileWatcher) RemoveFile(path string) error {
 	fw.mu.Lock()
 	defer fw.mu.Unlock()
 
-	if _, exists := fw.files[absPath]; !exists {
-		return nil // Not watching
+	if _, exists := fw.dirs[absPath]; exists {
+		return nil // Already watching
+	}
+
+	if err := fw.watchDir(absPath); err != nil {
+		return err
+	}
+
+	fw.dirs[absPath] = match
+	return nil
+}
+
+// adoptNewFile starts watching a file created in a watched directory if it
+// matches that directory's pattern
+func (fw *FileWatcher) adoptNewFile(path string) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if _, exists := fw.files[path]; exists {
+		return
+	}
+
+	match, watchingDir := fw.dirs[filepath.Dir(path)]
+	if !watchingDir || match == nil || !match(path) {
+		return
 	}
 
-	err = fw.watcher.Remove(absPath)
+	fw.files[path] = time.Now()
+}
+
+func (fw *FileWatcher) RemoveFile(path string) error {
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return err
 	}
 
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if _, exists := fw.files[absPath]; !exists {
+		return nil // Not watching
+	}
 	delete(fw.files, absPath)
+	dirs := []string{filepath.Dir(absPath)}
+	for target, file := range fw.targets {
+		if file == absPath {
+			delete(fw.targets, target)
+			dirs = append(dirs, filepath.Dir(target))
+		}
+	}
+
+	// Stop watching the directories once nothing in them is watched
+	for _, dir := range dirs {
+		if fw.watched[dir] && !fw.watchingIn(dir) {
+			delete(fw.watched, dir)
+			if err := fw.watcher.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// watchingIn reports whether anything in dir is watched. Callers must hold
+// fw.mu.
+func (fw *FileWatcher) watchingIn(dir string) bool {
+	if _, watchingDir := fw.dirs[dir]; watchingDir {
+		return true
+	}
+	for file := range fw.files {
+		if filepath.Dir(file) == dir {
+			return true
+		}
+	}
+	for target := range fw.targets {
+		if filepath.Dir(target) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// addedAs returns the path a changed file was added with: the symlink to it
+// when it's the target of one
+func (fw *FileWatcher) addedAs(path string) string {
+	fw.mu.RLock()
+	defer fw.mu.RUnlock()
+	if file, ok := fw.targets[path]; ok {
+		return file
+	}
+	return path
+}
+
+// watching reports whether changes to path are reported
+func (fw *FileWatcher) watching(path string) bool {
+	fw.mu.RLock()
+	defer fw.mu.RUnlock()
+	_, watching := fw.files[path]
+	return watching
+}
+
 func (fw *FileWatcher) watch() {
 	debounce := make(map[string]*time.Timer)
+	var debounceMu sync.Mutex // Timers remove themselves from their own goroutines
 
 	for {
 		select {
@@ -87,33 +219,41 @@ func (fw *FileWatcher) watch() {
 				return
 			}
 
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
-				// Debounce rapid events
-				if timer, exists := debounce[event.Name]; exists {
-					timer.Stop()
-				}
+			if event.Op&fsnotify.Create != 0 {
+				fw.adoptNewFile(event.Name)
+			}
+
+			// Saving by renaming a new file over the old one shows up as a
+			// rename or remove followed by a create, reported together once
+			// the file is quiet
+			name := fw.addedAs(event.Name)
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 || !fw.watching(name) {
+				continue
+			}
 
-				debounce[event.Name] = time.AfterFunc(100*time.Millisecond, func() {
-					fw.mu.RLock()
-					if _, watching := fw.files[event.Name]; watching {
-						fw.mu.RUnlock()
-						if fw.onChange != nil {
-							fw.onChange(event.Name)
-						}
-					} else {
-						fw.mu.RUnlock()
-					}
-
-					delete(debounce, event.Name)
-				})
+			debounceMu.Lock()
+			if timer, exists := debounce[name]; exists {
+				timer.Stop()
 			}
 
+			debounce[name] = time.AfterFunc(watchDebounce, func() {
+				if fw.watching(name) && fw.onChange != nil {
+					fw.onChange(name)
+				}
+
+				debounceMu.Lock()
+				delete(debounce, name)
+				debounceMu.Unlock()
+			})
+			debounceMu.Unlock()
+
 		case err, ok := <-fw.watcher.Errors:
 			if !ok {
 				return
 			}
-			// Log error but continue watching
-			_ = err
+			if fw.onError != nil {
+				fw.onError(err)
+			}
 
 		case <-fw.done:
 			return
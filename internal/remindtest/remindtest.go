@@ -0,0 +1,229 @@
+// Package remindtest provides a fake remind command for end-to-end tests.
+// The fake reads real remind files with urd's built-in evaluator and
+// answers in remind's formats, so tests can add, edit and remove reminders
+// in temporary files and see the changes the way urd would with remind.
+package remindtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// fakeEnv tells the test binary it was run as the fake remind
+const fakeEnv = "URD_FAKE_REMIND"
+
+// Main makes the test binary act as remind when a Fake runs it. Call it
+// first in the TestMain of packages using New.
+func Main() {
+	if os.Getenv(fakeEnv) == "" {
+		return
+	}
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
+
+// Fake is a fake remind executable, a script in the test's temporary
+// directory running the test binary
+type Fake struct {
+	Path string // Executable to use as the remind command
+	dir  string
+}
+
+// New installs a fake remind for the test. The test binary must call Main
+// from TestMain.
+func New(t testing.TB) *Fake {
+	t.Helper()
+	binary, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	f := &Fake{Path: filepath.Join(dir, "remind"), dir: dir}
+
+	script := fmt.Sprintf(`#!/bin/sh
+printf '%%s\n' "$*" >> '%[1]s/calls'
+if [ -f '%[1]s/stderr' ]; then
+	cat '%[1]s/stderr' >&2
+	exit 1
+fi
+%[2]s=1 exec '%[3]s' "$@"
+`, dir, fakeEnv, binary)
+	if err := os.WriteFile(f.Path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// FailWith makes the runs that follow print stderr and exit with 1, like
+// remind does for errors in the files. An empty stderr works again.
+func (f *Fake) FailWith(t testing.TB, stderr string) {
+	t.Helper()
+	path := filepath.Join(f.dir, "stderr")
+	if stderr == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(path, []byte(stderr), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Calls returns the arguments of each run so far, joined with spaces
+func (f *Fake) Calls(t testing.TB) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "calls"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// run answers like remind run with args: the JSON of -ppp for the month
+// starting on the date given, or the next occurrences of -n
+func run(args []string, stdout io.Writer) int {
+	var next bool
+	var files []string
+	for _, arg := range args {
+		switch {
+		case arg == "-n":
+			next = true
+		case strings.HasPrefix(arg, "-"):
+			// Output formats urd always asks for
+		default:
+			files = append(files, arg)
+		}
+	}
+	date := time.Now()
+	if len(files) >= 3 {
+		given := strings.Join(files[len(files)-3:], " ")
+		if parsed, err := time.ParseInLocation("Jan 2 2006", given, time.Local); err == nil {
+			date, files = parsed, files[:len(files)-3]
+		}
+	}
+	if len(files) == 0 {
+		// Checking that remind works
+		return 0
+	}
+
+	client := remind.NewClient()
+	client.RemindPath = "urd-fake-remind-builtin" // Never found, the built-in evaluator reads the files
+	client.SetFiles(files)
+
+	var err error
+	if next {
+		err = writeNext(stdout, client, date)
+	} else {
+		err = writeMonth(stdout, client, date)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// writeMonth writes the events of the month of date as remind -ppp does
+func writeMonth(w io.Writer, client *remind.Client, date time.Time) error {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+	last := first.AddDate(0, 1, -1)
+	events, err := client.GetEvents(first, last)
+	if err != nil {
+		return err
+	}
+
+	month := remind.RemindJSON{
+		MonthName:   first.Month().String(),
+		Year:        first.Year(),
+		DaysInMonth: last.Day(),
+		FirstWkDay:  int(first.Weekday()),
+		DayNames:    []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		Entries:     []remind.RemindEntry{},
+	}
+	for _, event := range events {
+		month.Entries = append(month.Entries, entryOf(event))
+	}
+	return json.NewEncoder(w).Encode([]remind.RemindJSON{month})
+}
+
+// entryOf returns the remind -ppp entry of event
+func entryOf(event remind.Event) remind.RemindEntry {
+	entry := remind.RemindEntry{
+		Date:     event.Date.Format(time.DateOnly),
+		Filename: event.Filename,
+		LineNo:   event.LineNumber,
+		Priority: remindPriority[event.Priority],
+		RawBody:  event.Description,
+		Body:     event.Description,
+		Tags:     event.Tags,
+	}
+	if event.Time != nil {
+		minutes := event.Time.Hour()*60 + event.Time.Minute()
+		entry.Time = &minutes
+		if event.Duration != nil {
+			duration := int(event.Duration.Minutes())
+			entry.Duration = &duration
+		}
+	}
+	if event.Location != "" {
+		entry.Info = map[string]string{"location": event.Location}
+	}
+	if !event.IsRepeating {
+		day, month, year := event.Date.Day(), int(event.Date.Month()), event.Date.Year()
+		entry.D, entry.M, entry.Y = &day, &month, &year
+	}
+	return entry
+}
+
+// remindPriority is the PRIORITY of a reminder read with each priority
+var remindPriority = map[remind.Priority]int{
+	remind.PriorityNone:   5000,
+	remind.PriorityLow:    5500,
+	remind.PriorityMedium: 6500,
+	remind.PriorityHigh:   9000,
+}
+
+// writeNext writes the next occurrence of each reminder from date on, in
+// the year after it, as remind -n does
+func writeNext(w io.Writer, client *remind.Client, date time.Time) error {
+	events, err := client.GetEvents(date, date.AddDate(1, 0, 0))
+	if err != nil {
+		return err
+	}
+
+	first := make(map[string]remind.Event)
+	var order []string
+	for _, event := range events {
+		key := event.Filename + ":" + strconv.Itoa(event.LineNumber)
+		kept, ok := first[key]
+		if !ok {
+			order = append(order, key)
+		}
+		if !ok || event.Date.Before(kept.Date) {
+			first[key] = event
+		}
+	}
+	for _, key := range order {
+		event := first[key]
+		line := event.Date.Format("2006/01/02")
+		if event.Time != nil {
+			line += " " + event.Time.Format("15:04")
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", line, event.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package remindtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+func TestFake(t *testing.T) {
+	fake := New(t)
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	content := "REM Aug 25 2025 AT 10:00 DURATION 1:30 PRIORITY 9000 TAG work MSG Planning\nREM Mon MSG Weekly review\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := remind.NewClient()
+	client.RemindPath = fake.Path
+	client.SetFiles([]string{file})
+	if client.Degraded() {
+		t.Fatal("expected the fake to be found")
+	}
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	events, err := client.GetEvents(day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected both reminders on Aug 25, got %+v", events)
+	}
+	for _, event := range events {
+		switch event.Description {
+		case "Planning":
+			if event.Time == nil || event.Time.Hour() != 10 || event.Duration == nil || *event.Duration != 90*time.Minute ||
+				event.Priority != remind.PriorityHigh || event.LineNumber != 1 || event.IsRepeating {
+				t.Errorf("Planning read as %+v", event)
+			}
+		case "Weekly review":
+			if event.Time != nil || !event.IsRepeating || event.LineNumber != 2 {
+				t.Errorf("Weekly review read as %+v", event)
+			}
+		default:
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+
+	next, err := client.FindNext("review", day.Add(12*time.Hour))
+	if err != nil || next == nil || !next.Date.Equal(day.AddDate(0, 0, 7)) {
+		t.Errorf("FindNext = %+v, %v, want next Monday", next, err)
+	}
+
+	fake.FailWith(t, file+"(2): Expecting time after AT\n")
+	var syntaxErr *remind.RemindSyntaxError
+	if _, err := client.GetEvents(day, day); !errors.As(err, &syntaxErr) || syntaxErr.Line != 2 {
+		t.Errorf("expected the scripted syntax error, got %v", err)
+	}
+	fake.FailWith(t, "")
+	if _, err := client.GetEvents(day, day); err != nil {
+		t.Errorf("expected the fake to work again, got %v", err)
+	}
+
+	calls := fake.Calls(t)
+	if len(calls) == 0 || !strings.Contains(calls[len(calls)-1], "-pppq") {
+		t.Errorf("expected the runs recorded, got %q", calls)
+	}
+}
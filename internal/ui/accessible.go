@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// AccessibleStyles are styles without colors or borders, for screen readers
+// and braille displays
+func AccessibleStyles() Styles {
+	plain := lipgloss.NewStyle()
+	return Styles{
+		Normal: plain, Selected: plain, Today: plain, Weekend: plain,
+		Header: plain, Event: plain, Priority: plain, Help: plain,
+		Message: plain, Border: plain, TodaySeparator: plain,
+		WeekendSeparator: plain, WeekendShade: plain, OffHoursShade: plain,
+	}
+}
+
+// renderAccessibleView renders the schedule as plain labeled lines instead
+// of layered blocks: messages on the first line, where they are announced,
+// then the selection and one line per event of the selected day, each with
+// its date and time
+func (m *Model) renderAccessibleView() string {
+	var lines []string
+
+	// Messages always take the first line
+	announce := m.message
+	if err := m.statusError(); err != nil {
+		announce = "Error: " + describeError(err)
+	} else if len(m.pendingKeys) > 0 {
+		announce = strings.Join(m.pendingKeys, " ") + "-"
+	} else if announce == "" && m.remindMissing {
+		announce = degradedBanner
+	}
+	lines = append(lines, "Message: "+announce)
+
+	day := m.selectedDay()
+	slotsPerDay := m.getSlotsPerDay()
+	localSlot := ((m.selectedSlot % slotsPerDay) + slotsPerDay) % slotsPerDay
+	hour, minute := m.slotToTime(localSlot)
+	selected := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+	if m.focusUntimed {
+		lines = append(lines, "Selected: untimed reminders of "+day.Format(m.config.DateLayout("Monday, January 2, 2006")))
+	} else {
+		lines = append(lines, "Selected: "+selected.Format("Monday, January 2, 2006 at 15:04"))
+	}
+
+	atSelection := map[string]bool{}
+	for _, event := range m.selectedEvents() {
+		atSelection[event.ID] = true
+	}
+
+	events := m.dayAgenda(day)
+	heading := fmt.Sprintf("%s: %d events", day.Format(m.config.DateLayout("Monday, January 2")), len(events))
+	if m.hasNote(day) {
+		heading += ", has a note"
+	}
+	lines = append(lines, heading)
+	for _, event := range events {
+		line := m.accessibleEventLine(m.displayEvent(event))
+		if event.ID != "" && atSelection[event.ID] {
+			line += " (selected)"
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "Press ? for help")
+
+	// Keep to the screen, saying how much didn't fit
+	if m.height >= 3 && len(lines) > m.height {
+		hidden := len(lines) - 1 - (m.height - 2)
+		lines = append(lines[:m.height-2], fmt.Sprintf("%d more events not shown", hidden), lines[len(lines)-1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// accessibleEventLine describes an event in words, with its date and time
+func (m *Model) accessibleEventLine(event remind.Event) string {
+	date := event.Date.Format(m.config.DateLayout("Mon Jan 2"))
+	var when string
+	switch {
+	case event.Time == nil:
+		when = date + ", all day"
+	case event.Duration != nil && *event.Duration > 0:
+		when = fmt.Sprintf("%s, %s to %s", date, event.Time.Format("15:04"), event.Time.Add(*event.Duration).Format("15:04"))
+	default:
+		when = fmt.Sprintf("%s, %s", date, event.Time.Format("15:04"))
+	}
+
+	line := when + ": " + event.Description
+	if event.Location != "" {
+		line += ", at " + event.Location
+	}
+	switch event.Priority {
+	case remind.PriorityLow:
+		line += ", low priority"
+	case remind.PriorityMedium:
+		line += ", medium priority"
+	case remind.PriorityHigh:
+		line += ", high priority"
+	}
+	return line
+}
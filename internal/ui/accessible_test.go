@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestAccessibleView(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		mode:          ViewHourly,
+		width:         80,
+		height:        20,
+		timeIncrement: 60,
+		selectedDate:  day,
+		selectedSlot:  10,
+		config:        &config.Config{Accessible: true},
+		styles:        AccessibleStyles(),
+		message:       "Event pasted",
+		events: []remind.Event{
+			{ID: "1", Date: day, Time: timePtr(10, 0), Duration: durationPtr(90), Description: "Design review", Location: "Room 4"},
+			{ID: "2", Date: day, Time: timePtr(14, 0), Description: "Call Sam", Priority: remind.PriorityHigh},
+			{ID: "3", Date: day, Description: "Pay rent"},
+		},
+	}
+
+	lines := strings.Split(m.View(), "\n")
+	want := []string{
+		"Message: Event pasted",
+		"Selected: Monday, August 25, 2025 at 10:00",
+		"Monday, August 25: 3 events",
+		"Mon Aug 25, 10:00 to 11:30: Design review, at Room 4 (selected)",
+		"Mon Aug 25, 14:00: Call Sam, high priority",
+		"Mon Aug 25, all day: Pay rent",
+		"Press ? for help",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("View() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Errors take the message line, and lines that don't fit are counted
+	m.height = 5
+	m.configError = errors.New("bad line 3")
+	lines = strings.Split(m.View(), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("View() has %d lines, want 5:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if lines[0] != "Message: Error: urdrc not reloaded: bad line 3" {
+		t.Errorf("first line = %q", lines[0])
+	}
+	if lines[3] != "3 more events not shown" {
+		t.Errorf("overflow line = %q", lines[3])
+	}
+}
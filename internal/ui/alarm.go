@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// dueAlarms returns the timed events starting within before from now that
+// haven't rung yet, leaving out those tagged SILENT
+func (m *Model) dueAlarms(now time.Time, before time.Duration) []remind.Event {
+	var due []remind.Event
+	for _, event := range m.events {
+		if event.Time == nil || event.HasTag(remind.SilentTag) || m.rungAlarms[meetingKey(event)] {
+			continue
+		}
+		if untilStart := event.Time.Sub(now); untilStart >= 0 && untilStart <= before {
+			due = append(due, event)
+		}
+	}
+	return due
+}
+
+// ringAlarms rings the terminal bell for the events starting within the
+// alarm time, once for each, while the terminal has focus. The on_alarm
+// hook, if set, runs for each instead, to play a sound.
+func (m *Model) ringAlarms(now time.Time) tea.Cmd {
+	if m.config.Alarm <= 0 || m.blurred {
+		return nil
+	}
+	due := m.dueAlarms(now, m.config.Alarm)
+	if len(due) == 0 {
+		return nil
+	}
+
+	if m.rungAlarms == nil {
+		m.rungAlarms = make(map[string]bool)
+	}
+	for _, event := range due {
+		m.rungAlarms[meetingKey(event)] = true
+		m.runHook(HookAlarm, &event)
+	}
+	event := m.displayEvent(due[0])
+	m.showMessage(fmt.Sprintf("Starting in %d min: %s", int(event.Time.Sub(now).Round(time.Minute).Minutes()), event.Description))
+
+	if m.config.Hooks[HookAlarm] != "" {
+		return nil // the hooks queued above play the sound
+	}
+	return tea.Raw("\a")
+}
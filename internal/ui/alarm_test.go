@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestRingAlarms(t *testing.T) {
+	at := func(hour, minute int) *time.Time {
+		tm := time.Date(2025, 8, 25, hour, minute, 0, 0, time.Local)
+		return &tm
+	}
+	m := &Model{
+		config: &config.Config{Alarm: 5 * time.Minute},
+		events: []remind.Event{
+			{ID: "evt-1", Date: *at(0, 0), Time: at(9, 0), Description: "Standup"},
+			{ID: "evt-2", Date: *at(0, 0), Time: at(9, 0), Description: "Focus time", Tags: []string{"SILENT"}},
+			{ID: "evt-3", Date: *at(0, 0), Description: "Water plants"},
+			{ID: "evt-4", Date: *at(0, 0), Time: at(10, 0), Description: "Review"},
+		},
+	}
+
+	if cmd := m.ringAlarms(*at(8, 50)); cmd != nil {
+		t.Error("rang before any event was due")
+	}
+	cmd := m.ringAlarms(*at(8, 56))
+	if cmd == nil {
+		t.Fatal("expected the alarm to ring for the standup")
+	}
+	if raw, ok := cmd().(tea.RawMsg); !ok || raw.Msg != "\a" {
+		t.Errorf("expected the bell, got %#v", cmd())
+	}
+	if m.message != "Starting in 4 min: Standup" {
+		t.Errorf("unexpected message %q", m.message)
+	}
+	if cmd := m.ringAlarms(*at(8, 57)); cmd != nil {
+		t.Error("rang twice for the standup")
+	}
+
+	// Not while the terminal has lost focus, but once it is back
+	m.clock = remind.FixedClock(*at(9, 56))
+	m.update(tea.BlurMsg{})
+	if cmd := m.ringAlarms(*at(9, 56)); cmd != nil {
+		t.Error("rang without focus")
+	}
+	if _, cmd := m.update(tea.FocusMsg{}); cmd == nil {
+		t.Error("expected the review to ring once focused again")
+	}
+
+	// With on_alarm, the hook plays the sound instead
+	m.config.Hooks = map[string]string{HookAlarm: "true"}
+	m.rungAlarms = nil
+	if cmd := m.ringAlarms(*at(8, 56)); cmd != nil || len(m.pendingHooks) != 1 {
+		t.Errorf("expected the on_alarm hook instead of the bell, got %d hooks", len(m.pendingHooks))
+	}
+}
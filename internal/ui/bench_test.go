@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// benchmarkModel returns a model with two years of events around the
+// selected date: four timed events a working day, some overlapping, and an
+// untimed reminder a week
+func benchmarkModel() *Model {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	cfg := config.DefaultConfig()
+	m := &Model{
+		config:        cfg,
+		styles:        stylesFor(cfg),
+		clock:         remind.FixedClock(day.Add(10 * time.Hour)),
+		mode:          ViewHourly,
+		timeIncrement: 30,
+		selectedDate:  day,
+		selectedSlot:  20,
+		topSlot:       14,
+		width:         120,
+		height:        40,
+	}
+
+	for offset := -365; offset < 365; offset++ {
+		date := day.AddDate(0, 0, offset)
+		if date.Weekday() == time.Sunday {
+			m.events = append(m.events, remind.Event{ID: fmt.Sprintf("u%d", offset), Date: date, Description: "Water the plants"})
+		}
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		for i, start := range []int{9, 10, 10, 14} {
+			at := date.Add(time.Duration(start)*time.Hour + time.Duration(i%2)*30*time.Minute)
+			m.events = append(m.events, remind.Event{
+				ID:          fmt.Sprintf("e%d-%d", offset, i),
+				Date:        date,
+				Time:        &at,
+				Duration:    durationPtr(30 + 30*i),
+				Description: fmt.Sprintf("Meeting %d", i),
+			})
+		}
+	}
+	m.eventsLoadedFor = m.selectedDate
+	return m
+}
+
+func BenchmarkCreateEventBlockLayers(b *testing.B) {
+	m := benchmarkModel()
+	slotsPerDay := m.getSlotsPerDay()
+	b.ReportMetric(float64(len(m.events)), "events")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.createEventBlockLayers(slotsPerDay, 36, 7, 80)
+	}
+}
+
+func BenchmarkView(b *testing.B) {
+	m := benchmarkModel()
+	b.ReportMetric(float64(len(m.events)), "events")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.View()
+	}
+}
+
+func BenchmarkSelectedEvents(b *testing.B) {
+	m := benchmarkModel()
+	b.ReportMetric(float64(len(m.events)), "events")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.selectedEvents()
+		m.renderSelectedSlotEvents()
+		m.getSortedUntimedEvents(m.selectedDate)
+	}
+}
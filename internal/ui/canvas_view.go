@@ -7,16 +7,15 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/config"
 	"github.com/cwarden/urd/internal/remind"
 )
 
 // renderCanvasView renders the entire screen using a lipgloss Canvas
 func (m *Model) renderCanvasView() string {
 	// Calculate basic dimensions
-	scheduleWidth := m.width * 2 / 3
-	if scheduleWidth < 40 {
-		scheduleWidth = 40
-	}
+	scheduleWidth := m.scheduleWidth()
 
 	// Calculate time configuration
 	slotsPerDay := 24
@@ -27,9 +26,17 @@ func (m *Model) renderCanvasView() string {
 	}
 
 	// Reserve space for status bar (2 lines at bottom)
-	visibleSlots := m.height - 2
-	if visibleSlots < 1 {
-		visibleSlots = 1
+	rows := m.height - 2
+	if rows < 1 {
+		rows = 1
+	}
+
+	// Runs of empty slots take a row each in compact mode, so more slots fit
+	visibleSlots := rows
+	m.collapsed = compactRows{}
+	if m.compact {
+		m.collapsed = m.compactLayout(slotsPerDay, rows)
+		visibleSlots = m.collapsed.shown
 	}
 
 	var layers []*lipgloss.Layer
@@ -40,10 +47,23 @@ func (m *Model) renderCanvasView() string {
 
 	// Create event block layers
 	timeWidth := 7 // "HH:MM  "
+	if m.config.ShadeWeekends {
+		layers = append(layers, m.createWeekendShadeLayers(slotsPerDay, visibleSlots, timeWidth, scheduleWidth)...)
+	}
+	if m.config.ShadeOffHours {
+		layers = append(layers, m.createOffHoursShadeLayers(slotsPerDay, visibleSlots, timeWidth, scheduleWidth)...)
+	}
 	eventAreaWidth := scheduleWidth - timeWidth
 	eventLayers := m.createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eventAreaWidth)
 	layers = append(layers, eventLayers...)
 
+	// Show the selected events in full over the schedule
+	if m.peeking {
+		if peekLayer := m.createPeekLayer(slotsPerDay, rows, timeWidth, eventAreaWidth); peekLayer != nil {
+			layers = append(layers, peekLayer)
+		}
+	}
+
 	// Create sidebar layer with 1 column spacing
 	sidebarWidth := m.width - scheduleWidth - 1
 	if sidebarWidth > 0 {
@@ -52,7 +72,7 @@ func (m *Model) renderCanvasView() string {
 	}
 
 	// Add status bar layers at the bottom
-	statusLayers := m.createStatusBarLayers(visibleSlots)
+	statusLayers := m.createStatusBarLayers(rows)
 	layers = append(layers, statusLayers...)
 
 	// Render the canvas
@@ -66,7 +86,7 @@ func (m *Model) renderCanvasView() string {
 // createTimeColumnLayers creates individual layers for each time label and date separator
 func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipgloss.Layer {
 	var layers []*lipgloss.Layer
-	now := time.Now()
+	now := m.now()
 	prevDay := -999
 	rowIndex := 0
 
@@ -86,8 +106,17 @@ func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipglos
 				break // No more room for content
 			}
 			currentDate := m.selectedDate.AddDate(0, 0, dayOffset)
-			dateLine := currentDate.Format("─Mon Jan 02")
-			dateLayer := lipgloss.NewLayer(m.styles.Header.Render(dateLine)).X(0).Y(rowIndex).Z(0)
+			dateLine := currentDate.Format(m.config.DateLayout("─Mon Jan 02"))
+			if m.hasNote(currentDate) {
+				dateLine += " " + noteMark
+			}
+			style := m.styles.Header
+			if sameDay(currentDate, now) {
+				style = m.styles.TodaySeparator
+			} else if currentDate.Weekday() == time.Saturday || currentDate.Weekday() == time.Sunday {
+				style = m.styles.WeekendSeparator
+			}
+			dateLayer := lipgloss.NewLayer(style.Render(dateLine)).X(0).Y(rowIndex).Z(0)
 			layers = append(layers, dateLayer)
 			prevDay = dayOffset
 			rowIndex++
@@ -98,6 +127,18 @@ func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipglos
 			break // No more room for content
 		}
 
+		// A run of empty slots collapsed in compact mode takes one row,
+		// over the shading of the event area
+		if m.collapsed.shared[globalSlot] {
+			continue
+		}
+		if run := m.collapsed.runs[globalSlot]; run > 0 {
+			runLayer := lipgloss.NewLayer(m.styles.Help.Render(m.emptyRunLabel(run))).X(0).Y(rowIndex).Z(1)
+			layers = append(layers, runLayer)
+			rowIndex++
+			continue
+		}
+
 		// Calculate time for this slot
 		slotInDay := globalSlot % slotsPerDay
 		if globalSlot < 0 {
@@ -125,8 +166,9 @@ func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipglos
 		slotTime := time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(),
 			hour, minute, 0, 0, currentDate.Location())
 
-		// Apply styling
-		style := m.styles.Normal
+		// Apply styling, shaded by how busy the hour usually is with
+		// gutter_heat
+		style := m.heatStyle(m.styles.Normal, hour)
 
 		// Highlight current time
 		if slotTime.Year() == now.Year() &&
@@ -139,8 +181,8 @@ func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipglos
 			}
 		}
 
-		// Highlight selected slot
-		if globalSlot == m.selectedSlot {
+		// Highlight selected slot, and the slots new events cover
+		if globalSlot == m.selectedSlot || m.inSpan(slotTime) {
 			style = m.styles.Selected
 		}
 
@@ -153,6 +195,51 @@ func (m *Model) createTimeColumnLayers(slotsPerDay, visibleSlots int) []*lipglos
 	return layers
 }
 
+// createWeekendShadeLayers creates the background of the weekend rows,
+// under the event blocks
+func (m *Model) createWeekendShadeLayers(slotsPerDay, visibleSlots, timeWidth, scheduleWidth int) []*lipgloss.Layer {
+	var layers []*lipgloss.Layer
+	shade := m.styles.WeekendShade.Render(strings.Repeat(" ", scheduleWidth-timeWidth))
+	for i := 0; i < visibleSlots; i++ {
+		day := m.selectedDate.AddDate(0, 0, floorDiv(m.topSlot+i, slotsPerDay))
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			continue
+		}
+		row := m.slotToRowIndex(i, slotsPerDay)
+		if row >= visibleSlots {
+			break
+		}
+		layers = append(layers, lipgloss.NewLayer(shade).X(timeWidth).Y(row).Z(0))
+	}
+	return layers
+}
+
+// createOffHoursShadeLayers creates the background of the rows outside the
+// working hours of their day, under the event blocks. Weekend rows already
+// shaded with shade_weekends are left as they are.
+func (m *Model) createOffHoursShadeLayers(slotsPerDay, visibleSlots, timeWidth, scheduleWidth int) []*lipgloss.Layer {
+	var layers []*lipgloss.Layer
+	shade := m.styles.OffHoursShade.Render(strings.Repeat(" ", scheduleWidth-timeWidth))
+	for i := 0; i < visibleSlots; i++ {
+		slot := m.topSlot + i
+		day := m.selectedDate.AddDate(0, 0, floorDiv(slot, slotsPerDay))
+		if m.config.ShadeWeekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		hour, minute := m.slotToTime(slot - floorDiv(slot, slotsPerDay)*slotsPerDay)
+		at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+		if from, until := m.workingHours(day); !at.Before(from) && at.Before(until) {
+			continue
+		}
+		row := m.slotToRowIndex(i, slotsPerDay)
+		if row >= visibleSlots {
+			break
+		}
+		layers = append(layers, lipgloss.NewLayer(shade).X(timeWidth).Y(row).Z(0))
+	}
+	return layers
+}
+
 // createEventBlockLayers creates individual layers for each event block
 func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eventAreaWidth int) []*lipgloss.Layer {
 	var layers []*lipgloss.Layer
@@ -163,118 +250,66 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 
 	// Map events to visible slots and assign columns
 	type EventPosition struct {
-		Event        remind.Event
-		StartRow     int // Row index in visible area (accounting for date separators)
-		SpanRows     int // Number of rows to span
-		Column       int // Column assignment
-		ColumnSpan   int // Number of columns to span
-		ClippedStart int // For tracking slot occupancy
-		ClippedEnd   int // For tracking slot occupancy
+		Event      remind.Event
+		StartRow   int // Row index in visible area (accounting for date separators)
+		SpanRows   int // Number of rows to span
+		Column     int // Column assignment
+		ColumnSpan int // Number of columns to span
+		FirstSlot  int // First slot of the whole event, visible or not
+		EndSlot    int // Slot after the event ends
 	}
 
-	var eventPositions []EventPosition
-	slotOccupancy := make(map[int]map[int]bool) // slot -> column -> occupied
-
-	// Sort events by time, then by description for consistent ordering
-	sortedEvents := make([]remind.Event, len(m.events))
-	copy(sortedEvents, m.events)
+	// Lay out the whole days on screen rather than just the visible slots,
+	// so events keep their columns while scrolling
+	firstSlot := floorDiv(m.topSlot, slotsPerDay) * slotsPerDay
+	endSlot := (floorDiv(m.topSlot+visibleSlots-1, slotsPerDay) + 1) * slotsPerDay
+
+	// Only the events of those days are sorted, by time, then by
+	// description for consistent ordering
+	visibleEvents := m.eventsReaching(baseDate.AddDate(0, 0, firstSlot/slotsPerDay), baseDate.AddDate(0, 0, endSlot/slotsPerDay-1))
+	sortedEvents := make([]remind.Event, len(visibleEvents))
+	for i, event := range visibleEvents {
+		sortedEvents[i] = m.displayEvent(event)
+	}
 	sort.Slice(sortedEvents, func(i, j int) bool {
-		// Untimed events go last
-		if sortedEvents[i].Time == nil && sortedEvents[j].Time != nil {
-			return false
-		}
-		if sortedEvents[i].Time != nil && sortedEvents[j].Time == nil {
-			return true
-		}
-		if sortedEvents[i].Time == nil && sortedEvents[j].Time == nil {
-			// Sort untimed events by priority, then description, then ID
-			if sortedEvents[i].Priority != sortedEvents[j].Priority {
-				return sortedEvents[i].Priority > sortedEvents[j].Priority
-			}
-			if sortedEvents[i].Description != sortedEvents[j].Description {
-				return sortedEvents[i].Description < sortedEvents[j].Description
-			}
-			return sortedEvents[i].ID < sortedEvents[j].ID
-		}
-
-		// Both have times - sort by date first
-		if !sortedEvents[i].Date.Equal(sortedEvents[j].Date) {
-			return sortedEvents[i].Date.Before(sortedEvents[j].Date)
-		}
-
-		// Same date - sort by time
-		iTime := sortedEvents[i].Time.Hour()*60 + sortedEvents[i].Time.Minute()
-		jTime := sortedEvents[j].Time.Hour()*60 + sortedEvents[j].Time.Minute()
-		if iTime != jTime {
-			return iTime < jTime
-		}
-
-		// Same time - sort by priority (higher priority first)
-		if sortedEvents[i].Priority != sortedEvents[j].Priority {
-			return sortedEvents[i].Priority > sortedEvents[j].Priority
-		}
-
-		// Sort by description
-		if sortedEvents[i].Description != sortedEvents[j].Description {
-			return sortedEvents[i].Description < sortedEvents[j].Description
-		}
-
-		// Finally, sort by ID for absolute stability
-		return sortedEvents[i].ID < sortedEvents[j].ID
+		return eventLess(sortedEvents[i], sortedEvents[j])
 	})
 
-	// Calculate positions for each event
+	var timedEvents []remind.Event
+	var intervals []slotInterval
 	for _, event := range sortedEvents {
 		if event.Time == nil {
 			continue
 		}
-
-		// Calculate event's slot position
-		eventDate := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, event.Date.Location())
-		dayDiff := int(eventDate.Sub(baseDate).Hours() / 24)
-
-		hour := event.Time.Hour()
-		minute := event.Time.Minute()
-		localSlot := hour
-
-		if m.timeIncrement == 30 {
-			localSlot = hour*2 + minute/30
-		} else if m.timeIncrement == 15 {
-			localSlot = hour*4 + minute/15
-		}
-
-		eventSlot := dayDiff*slotsPerDay + localSlot
-
-		// Check if event is in visible range
-		visibleStart := eventSlot - m.topSlot
-		if visibleStart >= visibleSlots {
-			continue // Event is after visible area
+		eventSlot := m.findEventSlot(event, slotsPerDay, baseDate)
+		interval := slotInterval{start: eventSlot, end: eventSlot + m.eventSlotSpan(event)}
+		if interval.end <= firstSlot || interval.start >= endSlot {
+			continue
 		}
+		timedEvents = append(timedEvents, event)
+		intervals = append(intervals, interval)
+	}
+	columns := assignColumns(intervals)
 
-		// Calculate duration in slots
-		slotSpan := 1
-		if event.Duration != nil {
-			durationMinutes := int(event.Duration.Minutes())
-			if m.timeIncrement == 30 {
-				slotSpan = (durationMinutes + 29) / 30
-			} else if m.timeIncrement == 15 {
-				slotSpan = (durationMinutes + 14) / 15
-			} else {
-				slotSpan = (durationMinutes + 59) / 60
+	slotOccupancy := make(map[int]map[int]bool) // slot -> column -> occupied
+	for i, interval := range intervals {
+		for slot := interval.start; slot < interval.end; slot++ {
+			if slotOccupancy[slot] == nil {
+				slotOccupancy[slot] = make(map[int]bool)
 			}
+			slotOccupancy[slot][columns[i]] = true
 		}
+	}
 
-		visibleEnd := visibleStart + slotSpan
-		if visibleEnd <= 0 {
-			continue // Event is before visible area
-		}
-
+	// Calculate positions for the visible events
+	var eventPositions []EventPosition
+	for i, event := range timedEvents {
 		// Clip to visible area
-		clippedStart := visibleStart
+		clippedStart := intervals[i].start - m.topSlot
 		if clippedStart < 0 {
 			clippedStart = 0
 		}
-		clippedEnd := visibleEnd
+		clippedEnd := intervals[i].end - m.topSlot
 		if clippedEnd > visibleSlots {
 			clippedEnd = visibleSlots
 		}
@@ -288,48 +323,19 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 		startRow := m.slotToRowIndex(clippedStart, slotsPerDay)
 		spanRows := clippedSpan // Simplified: assume 1 slot = 1 row for now
 
-		// Find available column
-		column := 0
-		for {
-			available := true
-			for slot := clippedStart; slot < clippedEnd; slot++ {
-				if slotOccupancy[slot] == nil {
-					slotOccupancy[slot] = make(map[int]bool)
-				}
-				if slotOccupancy[slot][column] {
-					available = false
-					break
-				}
-			}
-
-			if available {
-				// Mark slots as occupied
-				for slot := clippedStart; slot < clippedEnd; slot++ {
-					slotOccupancy[slot][column] = true
-				}
-				break
-			}
-
-			column++
-			if column > 10 { // Safety limit
-				column = 0
-				break
-			}
-		}
-
 		eventPositions = append(eventPositions, EventPosition{
-			Event:        event,
-			StartRow:     startRow,
-			SpanRows:     spanRows,
-			Column:       column,
-			ColumnSpan:   1, // Start with single column
-			ClippedStart: clippedStart,
-			ClippedEnd:   clippedEnd,
+			Event:      event,
+			StartRow:   startRow,
+			SpanRows:   spanRows,
+			Column:     columns[i],
+			ColumnSpan: 1, // Start with single column
+			FirstSlot:  intervals[i].start,
+			EndSlot:    intervals[i].end,
 		})
 	}
 
 	// Calculate initial column width to determine if expansion is needed
-	maxColumn := 0
+	maxColumn := -1
 	for _, pos := range eventPositions {
 		if pos.Column > maxColumn {
 			maxColumn = pos.Column
@@ -366,7 +372,7 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 		for nextCol := pos.Column + 1; nextCol < initialNumColumns; nextCol++ {
 			// Check if this column is free for all slots this event occupies
 			canExpand := true
-			for slot := pos.ClippedStart; slot < pos.ClippedEnd; slot++ {
+			for slot := pos.FirstSlot; slot < pos.EndSlot; slot++ {
 				if slotOccupancy[slot] != nil && slotOccupancy[slot][nextCol] {
 					canExpand = false
 					break
@@ -378,7 +384,7 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 			}
 
 			// Mark the new column as occupied
-			for slot := pos.ClippedStart; slot < pos.ClippedEnd; slot++ {
+			for slot := pos.FirstSlot; slot < pos.EndSlot; slot++ {
 				if slotOccupancy[slot] == nil {
 					slotOccupancy[slot] = make(map[int]bool)
 				}
@@ -409,23 +415,68 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 			maxColumn = endColumn
 		}
 	}
-
 	numColumns := maxColumn + 1
-	if numColumns == 0 {
-		return layers // No events
+
+	// Only as many columns as fit at the minimum width are shown. When there
+	// are more, the last one counts the events that don't fit instead.
+	shownColumns := numColumns
+	maxColumns := (eventAreaWidth + padding) / (minColumnWidth + padding)
+	overflow := numColumns > maxColumns
+	if overflow {
+		shownColumns = maxColumns - 1
+		if shownColumns < 1 {
+			shownColumns = 1
+		}
+		numColumns = shownColumns + 1
 	}
 
+	// The columns that don't fit are scrolled to with scroll_left and
+	// scroll_right, the time column stays put
+	m.maxColumnOffset = maxColumn + 1 - shownColumns
+	firstColumn := m.columnOffset
+	if firstColumn > m.maxColumnOffset {
+		firstColumn = m.maxColumnOffset
+	}
+	if firstColumn < 0 {
+		firstColumn = 0
+	}
+	lastColumn := firstColumn + shownColumns // Column after the last shown
+
 	// Recalculate column width based on actual columns used
 	columnWidth := eventAreaWidth / numColumns
 	if numColumns > 1 {
 		columnWidth = (eventAreaWidth - padding*(numColumns-1)) / numColumns
 	}
-	if columnWidth < 10 {
-		columnWidth = 10
+	if columnWidth < minColumnWidth {
+		columnWidth = minColumnWidth
 	}
 
 	// Create layer for each event
+	travelWarnings := m.travelWarnings()
+	hidden := make([]int, visibleSlots) // Events not shown, by visible slot
+	shown := make([]int, visibleSlots)  // Events shown, by visible slot
 	for i, pos := range eventPositions {
+		count := shown
+		if pos.Column >= lastColumn || pos.Column+pos.ColumnSpan <= firstColumn {
+			count = hidden
+		}
+		for slot := pos.FirstSlot; slot < pos.EndSlot; slot++ {
+			if visible := slot - m.topSlot; visible >= 0 && visible < visibleSlots {
+				count[visible]++
+			}
+		}
+		if pos.Column >= lastColumn || pos.Column+pos.ColumnSpan <= firstColumn {
+			continue
+		}
+		// Cut expanded events at the edges of the shown columns
+		if pos.Column < firstColumn {
+			pos.ColumnSpan -= firstColumn - pos.Column
+			pos.Column = firstColumn
+		}
+		if pos.Column+pos.ColumnSpan > lastColumn {
+			pos.ColumnSpan = lastColumn - pos.Column
+		}
+
 		// Calculate the width for this event based on its column span
 		eventWidth := columnWidth*pos.ColumnSpan + padding*(pos.ColumnSpan-1)
 
@@ -433,10 +484,13 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 		text := ""
 		if visibleStart := pos.Event.Time; visibleStart != nil {
 			// Check if this is the start of the event
-			eventSlot := m.findEventSlot(pos.Event, slotsPerDay, baseDate)
-			visibleEventStart := eventSlot - m.topSlot
+			visibleEventStart := pos.FirstSlot - m.topSlot
 			if visibleEventStart >= 0 {
 				text = pos.Event.Description
+				if _, ok := travelWarnings[pos.Event.ID]; ok {
+					// Not enough time to get here from the previous location
+					text = "⚠ " + text
+				}
 				if m.showEventIDs {
 					text = fmt.Sprintf("[%s] %s", pos.Event.ID, text)
 				}
@@ -447,20 +501,44 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 			}
 		}
 
+		// Show when the event ends: a └ marker on its last row when that's on
+		// screen, and "until HH:MM" where it fits
+		if duration := pos.Event.Duration; duration != nil && *duration > 0 {
+			until := "until " + pos.Event.Time.Add(*duration).Format("15:04")
+			if pos.SpanRows > 1 && pos.EndSlot-m.topSlot <= visibleSlots {
+				marker := "└ " + until
+				if lipgloss.Width(marker) > eventWidth {
+					marker = "└"
+				}
+				text += strings.Repeat("\n", pos.SpanRows-1) + marker
+			} else if text != "" && len(text)+2+len(until) <= eventWidth {
+				text += "  " + until
+			}
+		}
+
 		// Get event colors
 		bgColor := m.getEventBackgroundColor(pos.Event)
 		textColor := m.getEventTextColor(bgColor)
+		if c := pos.Event.Color; c != nil || m.timeAudit {
+			if m.timeAudit {
+				tagColor := m.tagColor(m.primaryTag(pos.Event))
+				c = &tagColor
+				bgColor = rgbToANSI(tagColor)
+			}
+			// Explicit colors can be anything, so pick contrast from the RGB value
+			textColor = lipgloss.ANSIColor(15)
+			if isLightColor(*c) {
+				textColor = lipgloss.ANSIColor(0)
+			}
+		}
 
 		// Create styled block with calculated width
-		block := lipgloss.NewStyle().
+		block := statusBlock(lipgloss.NewStyle().
 			Background(bgColor).
-			Foreground(textColor).
-			Width(eventWidth).
-			Height(pos.SpanRows).
-			Render(text)
+			Foreground(textColor), pos.Event, text, eventWidth, pos.SpanRows)
 
 		// Position the layer
-		xPos := timeWidth + pos.Column*(columnWidth+padding)
+		xPos := timeWidth + (pos.Column-firstColumn)*(columnWidth+padding)
 		yPos := pos.StartRow
 
 		layer := lipgloss.NewLayer(block).
@@ -471,9 +549,134 @@ func (m *Model) createEventBlockLayers(slotsPerDay, visibleSlots, timeWidth, eve
 		layers = append(layers, layer)
 	}
 
+	// Count the hidden events where they start to overlap the shown ones,
+	// peek lists them all. When there's no room for the count next to the
+	// shown column, a badge on it says how many events share the slot, which
+	// edit lets you choose from.
+	if overflow {
+		xPos := timeWidth + shownColumns*(columnWidth+padding)
+		badge := xPos+columnWidth > timeWidth+eventAreaWidth
+		for slot, count := range hidden {
+			if count == 0 || (slot > 0 && hidden[slot-1] == count && (!badge || shown[slot-1] == shown[slot])) {
+				continue
+			}
+			var more string
+			x := xPos
+			if badge {
+				more = m.styles.Help.Render(fmt.Sprintf("(%d)", count+shown[slot]))
+				x = timeWidth + min(columnWidth, eventAreaWidth) - lipgloss.Width(more)
+			} else {
+				more = m.styles.Help.Copy().Width(columnWidth).Render(fmt.Sprintf("+%d more", count))
+			}
+			layers = append(layers, lipgloss.NewLayer(more).
+				X(x).
+				Y(m.slotToRowIndex(slot, slotsPerDay)).
+				Z(len(eventPositions)+1))
+		}
+	}
+
 	return layers
 }
 
+// minColumnWidth is the narrowest an event column gets
+const minColumnWidth = 10
+
+// slotInterval is the slots from start up to end an event takes
+type slotInterval struct {
+	start, end int
+}
+
+// assignColumns colors the interval graph of the events: each interval,
+// sorted by start, gets the lowest column not taken by an interval it
+// overlaps. This uses as few columns as the most events at once.
+func assignColumns(intervals []slotInterval) []int {
+	columns := make([]int, len(intervals))
+	var columnEnds []int // Slot after the last interval in each column
+	for i, interval := range intervals {
+		column := 0
+		for column < len(columnEnds) && columnEnds[column] > interval.start {
+			column++
+		}
+		if column == len(columnEnds) {
+			columnEnds = append(columnEnds, 0)
+		}
+		columnEnds[column] = interval.end
+		columns[i] = column
+	}
+	return columns
+}
+
+// eventLess orders events the way the schedule lays them out: timed events
+// by date and time, then priority, description and ID for stability, and
+// untimed events last
+func eventLess(a, b remind.Event) bool {
+	// Untimed events go last
+	if a.Time == nil && b.Time != nil {
+		return false
+	}
+	if a.Time != nil && b.Time == nil {
+		return true
+	}
+	if a.Time == nil && b.Time == nil {
+		// Sort untimed events by priority, then description, then ID
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Description != b.Description {
+			return a.Description < b.Description
+		}
+		return a.ID < b.ID
+	}
+
+	// Both have times - sort by date first
+	if !a.Date.Equal(b.Date) {
+		return a.Date.Before(b.Date)
+	}
+
+	// Same date - sort by time
+	iTime := a.Time.Hour()*60 + a.Time.Minute()
+	jTime := b.Time.Hour()*60 + b.Time.Minute()
+	if iTime != jTime {
+		return iTime < jTime
+	}
+
+	// Same time - sort by priority (higher priority first)
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+
+	// Sort by description
+	if a.Description != b.Description {
+		return a.Description < b.Description
+	}
+
+	// Finally, sort by ID for absolute stability
+	return a.ID < b.ID
+}
+
+// eventSlotSpan returns how many slots an event takes, one without a
+// duration
+func (m *Model) eventSlotSpan(event remind.Event) int {
+	if event.Duration == nil {
+		return 1
+	}
+	durationMinutes := int(event.Duration.Minutes())
+	increment := m.timeIncrement
+	if increment != 15 && increment != 30 {
+		increment = 60
+	}
+	return (durationMinutes + increment - 1) / increment
+}
+
+// floorDiv divides rounding toward negative infinity, so slots before the
+// selected date fall on the previous days
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
 // slotToRowIndex converts a slot index to a row index, accounting for date separators
 func (m *Model) slotToRowIndex(slotIndex, slotsPerDay int) int {
 	// Count exactly how many date separators appear before this slot
@@ -495,11 +698,18 @@ func (m *Model) slotToRowIndex(slotIndex, slotsPerDay int) int {
 			rowIndex++ // Date separator row
 		}
 
+		// Slots of a collapsed run share the row of its first slot
+		shared := m.collapsed.shared[globalSlot] && i > 0
 		if i == slotIndex {
+			if shared {
+				return rowIndex - 1
+			}
 			return rowIndex
 		}
 
-		rowIndex++ // Time slot row
+		if !shared {
+			rowIndex++ // Time slot row
+		}
 	}
 
 	return rowIndex
@@ -529,66 +739,147 @@ func (m *Model) findEventSlot(event remind.Event, slotsPerDay int, baseDate time
 
 // createSidebarLayer creates the sidebar with calendar and untimed reminders
 func (m *Model) createSidebarLayer(xOffset, width int) *lipgloss.Layer {
+	// Rendered again only when something it shows changed
+	key := m.sidebarKeyFor(width)
+	if m.sidebar.content == "" || m.sidebar.key != key {
+		m.sidebar = sidebarCache{key: key, content: m.renderSidebar(width)}
+	}
+	sidebarContent := m.sidebar.content
+
+	// How current cached sources like p2 are, which changes as they refresh
+	if cacheLines := m.cacheStatusLines(width); len(cacheLines) > 0 {
+		sidebarContent += "\n\n" + strings.Join(cacheLines, "\n")
+	}
+
+	return lipgloss.NewLayer(sidebarContent).
+		X(xOffset).
+		Y(0).
+		Z(1000) // High Z to ensure sidebar is on top
+}
+
+// sidebarKey is what the sidebar depends on, other than the states of cached
+// sources
+type sidebarKey struct {
+	config               *config.Config
+	eventsRevision       int
+	selectedDate         time.Time
+	selectedSlot         int
+	timeIncrement        int
+	width                int
+	minute               time.Time // now, to the minute
+	showAgenda           bool
+	focusUntimed         bool
+	selectedUntimedIndex int
+	untimedScroll        int
+	presentationMode     bool
+	showEventIDs         bool
+	pinsRevision         int
+}
+
+// sidebarCache is the sidebar last rendered, and what it was rendered for
+type sidebarCache struct {
+	key     sidebarKey
+	content string
+}
+
+func (m *Model) sidebarKeyFor(width int) sidebarKey {
+	return sidebarKey{
+		config:               m.config,
+		eventsRevision:       m.eventsRevision,
+		selectedDate:         m.selectedDate,
+		selectedSlot:         m.selectedSlot,
+		timeIncrement:        m.timeIncrement,
+		width:                width,
+		minute:               m.now().Truncate(time.Minute),
+		showAgenda:           m.showAgenda,
+		focusUntimed:         m.focusUntimed,
+		selectedUntimedIndex: m.selectedUntimedIndex,
+		untimedScroll:        m.untimedScroll[m.untimedScrollKey(m.selectedDay())],
+		presentationMode:     m.presentationMode,
+		showEventIDs:         m.showEventIDs,
+		pinsRevision:         m.pinsRevision,
+	}
+}
+
+// renderSidebar renders the mini calendar, the selected events, the
+// untimed reminders, countdowns and pinned events
+func (m *Model) renderSidebar(width int) string {
 	var lines []string
 
 	// Add calendar
 	calendarContent := m.renderMiniCalendar()
 	lines = append(lines, calendarContent)
 
+	// The selected week's busy days
+	lines = append(lines, m.renderWeekStrip(m.now()))
+
 	// Add spacing
 	lines = append(lines, "")
 
-	// Add current slot info
-	selectedContent := m.renderSelectedSlotEvents()
-	lines = append(lines, selectedContent)
+	// Add current slot info, or the whole day when the agenda is shown
+	if m.showAgenda {
+		lines = append(lines, m.renderDayAgenda())
+	} else {
+		lines = append(lines, m.renderSelectedSlotEvents())
+	}
 
 	// Add spacing
 	lines = append(lines, "")
 
 	// Add untimed reminders for the selected date
 	headerText := "Untimed Reminders"
+	if m.dueGroups {
+		headerText += " by Due Date"
+	}
 	if m.focusUntimed {
 		headerText = "▶ " + headerText
 	}
 	lines = append(lines, m.styles.Header.Render(headerText))
+	lines = append(lines, m.renderUntimed(width)...)
 
-	// Collect untimed events for the selected date
-	var untimedEvents []remind.Event
-	for _, event := range m.events {
-		// Only show untimed events for the selected date (compare date only, not time)
-		if event.Time == nil &&
-			event.Date.Year() == m.selectedDate.Year() &&
-			event.Date.Month() == m.selectedDate.Month() &&
-			event.Date.Day() == m.selectedDate.Day() {
-			untimedEvents = append(untimedEvents, event)
+	// Days left until COUNTDOWN deadlines
+	if countdowns := m.countdowns(m.now()); len(countdowns) > 0 {
+		lines = append(lines, "")
+		for _, c := range countdowns {
+			lines = append(lines, m.countdownLine(c, width-2))
 		}
 	}
 
-	// Sort untimed events for consistent ordering
-	sort.Slice(untimedEvents, func(i, j int) bool {
-		// Sort by priority first (higher priority first)
-		if untimedEvents[i].Priority != untimedEvents[j].Priority {
-			return untimedEvents[i].Priority > untimedEvents[j].Priority
-		}
-		// Then by description alphabetically
-		if untimedEvents[i].Description != untimedEvents[j].Description {
-			return untimedEvents[i].Description < untimedEvents[j].Description
-		}
-		// Finally by ID for absolute stability
-		return untimedEvents[i].ID < untimedEvents[j].ID
-	})
+	// Pinned events, whatever the date
+	if pinned := m.pinnedLines(m.now(), width-2); len(pinned) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, pinned...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderUntimed lists the untimed reminders of the selected day, as many as
+// fit and scrolled to the selected one, or grouped by due date
+func (m *Model) renderUntimed(width int) []string {
+	if m.dueGroups {
+		return m.renderDueSections(width)
+	}
+
+	var lines []string
+	day := m.selectedDay()
+	untimedEvents := m.getSortedUntimedEvents(day)
+	offset := m.untimedOffset(day, len(untimedEvents))
+	end := min(offset+m.untimedHeight(), len(untimedEvents))
+	if offset > 0 {
+		lines = append(lines, m.styles.Help.Render(fmt.Sprintf("↑ %d more", offset)))
+	}
 
 	// Display sorted untimed events
 	hasUntimed := len(untimedEvents) > 0
-	for untimedIndex, event := range untimedEvents {
-		line := event.Description
+	for untimedIndex := offset; untimedIndex < end; untimedIndex++ {
+		event := untimedEvents[untimedIndex]
+		line := m.displayEvent(event).Description
 		if event.Priority > remind.PriorityNone {
 			line = strings.Repeat("!", int(event.Priority)) + " " + line
 		}
 		// Truncate if too long for sidebar
-		if len(line) > width-2 {
-			line = line[:width-5] + "..."
-		}
+		line = ansi.Truncate(line, max(width-2, 0), "...")
 
 		// Highlight selected untimed reminder when focused
 		if m.focusUntimed && untimedIndex == m.selectedUntimedIndex {
@@ -599,23 +890,59 @@ func (m *Model) createSidebarLayer(xOffset, width int) *lipgloss.Layer {
 
 		lines = append(lines, line)
 	}
+	if end < len(untimedEvents) {
+		lines = append(lines, m.styles.Help.Render(fmt.Sprintf("↓ %d more", len(untimedEvents)-end)))
+	}
 
 	if !hasUntimed {
 		lines = append(lines, "(no untimed reminders)")
 	}
 
-	sidebarContent := strings.Join(lines, "\n")
+	return lines
+}
 
-	return lipgloss.NewLayer(sidebarContent).
-		X(xOffset).
-		Y(0).
-		Z(1000) // High Z to ensure sidebar is on top
+// cacheStatusLines describes how up to date the results of cached sources are
+func (m *Model) cacheStatusLines(width int) []string {
+	cached, ok := m.source.(remind.CachedSource)
+	if !ok {
+		return nil
+	}
+
+	var lines []string
+	for _, state := range cached.CacheStates() {
+		var line string
+		switch {
+		case state.Loading:
+			line = fmt.Sprintf("%s: loading...", state.Name)
+		case state.Refreshing:
+			line = fmt.Sprintf("%s: refreshing...", state.Name)
+		case state.Err != nil:
+			line = fmt.Sprintf("%s: %v", state.Name, state.Err)
+		case state.Updated.IsZero():
+			continue
+		case state.Stale:
+			line = fmt.Sprintf("%s: stale, from %s", state.Name, state.Updated.Format("15:04"))
+		default:
+			line = fmt.Sprintf("%s: from %s", state.Name, state.Updated.Format("15:04"))
+		}
+		line = ansi.Truncate(line, max(width-2, 0), "...")
+
+		if state.Err != nil || state.Stale {
+			lines = append(lines, m.styles.Priority.Render(line))
+		} else {
+			lines = append(lines, m.styles.Help.Render(line))
+		}
+	}
+	return lines
 }
 
+// degradedBanner is shown while remind is missing
+const degradedBanner = "remind not found: reminders read by urd, some may be missing"
+
 // createStatusBarLayers creates layers for the status bar at the bottom of the screen
 func (m *Model) createStatusBarLayers(visibleSlots int) []*lipgloss.Layer {
 	var layers []*lipgloss.Layer
-	now := time.Now()
+	now := m.now()
 
 	// Add a background layer for the status bar to ensure nothing bleeds through
 	backgroundLine1 := strings.Repeat(" ", m.width)
@@ -633,29 +960,83 @@ func (m *Model) createStatusBarLayers(visibleSlots int) []*lipgloss.Layer {
 	layers = append(layers, bgLayer2)
 
 	// First line: Current time
-	dateStr := now.Format("Monday, January 2 at 15:04")
+	dateStr := now.Format(m.config.DateLayout("Monday, January 2 at 15:04"))
 	currentTime := fmt.Sprintf(" Currently: %s", dateStr)
+	if !m.lastRefresh.IsZero() {
+		currentTime += ", updated " + m.lastRefresh.Format("15:04")
+	}
 	timeLayer := lipgloss.NewLayer(m.styles.Help.Render(currentTime)).
 		X(0).
 		Y(visibleSlots).
 		Z(2000) // High Z to ensure status bar is on top
 	layers = append(layers, timeLayer)
 
+	// Running focus session, right-aligned on the first line, after the
+	// banner saying remind is missing, the tag filter and visual mode
+	var right string
+	if focus := m.focusStatus(now); focus != "" {
+		right = m.styles.Message.Render(focus)
+	}
+	if m.visual {
+		visual := m.styles.Message.Render("-- VISUAL --")
+		if right != "" {
+			visual += "  "
+		}
+		right = visual + right
+	}
+	if m.tagFilter != "" {
+		filter := m.styles.Message.Render("only @" + m.tagFilter)
+		if right != "" {
+			filter += "  "
+		}
+		right = filter + right
+	}
+	if m.remindMissing {
+		banner := m.styles.Priority.Render(degradedBanner)
+		if right != "" {
+			banner += "  "
+		}
+		right = banner + right
+	}
+	if right != "" {
+		right += " "
+		x := m.width - lipgloss.Width(right)
+		if minX := lipgloss.Width(currentTime) + 2; x < minX {
+			x = minX
+		}
+		focusLayer := lipgloss.NewLayer(right).
+			X(x).
+			Y(visibleSlots).
+			Z(2000)
+		layers = append(layers, focusLayer)
+	}
+
 	// Second line: Error message (highest priority), then regular message, then help shortcuts
 	var helpText string
-	if m.syntaxError != nil {
-		// Display syntax error prominently with red background
+	if statusErr := m.statusError(); statusErr != nil {
+		// Display the error prominently with red background
 		errorStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("196")). // Red background
 			Foreground(lipgloss.Color("231")). // White text
 			Bold(true).
 			Width(m.width)
-		errorMsg := fmt.Sprintf(" ERROR: %v", m.syntaxError)
+		errorMsg := " ERROR: " + describeError(statusErr)
+		if _, _, ok := m.errorLocation(); ok {
+			errorMsg += "  (!: edit the line)"
+		}
 		helpLayer := lipgloss.NewLayer(errorStyle.Render(errorMsg)).
 			X(0).
 			Y(visibleSlots + 1).
 			Z(2000)
 		layers = append(layers, helpLayer)
+	} else if len(m.pendingKeys) > 0 {
+		// Show the start of a multi-key binding while waiting for the rest
+		helpText = strings.Join(m.pendingKeys, " ") + "-"
+		helpLayer := lipgloss.NewLayer(m.styles.Message.Render(helpText)).
+			X(0).
+			Y(visibleSlots + 1).
+			Z(2000)
+		layers = append(layers, helpLayer)
 	} else if m.message != "" {
 		helpText = m.message
 		helpLayer := lipgloss.NewLayer(m.styles.Message.Render(helpText)).
@@ -663,6 +1044,13 @@ func (m *Model) createStatusBarLayers(visibleSlots int) []*lipgloss.Layer {
 			Y(visibleSlots + 1).
 			Z(2000)
 		layers = append(layers, helpLayer)
+	} else if m.timeAudit {
+		// Hours per tag of the visible days in place of the help
+		helpLayer := lipgloss.NewLayer(m.auditSummary()).
+			X(0).
+			Y(visibleSlots + 1).
+			Z(2000)
+		layers = append(layers, helpLayer)
 	} else {
 		helpText = "j/k:slot  H/L:day  J/K:week  {/}:month  g:goto  /:search  n:next  z:zoom  o:today  ?:help  q:quit"
 		// Right-align the help text
@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/cwarden/urd/internal/config"
 	"github.com/cwarden/urd/internal/remind"
 )
@@ -922,3 +925,305 @@ func TestEventSlotCalculationWithDifferentIncrements(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []slotInterval
+		want      []int
+	}{
+		{
+			name:      "Back to back events share a column",
+			intervals: []slotInterval{{8, 9}, {9, 10}, {10, 12}},
+			want:      []int{0, 0, 0},
+		},
+		{
+			name:      "More than four events at once",
+			intervals: []slotInterval{{8, 10}, {8, 10}, {8, 10}, {8, 10}, {8, 10}, {8, 10}},
+			want:      []int{0, 1, 2, 3, 4, 5},
+		},
+		{
+			name:      "Freed columns are reused",
+			intervals: []slotInterval{{8, 10}, {8, 9}, {9, 11}, {10, 11}},
+			want:      []int{0, 1, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assignColumns(tt.intervals)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("assignColumns() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestColumnOverflow tests that events beyond the columns that fit are
+// counted instead of drawn over the others
+func TestColumnOverflow(t *testing.T) {
+	baseDate := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		width:         80,
+		height:        30,
+		timeIncrement: 60,
+		selectedDate:  baseDate,
+		topSlot:       8,
+		config:        &config.Config{},
+		styles:        defaultStyles(),
+	}
+	for i := 0; i < 6; i++ {
+		m.events = append(m.events, remind.Event{
+			ID:          string(rune('a' + i)),
+			Date:        baseDate,
+			Time:        timePtr(9, 0),
+			Description: "Meeting",
+			Duration:    durationPtr(60),
+		})
+	}
+
+	// 40 columns fit three at the minimum width: two events and the count
+	layers := m.createEventBlockLayers(24, 20, 7, 40)
+	if len(layers) != 3 {
+		t.Fatalf("Expected 2 event layers and an overflow layer, got %d layers", len(layers))
+	}
+
+	output := lipgloss.NewCanvas(layers...).Render()
+	if !strings.Contains(output, "+4 more") {
+		t.Errorf("Output missing overflow count:\n%s", output)
+	}
+
+	// Scrolling right shows the next columns, up to the last one
+	for i := 0; i < 10; i++ {
+		m.handleHourlyKeys("]", "scroll_right")
+	}
+	if m.columnOffset != 4 {
+		t.Errorf("columnOffset = %d after scrolling right, want 4", m.columnOffset)
+	}
+	layers = m.createEventBlockLayers(24, 20, 7, 40)
+	if len(layers) != 3 || layers[0].GetX() != 7 {
+		t.Errorf("Expected the last 2 events from the time column on and the count, got %d layers", len(layers))
+	}
+	m.handleHourlyKeys("[", "scroll_left")
+	if m.columnOffset != 3 {
+		t.Errorf("columnOffset = %d after scrolling left, want 3", m.columnOffset)
+	}
+
+	// With room for all of them there's no count
+	layers = m.createEventBlockLayers(24, 20, 7, 100)
+	if len(layers) != 6 {
+		t.Errorf("Expected 6 event layers, got %d", len(layers))
+	}
+}
+
+// TestColumnOverflowBadge tests that when only one column fits, the events
+// sharing a slot are counted on it, and edit lets you choose between them
+func TestColumnOverflowBadge(t *testing.T) {
+	baseDate := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		width:         30,
+		height:        30,
+		timeIncrement: 60,
+		selectedDate:  baseDate,
+		selectedSlot:  9,
+		topSlot:       8,
+		config:        &config.Config{},
+		styles:        defaultStyles(),
+	}
+	for i := 0; i < 3; i++ {
+		m.events = append(m.events, remind.Event{
+			ID:          string(rune('a' + i)),
+			Date:        baseDate,
+			Time:        timePtr(9, 0),
+			Description: "Meeting",
+			Duration:    durationPtr(60),
+			Filename:    "test.rem",
+			LineNumber:  i + 1,
+		})
+	}
+
+	// 15 columns fit one event and no count next to it
+	layers := m.createEventBlockLayers(24, 20, 7, 15)
+	if len(layers) != 2 {
+		t.Fatalf("Expected an event layer and a badge, got %d layers", len(layers))
+	}
+	output := lipgloss.NewCanvas(layers...).Render()
+	if !strings.Contains(output, "(3)") {
+		t.Errorf("Output missing badge:\n%s", output)
+	}
+	if strings.Contains(output, "more") {
+		t.Errorf("Count drawn outside the event area:\n%s", output)
+	}
+	if width := lipgloss.Width(output); width > 7+15 {
+		t.Errorf("Output is %d wide, want at most %d", width, 7+15)
+	}
+
+	m.handleHourlyKeys("enter", "edit")
+	if m.mode != ViewEventSelector || len(m.eventChoices) != 3 {
+		t.Errorf("Expected the event selector with 3 events, got mode %v with %d", m.mode, len(m.eventChoices))
+	}
+}
+
+// TestColumnsStableWhileScrolling tests that an event keeps its column when
+// the event it overlaps scrolls out of view
+func TestColumnsStableWhileScrolling(t *testing.T) {
+	baseDate := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		width:         120,
+		height:        30,
+		timeIncrement: 60,
+		selectedDate:  baseDate,
+		config:        &config.Config{},
+		styles:        defaultStyles(),
+		events: []remind.Event{
+			{Date: baseDate, Time: timePtr(8, 0), Description: "Early", Duration: durationPtr(120)},
+			{Date: baseDate, Time: timePtr(9, 0), Description: "Late", Duration: durationPtr(180)},
+		},
+	}
+
+	var x []int
+	for _, top := range []int{8, 10} {
+		m.topSlot = top
+		layers := m.createEventBlockLayers(24, 5, 7, 80)
+		x = append(x, layers[len(layers)-1].GetX())
+	}
+	if x[0] != x[1] {
+		t.Errorf("Late moved from x=%d to x=%d when scrolling", x[0], x[1])
+	}
+}
+
+// TestEventEndMarkers tests that blocks show when their event ends
+func TestEventEndMarkers(t *testing.T) {
+	baseDate := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		width:         120,
+		height:        30,
+		timeIncrement: 30,
+		selectedDate:  baseDate,
+		topSlot:       16,
+		config:        &config.Config{},
+		styles:        defaultStyles(),
+	}
+
+	tests := []struct {
+		name  string
+		event remind.Event
+		want  string
+		not   string
+	}{
+		{
+			name:  "Long event gets a marker on its last row",
+			event: remind.Event{Date: baseDate, Time: timePtr(9, 0), Description: "Workshop", Duration: durationPtr(150)},
+			want:  "└ until 11:30",
+		},
+		{
+			name:  "One slot event says until where it fits",
+			event: remind.Event{Date: baseDate, Time: timePtr(9, 0), Description: "Standup", Duration: durationPtr(15)},
+			want:  "Standup  until 09:15",
+			not:   "└",
+		},
+		{
+			name:  "Event ending off screen says until after its description",
+			event: remind.Event{Date: baseDate, Time: timePtr(9, 0), Description: "Offsite", Duration: durationPtr(600)},
+			want:  "Offsite  until 19:00",
+			not:   "└",
+		},
+		{
+			name:  "Event without a duration has no end",
+			event: remind.Event{Date: baseDate, Time: timePtr(9, 0), Description: "Call"},
+			not:   "until",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.events = []remind.Event{tt.event}
+			output := lipgloss.NewCanvas(m.createEventBlockLayers(48, 10, 7, 60)...).Render()
+			if tt.want != "" && !strings.Contains(output, tt.want) {
+				t.Errorf("Output missing %q:\n%s", tt.want, output)
+			}
+			if tt.not != "" && strings.Contains(output, tt.not) {
+				t.Errorf("Output has %q:\n%s", tt.not, output)
+			}
+		})
+	}
+}
+
+func TestSidebarCache(t *testing.T) {
+	m := snapshotModel()
+	m.width, m.height = 100, 24
+	m.eventsLoadedFor = m.selectedDate
+	render := func() string {
+		return lipgloss.NewCanvas(m.createSidebarLayer(0, 30)).Render()
+	}
+
+	first := render()
+	if !strings.Contains(first, "Pick up dry cleaning") {
+		t.Fatalf("Sidebar missing the untimed reminder:\n%s", first)
+	}
+
+	// Nothing the sidebar depends on changed, so it isn't rendered again
+	m.events[5].Description = "Pick up laundry"
+	if got := render(); got != first {
+		t.Errorf("Expected the cached sidebar, got:\n%s", got)
+	}
+
+	// Loading events renders it again
+	m.setEvents(m.events)
+	if got := render(); !strings.Contains(got, "Pick up laundry") {
+		t.Errorf("Expected the sidebar rendered again after loading events:\n%s", got)
+	}
+
+	// So does moving the selection
+	m.selectedSlot = 14
+	if got := render(); !strings.Contains(got, "Pay invoices") {
+		t.Errorf("Expected the selected event at 14:00 in the sidebar:\n%s", got)
+	}
+}
+
+// cacheStatesSource reports fixed cache states
+type cacheStatesSource struct {
+	remind.ReminderSource
+	states []remind.CacheState
+}
+
+func (s *cacheStatesSource) Refresh() {}
+
+func (s *cacheStatesSource) CacheStates() []remind.CacheState {
+	return s.states
+}
+
+func TestCacheStatusLinesTruncate(t *testing.T) {
+	m := snapshotModel()
+	m.source = &cacheStatesSource{states: []remind.CacheState{
+		{Name: "jira", Err: errors.New("résumé écrit «trop» tard — réessayez")},
+	}}
+
+	for _, width := range []int{0, 3, 5, 12, 30} {
+		lines := m.cacheStatusLines(width)
+		if len(lines) != 1 {
+			t.Fatalf("width %d: got %d lines", width, len(lines))
+		}
+		line := ansi.Strip(lines[0])
+		if !utf8.ValidString(line) {
+			t.Errorf("width %d: invalid UTF-8 in %q", width, line)
+		}
+		if got := ansi.StringWidth(line); got > max(width-2, 0) {
+			t.Errorf("width %d: %q is %d cells wide", width, line, got)
+		}
+	}
+	if line := ansi.Strip(m.cacheStatusLines(12)[0]); line != "jira: r..." {
+		t.Errorf("cacheStatusLines(12) = %q", line)
+	}
+
+	// The untimed reminders in a sidebar as narrow are cut the same way
+	m.eventsLoadedFor = m.selectedDate
+	for _, line := range m.renderUntimed(3) {
+		if got := ansi.StringWidth(line); got > 1 {
+			t.Errorf("renderUntimed(3) line %q is %d cells wide", ansi.Strip(line), got)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// savedClipboard is the clipboard as kept in the clipboard file
+type savedClipboard struct {
+	Event remind.Event
+	Cut   bool // removed from its source, so only kept here
+}
+
+// setClipboard puts event on the clipboard, or empties it when nil, and
+// keeps it in the clipboard file
+func (m *Model) setClipboard(event *remind.Event, cut bool) {
+	m.clipboardEvent = event
+	m.clipboardCut = cut
+	if err := m.saveClipboard(); err != nil {
+		m.showError("Clipboard not saved", err)
+	}
+}
+
+// saveClipboard writes the clipboard to the clipboard file, removing the
+// file when the clipboard is empty
+func (m *Model) saveClipboard() error {
+	if m.clipboardFile == "" {
+		return nil
+	}
+	if m.clipboardEvent == nil {
+		if err := os.Remove(m.clipboardFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(savedClipboard{Event: *m.clipboardEvent, Cut: m.clipboardCut})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.clipboardFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.clipboardFile, data, 0600)
+}
+
+// restoreClipboard reads the clipboard back from the clipboard file,
+// warning when it holds a cut event that is only kept there
+func (m *Model) restoreClipboard() {
+	if m.clipboardFile == "" {
+		return
+	}
+	data, err := os.ReadFile(m.clipboardFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			m.showError("Clipboard not restored", err)
+		}
+		return
+	}
+	var saved savedClipboard
+	if err := json.Unmarshal(data, &saved); err != nil {
+		m.showError("Clipboard not restored", err)
+		return
+	}
+	m.clipboardEvent = &saved.Event
+	m.clipboardCut = saved.Cut
+	if saved.Cut {
+		m.showMessage(fmt.Sprintf("Cut event waiting to be pasted: %s", m.displayEvent(saved.Event).Description))
+	}
+}
+
+// copyText copies the selected date in ISO format, or the description or
+// REM line of each selected event, one per line
+func (m *Model) copyText(action string) tea.Cmd {
+	if action == "copy_date" {
+		date := m.selectedDay().Format("2006-01-02")
+		m.showMessage("Copied " + date)
+		return m.copyToClipboard(date)
+	}
+
+	events := m.selectedEvents()
+	if len(events) == 0 {
+		m.showMessage("No event to copy")
+		return nil
+	}
+
+	var lines []string
+	for _, event := range events {
+		switch action {
+		case "copy_description":
+			lines = append(lines, m.displayEvent(event).Description)
+		case "copy_rem_line":
+			if m.presentationMode && event.IsPrivate() {
+				m.showMessage("Private reminders can't be copied in presentation mode")
+				return nil
+			}
+			line, err := remind.SourceLine(event)
+			if err != nil {
+				m.showError("Failed to copy REM line", err)
+				return nil
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 1 {
+		m.showMessage("Copied " + lines[0])
+	} else {
+		m.showMessage(fmt.Sprintf("Copied %d lines", len(lines)))
+	}
+	return m.copyToClipboard(strings.Join(lines, "\n"))
+}
+
+// copyToClipboard puts text on the system clipboard. It always sends OSC52,
+// which the terminal handles even over SSH, and also uses a local clipboard
+// tool when one is available for terminals that ignore OSC52.
+func (m *Model) copyToClipboard(text string) tea.Cmd {
+	if m.config.ReadOnly {
+		m.showError("Not copying", errReplaying)
+		return nil
+	}
+	cmds := []tea.Cmd{tea.SetClipboard(text)}
+	if tool := clipboardTool(); tool != nil {
+		cmds = append(cmds, func() tea.Msg {
+			cmd := exec.Command(tool[0], tool[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			// OSC52 was sent too, so a failing tool isn't worth reporting
+			_ = cmd.Run()
+			return nil
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// clipboardTool returns the command line of a local clipboard tool, or nil
+func clipboardTool() []string {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if os.Getenv("DISPLAY") != "" {
+			candidates = append(candidates,
+				[]string{"xclip", "-selection", "clipboard"},
+				[]string{"xsel", "--clipboard", "--input"})
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestClipboardSurvivesRestart(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "urd", "clipboard.json")
+	event := remind.Event{
+		Date:        time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+		Time:        timePtr(10, 0),
+		Duration:    durationPtr(30),
+		Description: "Call Sam",
+		Tags:        []string{"work"},
+	}
+
+	m := &Model{config: &config.Config{}, clipboardFile: file}
+	m.setClipboard(&event, true)
+
+	// Started again, the cut event is back on the clipboard
+	restarted := &Model{config: &config.Config{}, clipboardFile: file}
+	restarted.restoreClipboard()
+	if restarted.clipboardEvent == nil || !restarted.clipboardCut {
+		t.Fatal("expected the cut event to be restored")
+	}
+	if got := restarted.clipboardEvent; got.Description != "Call Sam" || got.Time.Hour() != 10 || *got.Duration != 30*time.Minute || got.Tags[0] != "work" {
+		t.Errorf("restored %+v", got)
+	}
+	if !strings.Contains(restarted.message, "Cut event waiting to be pasted: Call Sam") {
+		t.Errorf("expected a warning about the cut event, got %q", restarted.message)
+	}
+
+	// Once pasted, nothing is left to restore
+	restarted.setClipboard(nil, false)
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("expected the clipboard file to be removed, got %v", err)
+	}
+
+	// Copies are restored without a warning
+	m.setClipboard(&event, false)
+	restarted = &Model{config: &config.Config{}, clipboardFile: file}
+	restarted.restoreClipboard()
+	if restarted.clipboardEvent == nil || restarted.clipboardCut || restarted.message != "" {
+		t.Errorf("expected the copied event back quietly, got %+v, %q", restarted.clipboardEvent, restarted.message)
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// colorNames are the color names of urdrc color specs, as ANSI colors
+var colorNames = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3,
+	"blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+}
+
+// parseColorSpec converts the color spec of an urdrc color line to a style.
+// A spec is a foreground and an optional background color, each a name, a
+// 256-color number or #rrggbb, along with any of bold, underline and
+// reverse. "default" leaves a color as it is.
+func parseColorSpec(spec string) (lipgloss.Style, error) {
+	style := lipgloss.NewStyle()
+	colors := 0
+	for _, word := range strings.Fields(strings.ToLower(strings.Trim(spec, `"`))) {
+		switch word {
+		case "bold":
+			style = style.Bold(true)
+			continue
+		case "underline":
+			style = style.Underline(true)
+			continue
+		case "reverse":
+			style = style.Reverse(true)
+			continue
+		}
+
+		var c color.Color
+		if n, ok := colorNames[word]; ok {
+			c = lipgloss.ANSIColor(n)
+		} else if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+			c = lipgloss.ANSIColor(n)
+		} else if strings.HasPrefix(word, "#") && len(word) == 7 {
+			if _, err := strconv.ParseUint(word[1:], 16, 32); err != nil {
+				return style, fmt.Errorf("invalid color: %s", word)
+			}
+			c = lipgloss.Color(word)
+		} else if word != "default" {
+			return style, fmt.Errorf("unknown color: %s", word)
+		}
+
+		switch colors {
+		case 0:
+			if c != nil {
+				style = style.Foreground(c)
+			}
+		case 1:
+			if c != nil {
+				style = style.Background(c)
+			}
+		default:
+			return style, fmt.Errorf("more than a foreground and background color: %s", spec)
+		}
+		colors++
+	}
+	return style, nil
+}
+
+// applyColors sets the styles urdrc color lines can change. Specs that
+// don't parse leave the style as it is.
+func (s *Styles) applyColors(colors map[string]string) {
+	for element, style := range map[string]*lipgloss.Style{
+		"today_separator":   &s.TodaySeparator,
+		"weekend_separator": &s.WeekendSeparator,
+		"weekend_shade":     &s.WeekendShade,
+		"off_hours_shade":   &s.OffHoursShade,
+	} {
+		spec, ok := colors[element]
+		if !ok {
+			continue
+		}
+		if parsed, err := parseColorSpec(spec); err == nil {
+			*style = parsed
+		}
+	}
+}
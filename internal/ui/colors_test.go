@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestParseColorSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    lipgloss.Style
+		wantErr bool
+	}{
+		{spec: "yellow", want: lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(3))},
+		{spec: "black yellow bold", want: lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(0)).Background(lipgloss.ANSIColor(3)).Bold(true)},
+		{spec: "default 235", want: lipgloss.NewStyle().Background(lipgloss.ANSIColor(235))},
+		{spec: "#ff8000 reverse", want: lipgloss.NewStyle().Foreground(lipgloss.Color("#ff8000")).Reverse(true)},
+		{spec: "underline", want: lipgloss.NewStyle().Underline(true)},
+		{spec: "chartreuse", wantErr: true},
+		{spec: "#ff80zz", wantErr: true},
+		{spec: "red green blue", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseColorSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseColorSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.GetForeground() != tt.want.GetForeground() || got.GetBackground() != tt.want.GetBackground() ||
+				got.GetBold() != tt.want.GetBold() || got.GetReverse() != tt.want.GetReverse() ||
+				got.GetUnderline() != tt.want.GetUnderline() {
+				t.Errorf("parseColorSpec(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyColors(t *testing.T) {
+	styles := DefaultStyles()
+	styles.applyColors(map[string]string{
+		"weekend_separator": "magenta",
+		"weekend_shade":     "no such color",
+	})
+	if got := styles.WeekendSeparator.GetForeground(); got != lipgloss.ANSIColor(5) {
+		t.Errorf("weekend_separator foreground = %v, want magenta", got)
+	}
+	if got := styles.WeekendShade.GetBackground(); got != DefaultStyles().WeekendShade.GetBackground() {
+		t.Errorf("invalid weekend_shade changed the style to %v", got)
+	}
+}
+
+func TestWeekendShade(t *testing.T) {
+	friday := time.Date(2025, 8, 29, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		timeIncrement: 60,
+		selectedDate:  friday,
+		topSlot:       20, // Friday 20:00 on, with the date separators up to Saturday 03:00
+		config:        &config.Config{ShadeWeekends: true},
+		styles:        defaultStyles(),
+	}
+
+	layers := m.createWeekendShadeLayers(24, 10, 7, 60)
+	if len(layers) != 4 {
+		t.Fatalf("Expected the 4 Saturday rows on screen shaded, got %d layers", len(layers))
+	}
+	// Friday's 4 rows and the date separator come first
+	if y := layers[0].GetY(); y != 6 {
+		t.Errorf("First shaded row is %d, want 6", y)
+	}
+}
+
+func TestOffHoursShade(t *testing.T) {
+	thursday := time.Date(2025, 8, 28, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		timeIncrement: 60,
+		selectedDate:  thursday,
+		topSlot:       24 + 10, // Friday 10:00 on
+		config: &config.Config{
+			ShadeOffHours: true,
+			WorkStart:     9 * time.Hour,
+			WorkEnd:       17 * time.Hour,
+			WeekdayHours:  map[time.Weekday]config.WorkHours{time.Friday: {Start: 9 * time.Hour, End: 14 * time.Hour}},
+		},
+		styles: defaultStyles(),
+	}
+
+	// Fridays end at 14:00, so the rows from 14:00 on are shaded
+	layers := m.createOffHoursShadeLayers(24, 8, 7, 60)
+	var rows []int
+	for _, layer := range layers {
+		rows = append(rows, layer.GetY())
+	}
+	// The date separator comes first, then 10:00 to 13:00
+	if fmt.Sprint(rows) != "[5 6 7]" {
+		t.Errorf("Shaded rows %v, want the rows of 14:00 to 16:00, [5 6 7]", rows)
+	}
+
+	// Openings for suggestions end with the working hours too
+	friday := thursday.AddDate(0, 0, 1)
+	openings := m.openings(nil, friday, friday.Add(13*time.Hour), time.Hour)
+	if len(openings) != 1 || openings[0].Format("15:04") != "13:00" {
+		t.Errorf("Expected a single opening at 13:00, got %v", openings)
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// compactRows is how the schedule is laid out in compact mode, where runs
+// of empty slots take a single row
+type compactRows struct {
+	runs   map[int]int  // Length of each collapsed run, by the slot it starts at
+	shared map[int]bool // Slots shown on the row of the run they belong to
+	shown  int          // Slots from topSlot on that fit in the rows
+}
+
+// compactLayout lays out the slots from topSlot on in rows rows, each run of
+// two or more empty slots of a day taking one row. The run with the selected
+// slot is shown expanded, so moving into a run expands it.
+func (m *Model) compactLayout(slotsPerDay, rows int) compactRows {
+	layout := compactRows{runs: make(map[int]int), shared: make(map[int]bool)}
+
+	// Every day needs a row for its separator, so no more than rows days
+	// are shown
+	baseDate := time.Date(m.selectedDate.Year(), m.selectedDate.Month(), m.selectedDate.Day(), 0, 0, 0, 0, m.selectedDate.Location())
+	firstDay := floorDiv(m.topSlot, slotsPerDay)
+	occupied := make(map[int]bool)
+	for _, event := range m.eventsReaching(baseDate.AddDate(0, 0, firstDay), baseDate.AddDate(0, 0, firstDay+rows)) {
+		if event.Time == nil {
+			continue
+		}
+		start := m.findEventSlot(event, slotsPerDay, baseDate)
+		for slot := start; slot < start+m.eventSlotSpan(event); slot++ {
+			occupied[slot] = true
+		}
+	}
+
+	row, slot, prevDay := 0, m.topSlot, firstDay-1
+	for row < rows {
+		day := floorDiv(slot, slotsPerDay)
+		if day != prevDay {
+			prevDay = day
+			if row++; row >= rows {
+				break
+			}
+		}
+
+		// The empty slots from here to the end of the run or the day
+		end := slot
+		for end < (day+1)*slotsPerDay && !occupied[end] {
+			end++
+		}
+		if end-slot < 2 || (m.selectedSlot >= slot && m.selectedSlot < end) {
+			// Shown slot by slot, up to the next event or the end of the run
+			next := max(end, slot+1)
+			for ; slot < next && row < rows; slot++ {
+				row++
+			}
+			continue
+		}
+		layout.runs[slot] = end - slot
+		for shared := slot + 1; shared < end; shared++ {
+			layout.shared[shared] = true
+		}
+		slot = end
+		row++
+	}
+	layout.shown = slot - m.topSlot
+	return layout
+}
+
+// followCompact scrolls the compact schedule as little as it takes to show
+// the selected slot. Runs expanding as the selection moves into them make
+// how far that is vary.
+func (m *Model) followCompact() {
+	if m.selectedSlot < m.topSlot {
+		m.topSlot = m.selectedSlot
+		return
+	}
+	for !m.isSlotVisible(m.selectedSlot) {
+		m.topSlot++
+	}
+}
+
+// emptyRunLabel is the row standing in for a run of empty slots
+func (m *Model) emptyRunLabel(slots int) string {
+	length := time.Duration(slots*m.timeIncrement) * time.Minute
+	switch {
+	case length == time.Hour:
+		return "── 1 empty hour ──"
+	case length%time.Hour == 0:
+		return fmt.Sprintf("── %d empty hours ──", int(length.Hours()))
+	}
+	return fmt.Sprintf("── %s empty ──", formatDuration(length))
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestCompactLayout(t *testing.T) {
+	m := snapshotModel()
+	m.compact = true
+	m.height = 24
+
+	// 12:00 and 13:00 are empty, as are 15:00 to the end of the day
+	layout := m.compactLayout(24, 22)
+	if layout.runs[12] != 2 || !layout.shared[13] || layout.runs[15] != 9 {
+		t.Errorf("unexpected runs %v", layout.runs)
+	}
+	if label := m.emptyRunLabel(layout.runs[15]); label != "── 9 empty hours ──" {
+		t.Errorf("label = %q", label)
+	}
+	if !m.isSlotVisible(40) || m.isSlotVisible(72) {
+		t.Errorf("expected the next day's empty hours collapsed to fit, showing %d slots", layout.shown)
+	}
+
+	// Moving up into the evening from the next day expands it, and the
+	// schedule scrolls to keep the selection on screen
+	m.selectedSlot, m.topSlot = 24, 24
+	m.handleHourlyKeys("k", "scroll_up")
+	if m.selectedSlot != 23 || !m.isSlotVisible(m.selectedSlot) {
+		t.Fatalf("selected slot %d not visible from %d", m.selectedSlot, m.topSlot)
+	}
+	if layout := m.compactLayout(24, 22); layout.runs[15] != 0 || layout.shared[16] {
+		t.Errorf("expected the run with the selection expanded, got %v", layout.runs)
+	}
+
+	m.handleHourlyKeys("c", "toggle_compact")
+	if m.compact {
+		t.Error("expected toggle_compact to show every slot again")
+	}
+}
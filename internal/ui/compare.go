@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// compareKind is how an event of one compared day differs on the other
+type compareKind int
+
+const (
+	compareSame      compareKind = iota // at the same time on both days
+	compareChanged                      // on both days, at another time or for another length
+	compareOnlyLeft                     // only on the first day
+	compareOnlyRight                    // only on the second day
+)
+
+// compareRow is an event of the compared days, side by side with the same
+// event on the other day if it has one
+type compareRow struct {
+	left, right *remind.Event
+	kind        compareKind
+}
+
+// compareMarkers mark how each row differs
+var compareMarkers = map[compareKind]string{
+	compareSame:      " ",
+	compareChanged:   "~",
+	compareOnlyLeft:  "-",
+	compareOnlyRight: "+",
+}
+
+// compareKey returns what an event is matched with the other day by
+func compareKey(event remind.Event) string {
+	return strings.ToLower(strings.Join(strings.Fields(event.Description), " "))
+}
+
+// sameSlot reports whether two events take place at the same time of day for
+// as long, or are both untimed
+func sameSlot(a, b remind.Event) bool {
+	if (a.Time == nil) != (b.Time == nil) {
+		return false
+	}
+	if a.Time != nil && (a.Time.Hour() != b.Time.Hour() || a.Time.Minute() != b.Time.Minute()) {
+		return false
+	}
+	if (a.Duration == nil) != (b.Duration == nil) {
+		return false
+	}
+	return a.Duration == nil || *a.Duration == *b.Duration
+}
+
+// compareRows pairs the events of two days by their description, first
+// those at the same time on both, in the order of the day
+func compareRows(left, right []remind.Event) []compareRow {
+	left, right = agendaOrder(left), agendaOrder(right)
+	rows := make([]compareRow, len(left))
+	used := make([]bool, len(right))
+	match := func(i int, same bool) {
+		for j := range right {
+			if used[j] || compareKey(right[j]) != compareKey(left[i]) || same && !sameSlot(left[i], right[j]) {
+				continue
+			}
+			used[j] = true
+			rows[i].right = &right[j]
+			rows[i].kind = compareChanged
+			if same {
+				rows[i].kind = compareSame
+			}
+			return
+		}
+	}
+	for i := range left {
+		rows[i] = compareRow{left: &left[i], kind: compareOnlyLeft}
+		match(i, true)
+	}
+	for i := range left {
+		if rows[i].right == nil {
+			match(i, false)
+		}
+	}
+	for j := range right {
+		if !used[j] {
+			rows = append(rows, compareRow{right: &right[j], kind: compareOnlyRight})
+		}
+	}
+
+	// Untimed events first, then by the time of day on either day
+	minute := func(row compareRow) int {
+		event := row.left
+		if event == nil {
+			event = row.right
+		}
+		if event.Time == nil {
+			return -1
+		}
+		return event.Time.Hour()*60 + event.Time.Minute()
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return minute(rows[i]) < minute(rows[j])
+	})
+	return rows
+}
+
+// startCompare opens the comparison of the selected day with the same day
+// a week later
+func (m *Model) startCompare() {
+	day := m.selectedDay()
+	m.compareDays = [2]time.Time{day, day.AddDate(0, 0, 7)}
+	m.loadCompare()
+	m.mode = ViewCompare
+}
+
+// loadCompare gets the events of the compared days, from the source when
+// they aren't loaded for the schedule
+func (m *Model) loadCompare() {
+	for i, day := range m.compareDays {
+		m.compareEvents[i] = nil
+		if !m.eventsLoadedFor.IsZero() && abs(int(day.Sub(m.eventsLoadedFor).Hours()/24)) < 14 {
+			m.compareEvents[i] = m.eventsOn(day)
+			continue
+		}
+		all, err := m.source.GetEvents(day, day.AddDate(0, 0, 1))
+		if err != nil {
+			m.showMessage(fmt.Sprintf("Error loading %s: %v", day.Format(m.config.DateLayout("Jan 2")), err))
+			continue
+		}
+		events, _ := splitSpecials(all)
+		for _, event := range m.filterTagged(events) {
+			if sameDay(event.Date, day) {
+				m.compareEvents[i] = append(m.compareEvents[i], event)
+			}
+		}
+	}
+}
+
+func (m *Model) handleCompareKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	days := 0
+	switch keyName(msg) {
+	case "<esc>", "q":
+		m.mode = ViewHourly
+		m.compareEvents = [2][]remind.Event{}
+		return m, nil
+	case "l", "<right>":
+		days = 1
+	case "h", "<left>":
+		days = -1
+	case "]":
+		days = 7
+	case "[":
+		days = -7
+	case "<enter>":
+		// Go to the second day, to reschedule its events
+		m.mode = ViewHourly
+		m.compareEvents = [2][]remind.Event{}
+		m.selectedDate = m.compareDays[1]
+		m.selectedSlot = m.getNoonSlot()
+		m.centerSelectedSlot()
+		m.loadEventsForSchedule()
+		return m, nil
+	}
+	if days != 0 {
+		m.compareDays[1] = m.compareDays[1].AddDate(0, 0, days)
+		m.loadCompare()
+	}
+	return m, nil
+}
+
+func (m *Model) viewCompare() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Compare Days"))
+	sections = append(sections, "")
+
+	width := (m.width - 3) / 2
+	if width < 20 {
+		width = 20
+	}
+	cell := func(event *remind.Event) string {
+		if event == nil {
+			return ""
+		}
+		e := m.displayEvent(*event)
+		return agendaWhen(e) + " " + e.Description
+	}
+	line := func(marker, left, right string) string {
+		return marker + " " + fitWidth(left, width-2) + " " + fitWidth(right, width)
+	}
+
+	layout := m.config.DateLayout("Mon Jan 2, 2006")
+	sections = append(sections, m.styles.Header.Render(line(" ", m.compareDays[0].Format(layout), m.compareDays[1].Format(layout))))
+
+	rows := compareRows(m.compareEvents[0], m.compareEvents[1])
+	if len(rows) == 0 {
+		sections = append(sections, m.styles.Help.Render("(nothing scheduled)"))
+	}
+	changed := 0
+	for _, row := range rows {
+		text := line(compareMarkers[row.kind], cell(row.left), cell(row.right))
+		if row.kind == compareSame {
+			sections = append(sections, m.styles.Normal.Render(text))
+		} else {
+			changed++
+			sections = append(sections, m.styles.Priority.Render(text))
+		}
+	}
+
+	sections = append(sections, "")
+	if len(rows) > 0 {
+		sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("%d of %d events differ", changed, len(rows))))
+	}
+	sections = append(sections, m.styles.Help.Render("h/l: Other day -/+ a day  [/]: -/+ a week  Enter: Go to the other day  Esc: Close"))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// fitWidth pads or cuts s to width columns
+func fitWidth(s string, width int) string {
+	s = ansi.Truncate(s, width, "...")
+	return s + strings.Repeat(" ", width-lipgloss.Width(s))
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestCompareRows(t *testing.T) {
+	monday := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	next := monday.AddDate(0, 0, 7)
+	at := func(day time.Time, hour, minute int) *time.Time {
+		tm := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		return &tm
+	}
+	hour := time.Hour
+
+	left := []remind.Event{
+		{Date: monday, Time: at(monday, 9, 0), Duration: &hour, Description: "Standup"},
+		{Date: monday, Time: at(monday, 11, 0), Duration: &hour, Description: "1:1 with Sam"},
+		{Date: monday, Time: at(monday, 15, 0), Description: "Retro"},
+		{Date: monday, Description: "Water plants"},
+	}
+	right := []remind.Event{
+		{Date: next, Time: at(next, 9, 0), Duration: &hour, Description: "standup"},
+		{Date: next, Time: at(next, 14, 0), Duration: &hour, Description: "1:1 with Sam"},
+		{Date: next, Time: at(next, 10, 0), Description: "Dentist"},
+		{Date: next, Description: "Water plants"},
+	}
+
+	var got []string
+	for _, row := range compareRows(left, right) {
+		desc := ""
+		if row.left != nil {
+			desc = row.left.Description
+		} else {
+			desc = row.right.Description
+		}
+		got = append(got, compareMarkers[row.kind]+desc)
+	}
+	expected := []string{" Water plants", " Standup", "+Dentist", "~1:1 with Sam", "-Retro"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("compareRows = %q, want %q", got, expected)
+	}
+}
+
+func TestViewCompare(t *testing.T) {
+	m := snapshotModel()
+	m.width = 120
+	m.eventsLoadedFor = m.selectedDate
+	m.startCompare()
+	if m.mode != ViewCompare {
+		t.Fatal("expected compare mode")
+	}
+	if !m.compareDays[1].Equal(m.compareDays[0].AddDate(0, 0, 7)) {
+		t.Errorf("expected to compare with a week later, got %v", m.compareDays)
+	}
+
+	view := ansi.Strip(m.viewCompare())
+	for _, want := range []string{"Mon Aug 25, 2025", "Mon Sep 1, 2025", "- 09:00-12:00 Quarterly planning"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m.handleCompareKeys(tea.KeyPressMsg{Code: 'l', Text: "l"})
+	if m.compareDays[1].Day() != 2 {
+		t.Errorf("expected l to move the second day on, got %v", m.compareDays[1])
+	}
+}
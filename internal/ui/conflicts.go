@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// overlapping returns the timed events taking place during length from
+// start, on its day. Events without a duration take up the minute they
+// start; cancelled ones take up none.
+func overlapping(events []remind.Event, start time.Time, length time.Duration) []remind.Event {
+	end := start.Add(length)
+	var found []remind.Event
+	for _, event := range events {
+		if event.Time == nil || !sameDay(*event.Time, start) || event.IsCancelled() {
+			continue
+		}
+		eventEnd := event.Time.Add(time.Minute)
+		if event.Duration != nil && *event.Duration > 0 {
+			eventEnd = event.Time.Add(*event.Duration)
+		}
+		if event.Time.Before(end) && eventEnd.After(start) {
+			found = append(found, event)
+		}
+	}
+	return found
+}
+
+// nextFreeStart returns the first start from start on, rounded up to
+// slotStep, with nothing taking place for length and done by end unless end
+// is zero, reporting false when there's none left on its day
+func nextFreeStart(events []remind.Event, start, end time.Time, length time.Duration) (time.Time, bool) {
+	next := start.Truncate(slotStep)
+	if next.Before(start) {
+		next = next.Add(slotStep)
+	}
+	for ; sameDay(next, start) && (end.IsZero() || !next.Add(length).After(end)); next = next.Add(slotStep) {
+		if len(overlapping(events, next, length)) == 0 {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// quickAddSlot returns when the quick-add input would take place and for
+// how long, one schedule slot unless it has a duration. ok is false for
+// untimed input.
+func (m *Model) quickAddSlot() (start time.Time, length time.Duration, ok bool) {
+	parsed, _, err := remind.QuickEventLine(m.quickAddInput.Value(), m.now(), m.config.QuickDayFirst)
+	if err != nil || !parsed.HasTime {
+		return time.Time{}, 0, false
+	}
+	length = parsed.Duration
+	if length <= 0 {
+		length = time.Duration(m.timeIncrement) * time.Minute
+	}
+	if length <= 0 {
+		length = time.Hour
+	}
+	return parsed.Time, length, true
+}
+
+// quickAddConflicts returns the events already taking place when the
+// quick-add input would, and the next free start that day if there is one,
+// within working hours when the input is
+func (m *Model) quickAddConflicts() ([]remind.Event, *time.Time) {
+	start, length, ok := m.quickAddSlot()
+	if !ok {
+		return nil, nil
+	}
+	events := m.eventsOn(start)
+	conflicts := overlapping(events, start, length)
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+	var end time.Time
+	if from, until := m.workingHours(start); !start.Before(from) && start.Before(until) {
+		end = until
+	}
+	if free, ok := nextFreeStart(events, start, end, length); ok {
+		return conflicts, &free
+	}
+	return conflicts, nil
+}
+
+// moveQuickAddToFreeSlot changes the time of the quick-add input to the
+// next free slot, when it conflicts with existing events
+func (m *Model) moveQuickAddToFreeSlot() {
+	conflicts, free := m.quickAddConflicts()
+	switch {
+	case len(conflicts) == 0:
+		return
+	case free == nil:
+		m.showMessage("No free slot left that day")
+		return
+	}
+	m.quickAddInput.SetValue(remind.WithTime(m.quickAddInput.Value(), free.Hour(), free.Minute()))
+	m.showMessage(fmt.Sprintf("Moved to %s", free.Format("15:04")))
+}
+
+// conflictLines describes the events conflicting with the quick-add input
+// for its preview
+func (m *Model) conflictLines() []string {
+	conflicts, free := m.quickAddConflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	lines := []string{m.styles.Priority.Render("Already scheduled:")}
+	for _, event := range conflicts {
+		event = m.displayEvent(event)
+		when := event.Time.Format("15:04")
+		if event.Duration != nil && *event.Duration > 0 {
+			when += "-" + event.Time.Add(*event.Duration).Format("15:04")
+		}
+		lines = append(lines, m.styles.Normal.Render(fmt.Sprintf("  %-11s  %s", when, event.Description)))
+	}
+	if free != nil {
+		lines = append(lines, m.styles.Help.Render("Tab: move to the next free slot, "+free.Format("15:04")))
+	}
+	return lines
+}
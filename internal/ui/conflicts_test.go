@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuickAddConflicts(t *testing.T) {
+	m := snapshotModel()
+	m.mode = ViewEventEditor
+
+	// Planning runs until 12:00, with Design review and Call Sam within it
+	m.quickAddInput.SetValue("Interview at 10:15am")
+	preview := strings.Join(m.quickAddPreview(), "\n")
+	for _, want := range []string{"Already scheduled:", "10:00-11:00  Design review", "10:30-11:00  Call Sam", "Tab: move to the next free slot, 12:00"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Preview missing %q:\n%s", want, preview)
+		}
+	}
+
+	m.moveQuickAddToFreeSlot()
+	if got := m.quickAddInput.Value(); got != "Interview 12:00" {
+		t.Errorf("Input moved to %q, want %q", got, "Interview 12:00")
+	}
+	if preview := strings.Join(m.quickAddPreview(), "\n"); strings.Contains(preview, "Already scheduled") {
+		t.Errorf("Expected no conflicts at 12:00:\n%s", preview)
+	}
+
+	// Events without a duration take up their start
+	m.quickAddInput.SetValue("Pay rent 2pm")
+	if conflicts, free := m.quickAddConflicts(); len(conflicts) != 1 || free == nil || free.Format("15:04") != "14:15" {
+		t.Errorf("quickAddConflicts = %v, %v", conflicts, free)
+	}
+
+	m.quickAddInput.SetValue("Untimed errand")
+	if lines := m.conflictLines(); lines != nil {
+		t.Errorf("Expected no conflicts for untimed input, got %q", lines)
+	}
+}
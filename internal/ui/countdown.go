@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// countdown is a COUNTDOWN event and the days left until it
+type countdown struct {
+	event remind.Event
+	days  int
+}
+
+// label is the counter of the countdown: D-12, or D-day on the day
+func (c countdown) label() string {
+	if c.days == 0 {
+		return "D-day"
+	}
+	return fmt.Sprintf("D-%d", c.days)
+}
+
+// countdowns returns the loaded COUNTDOWN events from today on, soonest
+// first. A recurring one is only counted down to its next occurrence.
+func (m *Model) countdowns(now time.Time) []countdown {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var countdowns []countdown
+	seen := make(map[string]bool)
+	for _, event := range m.events {
+		if !event.HasTag(remind.CountdownTag) {
+			continue
+		}
+		date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, now.Location())
+		days := int(math.Round(date.Sub(today).Hours() / 24))
+		if days < 0 {
+			continue
+		}
+		countdowns = append(countdowns, countdown{event: event, days: days})
+	}
+	sort.SliceStable(countdowns, func(i, j int) bool {
+		if countdowns[i].days != countdowns[j].days {
+			return countdowns[i].days < countdowns[j].days
+		}
+		return countdowns[i].event.Description < countdowns[j].event.Description
+	})
+
+	var next []countdown
+	for _, c := range countdowns {
+		key := c.event.Description
+		if c.event.Filename != "" {
+			key = fmt.Sprintf("%s:%d", c.event.Filename, c.event.LineNumber)
+		}
+		if !seen[key] {
+			seen[key] = true
+			next = append(next, c)
+		}
+	}
+	return next
+}
+
+// countdownStyle colors a counter once it's within countdown_warning days,
+// and as an alert within countdown_urgent days
+func (m *Model) countdownStyle(days int) lipgloss.Style {
+	switch {
+	case days <= m.config.CountdownUrgent:
+		return m.styles.Priority
+	case days <= m.config.CountdownWarning:
+		return m.styles.Normal.Foreground(m.colors().priorities[2])
+	}
+	return m.styles.Normal
+}
+
+// countdownLine renders a countdown in width cells, as its counter and
+// description
+func (m *Model) countdownLine(c countdown, width int) string {
+	line := c.label() + " " + m.displayEvent(c.event).Description
+	if len(line) > width {
+		line = line[:width-3] + "..."
+	}
+	return m.countdownStyle(c.days).Render(line)
+}
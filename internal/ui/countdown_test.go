@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestCountdowns(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local)
+	day := func(d int) time.Time { return time.Date(2025, 8, 25+d, 0, 0, 0, 0, time.Local) }
+	cfg := config.DefaultConfig()
+	m := &Model{
+		config:       cfg,
+		styles:       stylesFor(cfg),
+		clock:        remind.FixedClock(now),
+		selectedDate: now,
+		events: []remind.Event{
+			{Date: day(-2), Description: "Missed", Tags: []string{"COUNTDOWN"}},
+			{Date: day(9), Description: "Launch", Tags: []string{"countdown"}, Filename: "a.rem", LineNumber: 2},
+			{Date: day(4), Description: "Proposal due", Tags: []string{"COUNTDOWN"}, Filename: "a.rem", LineNumber: 1},
+			{Date: day(16), Description: "Launch", Tags: []string{"countdown"}, Filename: "a.rem", LineNumber: 2},
+			{Date: day(1), Description: "Standup"},
+			{Date: day(0), Description: "Review", Tags: []string{"COUNTDOWN"}},
+		},
+	}
+
+	countdowns := m.countdowns(now)
+	var labels []string
+	for _, c := range countdowns {
+		labels = append(labels, c.label()+" "+c.event.Description)
+	}
+	want := []string{"D-day Review", "D-4 Proposal due", "D-9 Launch"}
+	if strings.Join(labels, ", ") != strings.Join(want, ", ") {
+		t.Errorf("countdowns = %v, want %v", labels, want)
+	}
+
+	// Colored by the thresholds
+	if got := m.countdownLine(countdowns[0], 30); got != m.styles.Priority.Render("D-day Review") {
+		t.Errorf("Expected D-day to be urgent, got %q", got)
+	}
+	if got := m.countdownLine(countdowns[1], 30); got == m.styles.Normal.Render("D-4 Proposal due") {
+		t.Errorf("Expected D-4 to be a warning, got %q", got)
+	}
+	if got := m.countdownLine(countdowns[2], 30); got != m.styles.Normal.Render("D-9 Launch") {
+		t.Errorf("Expected D-9 to be plain, got %q", got)
+	}
+
+	// Counted in the month view and the untimed panel, which also counts
+	// the ones in later months
+	m.styles = defaultStyles()
+	if calendar := m.renderMiniCalendar(); !strings.Contains(calendar, "D-4 Proposal due") || strings.Contains(calendar, "Launch") {
+		t.Errorf("Expected the month's countdowns in the calendar:\n%s", calendar)
+	}
+	m.width, m.height = 100, 40
+	if sidebar := lipgloss.NewCanvas(m.createSidebarLayer(0, 30)).Render(); !strings.Contains(sidebar, "D-9 Launch") {
+		t.Errorf("Expected the countdowns in the untimed panel:\n%s", sidebar)
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// dayKey is a calendar day, whatever the location of the time it came from
+type dayKey struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dayKeyOf(t time.Time) dayKey {
+	return dayKey{t.Year(), t.Month(), t.Day()}
+}
+
+// dayIndex groups the loaded events by their date, so rendering and the
+// lookups done on every key press look at one day instead of every event
+// loaded. It's rebuilt when events are loaded, and whenever the events it
+// was built from have been replaced since.
+type dayIndex struct {
+	events  []remind.Event // The events indexed, to notice when they're replaced
+	days    map[dayKey][]remind.Event
+	maxDays int // Most days an event reaches past its date
+}
+
+// indexFor reports whether the index was built from events
+func (x *dayIndex) indexFor(events []remind.Event) bool {
+	if x.days == nil || len(x.events) != len(events) {
+		return false
+	}
+	return len(events) == 0 || &x.events[0] == &events[0]
+}
+
+func newDayIndex(events []remind.Event) dayIndex {
+	x := dayIndex{events: events, days: make(map[dayKey][]remind.Event)}
+	for _, event := range events {
+		key := dayKeyOf(event.Date)
+		x.days[key] = append(x.days[key], event)
+		if event.Time != nil && event.Duration != nil {
+			date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, event.Time.Location())
+			if days := int(event.Time.Add(*event.Duration).Sub(date).Hours() / 24); days > x.maxDays {
+				x.maxDays = days
+			}
+		}
+	}
+	return x
+}
+
+// dayIndex returns the index of the loaded events, rebuilding it when they
+// have been reloaded
+func (m *Model) dayIndex() *dayIndex {
+	if !m.eventIndex.indexFor(m.events) {
+		m.eventIndex = newDayIndex(m.events)
+	}
+	return &m.eventIndex
+}
+
+// eventsOn returns the events dated day, in the order they were loaded
+func (m *Model) eventsOn(day time.Time) []remind.Event {
+	return m.dayIndex().days[dayKeyOf(day)]
+}
+
+// eventsReaching returns the events dated from the days before first that
+// can still run into it up to last, in the order of their dates
+func (m *Model) eventsReaching(first, last time.Time) []remind.Event {
+	index := m.dayIndex()
+	var events []remind.Event
+	day := time.Date(first.Year(), first.Month(), first.Day()-index.maxDays, 0, 0, 0, 0, first.Location())
+	for !day.After(last) {
+		events = append(events, index.days[dayKeyOf(day)]...)
+		day = day.AddDate(0, 0, 1)
+	}
+	return events
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestDayIndex(t *testing.T) {
+	monday := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(day time.Time, hour int) *time.Time {
+		at := day.Add(time.Duration(hour) * time.Hour)
+		return &at
+	}
+	sunday := monday.AddDate(0, 0, -1)
+	m := &Model{events: []remind.Event{
+		{ID: "1", Date: monday, Time: at(monday, 9)},
+		{ID: "2", Date: monday, Description: "Untimed"},
+		{ID: "3", Date: monday.AddDate(0, 0, 1), Time: at(monday.AddDate(0, 0, 1), 9)},
+	}}
+
+	if got := len(m.eventsOn(monday)); got != 2 {
+		t.Errorf("eventsOn(Monday) has %d events, want 2", got)
+	}
+	if got := m.eventsOn(sunday); len(got) != 0 {
+		t.Errorf("eventsOn(Sunday) = %v, want none", got)
+	}
+
+	// Reloading the events rebuilds the index, and a night shift from
+	// Sunday into Monday is found from Monday on
+	m.events = append([]remind.Event{
+		{ID: "4", Date: sunday, Time: at(sunday, 22), Duration: durationPtr(10 * 60)},
+	}, m.events...)
+	if got := len(m.eventsOn(sunday)); got != 1 {
+		t.Errorf("eventsOn(Sunday) after reload has %d events, want 1", got)
+	}
+	var ids []string
+	for _, event := range m.eventsReaching(monday, monday) {
+		ids = append(ids, event.ID)
+	}
+	if len(ids) != 3 || ids[0] != "4" {
+		t.Errorf("eventsReaching(Monday, Monday) = %v, want 4, 1, 2", ids)
+	}
+}
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// dueDays is how many days back and ahead of today untimed reminders are
+// grouped by when they're due
+const dueDays = 28
+
+// Sections untimed reminders are grouped into by due date, in order
+const (
+	dueOverdue  = "Overdue"
+	dueToday    = "Today"
+	dueThisWeek = "This Week"
+	dueLater    = "Later"
+)
+
+var dueSectionNames = []string{dueOverdue, dueToday, dueThisWeek, dueLater}
+
+// dueSection is untimed reminders due at about the same time
+type dueSection struct {
+	name      string
+	events    []remind.Event
+	collapsed bool
+}
+
+// toggleDueGroups switches the untimed reminders between the selected day
+// and every day around today, grouped by when they're due
+func (m *Model) toggleDueGroups() {
+	m.dueGroups = !m.dueGroups
+	m.selectedUntimedIndex = 0
+	if !m.dueGroups {
+		m.dueLoaded = nil
+		m.eventsRevision++
+		m.showMessage("Untimed reminders of the selected day")
+		return
+	}
+	if err := m.loadDue(); err != nil {
+		m.dueGroups = false
+		m.showError("Error loading reminders", err)
+		return
+	}
+	m.showMessage("Untimed reminders grouped by due date")
+}
+
+// loadDue loads the untimed reminders grouped by due date, from dueDays
+// before today to dueDays after
+func (m *Model) loadDue() error {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := m.source.GetEvents(today.AddDate(0, 0, -dueDays), today.AddDate(0, 0, dueDays))
+	if err != nil {
+		return err
+	}
+	events, _ = splitSpecials(events)
+	m.dueLoaded = m.filterTagged(events)
+	m.eventsRevision++
+	return nil
+}
+
+// dueSections groups the loaded untimed reminders by when they're due.
+// One-off reminders from before today are overdue; repeating ones come
+// back anyway, so only their next occurrence is listed.
+func (m *Model) dueSections() []dueSection {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekEnd := today.AddDate(0, 0, 7-(int(today.Weekday())-int(m.config.WeekStartDay)+7)%7)
+
+	var untimed []remind.Event
+	for _, event := range m.dueLoaded {
+		if event.Time == nil && !event.IsCancelled() {
+			untimed = append(untimed, event)
+		}
+	}
+	sort.SliceStable(untimed, func(i, j int) bool {
+		a, b := untimed[i], untimed[j]
+		if !sameDay(a.Date, b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Description != b.Description {
+			return a.Description < b.Description
+		}
+		return a.ID < b.ID
+	})
+
+	sections := make([]dueSection, len(dueSectionNames))
+	for i, name := range dueSectionNames {
+		sections[i] = dueSection{name: name, collapsed: m.collapsedDue[name]}
+	}
+	seen := make(map[string]bool)
+	for _, event := range untimed {
+		day := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, now.Location())
+		if event.IsRepeating {
+			key := event.ID
+			if event.Filename != "" {
+				key = fmt.Sprintf("%s:%d", event.Filename, event.LineNumber)
+			}
+			if day.Before(today) || seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		var section int
+		switch {
+		case day.Before(today):
+			section = 0
+		case day.Equal(today):
+			section = 1
+		case day.Before(weekEnd):
+			section = 2
+		default:
+			section = 3
+		}
+		sections[section].events = append(sections[section].events, event)
+	}
+	return sections
+}
+
+// dueEvents returns the untimed reminders of the expanded sections, in the
+// order they're listed
+func (m *Model) dueEvents() []remind.Event {
+	var events []remind.Event
+	for _, section := range m.dueSections() {
+		if !section.collapsed {
+			events = append(events, section.events...)
+		}
+	}
+	return events
+}
+
+// toggleSelectedSection collapses the section of the selected untimed
+// reminder, or expands every section when none can be selected
+func (m *Model) toggleSelectedSection() {
+	if !m.dueGroups {
+		m.showMessage("Untimed reminders aren't grouped, press G to group them")
+		return
+	}
+	index := m.selectedUntimedIndex
+	for _, section := range m.dueSections() {
+		if section.collapsed {
+			continue
+		}
+		if index < len(section.events) {
+			m.collapseSection(section.name)
+			m.selectedUntimedIndex = 0
+			m.showMessage(fmt.Sprintf("%s collapsed", section.name))
+			return
+		}
+		index -= len(section.events)
+	}
+	m.expandSections()
+}
+
+// expandSections expands every section of untimed reminders
+func (m *Model) expandSections() {
+	if !m.dueGroups {
+		m.showMessage("Untimed reminders aren't grouped, press G to group them")
+		return
+	}
+	m.collapsedDue = nil
+	m.eventsRevision++
+	m.showMessage("All sections expanded")
+}
+
+// collapseSection lists only the header and count of the section name
+func (m *Model) collapseSection(name string) {
+	if m.collapsedDue == nil {
+		m.collapsedDue = make(map[string]bool)
+	}
+	m.collapsedDue[name] = true
+	m.eventsRevision++
+}
+
+// renderDueSections lists the untimed reminders grouped by due date, each
+// section headed by how many it has, scrolled to the selected reminder
+func (m *Model) renderDueSections(width int) []string {
+	var lines []string
+	offset := m.untimedOffset(m.selectedDay(), len(m.dueEvents()))
+	end := offset + m.untimedHeight()
+	index := 0
+	for _, section := range m.dueSections() {
+		marker := "▾"
+		if section.collapsed {
+			marker = "▸"
+		}
+		lines = append(lines, m.styles.Help.Render(fmt.Sprintf("%s %s (%d)", marker, section.name, len(section.events))))
+		if section.collapsed {
+			continue
+		}
+		for _, event := range section.events {
+			if index < offset || index >= end {
+				index++
+				continue
+			}
+			line := m.displayEvent(event).Description
+			if event.Priority > remind.PriorityNone {
+				line = strings.Repeat("!", int(event.Priority)) + " " + line
+			}
+			if section.name != dueToday {
+				line = event.Date.Format(m.config.DateLayout("Jan 2")) + " " + line
+			}
+			line = "  " + line
+			if len(line) > width-2 {
+				line = line[:width-5] + "..."
+			}
+			if m.focusUntimed && index == m.selectedUntimedIndex {
+				line = m.styles.Selected.Render(line)
+			} else {
+				line = m.styles.Normal.Render(line)
+			}
+			lines = append(lines, line)
+			index++
+		}
+	}
+	return lines
+}
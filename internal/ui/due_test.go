@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestDueSections(t *testing.T) {
+	// A Wednesday, with the week starting on Monday
+	today := time.Date(2025, 8, 27, 0, 0, 0, 0, time.Local)
+	at := func(days int) time.Time { return today.AddDate(0, 0, days) }
+	nine := today.Add(9 * time.Hour)
+
+	m := &Model{
+		config: config.DefaultConfig(),
+		clock:  remind.FixedClock(today.Add(8 * time.Hour)),
+		dueLoaded: []remind.Event{
+			{ID: "late", Date: at(-3), Description: "Renew passport"},
+			{ID: "weekly-old", Date: at(-7), Description: "Water plants", IsRepeating: true, Filename: "/r/home.rem", LineNumber: 2},
+			{ID: "weekly", Date: at(0), Description: "Water plants", IsRepeating: true, Filename: "/r/home.rem", LineNumber: 2},
+			{ID: "weekly-next", Date: at(7), Description: "Water plants", IsRepeating: true, Filename: "/r/home.rem", LineNumber: 2},
+			{ID: "today", Date: at(0), Description: "Call bank", Priority: remind.PriorityHigh},
+			{ID: "timed", Date: at(0), Time: &nine, Description: "Standup"},
+			{ID: "friday", Date: at(2), Description: "Send invoice"},
+			{ID: "cancelled", Date: at(3), Description: "Dinner", Tags: []string{"status:cancelled"}},
+			{ID: "monday", Date: at(5), Description: "Book flights"},
+		},
+	}
+
+	expected := map[string][]string{
+		dueOverdue:  {"late"},
+		dueToday:    {"today", "weekly"},
+		dueThisWeek: {"friday"},
+		dueLater:    {"monday"},
+	}
+	sections := m.dueSections()
+	if len(sections) != len(dueSectionNames) {
+		t.Fatalf("got %d sections, want %d", len(sections), len(dueSectionNames))
+	}
+	for _, section := range sections {
+		var ids []string
+		for _, event := range section.events {
+			ids = append(ids, event.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(expected[section.name], ",") {
+			t.Errorf("%s = %v, want %v", section.name, ids, expected[section.name])
+		}
+	}
+
+	// Grouped, the untimed reminders of any day are those listed
+	m.dueGroups = true
+	if got := m.getSortedUntimedEvents(at(10)); len(got) != 5 || got[1].ID != "today" {
+		t.Fatalf("expected the grouped reminders in order, got %+v", got)
+	}
+
+	rendered := strings.Join(m.renderDueSections(60), "\n")
+	for _, want := range []string{"▾ Overdue (1)", "▾ Today (2)", "!!! Call bank", "▾ Later (1)", "Aug 24 Renew passport"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in:\n%s", want, rendered)
+		}
+	}
+
+	// Collapsing the section of the selected reminder leaves its count
+	m.selectedUntimedIndex = 2
+	m.toggleSelectedSection()
+	if !m.collapsedDue[dueToday] {
+		t.Fatal("expected Today collapsed")
+	}
+	if got := m.getSortedUntimedEvents(today); len(got) != 3 || got[1].ID != "friday" {
+		t.Errorf("expected Today's reminders left out, got %+v", got)
+	}
+	rendered = strings.Join(m.renderDueSections(60), "\n")
+	if !strings.Contains(rendered, "▸ Today (2)") || strings.Contains(rendered, "Call bank") {
+		t.Errorf("expected Today collapsed to its count:\n%s", rendered)
+	}
+
+	m.expandSections()
+	if len(m.getSortedUntimedEvents(today)) != 5 {
+		t.Error("expected every section expanded")
+	}
+}
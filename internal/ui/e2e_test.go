@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+	"github.com/cwarden/urd/internal/remindtest"
+)
+
+func TestMain(m *testing.M) {
+	remindtest.Main()
+	os.Exit(m.Run())
+}
+
+// e2e runs the full model in a bubbletea program, on a remind file in a
+// temporary directory read by a fake remind
+type e2e struct {
+	t       *testing.T
+	file    string
+	remind  *remindtest.Fake
+	program *tea.Program
+	done    chan tea.Model // The model as the program ended
+}
+
+// e2eStart is when the programs of end-to-end tests start, a Monday
+var e2eStart = time.Date(2025, 8, 25, 9, 0, 0, 0, time.Local)
+
+// startE2E runs urd on a remind file with content, with the default
+// configuration changed by configure if not nil
+func startE2E(t *testing.T, content string, configure func(*config.Config)) *e2e {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	e := &e2e{
+		t:      t,
+		file:   filepath.Join(t.TempDir(), "reminders.rem"),
+		remind: remindtest.New(t),
+		done:   make(chan tea.Model, 1),
+	}
+	if err := os.WriteFile(e.file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.RemindFiles = []string{e.file}
+	// Editors opened, like after a paste, return right away
+	cfg.EditOldCommand, cfg.EditNewCommand, cfg.EditAnyCommand = "true %file%", "true %file%", "true %file%"
+	if configure != nil {
+		configure(cfg)
+	}
+	client := remind.NewClient()
+	client.RemindPath = e.remind.Path
+	client.Clock = remind.FixedClock(e2eStart)
+	client.SetFiles(cfg.RemindFiles)
+
+	// No keys come from the terminal, but editors need input to hand over
+	e.program = tea.NewProgram(NewModelWithRemind(cfg, client, client),
+		tea.WithInput(strings.NewReader("")), tea.WithOutput(io.Discard), tea.WithWindowSize(100, 30), tea.WithoutSignals(),
+		tea.WithFilter(e.probe))
+	go func() {
+		model, _ := e.program.Run()
+		e.done <- model
+	}()
+	t.Cleanup(func() {
+		e.program.Kill()
+		<-e.done
+	})
+
+	// Keys are only sent once the model knows the window size, which
+	// selects the current time
+	e.waitFor("the window size", func(m *Model) bool { return m.width > 0 })
+	return e
+}
+
+// probeMsg asks the program to check its model
+type probeMsg struct {
+	check  func(m *Model) bool
+	result chan bool
+}
+
+// probe checks the model for probeMsgs, as the filter of the program: the
+// messages before them are handled, and the model isn't changing
+func (e *e2e) probe(model tea.Model, msg tea.Msg) tea.Msg {
+	if probe, ok := msg.(probeMsg); ok {
+		probe.result <- probe.check(model.(*Model))
+		return nil
+	}
+	return msg
+}
+
+// waitFor waits until the model passes check, failing the test after a
+// few seconds
+func (e *e2e) waitFor(what string, check func(m *Model) bool) {
+	e.t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		result := make(chan bool, 1)
+		e.program.Send(probeMsg{check: check, result: result})
+		select {
+		case ok := <-result:
+			if ok {
+				return
+			}
+		case <-time.After(time.Second):
+			// Busy, or ended
+		}
+	}
+	e.t.Fatalf("timed out waiting for %s", what)
+}
+
+// keys presses keys in order, each a character or a key name like enter
+func (e *e2e) keys(keys ...string) {
+	for _, key := range keys {
+		switch key {
+		case "enter":
+			e.program.Send(tea.KeyPressMsg{Code: tea.KeyEnter})
+		case "esc":
+			e.program.Send(tea.KeyPressMsg{Code: tea.KeyEscape})
+		default:
+			for _, r := range key {
+				e.program.Send(tea.KeyPressMsg{Code: r, Text: string(r)})
+			}
+		}
+	}
+}
+
+// content returns what the remind file holds
+func (e *e2e) content() string {
+	e.t.Helper()
+	content, err := os.ReadFile(e.file)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return string(content)
+}
+
+// quit stops the program once the keys pressed are handled, returning the
+// model as it ends
+func (e *e2e) quit() *Model {
+	e.t.Helper()
+	e.program.Quit()
+	select {
+	case model := <-e.done:
+		e.done <- model // For the cleanup
+		return model.(*Model)
+	case <-time.After(5 * time.Second):
+		e.t.Fatal("program didn't quit")
+		return nil
+	}
+}
+
+// shows returns a check that the model shows an event with description
+// starting at hour:00 on day
+func shows(description string, day, hour int) func(m *Model) bool {
+	return func(m *Model) bool {
+		for _, event := range m.events {
+			if event.Description == description && event.Date.Day() == day && event.Time != nil && event.Time.Hour() == hour {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestE2EQuickAdd(t *testing.T) {
+	e := startE2E(t, "", nil)
+	e.keys("a", "Dentist at 3pm", "enter")
+	e.waitFor("the reminder added", shows("Dentist", 25, 15))
+
+	if content := e.content(); !strings.Contains(content, "AT 15:00") || !strings.Contains(content, "MSG Dentist") {
+		t.Errorf("expected the reminder written, got:\n%s", content)
+	}
+	e.quit()
+}
+
+func TestE2ECutAndPaste(t *testing.T) {
+	e := startE2E(t, "REM Aug 25 2025 AT 10:00 DURATION 1:00 MSG Planning\n", nil)
+
+	// From 9:00 down to the reminder, then the same time the next day
+	e.keys("j", "j", "X")
+	e.waitFor("the reminder cut", func(m *Model) bool { return len(m.events) == 0 })
+	if content := e.content(); strings.Contains(content, "Planning") {
+		t.Errorf("expected the reminder removed, got:\n%s", content)
+	}
+
+	e.keys("L", "p")
+	e.waitFor("the reminder pasted", shows("Planning", 26, 10))
+	if content := e.content(); !strings.Contains(content, "REM Aug 26 2025 AT 10:00 MSG Planning") {
+		t.Errorf("expected the reminder written on Aug 26, got:\n%s", content)
+	}
+
+	e.quit()
+	for _, call := range e.remind.Calls(t) {
+		if !strings.Contains(call, e.file) && call != "-n" {
+			t.Errorf("expected remind run on the remind file, got %q", call)
+		}
+	}
+}
+
+func TestE2EEdit(t *testing.T) {
+	// The editor renames the reminder on the line it's opened on
+	editor := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nsed -i.bak \"$2s/Lunch/Brunch/\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e := startE2E(t, "REM Aug 25 2025 AT 9:00 MSG Standup\nREM Aug 25 2025 AT 12:00 MSG Lunch\n", func(cfg *config.Config) {
+		cfg.EditOldCommand = editor + " %file% %line%"
+	})
+
+	e.keys("j", "j", "j", "j", "j", "j", "enter")
+	e.waitFor("the edit reloaded", shows("Brunch", 25, 12))
+	if content := e.content(); !strings.Contains(content, "MSG Standup\n") {
+		t.Errorf("expected only the line edited to change, got:\n%s", content)
+	}
+	e.quit()
+}
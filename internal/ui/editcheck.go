@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// checkEdited has remind check the file just edited. What remind reports
+// is shown until the file is fixed, rather than the events of the broken
+// reminders quietly going missing.
+func (m *Model) checkEdited(file string) {
+	if m.remindClient == nil || file == "" {
+		return
+	}
+	m.editError = m.remindClient.CheckFile(file)
+	m.editedFile = file
+}
+
+// recheckEdited checks the file last edited again if remind reported an
+// error in it, once the files changed
+func (m *Model) recheckEdited() {
+	if m.editError != nil {
+		m.checkEdited(m.editedFile)
+	}
+}
+
+// statusError returns the error shown in place of messages until it is
+// dealt with, if there is one
+func (m *Model) statusError() error {
+	switch {
+	case m.loadError != nil:
+		return m.loadError
+	case m.editError != nil:
+		return m.editError
+	case m.configError != nil:
+		return fmt.Errorf("urdrc not reloaded: %w", m.configError)
+	}
+	return nil
+}
+
+// errorLocation returns the file and line of the error remind reported,
+// when it names them
+func (m *Model) errorLocation() (string, int, bool) {
+	var syntaxErr *remind.RemindSyntaxError
+	if err := m.statusError(); !errors.As(err, &syntaxErr) || syntaxErr.File == "" {
+		return "", 0, false
+	}
+	return syntaxErr.File, syntaxErr.Line, true
+}
+
+// editErrorCmd opens the editor on the line remind reported an error on
+func (m *Model) editErrorCmd() tea.Cmd {
+	file, line, ok := m.errorLocation()
+	if !ok {
+		m.showMessage("No error to jump to")
+		return nil
+	}
+	m.showMessage("Launching editor...")
+	return m.editCmd(m.config.EditOldCommand, file, line)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestCheckEdited(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "reminders.rem")
+	script := filepath.Join(dir, "remind")
+	writeRemind := func(stderr string) {
+		if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '"+stderr+"' >&2\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRemind(file + "(4): Expecting time after AT\\n")
+
+	client := remind.NewClient()
+	client.RemindPath = script
+	client.SetFiles([]string{file})
+	m := &Model{
+		config:       &config.Config{EditOldCommand: "true %file% %line%"},
+		remindClient: client,
+		styles:       defaultStyles(),
+	}
+
+	m.checkEdited(file)
+	if err := m.statusError(); err == nil || !strings.Contains(err.Error(), "Expecting time after AT") {
+		t.Fatalf("expected remind's error to be shown, got %v", err)
+	}
+	if f, line, ok := m.errorLocation(); !ok || f != file || line != 4 {
+		t.Errorf("errorLocation() = %s, %d, %v", f, line, ok)
+	}
+	if m.editErrorCmd() == nil {
+		t.Error("expected edit_error to open the editor")
+	}
+
+	// Fixed in the editor, the error goes once the file changes
+	writeRemind("")
+	m.recheckEdited()
+	if err := m.statusError(); err != nil {
+		t.Errorf("expected the error to be gone, got %v", err)
+	}
+	if m.editErrorCmd() != nil {
+		t.Error("expected nothing to jump to")
+	}
+}
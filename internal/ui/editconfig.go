@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// configEditedMsg is sent when the editor opened on the urdrc exits
+type configEditedMsg struct {
+	path string
+	err  error
+}
+
+// editConfigCmd opens the urdrc in use with edit_any_command. Without one,
+// a starter urdrc is written where urd looks for it first.
+func (m *Model) editConfigCmd() tea.Cmd {
+	if m.config.ReadOnly {
+		return func() tea.Msg { return configEditedMsg{err: errReplaying} }
+	}
+	path := config.FindConfigFile()
+	if path == "" {
+		path = config.DefaultConfigPath()
+		var remindFile string
+		if len(m.config.RemindFiles) > 0 {
+			remindFile = m.config.RemindFiles[0]
+		}
+		starter := config.StarterConfig(remindFile, m.config.RemindCommand)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return func() tea.Msg { return configEditedMsg{path: path, err: err} }
+		}
+		if err := os.WriteFile(path, []byte(starter), 0644); err != nil {
+			return func() tea.Msg { return configEditedMsg{path: path, err: err} }
+		}
+	}
+
+	parts, err := remind.EditorCommand(m.config.EditAnyCommand, path, 0)
+	if err != nil {
+		return func() tea.Msg { return configEditedMsg{path: path, err: err} }
+	}
+
+	return tea.ExecProcess(exec.Command(parts[0], parts[1:]...), func(err error) tea.Msg {
+		return configEditedMsg{path: path, err: err}
+	})
+}
+
+// handleConfigEdited reloads the configuration once the urdrc was edited.
+// If it no longer loads, the configuration in use is kept and the error
+// shown until it does. The remind files and sources urd started with stay
+// as they are.
+func (m *Model) handleConfigEdited(msg configEditedMsg) {
+	if msg.err != nil {
+		m.showMessage(fmt.Sprintf("Editor failed: %v", msg.err))
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		// Shown until the urdrc is fixed
+		m.configError = err
+		return
+	}
+	cfg.RemindFiles = m.config.RemindFiles
+	*m.config = *cfg
+	m.styles = stylesFor(cfg)
+	m.sidebar = sidebarCache{} // Changed in place, so the sidebar doesn't notice
+	m.configError = nil
+	m.showMessage("Reloaded " + msg.path)
+}
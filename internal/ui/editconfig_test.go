@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestHandleConfigEdited(t *testing.T) {
+	urdrc := filepath.Join(t.TempDir(), "urdrc")
+	t.Setenv("URD_CONFIG", urdrc)
+
+	cfg := config.DefaultConfig()
+	cfg.RemindFiles = []string{"/given/with/-f.rem"}
+	m := &Model{config: cfg}
+
+	if err := os.WriteFile(urdrc, []byte("set remind_files /other.rem\nbind x quit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.handleConfigEdited(configEditedMsg{path: urdrc})
+	if cfg.KeyBindings["x"] != "quit" {
+		t.Errorf("expected the new binding after reloading, got %q", cfg.KeyBindings["x"])
+	}
+	if cfg.RemindFiles[0] != "/given/with/-f.rem" {
+		t.Errorf("expected the remind files to stay, got %v", cfg.RemindFiles)
+	}
+	if !strings.Contains(m.message, "Reloaded") {
+		t.Errorf("expected a reload message, got %q", m.message)
+	}
+
+	// A broken urdrc keeps the configuration in use and is reported
+	if err := os.WriteFile(urdrc, []byte("bind x quit\nset bogus 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.handleConfigEdited(configEditedMsg{path: urdrc})
+	if m.configError == nil || !strings.Contains(m.configError.Error(), "bogus") {
+		t.Errorf("expected the parse error kept, got %v", m.configError)
+	}
+	if cfg.KeyBindings["x"] != "quit" {
+		t.Error("expected the configuration in use to stay")
+	}
+
+	// Fixing it clears the error
+	if err := os.WriteFile(urdrc, []byte("bind x cut\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.handleConfigEdited(configEditedMsg{path: urdrc})
+	if m.configError != nil || cfg.KeyBindings["x"] != "cut" {
+		t.Errorf("expected the fixed urdrc loaded, got %v, %q", m.configError, cfg.KeyBindings["x"])
+	}
+
+	m.handleConfigEdited(configEditedMsg{path: urdrc, err: errors.New("exit status 1")})
+	if !strings.Contains(m.message, "Editor failed") {
+		t.Errorf("expected the editor failure shown, got %q", m.message)
+	}
+}
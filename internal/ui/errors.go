@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// errorFix suggests what to do about err, for the errors the remind package
+// tells apart, or returns "" when there's nothing to suggest
+func errorFix(err error) string {
+	var syntaxErr *remind.RemindSyntaxError
+	switch {
+	case errors.Is(err, remind.ErrNoFiles):
+		return "set remind_files in urdrc, or run urd setup"
+	case errors.Is(err, remind.ErrRemindNotFound):
+		return "install remind, or set remind_command to where it is"
+	case errors.Is(err, remind.ErrWriteConflict):
+		return "the file changed, press Ctrl+L to reload and try again"
+	case errors.As(err, &syntaxErr):
+		// The status bar offers to edit the line
+		return ""
+	case errors.Is(err, remind.ErrParse):
+		return "check the reminder's line for a typo"
+	}
+	return ""
+}
+
+// describeError returns err with what to do about it, when there's
+// something to suggest
+func describeError(err error) string {
+	if fix := errorFix(err); fix != "" {
+		return fmt.Sprintf("%v (%s)", err, fix)
+	}
+	return err.Error()
+}
+
+// showError shows what failed and what to do about it
+func (m *Model) showError(what string, err error) {
+	m.showMessage(what + ": " + describeError(err))
+}
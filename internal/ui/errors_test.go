@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestErrorFix(t *testing.T) {
+	tests := []struct {
+		err error
+		fix string
+	}{
+		{fmt.Errorf("loading: %w", remind.ErrNoFiles), "urd setup"},
+		{fmt.Errorf("%w: remind", remind.ErrRemindNotFound), "remind_command"},
+		{fmt.Errorf("%w: line 5 is past the end of a.rem", remind.ErrWriteConflict), "reload"},
+		{fmt.Errorf("%w: not a REM line", remind.ErrParse), "typo"},
+		{&remind.RemindSyntaxError{File: "a.rem", Line: 3, Message: "Bad date"}, ""},
+		{fmt.Errorf("disk full"), ""},
+	}
+	for _, tt := range tests {
+		fix := errorFix(tt.err)
+		if (tt.fix == "") != (fix == "") || !strings.Contains(fix, tt.fix) {
+			t.Errorf("errorFix(%v) = %q, want it to mention %q", tt.err, fix, tt.fix)
+		}
+	}
+}
+
+func TestLoadErrorPersists(t *testing.T) {
+	// No files configured, the events can't load until some are
+	client := remind.NewClient()
+	client.RemindPath = "urd-test-no-remind"
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		config:        &config.Config{},
+		source:        client,
+		remindClient:  client,
+		clock:         remind.FixedClock(day),
+		selectedDate:  day,
+		timeIncrement: 60,
+	}
+	m.loadEvents()
+
+	if err := m.statusError(); err == nil || !strings.Contains(describeError(err), "remind_files") {
+		t.Fatalf("expected the missing files shown with their fix, got %v", err)
+	}
+	m.showMessage("Moved to Aug 26")
+	if m.statusError() == nil {
+		t.Error("expected the error to stay shown over messages")
+	}
+}
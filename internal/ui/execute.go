@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// runCommandRegex finds a command embedded in an event as "RUN: command"
+var runCommandRegex = regexp.MustCompile(`(?m)\bRUN:[ \t]*(.+?)[ \t]*$`)
+
+// Phases of the execute view
+const (
+	execConfirm = iota // asking whether to run the command
+	execRunning        // waiting for the command to finish
+	execDone           // showing the command's output
+)
+
+type commandFinishedMsg struct {
+	output string
+	err    error
+}
+
+// extractCommand returns the command embedded in text, if any
+func extractCommand(text string) string {
+	if matches := runCommandRegex.FindStringSubmatch(text); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// eventCommand returns the command embedded in an event's description or body
+func eventCommand(event remind.Event) string {
+	if command := extractCommand(event.Description); command != "" {
+		return command
+	}
+	return extractCommand(event.Body)
+}
+
+// runCommandCmd runs command through the shell and captures its output
+func (m *Model) runCommandCmd(command string) tea.Cmd {
+	if m.config.ReadOnly {
+		return func() tea.Msg { return commandFinishedMsg{err: errReplaying} }
+	}
+	return func() tea.Msg {
+		output, err := shellCommand(command).CombinedOutput()
+		return commandFinishedMsg{output: string(output), err: err}
+	}
+}
+
+// shellCommand runs command through the shell, cmd.exe on Windows
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// execOutputLines splits command output for the scrollable pane
+func execOutputLines(output string) []string {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
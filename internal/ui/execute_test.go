@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestEventCommand(t *testing.T) {
+	tests := []struct {
+		event remind.Event
+		want  string
+	}{
+		{remind.Event{Description: "Standup RUN: ~/bin/standup.sh --team core"}, "~/bin/standup.sh --team core"},
+		{remind.Event{Description: "Standup", Body: "Agenda\nRUN: xdg-open https://zoom.us/j/123  \nNotes"}, "xdg-open https://zoom.us/j/123"},
+		{remind.Event{Description: "TRUN: not a command"}, ""},
+		{remind.Event{Description: "Nothing to run"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := eventCommand(tt.event); got != tt.want {
+			t.Errorf("eventCommand(%q, %q) = %q, want %q", tt.event.Description, tt.event.Body, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteFlow(t *testing.T) {
+	m := &Model{
+		config: &config.Config{},
+		styles: defaultStyles(),
+		mode:   ViewExecute,
+		height: 20,
+
+		execCommand: "printf 'one\\ntwo\\n'",
+		execPhase:   execConfirm,
+	}
+
+	_, cmd := m.handleExecuteKeys(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if m.execPhase != execRunning || cmd == nil {
+		t.Fatalf("expected command to start, phase=%d", m.execPhase)
+	}
+
+	m.Update(cmd())
+	if m.execPhase != execDone || m.execErr != nil {
+		t.Fatalf("expected finished command, phase=%d err=%v", m.execPhase, m.execErr)
+	}
+	if len(m.execOutput) != 2 || m.execOutput[0] != "one" || m.execOutput[1] != "two" {
+		t.Errorf("unexpected output %q", m.execOutput)
+	}
+
+	m.handleExecuteKeys(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.mode != ViewHourly {
+		t.Errorf("expected Esc to close the output pane")
+	}
+}
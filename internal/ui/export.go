@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// exportCSV saves the events of the visible days, as shown, to a CSV file
+// in screenshot_dir, like urd export csv does for any range of days
+func (m *Model) exportCSV() {
+	if m.config.ReadOnly {
+		m.showError("Failed to export", errReplaying)
+		return
+	}
+	first, last := m.visibleDays()
+	var events []remind.Event
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		for _, event := range m.eventsOn(day) {
+			events = append(events, m.displayEvent(event))
+		}
+	}
+
+	name := fmt.Sprintf("urd-%s-%s.csv", first.Format("20060102"), last.Format("20060102"))
+	path := filepath.Join(m.config.ScreenshotDir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		m.showError("Failed to export", err)
+		return
+	}
+	err = remind.WriteCSV(file, events)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		m.showError("Failed to export", err)
+		return
+	}
+	m.showMessage(fmt.Sprintf("Exported %d events to %s", len(events), path))
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestExportCSV(t *testing.T) {
+	m := snapshotModel()
+	m.width, m.height = 100, 24
+	m.config.ScreenshotDir = t.TempDir()
+	m.events[1].Tags = []string{remind.PrivateTag}
+	m.presentationMode = true
+
+	first, last := m.visibleDays()
+	m.exportCSV()
+
+	name := "urd-" + first.Format("20060102") + "-" + last.Format("20060102") + ".csv"
+	content, err := os.ReadFile(filepath.Join(m.config.ScreenshotDir, name))
+	if err != nil {
+		t.Fatalf("export not saved: %v (message %q)", err, m.message)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if lines[0] != "date,start,end,duration,description,tags,priority,source" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.Contains(string(content), "2025-08-25,08:00,08:30,30,Standup") {
+		t.Errorf("export missing Standup:\n%s", content)
+	}
+	if strings.Contains(string(content), "Quarterly") {
+		t.Errorf("export shows the private event in presentation mode:\n%s", content)
+	}
+	if !strings.HasPrefix(m.message, "Exported ") {
+		t.Errorf("message = %q, want Exported ...", m.message)
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// focusTickMsg updates the remaining time of a focus session
+type focusTickMsg struct {
+	seq int
+}
+
+// toggleFocus starts a focus session on the selected event, or stops the
+// one running
+func (m *Model) toggleFocus(now time.Time) tea.Cmd {
+	if m.focusActive {
+		m.focusActive = false
+		m.showMessage(fmt.Sprintf("Focus session on %s stopped", m.displayEvent(m.focusEvent).Description))
+		return nil
+	}
+
+	events := m.selectedEvents()
+	if len(events) == 0 {
+		m.showMessage("No event to focus on")
+		return nil
+	}
+
+	m.focusEvent = events[0]
+	m.focusActive = true
+	m.focusStart = now
+	m.focusEnd = now.Add(m.config.FocusLength)
+	m.focusSeq++
+	m.showMessage(fmt.Sprintf("Focusing on %s for %s", m.displayEvent(m.focusEvent).Description, formatRemaining(m.config.FocusLength)))
+	return m.focusTickCmd()
+}
+
+func (m *Model) focusTickCmd() tea.Cmd {
+	seq := m.focusSeq
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return focusTickMsg{seq: seq}
+	})
+}
+
+// handleFocusTick keeps the countdown going and finishes the session once
+// its time is up
+func (m *Model) handleFocusTick(msg focusTickMsg, now time.Time) tea.Cmd {
+	if !m.focusActive || msg.seq != m.focusSeq {
+		// The session was stopped or replaced
+		return nil
+	}
+	if now.Before(m.focusEnd) {
+		return m.focusTickCmd()
+	}
+
+	m.focusActive = false
+	description := m.displayEvent(m.focusEvent).Description
+	m.showMessage(fmt.Sprintf("Focus session on %s done", description))
+
+	if m.config.FocusLog != "" && !m.config.ReadOnly {
+		if err := appendFocusLog(m.config.FocusLog, m.focusStart, now, m.focusEvent); err != nil {
+			m.showError("Failed to log focus session", err)
+		}
+	}
+	return notifyCmd("Focus session done", description)
+}
+
+// focusStatus describes the running focus session for the status bar
+func (m *Model) focusStatus(now time.Time) string {
+	if !m.focusActive {
+		return ""
+	}
+	return fmt.Sprintf("Focus %s - %s", formatRemaining(m.focusEnd.Sub(now)), m.displayEvent(m.focusEvent).Description)
+}
+
+// formatRemaining formats a countdown as minutes and seconds
+func formatRemaining(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	// Round up so the countdown reaches 0:00 when the time is up
+	seconds := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// appendFocusLog records a completed session as a tab-separated line of
+// start time, length and description
+func appendFocusLog(path string, start, end time.Time, event remind.Event) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	minutes := int(end.Sub(start).Round(time.Minute) / time.Minute)
+	line := fmt.Sprintf("%s\t%dm\t%s\n", start.Format("2006-01-02 15:04"), minutes, event.Description)
+	if _, err := f.WriteString(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// notifyCmd sends a desktop notification when a notifier is available
+func notifyCmd(title, body string) tea.Cmd {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e",
+			fmt.Sprintf("display notification %q with title %q", body, title))
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil
+		}
+		cmd = exec.Command("notify-send", "urd: "+title, body)
+	}
+
+	return func() tea.Msg {
+		// The status bar shows the message too, so failures aren't reported
+		_ = cmd.Run()
+		return nil
+	}
+}
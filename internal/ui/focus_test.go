@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestFormatRemaining(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{25 * time.Minute, "25:00"},
+		{90*time.Second + 200*time.Millisecond, "1:31"},
+		{500 * time.Millisecond, "0:01"},
+		{-time.Second, "0:00"},
+	}
+
+	for _, tt := range tests {
+		if got := formatRemaining(tt.d); got != tt.want {
+			t.Errorf("formatRemaining(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFocusSession(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	start := day.Add(9 * time.Hour)
+	logFile := filepath.Join(t.TempDir(), "focus.log")
+
+	m := &Model{
+		config:       &config.Config{FocusLength: 25 * time.Minute, FocusLog: logFile},
+		mode:         ViewHourly,
+		selectedDate: day,
+		focusUntimed: true,
+		events: []remind.Event{
+			{ID: "evt-1", Date: day, Description: "Write report"},
+		},
+	}
+
+	if cmd := m.toggleFocus(start); cmd == nil || !m.focusActive {
+		t.Fatal("expected a focus session to start")
+	}
+	if got := m.focusStatus(start.Add(time.Minute)); got != "Focus 24:00 - Write report" {
+		t.Errorf("focusStatus = %q", got)
+	}
+
+	// Ticks from a stopped session are ignored
+	if cmd := m.handleFocusTick(focusTickMsg{seq: m.focusSeq - 1}, start.Add(time.Hour)); cmd != nil || !m.focusActive {
+		t.Error("stale tick affected the session")
+	}
+
+	// The countdown keeps going until the time is up
+	if cmd := m.handleFocusTick(focusTickMsg{seq: m.focusSeq}, start.Add(10*time.Minute)); cmd == nil || !m.focusActive {
+		t.Error("session ended early")
+	}
+	m.handleFocusTick(focusTickMsg{seq: m.focusSeq}, start.Add(25*time.Minute))
+	if m.focusActive {
+		t.Fatal("session should be done")
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "2025-08-25 09:00\t25m\tWrite report\n" {
+		t.Errorf("log = %q", got)
+	}
+
+	// Stopped sessions aren't logged
+	m.toggleFocus(start.Add(time.Hour))
+	m.toggleFocus(start.Add(time.Hour + time.Minute))
+	if m.focusActive {
+		t.Error("session should be stopped")
+	}
+	data, _ = os.ReadFile(logFile)
+	if strings.Count(string(data), "\n") != 1 {
+		t.Errorf("stopped session was logged: %q", data)
+	}
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// startFormat tidies the remind file of the selected event, or the one new
+// reminders go to, showing the changes before writing them
+func (m *Model) startFormat() {
+	file := m.primaryFile()
+	for _, event := range m.selectedEvents() {
+		if found, err := m.findEventFile(event); err == nil {
+			file = found
+			break
+		}
+	}
+	if file == "" {
+		m.showError("Error formatting", remind.ErrNoFiles)
+		return
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		m.showError("Error formatting", err)
+		return
+	}
+	formatted := remind.FormatReminders(string(content))
+	if formatted == string(content) {
+		m.showMessage(fmt.Sprintf("%s is already tidy", file))
+		return
+	}
+
+	m.formatFile = file
+	m.formatBefore = string(content)
+	m.formatAfter = formatted
+	m.formatDiff = strings.Split(strings.TrimSuffix(remind.UnifiedDiff(file, m.formatBefore, formatted), "\n"), "\n")
+	m.formatScroll = 0
+	m.mode = ViewFormat
+}
+
+// writeFormat writes the formatted remind file, unless it changed since it
+// was read
+func (m *Model) writeFormat() {
+	m.mode = ViewHourly
+	if m.config.ReadOnly {
+		m.showError("Error formatting", errReplaying)
+	} else if err := remind.WriteFormatted(m.formatFile, m.formatBefore, m.formatAfter); err != nil {
+		m.showError("Error formatting", err)
+	} else {
+		m.showMessage(fmt.Sprintf("Formatted %s", m.formatFile))
+		m.loadEventsForSchedule()
+	}
+	m.formatDiff = nil
+}
+
+func (m *Model) handleFormatKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	visibleLines := m.execVisibleLines()
+	switch keyName(msg) {
+	case "y", "Y", "<enter>":
+		m.writeFormat()
+		return m, nil
+	case "n", "N", "<esc>", "q":
+		m.mode = ViewHourly
+		m.formatDiff = nil
+		return m, nil
+	case "j", "<down>":
+		m.formatScroll++
+	case "k", "<up>":
+		m.formatScroll--
+	case "<pagedown>", "space":
+		m.formatScroll += visibleLines
+	case "<pageup>":
+		m.formatScroll -= visibleLines
+	}
+	m.formatScroll = max(min(m.formatScroll, len(m.formatDiff)-visibleLines), 0)
+	return m, nil
+}
+
+func (m *Model) viewFormat() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Format "+m.formatFile))
+	sections = append(sections, "")
+
+	end := min(m.formatScroll+m.execVisibleLines(), len(m.formatDiff))
+	for _, line := range m.formatDiff[m.formatScroll:end] {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			sections = append(sections, m.styles.Help.Render(line))
+		case strings.HasPrefix(line, "-"):
+			sections = append(sections, m.styles.Priority.Render(line))
+		case strings.HasPrefix(line, "+"):
+			sections = append(sections, m.styles.Event.Render(line))
+		default:
+			sections = append(sections, m.styles.Normal.Render(line))
+		}
+	}
+
+	sections = append(sections, "")
+	status := "Write these changes? [y/n]"
+	if len(m.formatDiff) > m.execVisibleLines() {
+		status += fmt.Sprintf("  (lines %d-%d of %d)", m.formatScroll+1, end, len(m.formatDiff))
+	}
+	sections = append(sections, m.styles.Selected.Render(status))
+	sections = append(sections, m.styles.Help.Render("j/k: Scroll  y: Write  Esc: Cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestFormatFile(t *testing.T) {
+	content := "rem aug 26 2025 msg Haircut\nREM 2025-08-25 AT 10:00 MSG Dentist\n"
+	m, file := newFileModel(t, content, time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local))
+	m.config.RemindFiles = []string{file}
+	m.height = 30
+
+	m.startFormat()
+	if m.mode != ViewFormat {
+		t.Fatalf("expected the changes previewed, got message %q", m.message)
+	}
+	view := m.viewFormat()
+	for _, want := range []string{"-rem aug 26 2025 msg Haircut", "+REM Aug 25 2025 AT 10:00 MSG Dentist", "Write these changes?"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in:\n%s", want, view)
+		}
+	}
+
+	// Cancelled, the file is left alone
+	m.handleFormatKeys(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if data, _ := os.ReadFile(file); string(data) != content || m.mode != ViewHourly {
+		t.Fatalf("expected the file unchanged, got %q", data)
+	}
+
+	m.startFormat()
+	m.handleFormatKeys(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	expected := "REM Aug 25 2025 AT 10:00 MSG Dentist\nREM Aug 26 2025 MSG Haircut\n"
+	if data, _ := os.ReadFile(file); string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+	if len(m.events) != 2 {
+		t.Errorf("expected the events reloaded, got %d", len(m.events))
+	}
+
+	m.startFormat()
+	if m.mode != ViewHourly || !strings.Contains(m.message, "already tidy") {
+		t.Errorf("expected nothing to format, got message %q", m.message)
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// slotStep is the granularity suggested start times are rounded up to
+const slotStep = 15 * time.Minute
+
+// timeRange is a span of time from start up to end
+type timeRange struct {
+	start, end time.Time
+}
+
+// busyRanges returns when the timed events on day take place, in order and
+// with overlapping events merged. Events without a duration and cancelled
+// events don't block time.
+func busyRanges(events []remind.Event, day time.Time) []timeRange {
+	var busy []timeRange
+	for _, event := range events {
+		if event.Time == nil || event.Duration == nil || *event.Duration <= 0 || !sameDay(*event.Time, day) || event.IsCancelled() {
+			continue
+		}
+		busy = append(busy, timeRange{*event.Time, event.Time.Add(*event.Duration)})
+	}
+
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].start.Before(busy[j].start)
+	})
+
+	var merged []timeRange
+	for _, r := range busy {
+		if n := len(merged); n > 0 && !r.start.After(merged[n-1].end) {
+			if r.end.After(merged[n-1].end) {
+				merged[n-1].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// freeSlots returns the time between from and until, on the same day, that
+// no event takes up
+func freeSlots(events []remind.Event, from, until time.Time) []timeRange {
+	var free []timeRange
+	cursor := from
+	for _, busy := range busyRanges(events, from) {
+		if !busy.end.After(cursor) {
+			continue
+		}
+		if !busy.start.Before(until) {
+			break
+		}
+		if busy.start.After(cursor) {
+			free = append(free, timeRange{cursor, busy.start})
+		}
+		cursor = busy.end
+	}
+	if cursor.Before(until) {
+		free = append(free, timeRange{cursor, until})
+	}
+	return free
+}
+
+// workingHours returns the start and end of the configured working hours on
+// day. On days off, the end isn't after the start.
+func (m *Model) workingHours(day time.Time) (time.Time, time.Time) {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	start, end := m.config.WorkHoursOn(day.Weekday())
+	return midnight.Add(start), midnight.Add(end)
+}
+
+// openings returns the earliest start in each free slot on day long enough
+// for a task of the given length, within working hours and not before now
+func (m *Model) openings(events []remind.Event, day, now time.Time, length time.Duration) []time.Time {
+	from, until := m.workingHours(day)
+	if now.After(from) {
+		from = now
+	}
+
+	var starts []time.Time
+	for _, slot := range freeSlots(events, from, until) {
+		start := slot.start
+		if rounded := start.Truncate(slotStep); !rounded.Equal(start) {
+			start = rounded.Add(slotStep)
+		}
+		if !start.Add(length).After(slot.end) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
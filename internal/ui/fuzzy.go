@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// fuzzyScore matches pattern against text as a case-insensitive subsequence.
+// Higher scores are better: consecutive matches and matches at the start of
+// words score extra, gaps cost a little.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score := 0
+	pi := 0
+	lastMatch := -1
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+
+		score += 10
+		if lastMatch == ti-1 {
+			score += 15 // Consecutive
+		} else if lastMatch >= 0 {
+			score -= ti - lastMatch - 1 // Gap
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 20 // Start of a word
+		}
+
+		lastMatch = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// fuzzyFilter returns the events whose description or tags match the
+// pattern, best matches first and chronologically among equal scores
+func fuzzyFilter(events []remind.Event, pattern string) []remind.Event {
+	type match struct {
+		event remind.Event
+		score int
+	}
+
+	var matches []match
+	for _, event := range events {
+		best, found := fuzzyScore(pattern, event.Description)
+		for _, tag := range event.Tags {
+			if score, ok := fuzzyScore(pattern, tag); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			matches = append(matches, match{event, best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return eventBefore(matches[i].event, matches[j].event)
+	})
+
+	result := make([]remind.Event, len(matches))
+	for i, m := range matches {
+		result[i] = m.event
+	}
+	return result
+}
+
+// eventBefore orders events by date, then time, with untimed events first
+func eventBefore(a, b remind.Event) bool {
+	if !sameDay(a.Date, b.Date) {
+		return a.Date.Before(b.Date)
+	}
+	if a.Time == nil || b.Time == nil {
+		return a.Time == nil && b.Time != nil
+	}
+	if !a.Time.Equal(*b.Time) {
+		return a.Time.Before(*b.Time)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("xyz", "Team standup"); ok {
+		t.Error("expected no match for letters not in text")
+	}
+	if _, ok := fuzzyScore("pudnats", "standup"); ok {
+		t.Error("expected no match when letters are out of order")
+	}
+	if score, ok := fuzzyScore("", "anything"); !ok || score != 0 {
+		t.Errorf("empty pattern should match with score 0, got %d, %v", score, ok)
+	}
+
+	// Word starts and consecutive letters beat scattered matches
+	word, ok := fuzzyScore("stand", "Team standup")
+	if !ok {
+		t.Fatal("expected match for word prefix")
+	}
+	inside, ok := fuzzyScore("stand", "substandard")
+	if !ok {
+		t.Fatal("expected match inside a word")
+	}
+	scattered, ok := fuzzyScore("stand", "fastest hand")
+	if !ok {
+		t.Fatal("expected scattered subsequence to match")
+	}
+	if word <= inside || inside <= scattered {
+		t.Errorf("word prefix scored %d, inside word %d, scattered %d", word, inside, scattered)
+	}
+
+	// Case insensitive
+	if _, ok := fuzzyScore("TEAM", "team standup"); !ok {
+		t.Error("expected case-insensitive match")
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	at := func(h int) *time.Time {
+		tm := day.Add(time.Duration(h) * time.Hour)
+		return &tm
+	}
+
+	events := []remind.Event{
+		{ID: "1", Date: day.AddDate(0, 0, 1), Time: at(9), Description: "Dentist"},
+		{ID: "2", Date: day, Time: at(14), Description: "Design review"},
+		{ID: "3", Date: day, Description: "Lunch", Tags: []string{"dining"}},
+		{ID: "4", Date: day, Time: at(10), Description: "Budget planning"},
+	}
+
+	matches := fuzzyFilter(events, "de")
+	var ids []string
+	for _, e := range matches {
+		ids = append(ids, e.ID)
+	}
+	// Dentist and Design both start with "de"; equal scores fall back to
+	// chronological order. The tag only matches scattered letters.
+	want := []string{"2", "1"}
+	if len(ids) < len(want) {
+		t.Fatalf("got matches %v, want prefix %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("got matches %v, want prefix %v", ids, want)
+			break
+		}
+	}
+
+	if got := fuzzyFilter(events, "dining"); len(got) != 1 || got[0].ID != "3" {
+		t.Errorf("expected tag match on event 3, got %v", got)
+	}
+
+	// An empty pattern lists everything chronologically
+	all := fuzzyFilter(events, "")
+	if len(all) != 4 || all[0].ID != "3" || all[1].ID != "4" || all[3].ID != "1" {
+		t.Errorf("unexpected order for empty pattern: %v", all)
+	}
+}
+
+func TestFuzzyFindHistory(t *testing.T) {
+	now := time.Date(2025, 8, 25, 9, 0, 0, 0, time.Local)
+	m, _ := newFileModel(t, "REM Aug 25 2025 AT 10:00 MSG Dentist\nREM Aug 26 2025 AT 11:00 MSG Design review\n", now)
+	m.loadEvents()
+
+	find := func(query string) {
+		m.handleHourlyKeys("", "fuzzy_find")
+		typeText(&m.fuzzyInput, query)
+		m.updateFuzzyMatches()
+		m.handleFuzzyFindKeys(tea.KeyPressMsg{Code: tea.KeyEnter})
+	}
+	find("dent")
+	find("design")
+
+	m.handleHourlyKeys("", "fuzzy_find")
+	// Down moves through the matches and leaves the query alone
+	m.handleFuzzyFindKeys(tea.KeyPressMsg{Code: tea.KeyDown})
+	if m.fuzzyInput.Value() != "" || m.selectedFuzzyIndex != 1 {
+		t.Fatalf("down gave query %q, selection %d", m.fuzzyInput.Value(), m.selectedFuzzyIndex)
+	}
+	m.handleFuzzyFindKeys(alt('p'))
+	m.handleFuzzyFindKeys(alt('p'))
+	if got := m.fuzzyInput.Value(); got != "dent" {
+		t.Errorf("alt+p twice recalled %q, want dent", got)
+	}
+	if len(m.fuzzyMatches) != 1 || m.fuzzyMatches[0].Description != "Dentist" {
+		t.Errorf("recalled query matched %v", m.fuzzyMatches)
+	}
+	m.handleFuzzyFindKeys(alt('n'))
+	if got := m.fuzzyInput.Value(); got != "design" {
+		t.Errorf("alt+n recalled %q, want design", got)
+	}
+}
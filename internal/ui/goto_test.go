@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"fmt"
-	"github.com/cwarden/urd/internal/remind"
 	"testing"
 	"time"
 )
@@ -59,6 +57,61 @@ func TestGotoDateParsing(t *testing.T) {
 			expected: time.Date(2025, 8, 21, 0, 0, 0, 0, time.Local),
 			wantErr:  false,
 		},
+		{
+			name:     "Relative weeks",
+			input:    "+3w",
+			expected: time.Date(2025, 9, 10, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Relative days backwards",
+			input:    "-2d",
+			expected: time.Date(2025, 8, 18, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Relative months",
+			input:    "+1m",
+			expected: time.Date(2025, 9, 20, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Abbreviated weekday",
+			input:    "next fri",
+			expected: time.Date(2025, 8, 29, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "End of month",
+			input:    "eom",
+			expected: time.Date(2025, 8, 31, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "ISO week",
+			input:    "2025-W40",
+			expected: time.Date(2025, 9, 29, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "ISO week with weekday",
+			input:    "2026-W01-5",
+			expected: time.Date(2026, 1, 2, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "ISO week 53",
+			input:    "2026-W53",
+			expected: time.Date(2026, 12, 28, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "ISO week 53 of a year with 52",
+			input:   "2025-W53",
+			wantErr: true,
+		},
+		{
+			name:    "ISO week 0",
+			input:   "2025-W00",
+			wantErr: true,
+		},
+		{
+			name:    "ISO week past the end",
+			input:   "2025-W99",
+			wantErr: true,
+		},
 		{
 			name:    "Invalid format",
 			input:   "not-a-date-at-all-xyz",
@@ -73,62 +126,49 @@ func TestGotoDateParsing(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseGotoInput(tt.input, now)
+			result, err := parseGotoDate(tt.input, now, true)
 
 			if tt.wantErr {
 				if err == nil {
-					t.Errorf("parseGotoInput() expected error but got none")
+					t.Errorf("parseGotoDate() expected error but got none")
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("parseGotoInput() unexpected error: %v", err)
+				t.Errorf("parseGotoDate() unexpected error: %v", err)
 				return
 			}
 
 			if !result.Equal(tt.expected) {
-				t.Errorf("parseGotoInput() = %v, want %v", result, tt.expected)
+				t.Errorf("parseGotoDate() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
 }
 
-// parseGotoInput extracts the date parsing logic from handleGotoDateKeys for testing
-func parseGotoInput(input string, now time.Time) (time.Time, error) {
-	if input == "" {
-		return time.Time{}, fmt.Errorf("empty input")
-	}
+func TestGotoDateEndianness(t *testing.T) {
+	now := time.Date(2025, 8, 20, 14, 30, 0, 0, time.Local)
+	march4 := time.Date(2025, 3, 4, 0, 0, 0, 0, time.Local)
+	april3 := time.Date(2025, 4, 3, 0, 0, 0, 0, time.Local)
 
-	// Try standard date formats FIRST
-	dateFormats := []string{
-		"2006-01-02", // YYYY-MM-DD
-		"01/02/2006", // MM/DD/YYYY
-		"1/2/2006",   // M/D/YYYY
-		"01/02",      // MM/DD (current year)
-		"1/2",        // M/D (current year)
+	tests := []struct {
+		input     string
+		bigEndian bool
+		expected  time.Time
+	}{
+		{"03/04", true, march4},
+		{"03/04/2025", true, march4},
+		{"20250304", true, march4},
+		{"03/04", false, april3},
+		{"3/4/2025", false, april3},
+		{"03042025", false, april3},
+		{"2025-03-04", false, march4},
 	}
-
-	for _, format := range dateFormats {
-		if pd, err := time.ParseInLocation(format, input, time.Local); err == nil {
-			// For MM/DD formats without year, use current year
-			if format == "01/02" || format == "1/2" {
-				return time.Date(now.Year(), pd.Month(), pd.Day(),
-					0, 0, 0, 0, time.Local), nil
-			} else {
-				// Ensure the date is in local timezone with time at midnight
-				return time.Date(pd.Year(), pd.Month(), pd.Day(),
-					0, 0, 0, 0, time.Local), nil
-			}
+	for _, tt := range tests {
+		result, err := parseGotoDate(tt.input, now, tt.bigEndian)
+		if err != nil || !result.Equal(tt.expected) {
+			t.Errorf("parseGotoDate(%q, big endian %v) = %v, %v; want %v", tt.input, tt.bigEndian, result, err, tt.expected)
 		}
 	}
-
-	// If standard formats failed, try natural language parsing
-	parser := &remind.TimeParser{Now: now, Location: time.Local}
-	date, err := parser.ParseDateOnly(input)
-	if err == nil {
-		return date, nil
-	}
-
-	return time.Time{}, fmt.Errorf("invalid date format: %s", input)
 }
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// sourceHealth returns how each source is doing by name, when the source
+// keeps track of it
+func (m *Model) sourceHealth() map[string]remind.SourceHealth {
+	reporter, ok := m.source.(remind.HealthSource)
+	if !ok {
+		return nil
+	}
+	byName := make(map[string]remind.SourceHealth)
+	for _, health := range reporter.Health() {
+		byName[health.Name] = health
+	}
+	return byName
+}
+
+// healthLines describes how fetching and watching a source went, like
+// "35 events at 10:02 in 120ms, watching", with the last error, if the
+// last fetch failed, on a line of its own
+func (m *Model) healthLines(health remind.SourceHealth) []string {
+	latency := health.Latency.Round(time.Millisecond).String()
+
+	var parts []string
+	switch {
+	case health.LastSuccess.IsZero() && health.LastError == nil:
+		parts = append(parts, "not fetched yet")
+	case health.LastError != nil && health.LastSuccess.IsZero():
+		parts = append(parts, "never fetched")
+	case health.LastError != nil:
+		parts = append(parts, fmt.Sprintf("%d events at %s", health.Events, health.LastSuccess.Format("15:04")))
+	default:
+		parts = append(parts, fmt.Sprintf("%d events at %s in %s", health.Events, health.LastSuccess.Format("15:04"), latency))
+	}
+	if health.Watch != "" {
+		parts = append(parts, health.Watch)
+	}
+	lines := []string{m.styles.Help.Render("    " + strings.Join(parts, ", "))}
+
+	if health.LastError != nil {
+		lines = append(lines, m.styles.Priority.Render(fmt.Sprintf("    failed at %s after %s: %v", health.ErrorAt.Format("15:04"), latency, health.LastError)))
+	}
+	if health.WatchError != nil {
+		lines = append(lines, m.styles.Priority.Render(fmt.Sprintf("    %s: %v", remind.WatchFailed, health.WatchError)))
+	}
+	return lines
+}
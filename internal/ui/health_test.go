@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestHealthLines(t *testing.T) {
+	m := &Model{styles: defaultStyles()}
+	at := time.Date(2025, 8, 25, 10, 2, 0, 0, time.Local)
+
+	tests := []struct {
+		health   remind.SourceHealth
+		expected []string
+	}{
+		{remind.SourceHealth{}, []string{"    not fetched yet"}},
+		{
+			remind.SourceHealth{LastSuccess: at, Events: 35, Latency: 120 * time.Millisecond, Watch: remind.WatchActive},
+			[]string{"    35 events at 10:02 in 120ms, watching"},
+		},
+		{
+			remind.SourceHealth{LastSuccess: at, Events: 35, LastError: errors.New("timeout"), ErrorAt: at.Add(time.Hour), Latency: 2 * time.Second},
+			[]string{"    35 events at 10:02", "    failed at 11:02 after 2s: timeout"},
+		},
+		{
+			remind.SourceHealth{LastSuccess: at, Watch: remind.WatchFailed, WatchError: errors.New("too many files")},
+			[]string{"    0 events at 10:02 in 0s, watch failed", "    watch failed: too many files"},
+		},
+	}
+	for _, tt := range tests {
+		lines := m.healthLines(tt.health)
+		if len(lines) != len(tt.expected) {
+			t.Errorf("healthLines(%+v) = %q, want %q", tt.health, lines, tt.expected)
+			continue
+		}
+		for i := range lines {
+			if got := ansi.Strip(lines[i]); got != tt.expected[i] {
+				t.Errorf("line %d = %q, want %q", i, got, tt.expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// heatShades are the backgrounds of the time gutter, from the hours events
+// took place at least often to the most. Grays read the same whatever the
+// palette.
+var heatShades = [4]lipgloss.ANSIColor{236, 238, 241, 244}
+
+// gutterHeat is how busy each hour of the day was over the last gutter_heat
+// weeks, from 0 for never to len(heatShades), as of a day
+type gutterHeat struct {
+	day      time.Time
+	revision int
+	levels   [24]int
+}
+
+// hourHeat returns how busy each hour of the day was over the weeks before
+// today. It asks the source once a day, and again when events were
+// reloaded.
+func (m *Model) hourHeat() [24]int {
+	now := m.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if m.heat.day.Equal(today) && m.heat.revision == m.eventsRevision {
+		return m.heat.levels
+	}
+	m.heat = gutterHeat{day: today, revision: m.eventsRevision}
+	if m.source == nil || m.config.GutterHeat <= 0 {
+		return m.heat.levels
+	}
+
+	events, err := m.source.GetEvents(today.AddDate(0, 0, -7*m.config.GutterHeat), today)
+	if err != nil {
+		return m.heat.levels // Unshaded rather than an error on every render
+	}
+	var counts [24]int
+	for _, event := range events {
+		if event.Time == nil || event.IsSpecial() || !event.Time.Before(today) {
+			continue
+		}
+		end := event.Time.Hour() + 1
+		if event.Duration != nil && *event.Duration > 0 {
+			last := event.Time.Add(*event.Duration - time.Minute)
+			end = 24
+			if sameDay(last, *event.Time) {
+				end = last.Hour() + 1
+			}
+		}
+		for hour := event.Time.Hour(); hour < end; hour++ {
+			counts[hour]++
+		}
+	}
+
+	most := 0
+	for _, count := range counts {
+		most = max(most, count)
+	}
+	for hour, count := range counts {
+		if count > 0 {
+			m.heat.levels[hour] = (count*len(heatShades) + most - 1) / most
+		}
+	}
+	return m.heat.levels
+}
+
+// heatStyle shades style, that of a time label, by how busy its hour was
+func (m *Model) heatStyle(style lipgloss.Style, hour int) lipgloss.Style {
+	if m.config.GutterHeat <= 0 {
+		return style
+	}
+	if level := m.hourHeat()[hour]; level > 0 {
+		return style.Background(heatShades[level-1])
+	}
+	return style
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestHourHeat(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 17, 0, 0, time.Local)
+	at := func(days, hour, minute int) *time.Time {
+		t := time.Date(2025, 8, 25+days, hour, minute, 0, 0, time.Local)
+		return &t
+	}
+	var events []remind.Event
+	for week := 1; week <= 4; week++ {
+		// A standup every Monday, with a planning meeting after it every
+		// other week
+		standup := at(-7*week, 9, 0)
+		events = append(events, remind.Event{Date: *standup, Time: standup, Duration: durationPtr(30), Description: "Standup"})
+		if week%2 == 0 {
+			planning := at(-7*week, 10, 0)
+			events = append(events, remind.Event{Date: *planning, Time: planning, Duration: durationPtr(120), Description: "Planning"})
+		}
+	}
+	// Today's events aren't history yet
+	events = append(events, remind.Event{Date: *at(0, 15, 0), Time: at(0, 15, 0), Description: "Review"})
+
+	source := &watchedSource{events: events}
+	m := &Model{
+		config: &config.Config{GutterHeat: 4},
+		clock:  remind.FixedClock(now),
+		source: source,
+	}
+	levels := m.hourHeat()
+	if levels[9] != 4 || levels[10] != 2 || levels[11] != 2 || levels[12] != 0 || levels[15] != 0 {
+		t.Errorf("unexpected levels %v", levels)
+	}
+
+	// Asked again only once events are reloaded
+	source.events = nil
+	if levels := m.hourHeat(); levels[9] != 4 {
+		t.Errorf("expected the levels cached, got %v", levels)
+	}
+	m.eventsRevision++
+	if levels := m.hourHeat(); levels[9] != 0 {
+		t.Errorf("expected the levels recounted, got %v", levels)
+	}
+
+	m.config.GutterHeat = 0
+	if style := m.heatStyle(m.styles.Normal, 9); style.GetBackground() != m.styles.Normal.GetBackground() {
+		t.Error("expected the gutter unshaded without gutter_heat")
+	}
+}
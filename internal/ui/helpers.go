@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -9,82 +10,141 @@ import (
 	"github.com/cwarden/urd/internal/remind"
 
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/muesli/reflow/wordwrap"
 )
 
+// displayEvent returns the event as it should be rendered, redacting
+// private events while presentation mode is active
+func (m *Model) displayEvent(event remind.Event) remind.Event {
+	if m.presentationMode && event.IsPrivate() {
+		return event.Redacted()
+	}
+	return event
+}
+
 // getEventTextColor returns an appropriate text color for the given background color
 func (m *Model) getEventTextColor(bgColor lipgloss.ANSIColor) lipgloss.ANSIColor {
 	// Use dark text for light backgrounds
-	lightBackgrounds := map[lipgloss.ANSIColor]bool{
-		48:  true, // Light green
-		220: true, // Yellow
-		228: true, // Light yellow
-		214: true, // Orange-yellow
-		105: true, // Very light purple
-	}
-
-	if lightBackgrounds[bgColor] {
+	if m.colors().isLight(bgColor) {
 		return lipgloss.ANSIColor(0) // Black text for better contrast
 	}
-
-	// Use white text for dark backgrounds
 	return lipgloss.ANSIColor(15) // White text
 }
 
 // getEventBackgroundColor returns a background color based on event properties
 func (m *Model) getEventBackgroundColor(event remind.Event) lipgloss.ANSIColor {
+	// An explicit color from the remind file wins over the heuristics below
+	if event.Color != nil {
+		return rgbToANSI(*event.Color)
+	}
+
+	colors := m.colors()
+
 	// P2 tasks get different colors than remind events
 	if len(event.ID) >= 3 && event.ID[:3] == "p2-" {
 		// P2 task colors based on duration
 		if event.Duration != nil {
-			duration := event.Duration.Hours()
-			if duration >= 4 {
-				return lipgloss.ANSIColor(88) // Dark red for long tasks (4+ hours)
-			} else if duration >= 2 {
-				return lipgloss.ANSIColor(208) // Orange for medium tasks (2-4 hours)
-			} else if duration >= 1 {
-				return lipgloss.ANSIColor(220) // Yellow for short tasks (1-2 hours)
-			} else {
-				return lipgloss.ANSIColor(48) // Light green for very short tasks (<1 hour)
-			}
+			return colors.p2Durations[durationLevel(*event.Duration)]
 		}
 		// Default for P2 tasks without duration
-		return lipgloss.ANSIColor(24) // Blue for P2 tasks
+		return colors.p2Default
 	}
 
 	// Remind events get different colors
 	if event.Duration != nil {
-		duration := event.Duration.Hours()
-		if duration >= 4 {
-			return lipgloss.ANSIColor(52) // Dark purple for long events
-		} else if duration >= 2 {
-			return lipgloss.ANSIColor(63) // Medium purple for medium events
-		} else if duration >= 1 {
-			return lipgloss.ANSIColor(99) // Light purple for short events
-		} else {
-			return lipgloss.ANSIColor(105) // Very light purple for brief events
-		}
+		return colors.remindDurations[durationLevel(*event.Duration)]
 	}
 
 	// Priority-based colors for events without duration
 	switch event.Priority {
 	case remind.PriorityHigh:
-		return lipgloss.ANSIColor(196) // Bright red
+		return colors.priorities[3]
 	case remind.PriorityMedium:
-		return lipgloss.ANSIColor(214) // Orange-yellow
+		return colors.priorities[2]
 	case remind.PriorityLow:
-		return lipgloss.ANSIColor(228) // Light yellow
+		return colors.priorities[1]
+	default:
+		return colors.priorities[0]
+	}
+}
+
+// durationLevel buckets a duration into 4+ hours (0), 2-4 hours (1), 1-2
+// hours (2) and shorter (3), the order palettes list their colors in
+func durationLevel(duration time.Duration) int {
+	switch hours := duration.Hours(); {
+	case hours >= 4:
+		return 0
+	case hours >= 2:
+		return 1
+	case hours >= 1:
+		return 2
 	default:
-		return lipgloss.ANSIColor(240) // Gray for normal remind events
+		return 3
+	}
+}
+
+// rgbToANSI maps an RGB color to the nearest color in the 256-color palette,
+// choosing between the 6x6x6 color cube and the grayscale ramp
+func rgbToANSI(c remind.Color) lipgloss.ANSIColor {
+	cubeLevels := []int{0, 95, 135, 175, 215, 255}
+	nearestLevel := func(v int) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(v-level) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	r, g, b := nearestLevel(c.R), nearestLevel(c.G), nearestLevel(c.B)
+	cube := 16 + 36*r + 6*g + b
+	cubeDist := sq(c.R-cubeLevels[r]) + sq(c.G-cubeLevels[g]) + sq(c.B-cubeLevels[b])
+
+	// Grayscale ramp 232-255 covers 8..238 in steps of 10
+	avg := (c.R + c.G + c.B) / 3
+	grayIndex := (avg - 8 + 5) / 10
+	if grayIndex < 0 {
+		grayIndex = 0
+	} else if grayIndex > 23 {
+		grayIndex = 23
+	}
+	grayLevel := 8 + 10*grayIndex
+	grayDist := sq(c.R-grayLevel) + sq(c.G-grayLevel) + sq(c.B-grayLevel)
+
+	if grayDist < cubeDist {
+		return lipgloss.ANSIColor(232 + grayIndex)
+	}
+	return lipgloss.ANSIColor(cube)
+}
+
+// isLightColor reports whether dark text should be used on the color
+func isLightColor(c remind.Color) bool {
+	// Perceived brightness (ITU-R BT.601)
+	return c.R*299+c.G*587+c.B*114 > 150000
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
 	}
+	return v
+}
+
+func sq(v int) int {
+	return v * v
 }
 
 // renderMiniCalendar renders a small calendar for navigation
 func (m *Model) renderMiniCalendar() string {
 	var lines []string
 
-	// Month/Year header
+	// Month/Year header, with any SPECIAL WEEK annotation for the selected week
 	monthYear := m.selectedDate.Format("January 2006")
+	if week := m.weekAnnotation(m.selectedDate); week != "" {
+		monthYear += " " + week
+	}
 	lines = append(lines, m.styles.Header.Render(monthYear))
 
 	// Day headers
@@ -100,7 +160,7 @@ func (m *Model) renderMiniCalendar() string {
 
 	// Build calendar grid
 	day := firstDay.AddDate(0, 0, -startOffset)
-	today := time.Now()
+	today := m.now()
 
 	var weekLines []string
 	weekDays := ""
@@ -117,6 +177,11 @@ func (m *Model) renderMiniCalendar() string {
 				dayStr = m.styles.Selected.Render(dayStr)
 			} else if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
 				dayStr = m.styles.Weekend.Render(dayStr)
+			} else if shade := m.dayShade(day); shade != nil {
+				dayStr = m.styles.Normal.
+					Background(rgbToANSI(*shade)).
+					Foreground(shadeTextColor(*shade)).
+					Render(dayStr)
 			} else {
 				dayStr = m.styles.Normal.Render(dayStr)
 			}
@@ -139,11 +204,303 @@ func (m *Model) renderMiniCalendar() string {
 
 	lines = append(lines, weekLines...)
 
+	// Moon phases for the month
+	if moons := m.moonPhases(m.selectedDate); moons != "" {
+		lines = append(lines, m.styles.Help.Render(moons))
+	}
+
+	// Days left until the month's COUNTDOWN deadlines
+	for _, c := range m.countdowns(today) {
+		if c.event.Date.Year() == m.selectedDate.Year() && c.event.Date.Month() == m.selectedDate.Month() {
+			lines = append(lines, m.countdownLine(c, 20))
+		}
+	}
+
 	// Add border
 	bordered := m.styles.Border.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 	return bordered
 }
 
+// moonSymbols are indexed by remind's MOON phase numbers
+var moonSymbols = []string{"●", "◐", "○", "◑"}
+
+// dayShade returns the SPECIAL SHADE color for a day, or nil if unshaded
+func (m *Model) dayShade(day time.Time) *remind.Color {
+	for _, special := range m.specials {
+		if special.Special == remind.SpecialShade && special.Color != nil && sameDay(special.Date, day) {
+			return special.Color
+		}
+	}
+	return nil
+}
+
+// shadeTextColor picks a readable text color for a shaded day
+func shadeTextColor(c remind.Color) lipgloss.ANSIColor {
+	if isLightColor(c) {
+		return lipgloss.ANSIColor(0)
+	}
+	return lipgloss.ANSIColor(15)
+}
+
+// weekAnnotation returns the SPECIAL WEEK text for the week containing date
+func (m *Model) weekAnnotation(date time.Time) string {
+	offset := (int(date.Weekday()) + 6) % 7 // Monday = 0
+	weekStart := time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, date.Location())
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	for _, special := range m.specials {
+		if special.Special == remind.SpecialWeek &&
+			!special.Date.Before(weekStart) && special.Date.Before(weekEnd) {
+			return special.Description
+		}
+	}
+	return ""
+}
+
+// moonPhases returns the SPECIAL MOON phases in date's month, e.g. "● 2  ◐ 9"
+func (m *Model) moonPhases(date time.Time) string {
+	var moons []remind.Event
+	for _, special := range m.specials {
+		if special.Special == remind.SpecialMoon &&
+			special.Date.Year() == date.Year() && special.Date.Month() == date.Month() {
+			moons = append(moons, special)
+		}
+	}
+	sort.Slice(moons, func(i, j int) bool {
+		return moons[i].Date.Before(moons[j].Date)
+	})
+
+	var parts []string
+	for _, moon := range moons {
+		parts = append(parts, fmt.Sprintf("%s %d", moonSymbols[moon.MoonPhase], moon.Date.Day()))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// scheduleWidth returns the width of the schedule, left of the sidebar
+func (m *Model) scheduleWidth() int {
+	// The schedule takes 2/3 of width, so we have 1/3 for the right side,
+	// unless untimed_window_width sets the sidebar's
+	scheduleWidth := m.width * 2 / 3
+	if m.config != nil && m.config.UntimedWidth > 0 {
+		scheduleWidth = m.width - m.config.UntimedWidth - 1
+	}
+	if scheduleWidth < 40 {
+		scheduleWidth = 40
+	}
+	return scheduleWidth
+}
+
+// sidebarBoxWidth returns the width of the boxes in the sidebar
+func (m *Model) sidebarBoxWidth() int {
+	// Right side width minus padding and borders
+	boxWidth := m.width - m.scheduleWidth() - 4
+	if boxWidth < 30 {
+		boxWidth = 30
+	}
+	return boxWidth
+}
+
+// dayAgenda returns the events on date, timed events in chronological
+// order followed by untimed ones
+func (m *Model) dayAgenda(date time.Time) []remind.Event {
+	return agendaOrder(m.eventsOn(date))
+}
+
+// agendaOrder returns the timed events in chronological order followed by
+// the untimed ones, most important first
+func agendaOrder(events []remind.Event) []remind.Event {
+	var timed, untimed []remind.Event
+	for _, event := range events {
+		if event.Time != nil {
+			timed = append(timed, event)
+		} else {
+			untimed = append(untimed, event)
+		}
+	}
+
+	sort.SliceStable(timed, func(i, j int) bool {
+		if !timed[i].Time.Equal(*timed[j].Time) {
+			return timed[i].Time.Before(*timed[j].Time)
+		}
+		if timed[i].Description != timed[j].Description {
+			return timed[i].Description < timed[j].Description
+		}
+		return timed[i].ID < timed[j].ID
+	})
+	sort.SliceStable(untimed, func(i, j int) bool {
+		if untimed[i].Priority != untimed[j].Priority {
+			return untimed[i].Priority > untimed[j].Priority
+		}
+		if untimed[i].Description != untimed[j].Description {
+			return untimed[i].Description < untimed[j].Description
+		}
+		return untimed[i].ID < untimed[j].ID
+	})
+
+	return append(timed, untimed...)
+}
+
+// renderDayAgenda renders every event on the selected day in compact form
+func (m *Model) renderDayAgenda() string {
+	boxWidth := m.sidebarBoxWidth()
+	date := m.selectedDate
+
+	var lines []string
+	lines = append(lines, m.styles.Header.Render(date.Format(m.config.DateLayout("Mon Jan 2, 2006"))+" agenda"))
+	lines = append(lines, "")
+
+	events := m.dayAgenda(date)
+	if len(events) == 0 {
+		lines = append(lines, m.styles.Help.Render("(nothing scheduled)"))
+	}
+
+	for _, event := range events {
+		event = m.displayEvent(event)
+
+		when := agendaWhen(event)
+		desc := event.Description
+		if event.Priority > remind.PriorityNone {
+			desc = strings.Repeat("!", int(event.Priority)) + " " + desc
+		}
+
+		line := when + " " + desc
+		if maxWidth := boxWidth - 4; maxWidth > 3 {
+			line = ansi.Truncate(line, maxWidth, "...")
+		}
+		lines = append(lines, m.styles.Normal.Render(line))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return m.styles.Border.Copy().Width(boxWidth).Render(content)
+}
+
+// agendaWhen returns when an event takes place in a fixed-width column, so
+// descriptions line up
+func agendaWhen(event remind.Event) string {
+	if event.Time == nil {
+		return "all day    "
+	}
+	if event.Duration != nil {
+		return event.Time.Format("15:04") + "-" + event.Time.Add(*event.Duration).Format("15:04")
+	}
+	return event.Time.Format("15:04") + "      "
+}
+
+// relativeTime describes when an event takes place from now, like "in 2h
+// 15m", "ends in 40m" while it does or "3 days ago". Those a day or more
+// away, and untimed ones, are counted in days.
+func relativeTime(now time.Time, event remind.Event) string {
+	if event.Time != nil {
+		start := *event.Time
+		if event.Duration != nil && *event.Duration > 0 && !now.Before(start) && now.Before(start.Add(*event.Duration)) {
+			return "ends in " + formatDuration(start.Add(*event.Duration).Sub(now).Round(time.Minute))
+		}
+		until := start.Sub(now).Round(time.Minute)
+		switch {
+		case until == 0:
+			return "now"
+		case until > 0 && until < 24*time.Hour:
+			return "in " + formatDuration(until)
+		case until < 0 && until > -24*time.Hour:
+			return formatDuration(-until) + " ago"
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, now.Location())
+	switch days := int(math.Round(date.Sub(today).Hours() / 24)); {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 0:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+// selectedDay returns the day of the selected slot
+func (m *Model) selectedDay() time.Time {
+	slotsPerDay := m.getSlotsPerDay()
+	dayOffset := m.selectedSlot / slotsPerDay
+	if m.selectedSlot < 0 {
+		dayOffset = -1 + (m.selectedSlot+1)/slotsPerDay
+	}
+	return m.selectedDate.AddDate(0, 0, dayOffset)
+}
+
+// selectedEvents returns the selected untimed reminder when the untimed box
+// has focus, otherwise the events at the selected slot
+func (m *Model) selectedEvents() []remind.Event {
+	if m.focusUntimed {
+		untimed := m.getSortedUntimedEvents(m.selectedDay())
+		if m.selectedUntimedIndex < len(untimed) {
+			return untimed[m.selectedUntimedIndex : m.selectedUntimedIndex+1]
+		}
+		return nil
+	}
+	return m.getEventsAtSlot(m.selectedSlot)
+}
+
+// visibleDays returns the first and last day shown in the schedule
+func (m *Model) visibleDays() (time.Time, time.Time) {
+	slotsPerDay := m.getSlotsPerDay()
+	dayOffset := func(slot int) int {
+		if slot < 0 {
+			return -1 + (slot+1)/slotsPerDay
+		}
+		return slot / slotsPerDay
+	}
+
+	base := time.Date(m.selectedDate.Year(), m.selectedDate.Month(), m.selectedDate.Day(), 0, 0, 0, 0, m.selectedDate.Location())
+	first := base.AddDate(0, 0, dayOffset(m.topSlot))
+	last := base.AddDate(0, 0, dayOffset(m.topSlot+m.getVisibleSlots()-1))
+	return first, last
+}
+
+// agendaSnapshot renders the events from start to end as fixed-width plain
+// text, suitable for pasting into chat or email
+func (m *Model) agendaSnapshot(start, end time.Time) string {
+	var b strings.Builder
+
+	if sameDay(start, end) {
+		fmt.Fprintf(&b, "Agenda for %s\n", start.Format(m.config.DateLayout("Mon Jan 2, 2006")))
+	} else {
+		fmt.Fprintf(&b, "Agenda for %s - %s\n", start.Format(m.config.DateLayout("Mon Jan 2")), end.Format(m.config.DateLayout("Mon Jan 2, 2006")))
+	}
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		fmt.Fprintf(&b, "\n%s\n", date.Format(m.config.DateLayout("Mon Jan _2")))
+
+		events := m.dayAgenda(date)
+		if len(events) == 0 {
+			b.WriteString("  nothing scheduled\n")
+		}
+		for _, event := range events {
+			event = m.displayEvent(event)
+
+			when := "all day"
+			if event.Time != nil {
+				when = event.Time.Format("15:04")
+				if event.Duration != nil {
+					when += "-" + event.Time.Add(*event.Duration).Format("15:04")
+				}
+			}
+			fmt.Fprintf(&b, "  %-11s  %s\n", when, event.Description)
+		}
+	}
+
+	return b.String()
+}
+
 // renderSelectedSlotEvents renders all events for the selected time slot
 func (m *Model) renderSelectedSlotEvents() string {
 	// Find event at selected slot
@@ -181,10 +538,8 @@ func (m *Model) renderSelectedSlotEvents() string {
 
 	// Find events active during this time slot
 	var selectedEvents []remind.Event
-	for _, event := range m.events {
-		if event.Time != nil &&
-			event.Date.Year() == selectedDate.Year() &&
-			event.Date.YearDay() == selectedDate.YearDay() {
+	for _, event := range m.eventsOn(selectedDate) {
+		if event.Time != nil {
 
 			// Calculate event start and end times
 			eventStart := *event.Time
@@ -239,22 +594,11 @@ func (m *Model) renderSelectedSlotEvents() string {
 	})
 
 	var lines []string
-
-	// Calculate available width for the box
-	// The schedule takes 2/3 of width, so we have 1/3 for the right side
-	scheduleWidth := m.width * 2 / 3
-	if scheduleWidth < 40 {
-		scheduleWidth = 40
-	}
-	// Right side width minus padding and borders
-	boxWidth := m.width - scheduleWidth - 4
-	if boxWidth < 30 {
-		boxWidth = 30
-	}
+	boxWidth := m.sidebarBoxWidth()
 
 	// Header with selected time
 	timeHeader := fmt.Sprintf("%s at %02d:%02d",
-		selectedDate.Format("Mon Jan 2, 2006"),
+		selectedDate.Format(m.config.DateLayout("Mon Jan 2, 2006")),
 		hour, minute)
 	// Wrap the header to fit within the box width
 	wrappedHeader := wordwrap.String(timeHeader, boxWidth-2)
@@ -266,26 +610,19 @@ func (m *Model) renderSelectedSlotEvents() string {
 		lines = append(lines, m.styles.Help.Render("(no reminders at this time)"))
 	} else {
 		lines = append(lines, "")
+		travelWarnings := m.travelWarnings()
 		for i, event := range selectedEvents {
 			if i > 0 {
 				lines = append(lines, "") // Separator between events
 			}
+			event = m.displayEvent(event)
 
 			// Event time and duration
 			eventTime := fmt.Sprintf("%02d:%02d", event.Time.Hour(), event.Time.Minute())
 			if event.Duration != nil {
-				// Format duration without seconds
-				hours := int(event.Duration.Hours())
-				minutes := int(event.Duration.Minutes()) % 60
-				if hours > 0 && minutes > 0 {
-					eventTime += fmt.Sprintf(" (%dh %dm)", hours, minutes)
-				} else if hours > 0 {
-					eventTime += fmt.Sprintf(" (%dh)", hours)
-				} else {
-					eventTime += fmt.Sprintf(" (%dm)", minutes)
-				}
+				eventTime += " (" + formatDuration(*event.Duration) + ")"
 			}
-			lines = append(lines, m.styles.Event.Render(eventTime))
+			lines = append(lines, m.styles.Event.Render(eventTime)+m.styles.Help.Render(", "+relativeTime(m.now(), event)))
 
 			// Event description
 			desc := event.Description
@@ -324,6 +661,21 @@ func (m *Model) renderSelectedSlotEvents() string {
 				}
 				lines = append(lines, m.styles.Priority.Render(priorityStr))
 			}
+
+			if event.Location != "" {
+				lines = append(lines, m.styles.Help.Render("Location: "+event.Location))
+			}
+			if warning, ok := travelWarnings[event.ID]; ok {
+				lines = append(lines, m.styles.Priority.Render("Travel: "+warning))
+			}
+
+			// Source file, which may be an included file
+			if location := event.SourceLocation(); location != "" {
+				lines = append(lines, m.styles.Help.Render("File: "+location))
+			}
+			if event.Source != "" && !m.eventCapabilities(event).Edit {
+				lines = append(lines, m.styles.Help.Render(fmt.Sprintf("Source: %s (read-only)", event.Source)))
+			}
 		}
 	}
 
@@ -4,9 +4,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/cwarden/urd/internal/config"
 	"github.com/cwarden/urd/internal/remind"
+
+	"github.com/charmbracelet/lipgloss/v2"
 )
 
 // TestSelectedSlotEventsSorting tests that events in the Selected box are sorted consistently
@@ -248,3 +251,245 @@ func TestSelectedSlotEventsStability(t *testing.T) {
 		t.Error("Sorting is not stable: output differs between second and third call")
 	}
 }
+
+func TestEventColorOverride(t *testing.T) {
+	m := &Model{config: &config.Config{}, styles: defaultStyles()}
+
+	duration := 3 * time.Hour
+	event := remind.Event{ID: "evt-1", Duration: &duration}
+	if got := m.getEventBackgroundColor(event); got != lipgloss.ANSIColor(63) {
+		t.Errorf("Expected duration-based color 63, got %d", got)
+	}
+
+	event.Color = &remind.Color{R: 255, G: 0, B: 0}
+	if got := m.getEventBackgroundColor(event); got != lipgloss.ANSIColor(196) {
+		t.Errorf("Expected explicit red to map to 196, got %d", got)
+	}
+}
+
+func TestRGBToANSI(t *testing.T) {
+	tests := []struct {
+		color    remind.Color
+		expected lipgloss.ANSIColor
+	}{
+		{remind.Color{R: 0, G: 0, B: 0}, 16},
+		{remind.Color{R: 255, G: 255, B: 255}, 231},
+		{remind.Color{R: 0, G: 255, B: 0}, 46},
+		{remind.Color{R: 0, G: 0, B: 255}, 21},
+		{remind.Color{R: 128, G: 128, B: 128}, 244},
+	}
+
+	for _, tt := range tests {
+		if got := rgbToANSI(tt.color); got != tt.expected {
+			t.Errorf("rgbToANSI(%v) = %d, want %d", tt.color, got, tt.expected)
+		}
+	}
+
+	if !isLightColor(remind.Color{R: 255, G: 255, B: 0}) {
+		t.Error("Expected yellow to be light")
+	}
+	if isLightColor(remind.Color{R: 0, G: 0, B: 128}) {
+		t.Error("Expected navy to be dark")
+	}
+}
+
+func TestCalendarSpecials(t *testing.T) {
+	m := &Model{config: &config.Config{}, styles: defaultStyles()}
+
+	meeting := time.Date(2025, 8, 11, 10, 0, 0, 0, time.Local)
+	m.events, m.specials = splitSpecials([]remind.Event{
+		{ID: "evt-1", Date: time.Date(2025, 8, 9, 0, 0, 0, 0, time.Local), Special: remind.SpecialMoon, MoonPhase: remind.MoonFull},
+		{ID: "evt-2", Date: time.Date(2025, 8, 23, 0, 0, 0, 0, time.Local), Special: remind.SpecialMoon, MoonPhase: remind.MoonNew},
+		{ID: "evt-3", Date: time.Date(2025, 8, 10, 0, 0, 0, 0, time.Local), Special: remind.SpecialShade, Color: &remind.Color{R: 255}},
+		{ID: "evt-4", Date: time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local), Special: remind.SpecialWeek, Description: "(W33)"},
+		{ID: "evt-5", Date: time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local), Time: &meeting, Description: "Meeting"},
+	})
+
+	if len(m.events) != 1 || m.events[0].ID != "evt-5" {
+		t.Fatalf("Expected only the meeting in events, got %v", m.events)
+	}
+	if len(m.specials) != 4 {
+		t.Fatalf("Expected 4 specials, got %d", len(m.specials))
+	}
+
+	if got := m.moonPhases(time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)); got != "○ 9  ● 23" {
+		t.Errorf("moonPhases() = %q", got)
+	}
+	if shade := m.dayShade(time.Date(2025, 8, 10, 0, 0, 0, 0, time.Local)); shade == nil || shade.R != 255 {
+		t.Errorf("Expected red shade on Aug 10, got %v", shade)
+	}
+	if shade := m.dayShade(time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local)); shade != nil {
+		t.Errorf("Expected no shade on Aug 11, got %v", shade)
+	}
+	if got := m.weekAnnotation(time.Date(2025, 8, 14, 0, 0, 0, 0, time.Local)); got != "(W33)" {
+		t.Errorf("weekAnnotation() = %q, want (W33)", got)
+	}
+	if got := m.weekAnnotation(time.Date(2025, 8, 18, 0, 0, 0, 0, time.Local)); got != "" {
+		t.Errorf("weekAnnotation() for next week = %q, want empty", got)
+	}
+}
+
+func TestDayAgenda(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(hour int) *time.Time {
+		tm := time.Date(2025, 8, 25, hour, 0, 0, 0, time.Local)
+		return &tm
+	}
+	hour := time.Hour
+
+	m := &Model{
+		config:       &config.Config{},
+		styles:       defaultStyles(),
+		width:        120,
+		selectedDate: day,
+		events: []remind.Event{
+			{ID: "evt-1", Date: day, Description: "Untimed low"},
+			{ID: "evt-2", Date: day, Time: at(14), Description: "Afternoon"},
+			{ID: "evt-3", Date: day, Description: "Untimed high", Priority: remind.PriorityHigh},
+			{ID: "evt-4", Date: day, Time: at(9), Duration: &hour, Description: "Standup"},
+			{ID: "evt-5", Date: day.AddDate(0, 0, 1), Time: at(9), Description: "Tomorrow"},
+		},
+	}
+
+	agenda := m.dayAgenda(day)
+	expected := []string{"evt-4", "evt-2", "evt-3", "evt-1"}
+	if len(agenda) != len(expected) {
+		t.Fatalf("Expected %d agenda entries, got %d", len(expected), len(agenda))
+	}
+	for i, id := range expected {
+		if agenda[i].ID != id {
+			t.Errorf("Agenda[%d] = %s, want %s", i, agenda[i].ID, id)
+		}
+	}
+
+	rendered := m.renderDayAgenda()
+	for _, want := range []string{"09:00-10:00 Standup", "14:00", "all day     !!! Untimed high"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Expected agenda to contain %q:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "Tomorrow") {
+		t.Error("Agenda should only list the selected day")
+	}
+
+	// Long descriptions are cut to the box by display width, not bytes
+	m.events = append(m.events, remind.Event{ID: "evt-6", Date: day, Time: at(16), Description: strings.Repeat("会議", 40)})
+	rendered = m.renderDayAgenda()
+	if !utf8.ValidString(rendered) || !strings.Contains(rendered, "...") {
+		t.Errorf("Expected the wide description truncated cleanly:\n%s", rendered)
+	}
+	for _, line := range strings.Split(rendered, "\n") {
+		if width := lipgloss.Width(line); width > m.sidebarBoxWidth() {
+			t.Errorf("Agenda line is %d wide, more than the box's %d: %q", width, m.sidebarBoxWidth(), line)
+		}
+	}
+}
+
+func TestUntimedIndexOf(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	events := []remind.Event{
+		{Date: day, Description: "Pay rent", Filename: "/r/home.rem", LineNumber: 4},
+		{Date: day, Description: "Pay rent", Filename: "/r/work.rem", LineNumber: 4},
+		{Date: day, Description: "Call mom", Filename: "/r/home.rem", LineNumber: 9},
+	}
+	for i := range events {
+		events[i].ID = remind.EventID(events[i])
+	}
+	m := &Model{config: &config.Config{}, events: events}
+
+	// Sorted by description, then ID: Call mom comes first
+	sorted := m.getSortedUntimedEvents(day)
+	for i, event := range sorted {
+		if got := m.untimedIndexOf(event); got != i {
+			t.Errorf("%s in %s: index %d, want %d", event.Description, event.Filename, got, i)
+		}
+	}
+
+	// Copy takes the selected event in the order shown, not file order
+	m.selectedDate = day
+	m.timeIncrement = 60
+	m.focusUntimed = true
+	m.selectedUntimedIndex = 0
+	m.handleHourlyKeys("y", "copy")
+	if m.clipboardEvent == nil || m.clipboardEvent.ID != sorted[0].ID {
+		t.Errorf("expected %s copied, got %v", sorted[0].Description, m.clipboardEvent)
+	}
+
+	// A remind -n result has no file, so it's found by description
+	found := remind.Event{Date: day, Description: "Call mom"}
+	found.ID = remind.EventID(found)
+	if got := m.untimedIndexOf(found); got != 0 {
+		t.Errorf("expected Call mom at 0, got %d", got)
+	}
+}
+
+func TestAgendaSnapshot(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(hour int) *time.Time {
+		tm := time.Date(2025, 8, 25, hour, 0, 0, 0, time.Local)
+		return &tm
+	}
+	hour := time.Hour
+
+	m := &Model{
+		config:           &config.Config{},
+		styles:           defaultStyles(),
+		selectedDate:     day,
+		presentationMode: true,
+		events: []remind.Event{
+			{ID: "evt-1", Date: day, Time: at(9), Duration: &hour, Description: "Standup"},
+			{ID: "evt-2", Date: day, Time: at(14), Description: "Interview", Tags: []string{"PRIVATE"}},
+			{ID: "evt-3", Date: day, Description: "Holiday"},
+			{ID: "evt-4", Date: day.AddDate(0, 0, 2), Time: at(10), Description: "Review"},
+		},
+	}
+
+	got := m.agendaSnapshot(day, day.AddDate(0, 0, 2))
+	want := `Agenda for Mon Aug 25 - Wed Aug 27, 2025
+
+Mon Aug 25
+  09:00-10:00  Standup
+  14:00        Busy
+  all day      Holiday
+
+Tue Aug 26
+  nothing scheduled
+
+Wed Aug 27
+  10:00        Review
+`
+	if got != want {
+		t.Errorf("agendaSnapshot =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 8, 25, 10, 15, 0, 0, time.Local)
+	day := func(d int) time.Time { return time.Date(2025, 8, 25+d, 0, 0, 0, 0, time.Local) }
+	at := func(d, hour, minute int) *time.Time {
+		t := time.Date(2025, 8, 25+d, hour, minute, 0, 0, time.Local)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		event    remind.Event
+		expected string
+	}{
+		{"later today", remind.Event{Date: day(0), Time: at(0, 12, 30)}, "in 2h 15m"},
+		{"starting now", remind.Event{Date: day(0), Time: at(0, 10, 15)}, "now"},
+		{"in progress", remind.Event{Date: day(0), Time: at(0, 10, 0), Duration: durationPtr(60)}, "ends in 45m"},
+		{"earlier today", remind.Event{Date: day(0), Time: at(0, 9, 0)}, "1h 15m ago"},
+		{"tomorrow morning", remind.Event{Date: day(1), Time: at(1, 9, 0)}, "in 22h 45m"},
+		{"days away", remind.Event{Date: day(3), Time: at(3, 9, 0)}, "in 3 days"},
+		{"days ago", remind.Event{Date: day(-3), Time: at(-3, 11, 0)}, "3 days ago"},
+		{"untimed today", remind.Event{Date: day(0)}, "today"},
+		{"untimed tomorrow", remind.Event{Date: day(1)}, "tomorrow"},
+		{"untimed yesterday", remind.Event{Date: day(-1)}, "yesterday"},
+	}
+	for _, tt := range tests {
+		if got := relativeTime(now, tt.event); got != tt.expected {
+			t.Errorf("%s: relativeTime = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
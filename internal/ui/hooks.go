@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// Hooks that can be set in urdrc
+const (
+	HookEventAdded   = "on_event_added"
+	HookEventRemoved = "on_event_removed"
+	HookEventEdited  = "on_event_edited"
+	HookStartup      = "on_startup"
+	HookRefresh      = "on_refresh"
+	HookAlarm        = "on_alarm"
+)
+
+// hookEvent is the event passed to hooks as JSON on stdin
+type hookEvent struct {
+	Hook        string   `json:"hook"`
+	ID          string   `json:"id,omitempty"`
+	Date        string   `json:"date,omitempty"`     // YYYY-MM-DD
+	Time        string   `json:"time,omitempty"`     // HH:MM, empty when untimed
+	Duration    int      `json:"duration,omitempty"` // minutes
+	Description string   `json:"description,omitempty"`
+	Body        string   `json:"body,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	File        string   `json:"file,omitempty"`
+	Line        int      `json:"line,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+type hookFinishedMsg struct {
+	hook string
+	err  error
+}
+
+func newHookEvent(hook string, event *remind.Event) hookEvent {
+	h := hookEvent{Hook: hook}
+	if event == nil {
+		return h
+	}
+
+	h.ID = event.ID
+	if !event.Date.IsZero() {
+		h.Date = event.Date.Format("2006-01-02")
+	}
+	if event.Time != nil {
+		h.Time = event.Time.Format("15:04")
+	}
+	if event.Duration != nil {
+		h.Duration = int(event.Duration.Minutes())
+	}
+	h.Description = event.Description
+	h.Body = event.Body
+	h.Priority = int(event.Priority)
+	h.Tags = event.Tags
+	h.File = event.Filename
+	h.Line = event.LineNumber
+	h.Source = event.Source
+	return h
+}
+
+// env returns the event as URD_* environment variables
+func (h hookEvent) env() []string {
+	env := []string{"URD_HOOK=" + h.Hook}
+	add := func(name, value string) {
+		if value != "" {
+			env = append(env, "URD_EVENT_"+name+"="+value)
+		}
+	}
+	add("ID", h.ID)
+	add("DATE", h.Date)
+	add("TIME", h.Time)
+	if h.Duration > 0 {
+		add("DURATION", strconv.Itoa(h.Duration))
+	}
+	add("DESCRIPTION", h.Description)
+	add("PRIORITY", strconv.Itoa(h.Priority))
+	add("TAGS", strings.Join(h.Tags, ","))
+	add("FILE", h.File)
+	if h.Line > 0 {
+		add("LINE", strconv.Itoa(h.Line))
+	}
+	add("SOURCE", h.Source)
+	return env
+}
+
+// runHook queues the command configured for hook, if any. Queued hooks are
+// started when the current update finishes.
+func (m *Model) runHook(hook string, event *remind.Event) {
+	if cmd := m.hookCmd(hook, event); cmd != nil {
+		m.pendingHooks = append(m.pendingHooks, cmd)
+	}
+}
+
+// flushHooks returns the queued hooks as a single command
+func (m *Model) flushHooks() tea.Cmd {
+	if len(m.pendingHooks) == 0 {
+		return nil
+	}
+	cmd := tea.Batch(m.pendingHooks...)
+	m.pendingHooks = nil
+	return cmd
+}
+
+// hookCmd runs the shell command configured for hook with the event in its
+// environment and as JSON on stdin
+func (m *Model) hookCmd(hook string, event *remind.Event) tea.Cmd {
+	command := m.config.Hooks[hook]
+	if command == "" || m.config.ReadOnly {
+		return nil
+	}
+
+	h := newHookEvent(hook, event)
+	return func() tea.Msg {
+		payload, err := json.Marshal(h)
+		if err != nil {
+			return hookFinishedMsg{hook: hook, err: err}
+		}
+
+		cmd := shellCommand(command)
+		cmd.Env = append(os.Environ(), h.env()...)
+		cmd.Stdin = bytes.NewReader(payload)
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(string(output)); msg != "" {
+				err = fmt.Errorf("%w: %s", err, msg)
+			}
+		}
+		return hookFinishedMsg{hook: hook, err: err}
+	}
+}
+
+// eventAtLine returns the loaded event defined at file:line, falling back
+// to an event with just its location when it isn't in the loaded range
+func (m *Model) eventAtLine(file string, line int) remind.Event {
+	for _, event := range m.events {
+		if event.LineNumber == line && filepath.Clean(event.Filename) == filepath.Clean(file) {
+			return event
+		}
+	}
+	return remind.Event{Filename: file, LineNumber: line}
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestHookCmd(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+	env := filepath.Join(dir, "env")
+
+	m := &Model{config: &config.Config{Hooks: map[string]string{
+		HookEventRemoved: `cat > "` + out + `"; echo "$URD_HOOK $URD_EVENT_DATE $URD_EVENT_TIME $URD_EVENT_DESCRIPTION" > "` + env + `"`,
+		HookRefresh:      "echo oops >&2; exit 3",
+	}}}
+
+	start := time.Date(2025, 8, 25, 9, 30, 0, 0, time.Local)
+	hour := time.Hour
+	event := remind.Event{
+		ID:          "evt-1",
+		Date:        start,
+		Time:        &start,
+		Duration:    &hour,
+		Description: "Standup",
+		Filename:    "/tmp/main.rem",
+		LineNumber:  12,
+	}
+
+	// Hooks are queued until the update finishes
+	m.runHook(HookEventRemoved, &event)
+	m.runHook(HookEventAdded, &event) // not configured
+	if len(m.pendingHooks) != 1 {
+		t.Fatalf("expected 1 queued hook, got %d", len(m.pendingHooks))
+	}
+	m.pendingHooks = nil
+
+	msg := m.hookCmd(HookEventRemoved, &event)()
+	if finished := msg.(hookFinishedMsg); finished.err != nil {
+		t.Fatalf("hook failed: %v", finished.err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook didn't receive stdin: %v", err)
+	}
+	var got hookEvent
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", data, err)
+	}
+	if got.Hook != HookEventRemoved || got.Date != "2025-08-25" || got.Time != "09:30" ||
+		got.Duration != 60 || got.Description != "Standup" || got.Line != 12 {
+		t.Errorf("unexpected hook event: %+v", got)
+	}
+
+	envData, _ := os.ReadFile(env)
+	if want := "on_event_removed 2025-08-25 09:30 Standup"; strings.TrimSpace(string(envData)) != want {
+		t.Errorf("hook env = %q, want %q", envData, want)
+	}
+
+	// Failures carry the command's output
+	msg = m.hookCmd(HookRefresh, nil)()
+	if finished := msg.(hookFinishedMsg); finished.err == nil || !strings.Contains(finished.err.Error(), "oops") {
+		t.Errorf("expected failing hook error with output, got %v", finished.err)
+	}
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// noteMark follows the date of days that have a note in the schedule
+const noteMark = "✎"
+
+// noteEditedMsg is sent when the editor opened on a day's note exits
+type noteEditedMsg struct {
+	err error
+}
+
+// notePath returns the note of day in journal_dir, or "" without one
+func (m *Model) notePath(day time.Time) string {
+	if m.config.JournalDir == "" {
+		return ""
+	}
+	return filepath.Join(m.config.JournalDir, day.Format("2006-01-02")+".md")
+}
+
+// hasNote reports whether there's a note for day
+func (m *Model) hasNote(day time.Time) bool {
+	path := m.notePath(day)
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// editNoteCmd opens the note of day with edit_any_command, starting it with
+// the date as its title when there's none yet
+func (m *Model) editNoteCmd(day time.Time) tea.Cmd {
+	if m.config.ReadOnly {
+		return func() tea.Msg { return noteEditedMsg{err: errReplaying} }
+	}
+	path := m.notePath(day)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(m.config.JournalDir, 0755); err != nil {
+			return func() tea.Msg { return noteEditedMsg{err: err} }
+		}
+		title := "# " + day.Format(m.config.DateLayout("Monday, January 2, 2006")) + "\n\n"
+		if err := os.WriteFile(path, []byte(title), 0644); err != nil {
+			return func() tea.Msg { return noteEditedMsg{err: err} }
+		}
+	}
+
+	parts, err := remind.EditorCommand(m.config.EditAnyCommand, path, 0)
+	if err != nil {
+		return func() tea.Msg { return noteEditedMsg{err: err} }
+	}
+
+	return tea.ExecProcess(exec.Command(parts[0], parts[1:]...), func(err error) tea.Msg {
+		return noteEditedMsg{err: err}
+	})
+}
+
+// handleNoteEdited reports when the editor failed on a note
+func (m *Model) handleNoteEdited(msg noteEditedMsg) {
+	if msg.err != nil {
+		m.showMessage(fmt.Sprintf("Editor failed: %v", msg.err))
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestDayNotes(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		config:        &config.Config{JournalDir: dir, EditAnyCommand: "true %file%"},
+		styles:        defaultStyles(),
+		selectedDate:  day,
+		timeIncrement: 60,
+		topSlot:       22,
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2025-08-25.md"), []byte("Notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the day with a note is marked
+	output := lipgloss.NewCanvas(m.createTimeColumnLayers(24, 6)...).Render()
+	if !strings.Contains(output, "Mon Aug 25 "+noteMark) {
+		t.Errorf("Expected the 25th marked as having a note:\n%s", output)
+	}
+	if strings.Contains(output, "Tue Aug 26 "+noteMark) {
+		t.Errorf("Expected the 26th without a note:\n%s", output)
+	}
+
+	// Opening the note of a day without one starts it
+	if cmd := m.editNoteCmd(day.AddDate(0, 0, 1)); cmd == nil {
+		t.Fatal("Expected a command opening the note")
+	}
+	note, err := os.ReadFile(filepath.Join(dir, "2025-08-26.md"))
+	if err != nil {
+		t.Fatalf("Expected the note to be created: %v", err)
+	}
+	if string(note) != "# Tuesday, August 26, 2025\n\n" {
+		t.Errorf("New note = %q", note)
+	}
+
+	// An existing note is left alone
+	m.editNoteCmd(day)
+	if note, _ := os.ReadFile(filepath.Join(dir, "2025-08-25.md")); string(note) != "Notes\n" {
+		t.Errorf("Existing note changed to %q", note)
+	}
+
+	// Without journal_dir there are no notes
+	m.config.JournalDir = ""
+	m.handleHourlyKeys("E", "edit_note")
+	if !strings.Contains(m.message, "journal_dir") {
+		t.Errorf("message = %q, want a hint to set journal_dir", m.message)
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// selectedSlotStart returns when the selected slot starts
+func (m *Model) selectedSlotStart() time.Time {
+	slotsPerDay := m.getSlotsPerDay()
+	hour, minute := m.slotToTime(((m.selectedSlot % slotsPerDay) + slotsPerDay) % slotsPerDay)
+	day := m.selectedDay()
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// adjacentEvent returns the first timed event starting after the selected
+// slot, or the last one starting before it, among those loaded and
+// prefetched
+func (m *Model) adjacentEvent(forward bool) (remind.Event, bool) {
+	start := m.selectedSlotStart()
+	end := start.Add(time.Duration(m.timeIncrement) * time.Minute)
+
+	events := m.wideEvents()
+	var found *remind.Event
+	for i, event := range events {
+		if event.Time == nil {
+			continue
+		}
+		if forward {
+			if !event.Time.Before(end) && (found == nil || event.Time.Before(*found.Time)) {
+				found = &events[i]
+			}
+		} else if event.Time.Before(start) && (found == nil || event.Time.After(*found.Time)) {
+			found = &events[i]
+		}
+	}
+	if found == nil {
+		return remind.Event{}, false
+	}
+	return *found, true
+}
+
+// jumpToAdjacentEvent moves the selection to the next or previous event,
+// across days and past empty slots
+func (m *Model) jumpToAdjacentEvent(forward bool) {
+	event, ok := m.adjacentEvent(forward)
+	if !ok {
+		if forward {
+			m.showMessage("No later event in the weeks loaded")
+		} else {
+			m.showMessage("No earlier event in the weeks loaded")
+		}
+		return
+	}
+	m.jumpToEvent(event)
+}
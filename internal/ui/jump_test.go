@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestJumpToAdjacentEvent(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, 25+d, 0, 0, 0, 0, time.Local) }
+	at := func(d, hour, minute int) *time.Time {
+		t := time.Date(2025, 8, 25+d, hour, minute, 0, 0, time.Local)
+		return &t
+	}
+	events := []remind.Event{
+		{ID: "standup", Date: day(0), Time: at(0, 9, 0), Description: "Standup"},
+		{ID: "review", Date: day(0), Time: at(0, 10, 30), Description: "Design review"},
+		{ID: "todo", Date: day(0), Description: "Pay invoices"},
+		{ID: "dentist", Date: day(2), Time: at(2, 16, 15), Description: "Dentist"},
+	}
+	m := &Model{
+		timeIncrement: 60,
+		selectedDate:  day(0),
+		selectedSlot:  9,
+		config:        &config.Config{},
+		source:        &watchedSource{events: events},
+		events:        events,
+	}
+
+	// Past the other event in the selected slot and the empty afternoons
+	m.handleHourlyKeys(")", "next_event")
+	if m.selectedSlot != 10 || !sameDay(m.selectedDate, day(0)) {
+		t.Fatalf("expected the design review, got slot %d on %v", m.selectedSlot, m.selectedDate)
+	}
+	m.handleHourlyKeys(")", "next_event")
+	if m.selectedSlot != 16 || !sameDay(m.selectedDate, day(2)) {
+		t.Fatalf("expected the dentist two days later, got slot %d on %v", m.selectedSlot, m.selectedDate)
+	}
+	m.handleHourlyKeys(")", "next_event")
+	if m.message != "No later event in the weeks loaded" {
+		t.Errorf("expected no later event, got %q", m.message)
+	}
+
+	m.handleHourlyKeys("(", "prev_event")
+	if m.selectedSlot != 10 || !sameDay(m.selectedDate, day(0)) {
+		t.Errorf("expected back to the design review, got slot %d on %v", m.selectedSlot, m.selectedDate)
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// keyName returns the name key bindings know a key press by
+func keyName(msg tea.KeyPressMsg) string {
+	key := msg.String()
+
+	// Handle special key representations
+	switch key {
+	case "up":
+		key = "<up>"
+	case "down":
+		key = "<down>"
+	case "left":
+		key = "<left>"
+	case "right":
+		key = "<right>"
+	case "enter":
+		key = "<enter>"
+	case "tab":
+		key = "<tab>"
+	case "backspace":
+		key = "<backspace>"
+	case "esc":
+		key = "<esc>"
+	case "pgup":
+		key = "<pageup>"
+	case "pgdown":
+		key = "<pagedown>"
+	case "home":
+		key = "<home>"
+	default:
+		// ctrl+l is bound as \Cl
+		if strings.HasPrefix(key, "ctrl+") && len(key) == len("ctrl+")+1 {
+			key = `\C` + key[len("ctrl+"):]
+		}
+	}
+
+	return key
+}
+
+// bindMode returns the name bind statements use for the current mode, see
+// config.BindModes
+func (m *Model) bindMode() string {
+	switch m.mode {
+	case ViewHourly:
+		if m.focusUntimed {
+			return "untimed"
+		}
+		return "schedule"
+	case ViewHelp:
+		return "help"
+	case ViewEventEditor:
+		return "quick_add"
+	case ViewGotoDate:
+		return "goto"
+	case ViewSearch:
+		return "search"
+	case ViewFuzzyFind:
+		return "find"
+	case ViewEventSelector:
+		return "select"
+	case ViewClipboardSelector:
+		return "clipboard"
+	case ViewURLSelector:
+		return "urls"
+	case ViewUpcoming:
+		return "upcoming"
+	case ViewExecute:
+		return "execute"
+	case ViewJoinPrompt:
+		return "join"
+	case ViewReview:
+		return "review"
+	case ViewPlan:
+		return "plan"
+	case ViewSources:
+		return "sources"
+	case ViewTagFilter:
+		return "tags"
+	case ViewShift:
+		return "shift"
+	case ViewCompare:
+		return "compare"
+	case ViewFormat:
+		return "format"
+	}
+	return ""
+}
+
+// inputAction returns the action for a key in a text input mode. Only
+// bindings scoped to the mode apply, so global bindings can still be typed;
+// otherwise Enter and Esc complete and cancel the entry.
+func (m *Model) inputAction(msg tea.KeyPressMsg) string {
+	if action := m.modeActionForKey(keyName(msg)); action != "" {
+		return action
+	}
+	switch msg.Code {
+	case tea.KeyEnter:
+		return "entry_complete"
+	case tea.KeyEscape:
+		return "entry_cancel"
+	}
+	return ""
+}
+
+// chordTimeoutMsg fires when no further key of a multi-key binding was
+// pressed in time
+type chordTimeoutMsg struct {
+	seq int
+}
+
+// resolveChord adds key to any pending multi-key binding. It returns the
+// action to run, or pending with a timeout command while the keys so far
+// are the start of a longer binding.
+func (m *Model) resolveChord(key string) (action string, pending bool, cmd tea.Cmd) {
+	seq := strings.Join(append(append([]string{}, m.pendingKeys...), key), " ")
+
+	if m.isChordPrefix(seq) {
+		m.pendingKeys = append(m.pendingKeys, key)
+		m.chordSeq++
+		id := m.chordSeq
+		return "", true, tea.Tick(m.config.ChordTimeout, func(time.Time) tea.Msg {
+			return chordTimeoutMsg{seq: id}
+		})
+	}
+
+	hadPending := len(m.pendingKeys) > 0
+	m.pendingKeys = nil
+	if action := m.getActionForKey(seq); action != "" || !hadPending {
+		return action, false, nil
+	}
+
+	// Not a bound sequence, so drop what was pending and take the key on
+	// its own
+	return m.resolveChord(key)
+}
+
+// isChordPrefix reports whether seq is the start of a longer binding
+func (m *Model) isChordPrefix(seq string) bool {
+	for _, bindings := range []map[string]string{m.config.KeyBindings, m.config.ModeKeyBindings[m.bindMode()]} {
+		for binding := range bindings {
+			if strings.HasPrefix(binding, seq+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleChordTimeout runs the binding for the keys pressed so far, if they
+// have one of their own, once the wait for further keys is over
+func (m *Model) handleChordTimeout(msg chordTimeoutMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.chordSeq || len(m.pendingKeys) == 0 {
+		// A later key press already resolved or extended the sequence
+		return m, nil
+	}
+
+	action := m.getActionForKey(strings.Join(m.pendingKeys, " "))
+	m.pendingKeys = nil
+	if action == "" || m.mode != ViewHourly {
+		return m, nil
+	}
+	return m.dispatchKey(tea.KeyPressMsg{}, "", action)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestResolveChord(t *testing.T) {
+	m := &Model{config: &config.Config{
+		KeyBindings: map[string]string{
+			"g":   "goto",
+			"g g": "home",
+			"d d": "cut",
+			"j":   "scroll_down",
+		},
+	}}
+
+	press := func(key string) (string, bool) {
+		action, pending, _ := m.resolveChord(key)
+		return action, pending
+	}
+
+	// Unrelated keys resolve straight away
+	if action, pending := press("j"); pending || action != "scroll_down" {
+		t.Errorf("j: got %q pending=%v", action, pending)
+	}
+
+	// A full sequence resolves on its last key
+	if _, pending := press("d"); !pending {
+		t.Fatal("d should wait for the rest of the sequence")
+	}
+	if action, pending := press("d"); pending || action != "cut" {
+		t.Errorf("dd: got %q pending=%v", action, pending)
+	}
+
+	// A key that breaks the sequence is taken on its own
+	press("d")
+	if action, pending := press("j"); pending || action != "scroll_down" {
+		t.Errorf("dj: got %q pending=%v", action, pending)
+	}
+	if len(m.pendingKeys) != 0 {
+		t.Errorf("pending keys not cleared: %v", m.pendingKeys)
+	}
+
+	// A prefix with its own binding runs it when the wait times out
+	press("g")
+	seq := m.chordSeq
+	m.handleChordTimeout(chordTimeoutMsg{seq: seq - 1})
+	if len(m.pendingKeys) != 1 {
+		t.Fatal("stale timeout should not resolve the pending key")
+	}
+	if action, pending := press("g"); pending || action != "home" {
+		t.Errorf("gg: got %q pending=%v", action, pending)
+	}
+}
+
+func TestModeScopedBindings(t *testing.T) {
+	m := &Model{config: &config.Config{
+		KeyBindings: map[string]string{
+			"d": "cut",
+		},
+		ModeKeyBindings: map[string]map[string]string{
+			"untimed": {"d": "done"},
+			"search":  {`\Cy`: "history_previous"},
+		},
+	}}
+
+	// The schedule gets the global binding, the untimed box its own
+	if action := m.getActionForKey("d"); action != "cut" {
+		t.Errorf("schedule d: got %q, want cut", action)
+	}
+	m.focusUntimed = true
+	if action := m.getActionForKey("d"); action != "done" {
+		t.Errorf("untimed d: got %q, want done", action)
+	}
+
+	// Scoped bindings drive text input actions, other keys are typed
+	m.mode = ViewSearch
+	m.searchInput.SetValue("dentist")
+	m.searchInput.Remember()
+	m.searchInput.Reset()
+
+	m.handleSearchKeys(ctrl('y'))
+	if got := m.searchInput.Value(); got != "dentist" {
+		t.Errorf("ctrl+y in search: got %q, want history recalled", got)
+	}
+	m.searchInput.Reset()
+	m.handleSearchKeys(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if got := m.searchInput.Value(); got != "d" {
+		t.Errorf("global binding should not apply while typing, got %q", got)
+	}
+}
+
+func TestBindModesCoverViewModes(t *testing.T) {
+	m := &Model{config: &config.Config{}}
+	for mode := ViewHourly; mode <= ViewCompare; mode++ {
+		m.mode = mode
+		name := m.bindMode()
+		found := false
+		for _, bindMode := range config.BindModes {
+			if bindMode == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("view mode %d has bind mode %q, not in config.BindModes", mode, name)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// meetingURLRegex matches links to video conferences
+var meetingURLRegex = regexp.MustCompile(`^https?://(` +
+	`([\w-]+\.)?zoom\.us/(j|my|w)/` +
+	`|meet\.google\.com/` +
+	`|teams\.microsoft\.com/l/meetup-join/` +
+	`|teams\.live\.com/meet/` +
+	`|([\w-]+\.)?webex\.com/` +
+	`)`)
+
+// meetingURL returns the first video conference link in an event, if any
+func meetingURL(event remind.Event) string {
+	for _, url := range extractURLs(event.Description + " " + event.Body) {
+		if meetingURLRegex.MatchString(url) {
+			return url
+		}
+	}
+	return ""
+}
+
+// meetingToJoin returns the meeting to join when none is selected: the one
+// in progress that started most recently, else the next one today
+func (m *Model) meetingToJoin(now time.Time) (remind.Event, bool) {
+	var best remind.Event
+	found := false
+	for _, event := range m.eventsOn(now) {
+		if event.Time == nil || !sameDay(*event.Time, now) || meetingURL(event) == "" {
+			continue
+		}
+
+		end := *event.Time
+		if event.Duration != nil {
+			end = end.Add(*event.Duration)
+		}
+		if end.Before(now) {
+			continue
+		}
+
+		if !found {
+			best, found = event, true
+			continue
+		}
+		// Prefer meetings already under way, the latest to start first
+		started, bestStarted := !event.Time.After(now), !best.Time.After(now)
+		switch {
+		case started && bestStarted:
+			if event.Time.After(*best.Time) {
+				best = event
+			}
+		case started != bestStarted:
+			if started {
+				best = event
+			}
+		default:
+			if event.Time.Before(*best.Time) {
+				best = event
+			}
+		}
+	}
+	return best, found
+}
+
+// dueMeeting returns a meeting starting within before from now that hasn't
+// been offered yet
+func (m *Model) dueMeeting(now time.Time, before time.Duration) (remind.Event, bool) {
+	for _, event := range m.events {
+		if event.Time == nil || meetingURL(event) == "" || m.promptedMeetings[meetingKey(event)] {
+			continue
+		}
+		if untilStart := event.Time.Sub(now); untilStart >= 0 && untilStart <= before {
+			return event, true
+		}
+	}
+	return remind.Event{}, false
+}
+
+// meetingKey identifies one occurrence of a meeting
+func meetingKey(event remind.Event) string {
+	return fmt.Sprintf("%s@%s", event.ID, event.Time.Format(time.RFC3339))
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestMeetingURL(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Standup https://example.zoom.us/j/123456?pwd=abc", "https://example.zoom.us/j/123456?pwd=abc"},
+		{"Sync https://meet.google.com/abc-defg-hij.", "https://meet.google.com/abc-defg-hij"},
+		{"Review https://teams.microsoft.com/l/meetup-join/19%3ameeting", "https://teams.microsoft.com/l/meetup-join/19%3ameeting"},
+		{"Docs https://example.com/doc then https://zoom.us/j/42", "https://zoom.us/j/42"},
+		{"Lunch https://example.com/menu", ""},
+		{"Nothing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := meetingURL(remind.Event{Description: tt.text}); got != tt.want {
+			t.Errorf("meetingURL(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMeetingPrompts(t *testing.T) {
+	at := func(hour, minute int) *time.Time {
+		tm := time.Date(2025, 8, 25, hour, minute, 0, 0, time.Local)
+		return &tm
+	}
+	half := 30 * time.Minute
+
+	m := &Model{
+		config: &config.Config{JoinPrompt: 2 * time.Minute},
+		mode:   ViewHourly,
+		events: []remind.Event{
+			{ID: "evt-1", Date: *at(0, 0), Time: at(9, 0), Duration: &half, Description: "Standup https://zoom.us/j/1"},
+			{ID: "evt-2", Date: *at(0, 0), Time: at(10, 0), Description: "Lunch"},
+			{ID: "evt-3", Date: *at(0, 0), Time: at(11, 0), Duration: &half, Description: "Sync https://meet.google.com/xyz"},
+		},
+	}
+
+	// In progress meetings win, then the next one
+	if event, ok := m.meetingToJoin(*at(9, 10)); !ok || event.ID != "evt-1" {
+		t.Errorf("at 9:10 expected evt-1, got %v %v", event.ID, ok)
+	}
+	if event, ok := m.meetingToJoin(*at(9, 45)); !ok || event.ID != "evt-3" {
+		t.Errorf("at 9:45 expected evt-3, got %v %v", event.ID, ok)
+	}
+	if _, ok := m.meetingToJoin(*at(12, 0)); ok {
+		t.Error("expected no meeting left to join at 12:00")
+	}
+
+	// Not due yet
+	m.promptDueMeeting(*at(10, 55))
+	if m.mode != ViewHourly {
+		t.Fatal("prompted too early")
+	}
+
+	m.promptDueMeeting(*at(10, 58))
+	if m.mode != ViewJoinPrompt || m.joinEvent.ID != "evt-3" {
+		t.Fatalf("expected prompt for evt-3, mode=%d event=%s", m.mode, m.joinEvent.ID)
+	}
+
+	// Each meeting is only offered once
+	m.mode = ViewHourly
+	m.promptDueMeeting(*at(10, 59))
+	if m.mode != ViewHourly {
+		t.Error("meeting offered twice")
+	}
+}
+
+func TestJoinPromptHidesPrivateLink(t *testing.T) {
+	at := time.Date(2025, 8, 25, 9, 0, 0, 0, time.Local)
+	m := &Model{
+		config:    &config.Config{},
+		styles:    defaultStyles(),
+		joinEvent: remind.Event{Date: at, Time: &at, Description: "Interview https://zoom.us/j/42", Tags: []string{remind.PrivateTag}},
+	}
+
+	if view := m.viewJoinPrompt(); !strings.Contains(view, "https://zoom.us/j/42") {
+		t.Errorf("expected the link shown outside presentation mode:\n%s", view)
+	}
+	m.presentationMode = true
+	if view := m.viewJoinPrompt(); strings.Contains(view, "zoom.us") || strings.Contains(view, "Interview") {
+		t.Errorf("expected the private meeting redacted in presentation mode:\n%s", view)
+	}
+}
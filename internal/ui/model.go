@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -16,6 +15,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type ViewMode int
@@ -29,8 +29,22 @@ const (
 	ViewSearch            // For entering search terms
 	ViewClipboardSelector // For choosing which event to cut/copy
 	ViewURLSelector       // For choosing which URL to open
+	ViewUpcoming          // For listing the next upcoming events
+	ViewFuzzyFind         // For fuzzy finding an event to jump to
+	ViewExecute           // For running a command embedded in an event
+	ViewJoinPrompt        // For offering to join a meeting that is about to start
+	ViewReview            // For the daily review of yesterday's items and today's plan
+	ViewPlan              // For previewing where estimated tasks would be scheduled
+	ViewSources           // For showing and hiding sources like p2 profiles
+	ViewTagFilter         // For choosing which tag of the selected event to filter by
+	ViewShift             // For shifting the events of a day by some time
+	ViewCompare           // For comparing the events of two days side by side
+	ViewFormat            // For previewing the changes formatting a remind file makes
 )
 
+// upcomingCount is how many events the upcoming list shows
+const upcomingCount = 10
+
 type Model struct {
 	// Core components
 	config       *config.Config
@@ -42,25 +56,88 @@ type Model struct {
 	mode            ViewMode
 	selectedDate    time.Time
 	events          []remind.Event
-	eventsLoadedFor time.Time // Track when we last loaded events
+	specials        []remind.Event                // Calendar annotations (MOON, SHADE, WEEK)
+	eventsLoadedFor time.Time                     // Track when we last loaded events
+	lastRefresh     time.Time                     // When events were last loaded, shown in the status bar
+	fileChanges     <-chan remind.FileChangeEvent // Changes the source reports, see waitForFileChange
+	eventIndex      dayIndex                      // Events by date, rebuilt when they change
+	eventsRevision  int                           // Counts the times events were loaded
+	sidebar         sidebarCache                  // The sidebar last rendered, see createSidebarLayer
 
 	// Hourly view state
-	selectedSlot  int // Selected time slot index (can span multiple days)
-	timeIncrement int // Minutes per slot (15, 30, or 60)
-	topSlot       int // First visible slot in the schedule
+	selectedSlot  int  // Selected time slot index (can span multiple days)
+	timeIncrement int  // Minutes per slot (15, 30, or 60)
+	topSlot       int  // First visible slot in the schedule
+	openPending   bool // Scroll to initial_time once the screen size is known
+
+	// Span of slots new events cover, see selectedSpan
+	spanStart  time.Time
+	spanLength time.Duration
+	visual     bool // j and k extend the span, see startVisual
+
+	// Events of the coming months by first day, loaded at idle with
+	// prefetch_months, see handlePrefetch
+	horizon     map[time.Time][]remind.Event
+	prefetching bool // a prefetchMsg is pending, or a month is being fetched
+	horizonSeq  int  // Changed when the horizon is dropped, see prefetchedMsg
+
+	// The last action acting on the selection, which repeat runs again
+	lastAction    string
+	lastActionKey string
+
+	// Current time, shared with the remind client. Tests fix it.
+	clock remind.Clock
 
 	// UI state
 	width        int
 	height       int
 	helpVisible  bool
 	message      string
-	messageTimer *time.Timer
+	messageSeq   int // Counts the messages shown, so a timeout only clears its own
 	showEventIDs bool
 
+	// Presentation mode hides the details of PRIVATE events
+	presentationMode bool
+
+	// remind wasn't found, so reminders come from the built-in evaluator
+	remindMissing bool
+
+	// Show the whole selected day as a list instead of the selected slot
+	showAgenda bool
+
+	// Color events by their tag and add up the hours per tag
+	timeAudit bool
+
+	// Show the selected events in full over the schedule until the next key,
+	// or the REM lines they come from
+	peeking     bool
+	peekRemLine bool
+
+	// How busy each hour usually is, for the time gutter
+	heat gutterHeat
+
+	// Collapse runs of empty slots into a row each, as laid out by the last
+	// render
+	compact   bool
+	collapsed compactRows
+
+	// First event column shown when not all of them fit, and the most it
+	// can be as of the last render
+	columnOffset    int
+	maxColumnOffset int
+
 	// Editor state
 	editingEvent *remind.Event
-	inputBuffer  string
-	cursorPos    int
+
+	// Text inputs, each keeping its own history
+	quickAddInput textInput
+	gotoInput     textInput
+	searchInput   textInput
+	fuzzyInput    textInput
+
+	// Fuzzy finder state
+	fuzzyMatches       []remind.Event // loaded events matching fuzzyInput
+	selectedFuzzyIndex int            // index of selected match
 
 	// Event selection state
 	eventChoices       []remind.Event
@@ -70,10 +147,20 @@ type Model struct {
 	clipboardEvent     *remind.Event
 	clipboardCut       bool   // true if event was cut (should be removed on paste)
 	clipboardOperation string // "cut" or "copy" - which operation is pending
+	clipboardFile      string // Where the clipboard is kept across restarts, empty to not keep it
+
+	// Pinned events, listed in the sidebar whatever the date
+	pinned       []remind.Event
+	pinsRevision int    // Changed with the pinned events, for the sidebar cache
+	pinsFile     string // Where the pinned events are kept, empty to not keep them
 
 	// Untimed reminders state
-	focusUntimed         bool // true when focused on untimed reminders box
-	selectedUntimedIndex int  // index of selected untimed reminder
+	focusUntimed         bool            // true when focused on untimed reminders box
+	selectedUntimedIndex int             // index of selected untimed reminder
+	untimedScroll        map[string]int  // First untimed reminder listed, by day, see untimedOffset
+	dueGroups            bool            // Untimed reminders of every day around today, grouped by due date
+	dueLoaded            []remind.Event  // The events grouped by due date, see loadDue
+	collapsedDue         map[string]bool // Due date sections listing only their count
 
 	// Search state
 	searchTerm       string         // current search term
@@ -85,11 +172,85 @@ type Model struct {
 	urlChoices       []string // URLs to choose from
 	selectedURLIndex int      // index of selected URL
 
+	// Upcoming events state
+	upcomingEvents        []remind.Event // next events from now on
+	selectedUpcomingIndex int            // index of selected upcoming event
+
+	// Keys typed so far of a multi-key binding
+	pendingKeys []string
+	chordSeq    int // identifies the latest pending key for its timeout
+
+	// Execute state
+	execCommand string   // command embedded in the selected event
+	execPhase   int      // execConfirm, execRunning or execDone
+	execOutput  []string // output lines of the finished command
+	execErr     error    // error from the finished command
+	execScroll  int      // first output line shown
+
+	// Meeting join prompt state
+	joinEvent        remind.Event    // meeting being offered
+	promptedMeetings map[string]bool // meetings already offered, by meetingKey
+	rungAlarms       map[string]bool // events the alarm rang for, by meetingKey
+	blurred          bool            // the terminal reported losing focus
+
+	// Daily review state
+	reviewPhase        int            // reviewItems or reviewPlan
+	reviewDay          time.Time      // the day being planned
+	reviewItems        []remind.Event // yesterday's untimed items still to review
+	reviewAgenda       []remind.Event // the day's reminders
+	reviewTodos        []remind.Event // the day's unplanned high-priority todos
+	selectedReviewTodo int            // index in reviewTodos
+	reviewOpenings     []time.Time    // free times a todo can be scheduled at
+	selectedOpening    int            // index in reviewOpenings
+
+	// Source toggle state
+	selectedSourceIndex int // index in the source names
+
+	// Tag filter state
+	tagFilter        string   // only events with this tag are shown, when set
+	tagChoices       []string // tags of the selected event to filter by
+	selectedTagIndex int      // index in tagChoices
+
+	// Task planner state
+	planPlacements []placement    // proposed times for estimated tasks
+	planUnplaced   []remind.Event // estimated tasks that don't fit this week
+
+	// Shift dialog state
+	shiftInput textInput
+	shiftDay   time.Time   // the day whose events are shifted
+	shiftFrom  *time.Time  // only timed events from then on are shifted, when set
+	lastShift  []shiftMove // how to move the last shifted events back
+
+	// Day comparison state
+	compareDays   [2]time.Time      // the days compared side by side
+	compareEvents [2][]remind.Event // the events of each compared day
+
+	// Remind file formatting state
+	formatFile   string   // the remind file formatted
+	formatBefore string   // its content, as read
+	formatAfter  string   // its content formatted
+	formatDiff   []string // the changes, as a unified diff
+	formatScroll int      // first diff line shown
+
+	// Focus session state
+	focusActive bool         // a focus session is counting down
+	focusEvent  remind.Event // event being focused on
+	focusStart  time.Time    // when the session started
+	focusEnd    time.Time    // when the session is done
+	focusSeq    int          // identifies the latest session for its ticks
+
+	// Hooks
+	editingNew   bool      // the editor is open on a newly added reminder
+	pendingHooks []tea.Cmd // hooks to start once the current update is done
+
 	// Activity tracking
 	lastKeyInput time.Time // last time a key was pressed
 
 	// Error state
-	syntaxError error // Persistent syntax error from remind files
+	loadError   error // Persistent error loading events, a syntax error in the remind files or what keeps them from loading
+	configError error // Why the edited urdrc couldn't be reloaded
+	editError   error // What remind reported after the last edit, until fixed
+	editedFile  string
 
 	// Styles
 	styles Styles
@@ -106,12 +267,25 @@ type Styles struct {
 	Help     lipgloss.Style
 	Message  lipgloss.Style
 	Border   lipgloss.Style
+
+	// Schedule day separators for today and weekends, and the background
+	// of weekend rows with shade_weekends and of the rows outside working
+	// hours with shade_off_hours
+	TodaySeparator   lipgloss.Style
+	WeekendSeparator lipgloss.Style
+	WeekendShade     lipgloss.Style
+	OffHoursShade    lipgloss.Style
 }
 
 func NewModelWithRemind(cfg *config.Config, source remind.ReminderSource, remindClient *remind.Client) *Model {
-	now := time.Now()
+	clock := remind.SystemClock
+	if remindClient != nil && remindClient.Clock != nil {
+		clock = remindClient.Clock
+	}
+	now := clock.Now()
 
 	m := &Model{
+		clock:         clock,
 		config:        cfg,
 		source:        source,
 		remindClient:  remindClient,
@@ -122,23 +296,34 @@ func NewModelWithRemind(cfg *config.Config, source remind.ReminderSource, remind
 		selectedSlot:  now.Hour()*2 + now.Minute()/30, // Default 30-min slots (can't use timeToSlot yet as timeIncrement not set)
 		timeIncrement: 30,                             // Default to 30-minute slots
 		topSlot:       0,
+		openPending:   true,
 		lastKeyInput:  now, // Initialize to current time
-		styles:        DefaultStyles(),
+		styles:        stylesFor(cfg),
+
+		presentationMode: cfg.PresentationMode,
+		compact:          cfg.Compact,
+		remindMissing:    remindClient != nil && remindClient.Degraded(),
+	}
+
+	// Set up file watcher using the source's watch capability. Changes are
+	// received by waitForFileChange. Files that can't be watched are
+	// reported as warnings by the first load.
+	if watchChan, err := source.WatchFiles(); err != nil {
+		m.showError("Not watching files for changes", err)
+	} else {
+		m.fileChanges = watchChan
 	}
 
 	// Load initial events for hourly view
 	m.loadEventsForSchedule()
 
-	// Set up file watcher using the source's watch capability
-	if watchChan, err := source.WatchFiles(); err == nil && watchChan != nil {
-		// Start a goroutine to handle file change events
-		go func() {
-			for range watchChan {
-				// Trigger refresh when files change
-				m.loadEvents()
-			}
-		}()
-	}
+	// The clipboard survives restarts, so a cut event isn't lost
+	m.clipboardFile = config.StatePath("clipboard.json")
+	m.restoreClipboard()
+
+	// Pinned events stay until unpinned
+	m.pinsFile = config.StatePath("pinned.json")
+	m.restorePins()
 
 	return m
 }
@@ -174,47 +359,130 @@ func DefaultStyles() Styles {
 		Border: lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("238")),
+		TodaySeparator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("235")).
+			Background(lipgloss.Color("220")).
+			Bold(true),
+		WeekendSeparator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")).
+			Bold(true).
+			Underline(true),
+		WeekendShade: lipgloss.NewStyle().
+			Background(lipgloss.Color("235")),
+		OffHoursShade: lipgloss.NewStyle().
+			Background(lipgloss.Color("234")),
 	}
 }
 
 func (m *Model) Init() tea.Cmd {
+	var clearMessage tea.Cmd
+	if m.message != "" {
+		// Shown while loading the first events
+		clearMessage = m.clearMessageCmd()
+	}
 	return tea.Batch(
 		tea.EnterAltScreen,
 		m.tickCmd(),
 		m.timeUpdateCmd(),
+		m.waitForFileChange(),
+		m.hookCmd(HookStartup, nil),
+		m.prefetchCmd(),
+		clearMessage,
 	)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	shown := m.messageSeq
+	model, cmd := m.update(msg)
+	// Clear the message the update showed after a while
+	if m.messageSeq != shown {
+		cmd = tea.Batch(cmd, m.clearMessageCmd())
+	}
+	// Start any hooks the update triggered
+	if hooks := m.flushHooks(); hooks != nil {
+		return model, tea.Batch(cmd, hooks)
+	}
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.openPending {
+			m.openPending = false
+			m.openSchedule(m.now())
+		}
 		return m, nil
 
 	case tea.KeyPressMsg:
-		m.lastKeyInput = time.Now()
+		m.lastKeyInput = m.now()
 		return m.handleKeyPress(msg)
 
 	case tickMsg:
 		// Refresh display periodically
 		if m.config.AutoRefresh {
 			m.loadEvents()
+			m.runHook(HookRefresh, nil)
 			return m, m.tickCmd()
 		}
 		return m, nil
 
+	case fileChangedMsg:
+		// Reload here rather than in the watcher's goroutine, which would
+		// race with Update and View
+		if msg.Err != nil {
+			m.showMessage(fmt.Sprintf("File watcher: %v, changes may be missed", msg.Err))
+		}
+		m.loadEvents()
+		m.recheckEdited()
+		return m, tea.Batch(m.waitForFileChange(), m.invalidateHorizon())
+
+	case prefetchMsg:
+		return m, m.handlePrefetch()
+
+	case prefetchedMsg:
+		return m, m.handlePrefetched(msg)
+
 	case timeUpdateMsg:
 		// Update current time display every minute and handle auto-advance
 		m.handleInactivityAutoAdvance()
-		return m, m.timeUpdateCmd()
+		m.promptDueMeeting(m.now())
+		return m, tea.Batch(m.timeUpdateCmd(), m.ringAlarms(m.now()))
+
+	case tea.FocusMsg:
+		// Ring for what came due while away
+		m.blurred = false
+		return m, m.ringAlarms(m.now())
+
+	case tea.BlurMsg:
+		m.blurred = true
+		return m, nil
 
 	case eventLoadedMsg:
-		m.events = msg.events
+		m.setEvents(msg.events)
 		return m, nil
 
+	case chordTimeoutMsg:
+		return m.handleChordTimeout(msg)
+
+	case focusTickMsg:
+		return m, m.handleFocusTick(msg, m.now())
+
 	case messageTimeoutMsg:
-		m.message = ""
+		// Messages shown since stay for their own time
+		if msg.seq == m.messageSeq {
+			m.message = ""
+		}
+		return m, nil
+
+	case configEditedMsg:
+		m.handleConfigEdited(msg)
+		return m, nil
+
+	case noteEditedMsg:
+		m.handleNoteEdited(msg)
 		return m, nil
 
 	case editorFinishedMsg:
@@ -225,6 +493,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Reload events after editing
 		m.loadEvents()
+		if msg.err == nil {
+			m.checkEdited(msg.file)
+		}
+
+		added := m.editingNew
+		m.editingNew = false
+		if msg.err == nil && msg.file != "" {
+			event := m.eventAtLine(msg.file, msg.line)
+			if added {
+				m.runHook(HookEventAdded, &event)
+			} else {
+				m.runHook(HookEventEdited, &event)
+			}
+		}
+		return m, nil
+
+	case commandFinishedMsg:
+		if m.mode != ViewExecute || m.execPhase != execRunning {
+			// The pane was closed while the command ran
+			if msg.err != nil {
+				m.showMessage(fmt.Sprintf("Command failed: %v", msg.err))
+			} else {
+				m.showMessage("Command finished")
+			}
+			return m, nil
+		}
+		m.execPhase = execDone
+		m.execOutput = execOutputLines(msg.output)
+		m.execErr = msg.err
+		m.execScroll = 0
+		return m, nil
+
+	case hookFinishedMsg:
+		if msg.err != nil {
+			m.showMessage(fmt.Sprintf("Hook %s failed: %v", msg.hook, msg.err))
+		}
 		return m, nil
 	}
 
@@ -238,6 +542,9 @@ func (m *Model) View() string {
 
 	switch m.mode {
 	case ViewHourly:
+		if m.config.Accessible {
+			return m.renderAccessibleView()
+		}
 		return m.renderCanvasView()
 	case ViewHelp:
 		return m.viewHelp()
@@ -253,56 +560,78 @@ func (m *Model) View() string {
 		return m.viewClipboardSelector()
 	case ViewURLSelector:
 		return m.viewURLSelector()
+	case ViewUpcoming:
+		return m.viewUpcoming()
+	case ViewFuzzyFind:
+		return m.viewFuzzyFind()
+	case ViewExecute:
+		return m.viewExecute()
+	case ViewJoinPrompt:
+		return m.viewJoinPrompt()
+	case ViewReview:
+		return m.viewReview()
+	case ViewPlan:
+		return m.viewPlan()
+	case ViewSources:
+		return m.viewSources()
+	case ViewTagFilter:
+		return m.viewTagFilter()
+	case ViewShift:
+		return m.viewShift()
+	case ViewCompare:
+		return m.viewCompare()
+	case ViewFormat:
+		return m.viewFormat()
 	default:
 		panic("unhandled mode")
 	}
 }
 
+// renderPlain renders the screen at the given size as plain text, without
+// colors or other escape sequences. With a fixed clock the output only
+// depends on the model, so it can be compared with snapshots.
+func (m *Model) renderPlain(width, height int) string {
+	m.width, m.height = width, height
+	return ansi.Strip(m.View())
+}
+
+// now returns the current time, or the time the clock is frozen at
+func (m *Model) now() time.Time {
+	if m.clock != nil {
+		return m.clock.Now()
+	}
+	return time.Now()
+}
+
 func (m *Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	// Check configured key bindings
-	key := msg.String()
+	key := keyName(msg)
 
-	// Handle special key representations
-	switch key {
-	case "up":
-		key = "<up>"
-	case "down":
-		key = "<down>"
-	case "left":
-		key = "<left>"
-	case "right":
-		key = "<right>"
-	case "enter":
-		key = "<enter>"
-	case "tab":
-		key = "<tab>"
-	case "backspace":
-		key = "<backspace>"
-	case "esc":
-		key = "<esc>"
-	case "pgup":
-		key = "<pageup>"
-	case "pgdown":
-		key = "<pagedown>"
-	case "home":
-		key = "<home>"
-	case "ctrl+l":
-		key = "\\Cl"
-	case "ctrl+b":
-		key = "\\Cb"
+	// Look up the action for this key. The schedule also accepts multi-key
+	// bindings, so the key may just be the start of one.
+	var action string
+	if m.mode == ViewHourly {
+		var pending bool
+		var cmd tea.Cmd
+		if action, pending, cmd = m.resolveChord(key); pending {
+			return m, cmd
+		}
+	} else {
+		action = m.getActionForKey(key)
 	}
 
-	// Look up the action for this key
-	action := m.getActionForKey(key)
+	return m.dispatchKey(msg, key, action)
+}
 
-	// If there's a configured action for this key, handle it
-	if action != "" {
+// dispatchKey handles a key press once its bound action, if any, is known
+func (m *Model) dispatchKey(msg tea.KeyPressMsg, key, action string) (tea.Model, tea.Cmd) {
+	// If there's a configured action for this key, handle it. Text inputs
+	// get the key instead so e.g. "?" and "Q" can be typed.
+	if action != "" && !m.inTextInput() {
 		// Global keys that work in all modes
 		switch action {
 		case "quit":
-			if m.mode != ViewEventEditor {
-				return m, tea.Quit
-			}
+			return m, tea.Quit
 		case "help":
 			if m.mode == ViewHelp {
 				m.mode = ViewHourly
@@ -311,22 +640,27 @@ func (m *Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "refresh":
+			// Fetch cached sources like p2 anew too
+			if cached, ok := m.source.(remind.CachedSource); ok {
+				cached.Refresh()
+			}
 			m.loadEvents()
-			now := time.Now()
+			m.runHook(HookRefresh, nil)
+			now := m.now()
 			currentTimeSlot := m.getCurrentTimeSlot()
 			m.showMessage(fmt.Sprintf("Refreshed - Now: %02d:%02d, slot=%d, selected=%d", now.Hour(), now.Minute(), currentTimeSlot, m.selectedSlot))
-			return m, nil
+			return m, m.invalidateHorizon()
 		}
-	} else {
+	} else if action == "" {
 		// No configured binding - check for hard-coded keys
 		switch key {
-		case "ctrl+c":
+		case "\\Cc":
 			if m.mode != ViewEventEditor {
 				return m, tea.Quit
 			}
 		case "i":
 			// Toggle showing event IDs (only if not in input modes)
-			if m.mode != ViewEventEditor && m.mode != ViewSearch && m.mode != ViewGotoDate {
+			if !m.inTextInput() {
 				m.showEventIDs = !m.showEventIDs
 				if m.showEventIDs {
 					m.showMessage("Showing event IDs")
@@ -350,7 +684,7 @@ func (m *Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		// Ignore all other keys in help mode
 		return m, nil
 	case ViewHourly:
-		return m.handleHourlyKeys(msg)
+		return m.handleRepeatable(key, action)
 	case ViewEventEditor:
 		return m.handleEditorKeys(msg)
 	case ViewEventSelector:
@@ -363,21 +697,100 @@ func (m *Model) handleKeyPress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleClipboardSelectorKeys(msg)
 	case ViewURLSelector:
 		return m.handleURLSelectorKeys(msg)
+	case ViewUpcoming:
+		return m.handleUpcomingKeys(msg)
+	case ViewFuzzyFind:
+		return m.handleFuzzyFindKeys(msg)
+	case ViewExecute:
+		return m.handleExecuteKeys(msg)
+	case ViewJoinPrompt:
+		return m.handleJoinPromptKeys(msg)
+	case ViewReview:
+		return m.handleReviewKeys(msg)
+	case ViewPlan:
+		return m.handlePlanKeys(msg)
+	case ViewSources:
+		return m.handleSourcesKeys(msg)
+	case ViewTagFilter:
+		return m.handleTagFilterKeys(msg)
+	case ViewShift:
+		return m.handleShiftKeys(msg)
+	case ViewCompare:
+		return m.handleCompareKeys(msg)
+	case ViewFormat:
+		return m.handleFormatKeys(msg)
 	}
 
 	return m, nil
 }
 
+// parseGotoDate parses the goto dialog input: fixed formats like
+// YYYY-MM-DD and MM/DD first, then natural language and relative
+// expressions such as "next fri", "+3w", "eom" or "2025-W40". Unless
+// bigEndian, numeric dates are read day first, like DD/MM and DDMMYYYY.
+func parseGotoDate(input string, now time.Time, bigEndian bool) (time.Time, error) {
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty input")
+	}
+
+	// Try standard date formats FIRST
+	dateFormats := []string{
+		"2006-01-02", // YYYY-MM-DD
+		"20060102",   // YYYYMMDD
+		"01/02/2006", // MM/DD/YYYY
+		"1/2/2006",   // M/D/YYYY
+		"01/02",      // MM/DD (current year)
+		"1/2",        // M/D (current year)
+	}
+	if !bigEndian {
+		dateFormats = []string{
+			"2006-01-02", // YYYY-MM-DD
+			"02012006",   // DDMMYYYY
+			"02/01/2006", // DD/MM/YYYY
+			"2/1/2006",   // D/M/YYYY
+			"02/01",      // DD/MM (current year)
+			"2/1",        // D/M (current year)
+		}
+	}
+
+	for _, format := range dateFormats {
+		if pd, err := time.ParseInLocation(format, input, time.Local); err == nil {
+			// For formats without year, use current year
+			if !strings.Contains(format, "2006") {
+				return time.Date(now.Year(), pd.Month(), pd.Day(),
+					0, 0, 0, 0, time.Local), nil
+			}
+			// Ensure the date is in local timezone with time at midnight
+			return time.Date(pd.Year(), pd.Month(), pd.Day(),
+				0, 0, 0, 0, time.Local), nil
+		}
+	}
+
+	// If standard formats failed, try natural language parsing
+	parser := &remind.TimeParser{Now: now, Location: time.Local, DayFirst: !bigEndian}
+	date, err := parser.ParseDateOnly(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format: %s", input)
+	}
+	return date, nil
+}
+
+// inTextInput reports whether the current mode is editing a text input
+func (m *Model) inTextInput() bool {
+	return m.mode == ViewEventEditor || m.mode == ViewSearch || m.mode == ViewGotoDate ||
+		m.mode == ViewFuzzyFind || m.mode == ViewShift
+}
+
 // handleInactivityAutoAdvance advances the selected slot to the current time
 // if the user has been inactive for more than 5 minutes and is currently at
 // the slot immediately before the current time slot.
 func (m *Model) handleInactivityAutoAdvance() {
 	// Only auto-advance after 5 minutes of inactivity
-	if time.Since(m.lastKeyInput) <= 5*time.Minute {
+	if m.now().Sub(m.lastKeyInput) <= 5*time.Minute {
 		return
 	}
 
-	now := time.Now()
+	now := m.now()
 
 	// Calculate the current slot based on current time increment
 	slotsPerDay := m.getSlotsPerDay()
@@ -411,41 +824,21 @@ func (m *Model) handleInactivityAutoAdvance() {
 	m.centerSelectedSlot()
 }
 
-func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m *Model) handleHourlyKeys(key, action string) (tea.Model, tea.Cmd) {
 	// Calculate slots per day based on increment
 	slotsPerDay := m.getSlotsPerDay()
 
 	visibleSlots := m.getVisibleSlots()
 
-	// Get the key string and action
-	key := msg.String()
-	// Handle special key representations
-	switch key {
-	case "up":
-		key = "<up>"
-	case "down":
-		key = "<down>"
-	case "left":
-		key = "<left>"
-	case "right":
-		key = "<right>"
-	case "enter":
-		key = "<enter>"
-	case "tab":
-		key = "<tab>"
-	case "pgup":
-		key = "<pageup>"
-	case "pgdown":
-		key = "<pagedown>"
-	case "home":
-		key = "<home>"
-	case "ctrl+l":
-		key = "\\Cl"
-	case "ctrl+b":
-		key = "\\Cb"
-	}
+	// Any key closes the peek overlay
+	peeking := m.peeking
+	m.peeking = false
 
-	action := m.getActionForKey(key)
+	if m.visual {
+		if handled, cmd := m.handleVisualKey(key, action); handled {
+			return m, cmd
+		}
+	}
 
 	switch action {
 	case "scroll_down":
@@ -455,8 +848,13 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		// Move down = next time slot (can roll to next day)
 		m.selectedSlot++
-		// Check if selected slot is still visible
-		if !m.isSlotVisible(m.selectedSlot) {
+		// Keep the selected slot centered with center_cursor, otherwise
+		// scroll only once it is no longer visible
+		if m.config.CenterCursor {
+			m.topSlot = m.selectedSlot - m.getVisibleSlots()/2
+		} else if m.compact {
+			m.followCompact()
+		} else if !m.isSlotVisible(m.selectedSlot) {
 			m.topSlot++
 		}
 		// Update selectedDate to match the day of the selected slot
@@ -469,8 +867,13 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		// Move up = previous time slot (can roll to previous day)
 		m.selectedSlot--
-		// Check if selected slot is still visible
-		if !m.isSlotVisible(m.selectedSlot) {
+		// Keep the selected slot centered with center_cursor, otherwise
+		// scroll only once it is no longer visible
+		if m.config.CenterCursor {
+			m.topSlot = m.selectedSlot - m.getVisibleSlots()/2
+		} else if m.compact {
+			m.followCompact()
+		} else if !m.isSlotVisible(m.selectedSlot) {
 			m.topSlot--
 		}
 		// Update selectedDate to match the day of the selected slot
@@ -516,9 +919,22 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Always reload events when changing months
 		m.loadEventsForSchedule()
 
+	case "goto_monday", "goto_tuesday", "goto_wednesday", "goto_thursday",
+		"goto_friday", "goto_saturday", "goto_sunday":
+		// Jump to a day of the week shown under the calendar
+		for i, dayAction := range weekDayActions {
+			if dayAction == action {
+				weekday := (int(m.selectedDate.Weekday()) + 6) % 7
+				m.selectedDate = m.selectedDate.AddDate(0, 0, i-weekday)
+			}
+		}
+		if m.needsEventReload() {
+			m.loadEventsForSchedule()
+		}
+
 	case "home":
 		// Go to current time - start fresh
-		now := time.Now()
+		now := m.now()
 		m.selectedDate = now
 
 		// Calculate current time slot for today (where day 0 = today)
@@ -578,16 +994,29 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "goto":
 		// Go to specific date
 		m.mode = ViewGotoDate
-		m.inputBuffer = ""
-		m.cursorPos = 0
+		m.gotoInput.Reset()
 		// Don't show a message here since the dialog will show instructions
 		return m, nil
 
 	case "begin_search":
 		// Start search
 		m.mode = ViewSearch
-		m.inputBuffer = ""
-		m.cursorPos = 0
+		m.searchInput.Reset()
+		return m, nil
+
+	case "visual":
+		// Select a range of slots for a new event
+		m.startVisual()
+		return m, nil
+
+	case "extend_span", "shrink_span":
+		// Select how many slots new events cover
+		m.resizeSpan(action == "extend_span")
+		return m, nil
+
+	case "next_event", "prev_event":
+		// Jump to the next or previous event, skipping empty slots
+		m.jumpToAdjacentEvent(action == "next_event")
 		return m, nil
 
 	case "search_next":
@@ -607,9 +1036,8 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ViewEventEditor
 		m.editingEvent = nil
 
-		// Clear input buffer for natural language input
-		m.inputBuffer = ""
-		m.cursorPos = 0
+		// Clear input for natural language input
+		m.quickAddInput.Reset()
 
 	case "edit_any":
 		// If focused on untimed reminders, edit the selected untimed reminder
@@ -636,7 +1064,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Edit this event
 				file, err := m.findEventFile(event)
 				if err != nil {
-					m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+					m.showError("Failed to find event file", err)
 				} else {
 					m.showMessage("Launching editor for untimed reminder...")
 					return m, m.editCmd(m.config.EditOldCommand, file, event.LineNumber)
@@ -651,16 +1079,16 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Find which file contains this event
 			file, err := m.findEventFile(*event)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+				m.showError("Failed to find event file", err)
 			} else {
 				m.showMessage("Launching editor...")
 				return m, m.editCmd(m.config.EditOldCommand, file, event.LineNumber)
 			}
 		} else {
 			// No event at this slot - edit file for new event
-			if len(m.config.RemindFiles) > 0 {
+			if file := m.primaryFile(); file != "" {
 				m.showMessage("Launching editor for new event...")
-				return m, m.editCmd(m.config.EditNewCommand, m.config.RemindFiles[0], 0)
+				return m, m.editCmd(m.config.EditNewCommand, file, 0)
 			} else {
 				m.showMessage("No remind files configured")
 			}
@@ -692,16 +1120,16 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.showMessage("Cannot add events: remind client not available")
 			return m, nil
 		}
-		lineNumber, err := m.remindClient.AddTimedEventFromTemplate(m.config.TimedTemplate, dateStr, timeStr)
+		file, lineNumber, err := m.addFromTemplate("timed_template", m.config.TimedTemplate, dateStr, timeStr)
 		if err != nil {
-			m.showMessage(fmt.Sprintf("Failed to add reminder: %v", err))
+			m.showError("Failed to add reminder", err)
 			return m, nil
 		}
 
 		// Launch editor at the new line
-		if len(m.config.RemindFiles) > 0 {
+		if file != "" {
 			m.showMessage("Launching editor for new timed reminder...")
-			return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+			return m, m.editNewEventCmd(file, lineNumber)
 		}
 
 	case "new_untimed":
@@ -719,16 +1147,16 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.showMessage("Cannot add events: remind client not available")
 			return m, nil
 		}
-		lineNumber, err := m.remindClient.AddEventFromTemplate(m.config.UntimedTemplate, dateStr, "")
+		file, lineNumber, err := m.addFromTemplate("untimed_template", m.config.UntimedTemplate, dateStr, "")
 		if err != nil {
-			m.showMessage(fmt.Sprintf("Failed to add untimed reminder: %v", err))
+			m.showError("Failed to add untimed reminder", err)
 			return m, nil
 		}
 
 		// Launch editor at the new line
-		if len(m.config.RemindFiles) > 0 {
+		if file != "" {
 			m.showMessage("Launching editor for new untimed reminder...")
-			return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+			return m, m.editNewEventCmd(file, lineNumber)
 		}
 		return m, nil
 
@@ -774,14 +1202,14 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.showMessage("Cannot add events: remind client not available")
 				return m, nil
 			}
-			lineNumber, err := m.remindClient.AddEventFromTemplate(template, dateStr, timeStr)
+			file, lineNumber, err := m.addFromTemplate(fmt.Sprintf("template%d", templateNum), template, dateStr, timeStr)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to add from template: %v", err))
+				m.showError("Failed to add from template", err)
 				return m, nil
 			}
-			if len(m.config.RemindFiles) > 0 {
+			if file != "" {
 				m.showMessage(fmt.Sprintf("Created from template %d...", templateNum))
-				return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+				return m, m.editNewEventCmd(file, lineNumber)
 			}
 		} else {
 			// Untimed template
@@ -789,14 +1217,14 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.showMessage("Cannot add events: remind client not available")
 				return m, nil
 			}
-			lineNumber, err := m.remindClient.AddEventFromTemplate(template, dateStr, "")
+			file, lineNumber, err := m.addFromTemplate(fmt.Sprintf("template%d", templateNum), template, dateStr, "")
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to add from template: %v", err))
+				m.showError("Failed to add from template", err)
 				return m, nil
 			}
-			if len(m.config.RemindFiles) > 0 {
+			if file != "" {
 				m.showMessage(fmt.Sprintf("Created from template %d...", templateNum))
-				return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+				return m, m.editNewEventCmd(file, lineNumber)
 			}
 		}
 		return m, nil
@@ -819,7 +1247,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Edit this event
 				file, err := m.findEventFile(event)
 				if err != nil {
-					m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+					m.showError("Failed to find event file", err)
 				} else {
 					m.showMessage("Launching editor for untimed reminder...")
 					return m, m.editCmd(m.config.EditOldCommand, file, event.LineNumber)
@@ -865,16 +1293,16 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.showMessage("Cannot add events: remind client not available")
 				return m, nil
 			}
-			lineNumber, err := m.remindClient.AddTimedEventFromTemplate(m.config.TimedTemplate, dateStr, timeStr)
+			file, lineNumber, err := m.addFromTemplate("timed_template", m.config.TimedTemplate, dateStr, timeStr)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to add reminder: %v", err))
+				m.showError("Failed to add reminder", err)
 				return m, nil
 			}
 
 			// Launch editor at the new line
-			if len(m.config.RemindFiles) > 0 {
+			if file != "" {
 				m.showMessage("Creating new timed reminder...")
-				return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+				return m, m.editNewEventCmd(file, lineNumber)
 			}
 
 		} else if len(events) == 1 {
@@ -888,7 +1316,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Regular event - edit it directly
 			file, err := m.findEventFile(event)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+				m.showError("Failed to find event file", err)
 			} else {
 				m.showMessage("Launching editor...")
 				return m, m.editCmd(m.config.EditOldCommand, file, event.LineNumber)
@@ -912,7 +1340,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				event := editableEvents[0]
 				file, err := m.findEventFile(event)
 				if err != nil {
-					m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+					m.showError("Failed to find event file", err)
 				} else {
 					m.showMessage("Launching editor...")
 					return m, m.editCmd(m.config.EditOldCommand, file, event.LineNumber)
@@ -967,43 +1395,30 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.showMessage("Cannot add events: remind client not available")
 			return m, nil
 		}
-		lineNumber, err := m.remindClient.AddEventFromTemplate(template, dateStr, "")
+		name := fmt.Sprintf("template%d", templateNum)
+		if action == "new_untimed_dialog" {
+			name = "untimed_template"
+		}
+		file, lineNumber, err := m.addFromTemplate(name, template, dateStr, "")
 		if err != nil {
-			m.showMessage(fmt.Sprintf("Failed to add from template: %v", err))
+			m.showError("Failed to add from template", err)
 			return m, nil
 		}
 
-		if len(m.config.RemindFiles) > 0 {
+		if file != "" {
 			m.showMessage("Launching editor...")
-			return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+			return m, m.editNewEventCmd(file, lineNumber)
 		}
 		return m, nil
 
 	case "copy":
 		// If focused on untimed reminders, copy the selected untimed reminder
 		if m.focusUntimed {
-			// Calculate the selected date based on the selected slot
-			dayOffset := m.selectedSlot / slotsPerDay
-			if m.selectedSlot < 0 {
-				dayOffset = -1 + (m.selectedSlot+1)/slotsPerDay
-			}
-
-			selectedDate := m.selectedDate.AddDate(0, 0, dayOffset)
-
-			// Find the selected untimed event
-			eventIndex := 0
-			for i := range m.events {
-				if m.events[i].Time == nil &&
-					m.events[i].Date.Year() == selectedDate.Year() &&
-					m.events[i].Date.YearDay() == selectedDate.YearDay() {
-					if eventIndex == m.selectedUntimedIndex {
-						m.clipboardEvent = &m.events[i]
-						m.clipboardCut = false
-						m.showMessage("Event copied to clipboard")
-						break
-					}
-					eventIndex++
-				}
+			// The selected untimed event, in the order the untimed box shows
+			if events := m.selectedEvents(); len(events) > 0 {
+				event := events[0]
+				m.setClipboard(&event, false)
+				m.showMessage("Event copied to clipboard")
 			}
 		} else {
 			// Get all events at the selected time slot
@@ -1012,8 +1427,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.showMessage("No event at current time to copy")
 			} else if len(events) == 1 {
 				// Single event - copy directly
-				m.clipboardEvent = &events[0]
-				m.clipboardCut = false
+				m.setClipboard(&events[0], false)
 				m.showMessage("Event copied to clipboard")
 			} else {
 				// Multiple events - show selector
@@ -1028,42 +1442,20 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "cut":
 		// If focused on untimed reminders, cut the selected untimed reminder
 		if m.focusUntimed {
-			// Calculate the selected date based on the selected slot
-			dayOffset := m.selectedSlot / slotsPerDay
-			if m.selectedSlot < 0 {
-				dayOffset = -1 + (m.selectedSlot+1)/slotsPerDay
-			}
-
-			selectedDate := m.selectedDate.AddDate(0, 0, dayOffset)
-
-			// Find the selected untimed event
-			eventIndex := 0
-			for i := range m.events {
-				if m.events[i].Time == nil &&
-					m.events[i].Date.Year() == selectedDate.Year() &&
-					m.events[i].Date.YearDay() == selectedDate.YearDay() {
-					if eventIndex == m.selectedUntimedIndex {
-						// Store in clipboard
-						m.clipboardEvent = &m.events[i]
-						m.clipboardCut = true
-
-						// Immediately remove from file
-						if m.remindClient == nil {
-							m.showMessage("Cannot remove events: remind client not available")
-							return m, nil
-						}
-						if err := m.remindClient.RemoveEvent(m.events[i]); err != nil {
-							m.showMessage(fmt.Sprintf("Failed to cut event: %v", err))
-							m.clipboardEvent = nil
-							m.clipboardCut = false
-						} else {
-							m.showMessage("Event cut to clipboard")
-							// Reload events to show the change
-							m.loadEvents()
-						}
-						break
-					}
-					eventIndex++
+			// The selected untimed event, in the order the untimed box shows
+			if events := m.selectedEvents(); len(events) > 0 {
+				// Store in clipboard
+				event := events[0]
+				m.setClipboard(&event, true)
+
+				// Immediately remove from its source
+				if err := m.removeEvent(event); err != nil {
+					m.showError("Failed to cut event", err)
+					m.setClipboard(nil, false)
+				} else {
+					m.showMessage("Event cut to clipboard")
+					// Reload events to show the change
+					m.loadEvents()
 				}
 			}
 		} else {
@@ -1073,18 +1465,12 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.showMessage("No event at current time to cut")
 			} else if len(events) == 1 {
 				// Single event - cut directly
-				m.clipboardEvent = &events[0]
-				m.clipboardCut = true
+				m.setClipboard(&events[0], true)
 
-				// Immediately remove from file
-				if m.remindClient == nil {
-					m.showMessage("Cannot remove events: remind client not available")
-					return m, nil
-				}
-				if err := m.remindClient.RemoveEvent(events[0]); err != nil {
-					m.showMessage(fmt.Sprintf("Failed to cut event: %v", err))
-					m.clipboardEvent = nil
-					m.clipboardCut = false
+				// Immediately remove from its source
+				if err := m.removeEvent(events[0]); err != nil {
+					m.showError("Failed to cut event", err)
+					m.setClipboard(nil, false)
 				} else {
 					m.showMessage("Event cut to clipboard")
 					// Reload events to show the change
@@ -1154,22 +1540,21 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		lineNumber, err := m.remindClient.AddEventStruct(newEvent)
 		if err != nil {
-			m.showMessage(fmt.Sprintf("Failed to paste event: %v", err))
+			m.showError("Failed to paste event", err)
 			return m, nil
 		}
 
 		// If it was cut, the original was already removed, so just clear clipboard
 		if m.clipboardCut {
 			m.showMessage("Event moved - launching editor...")
-			m.clipboardEvent = nil
-			m.clipboardCut = false
+			m.setClipboard(nil, false)
 		} else {
 			m.showMessage("Event pasted - launching editor...")
 		}
 
 		// Launch editor for the newly pasted event
-		if len(m.config.RemindFiles) > 0 {
-			return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+		if file := m.primaryFile(); file != "" {
+			return m, m.editNewEventCmd(file, lineNumber)
 		}
 		return m, nil
 
@@ -1227,23 +1612,258 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		lineNumber, err := m.remindClient.AddEventStruct(newEvent)
 		if err != nil {
-			m.showMessage(fmt.Sprintf("Failed to paste event: %v", err))
+			m.showError("Failed to paste event", err)
 			return m, nil
 		}
 
 		// If it was cut, the original was already removed, so just clear clipboard
 		if m.clipboardCut {
 			m.showMessage("Event moved - launching editor...")
-			m.clipboardEvent = nil
-			m.clipboardCut = false
+			m.setClipboard(nil, false)
 		} else {
 			m.showMessage("Event pasted - launching editor...")
 		}
 
 		// Launch editor for the newly pasted event
-		if len(m.config.RemindFiles) > 0 {
-			return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+		if file := m.primaryFile(); file != "" {
+			return m, m.editNewEventCmd(file, lineNumber)
+		}
+		return m, nil
+
+	case "format_file":
+		// Tidy the selected event's remind file, after a look at the changes
+		m.startFormat()
+		return m, nil
+
+	case "edit_config":
+		m.showMessage("Launching editor for urdrc...")
+		return m, m.editConfigCmd()
+
+	case "edit_note":
+		// Open the selected day's note, creating it if needed
+		if m.config.JournalDir == "" {
+			m.showMessage("Set journal_dir to keep notes for each day")
+			return m, nil
+		}
+		m.showMessage("Launching editor for the day's note...")
+		return m, m.editNoteCmd(m.selectedDay())
+
+	case "toggle_presentation":
+		// Toggle redaction of private events for screen sharing
+		m.presentationMode = !m.presentationMode
+		if m.presentationMode {
+			m.showMessage("Presentation mode on - private events hidden")
+		} else {
+			m.showMessage("Presentation mode off")
+		}
+		return m, nil
+
+	case "next":
+		// List the next events from now on, however far ahead
+		events, err := remind.Upcoming(m.source, m.now(), upcomingCount)
+		if err != nil {
+			m.showError("Failed to find upcoming events", err)
+			return m, nil
+		}
+		if len(events) == 0 {
+			m.showMessage("No upcoming events")
+			return m, nil
+		}
+		m.upcomingEvents = events
+		m.selectedUpcomingIndex = 0
+		m.mode = ViewUpcoming
+		return m, nil
+
+	case "fuzzy_find":
+		// Type to filter the loaded events, Enter jumps to the selection
+		m.mode = ViewFuzzyFind
+		m.fuzzyInput.Reset()
+		m.updateFuzzyMatches()
+		return m, nil
+
+	case "execute":
+		// Run the command embedded in the selected event as "RUN: command"
+		for _, event := range m.selectedEvents() {
+			if command := eventCommand(m.displayEvent(event)); command != "" {
+				m.execCommand = command
+				m.execPhase = execConfirm
+				m.execOutput = nil
+				m.execErr = nil
+				m.mode = ViewExecute
+				return m, nil
+			}
 		}
+		m.showMessage("No RUN: command in current reminder(s)")
+		return m, nil
+
+	case "join":
+		// Join the selected meeting, or the current or next one today
+		for _, event := range m.selectedEvents() {
+			if url := meetingURL(event); url != "" {
+				m.showMessage(fmt.Sprintf("Joining %s", m.displayEvent(event).Description))
+				return m, m.openURLCmd(url)
+			}
+		}
+		if event, ok := m.meetingToJoin(m.now()); ok {
+			m.showMessage(fmt.Sprintf("Joining %s", m.displayEvent(event).Description))
+			return m, m.openURLCmd(meetingURL(event))
+		}
+		m.showMessage("No meeting link found")
+		return m, nil
+
+	case "review":
+		// Walk through yesterday's leftovers, then plan today
+		if err := m.startReview(m.now()); err != nil {
+			m.showError("Failed to start review", err)
+		}
+		return m, nil
+
+	case "toggle_sources":
+		// Show or hide sources like p2 profiles
+		if _, ok := m.source.(remind.ToggleableSource); !ok {
+			m.showMessage("Only one source is configured")
+			return m, nil
+		}
+		m.selectedSourceIndex = 0
+		m.mode = ViewSources
+		return m, nil
+
+	case "filter_tag":
+		// Show only the events sharing a tag with the selected one, or all
+		// of them again
+		m.filterByTag()
+		return m, nil
+
+	case "shift_day":
+		// Move the events of the selected day by some time
+		m.startShift()
+		return m, nil
+
+	case "undo_shift":
+		m.undoShift()
+		return m, nil
+
+	case "compare_days":
+		// Show the selected day next to the same day a week later
+		m.startCompare()
+		return m, nil
+
+	case "plan":
+		// Propose times for the week's estimated tasks
+		if err := m.startPlan(m.now()); err != nil {
+			m.showError("Nothing to plan", err)
+		}
+		return m, nil
+
+	case "focus":
+		// Start a focus session on the selected event, or stop the current one
+		return m, m.toggleFocus(m.now())
+
+	case "copy_description", "copy_rem_line", "copy_date":
+		return m, m.copyText(action)
+
+	case "screenshot", "screenshot_redacted":
+		// Save the screen for documentation and bug reports
+		m.screenshot(action == "screenshot_redacted")
+		return m, nil
+
+	case "export_csv":
+		// Save the visible days for a spreadsheet
+		m.exportCSV()
+		return m, nil
+
+	case "copy_agenda":
+		// Share the visible days as plain text, e.g. to paste availability
+		start, end := m.visibleDays()
+		if sameDay(start, end) {
+			m.showMessage(fmt.Sprintf("Copied agenda for %s", start.Format(m.config.DateLayout("Jan 2"))))
+		} else {
+			m.showMessage(fmt.Sprintf("Copied agenda for %s - %s", start.Format(m.config.DateLayout("Jan 2")), end.Format(m.config.DateLayout("Jan 2"))))
+		}
+		return m, m.copyToClipboard(m.agendaSnapshot(start, end))
+
+	case "toggle_compact":
+		// Collapse runs of empty slots, or show every slot again
+		m.compact = !m.compact
+		if m.compact {
+			m.showMessage("Compact schedule on - empty hours collapsed")
+		} else {
+			m.showMessage("Compact schedule off")
+		}
+		return m, nil
+
+	case "toggle_agenda":
+		// Switch the sidebar between the selected slot and the day agenda
+		m.showAgenda = !m.showAgenda
+		return m, nil
+
+	case "toggle_time_audit":
+		// Color events by tag, with the hours per tag in the status bar
+		m.timeAudit = !m.timeAudit
+		return m, nil
+
+	case "scroll_left":
+		// Show the event columns left of the ones on screen
+		if m.columnOffset > m.maxColumnOffset {
+			m.columnOffset = m.maxColumnOffset
+		}
+		if m.columnOffset > 0 {
+			m.columnOffset--
+		}
+		return m, nil
+
+	case "scroll_right":
+		// Show the event columns that didn't fit on screen
+		if m.columnOffset < m.maxColumnOffset {
+			m.columnOffset++
+		} else if m.maxColumnOffset == 0 {
+			m.showMessage("All events fit on screen")
+		}
+		return m, nil
+
+	case "peek":
+		// Show the selected events in full, without truncation
+		if len(m.selectedEvents()) == 0 {
+			m.showMessage("No reminder selected")
+			return m, nil
+		}
+		m.peeking = !peeking
+		return m, nil
+
+	case "edit_error":
+		// Open the editor where remind reported an error
+		return m, m.editErrorCmd()
+
+	case "toggle_pin":
+		// Keep the selected events listed in the sidebar
+		m.togglePins()
+		return m, nil
+
+	case "cycle_status":
+		// Move the selected event on from confirmed to tentative to
+		// cancelled
+		m.cycleStatus()
+		return m, nil
+
+	case "toggle_due_groups":
+		// List the untimed reminders around today by when they're due
+		m.toggleDueGroups()
+		return m, nil
+
+	case "toggle_section":
+		// Collapse the due date section of the selected untimed reminder
+		m.toggleSelectedSection()
+		return m, nil
+
+	case "expand_sections":
+		m.expandSections()
+		return m, nil
+
+	case "toggle_rem_line":
+		// Switch peek between the events and their REM lines, peeking
+		// right away
+		m.peekRemLine = !m.peekRemLine
+		m.peeking = len(m.selectedEvents()) > 0
 		return m, nil
 
 	case "open_url":
@@ -1288,7 +1908,7 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if len(uniqueURLs) == 1 {
 			// Open single URL directly
 			m.showMessage(fmt.Sprintf("Opening URL: %s", uniqueURLs[0]))
-			return m, openURLCmd(uniqueURLs[0])
+			return m, m.openURLCmd(uniqueURLs[0])
 		} else {
 			// Multiple URLs - show selector
 			m.urlChoices = uniqueURLs
@@ -1303,8 +1923,9 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Toggle focus between timed slots and untimed reminders
 		m.focusUntimed = !m.focusUntimed
 		if m.focusUntimed {
-			// Reset untimed selection index when switching to untimed
-			m.selectedUntimedIndex = 0
+			// Start from the first reminder listed, where the list was left
+			day := m.selectedDay()
+			m.selectedUntimedIndex = m.untimedOffset(day, len(m.getSortedUntimedEvents(day)))
 			m.showMessage("Focused on untimed reminders")
 		} else {
 			m.showMessage("Focused on timed slots")
@@ -1334,11 +1955,13 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.selectedUntimedIndex < untimedCount-1 {
 				m.selectedUntimedIndex++
 			}
+			m.followUntimedSelection()
 			return m, nil
 		case "scroll_up":
 			if m.selectedUntimedIndex > 0 {
 				m.selectedUntimedIndex--
 			}
+			m.followUntimedSelection()
 			return m, nil
 		}
 
@@ -1348,11 +1971,13 @@ func (m *Model) handleHourlyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.selectedUntimedIndex < untimedCount-1 {
 				m.selectedUntimedIndex++
 			}
+			m.followUntimedSelection()
 			return m, nil
 		case "k", "<up>":
 			if m.selectedUntimedIndex > 0 {
 				m.selectedUntimedIndex--
 			}
+			m.followUntimedSelection()
 			return m, nil
 		}
 	}
@@ -1414,7 +2039,7 @@ func (m *Model) handleEventSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd
 			}
 			file, err := m.findEventFile(event)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+				m.showError("Failed to find event file", err)
 				m.mode = ViewHourly
 			} else {
 				m.showMessage("Launching editor...")
@@ -1457,7 +2082,7 @@ func (m *Model) handleEventSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd
 			event := m.eventChoices[index]
 			file, err := m.findEventFile(event)
 			if err != nil {
-				m.showMessage(fmt.Sprintf("Failed to find event file: %v", err))
+				m.showError("Failed to find event file", err)
 				m.mode = ViewHourly
 			} else {
 				m.showMessage("Launching editor...")
@@ -1474,61 +2099,51 @@ func (m *Model) handleEventSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd
 }
 
 func (m *Model) handleEditorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
-	switch msg.Code {
-	case tea.KeyEscape:
+	action := m.inputAction(msg)
+	if action == "" && msg.Code == tea.KeyTab {
+		action = "next_free_slot"
+	}
+	switch action {
+	case "next_free_slot":
+		m.moveQuickAddToFreeSlot()
+		return m, nil
+
+	case "entry_cancel":
 		m.mode = ViewHourly
 		return m, nil
 
-	case tea.KeyEnter:
+	case "entry_complete":
 		// Parse and save event using natural language processing
-		if m.inputBuffer != "" {
+		if input := m.quickAddInput.Value(); input != "" {
+			m.quickAddInput.Remember()
 			// Use the new quick event method with natural language parsing
 			if m.remindClient == nil {
 				m.showMessage("Cannot add events: remind client not available")
 				return m, nil
 			}
-			lineNumber, err := m.remindClient.AddQuickEvent(m.inputBuffer)
+			lineNumber, err := m.remindClient.AddQuickEvent(input)
+			if err == nil && m.config.TravelBlock {
+				m.addTravelBlock(input)
+			}
 			if err == nil {
 				m.showMessage("Event added - launching editor...")
 				m.mode = ViewHourly
 				m.loadEvents()
 
 				// Launch editor for the newly created event
-				if len(m.config.RemindFiles) > 0 {
-					return m, m.editCmd(m.config.EditOldCommand, m.config.RemindFiles[0], lineNumber)
+				if file := m.primaryFile(); file != "" {
+					return m, m.editNewEventCmd(file, lineNumber)
 				}
 			} else {
-				m.showMessage(fmt.Sprintf("Error: %v", err))
+				m.showError("Error", err)
 			}
 		}
 		m.mode = ViewHourly
 		return m, nil
 
-	case tea.KeyBackspace:
-		if m.cursorPos > 0 {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos-1] + m.inputBuffer[m.cursorPos:]
-			m.cursorPos--
-		}
-
-	case tea.KeyLeft:
-		if m.cursorPos > 0 {
-			m.cursorPos--
-		}
-
-	case tea.KeyRight:
-		if m.cursorPos < len(m.inputBuffer) {
-			m.cursorPos++
-		}
-
-	case tea.KeySpace:
-		// Handle space explicitly
-		m.inputBuffer = m.inputBuffer[:m.cursorPos] + " " + m.inputBuffer[m.cursorPos:]
-		m.cursorPos++
-
 	default:
-		for _, r := range msg.Text {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos] + string(r) + m.inputBuffer[m.cursorPos:]
-			m.cursorPos++
+		if !m.quickAddInput.HandleAction(action) {
+			m.quickAddInput.HandleKey(msg)
 		}
 	}
 
@@ -1536,50 +2151,17 @@ func (m *Model) handleEditorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleGotoDateKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
-	switch msg.Code {
-	case tea.KeyEscape:
+	action := m.inputAction(msg)
+	switch action {
+	case "entry_cancel":
 		m.mode = ViewHourly
 		return m, nil
-	case tea.KeyEnter:
+	case "entry_complete":
 		// Parse the date input
-		if m.inputBuffer != "" {
-			// Try standard date formats FIRST
-			dateFormats := []string{
-				"2006-01-02", // YYYY-MM-DD
-				"01/02/2006", // MM/DD/YYYY
-				"1/2/2006",   // M/D/YYYY
-				"01/02",      // MM/DD (current year)
-				"1/2",        // M/D (current year)
-			}
-
-			var parsedDate time.Time
-			var parseSuccess bool
-
-			for _, format := range dateFormats {
-				if pd, err := time.ParseInLocation(format, m.inputBuffer, time.Local); err == nil {
-					// For MM/DD formats without year, use current year
-					if format == "01/02" || format == "1/2" {
-						parsedDate = time.Date(time.Now().Year(), pd.Month(), pd.Day(),
-							0, 0, 0, 0, time.Local)
-					} else {
-						// Ensure the date is in local timezone with time at midnight
-						parsedDate = time.Date(pd.Year(), pd.Month(), pd.Day(),
-							0, 0, 0, 0, time.Local)
-					}
-					parseSuccess = true
-					break
-				}
-			}
-
-			// If standard formats failed, try natural language parsing
-			if !parseSuccess {
-				parser := &remind.TimeParser{Now: time.Now(), Location: time.Local}
-				date, err := parser.ParseDateOnly(m.inputBuffer)
-				if err == nil {
-					parsedDate = date
-					parseSuccess = true
-				}
-			}
+		if input := m.gotoInput.Value(); input != "" {
+			m.gotoInput.Remember()
+			parsedDate, err := parseGotoDate(input, m.now(), m.config == nil || !m.config.GotoDayFirst)
+			parseSuccess := err == nil
 
 			if parseSuccess {
 				// Jump to the parsed date
@@ -1593,51 +2175,32 @@ func (m *Model) handleGotoDateKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 
 				// Load events for the new date
 				m.loadEventsForSchedule()
-				m.showMessage(fmt.Sprintf("Jumped to %s (slot %d)", m.selectedDate.Format("Monday, Jan 2, 2006"), m.selectedSlot))
-				// Clear input buffer
-				m.inputBuffer = ""
-				m.cursorPos = 0
+				m.showMessage(fmt.Sprintf("Jumped to %s (slot %d)", m.selectedDate.Format(m.config.DateLayout("Monday, Jan 2, 2006")), m.selectedSlot))
 			} else {
-				m.showMessage(fmt.Sprintf("Invalid date format: %s", m.inputBuffer))
+				m.showMessage(fmt.Sprintf("Invalid date format: %s", input))
 			}
 		}
 		m.mode = ViewHourly
 		return m, nil
-	case tea.KeyBackspace:
-		if m.cursorPos > 0 {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos-1] + m.inputBuffer[m.cursorPos:]
-			m.cursorPos--
-		}
-	case tea.KeyLeft:
-		if m.cursorPos > 0 {
-			m.cursorPos--
-		}
-	case tea.KeyRight:
-		if m.cursorPos < len(m.inputBuffer) {
-			m.cursorPos++
-		}
-	case tea.KeySpace:
-		// Handle space explicitly
-		m.inputBuffer = m.inputBuffer[:m.cursorPos] + " " + m.inputBuffer[m.cursorPos:]
-		m.cursorPos++
 	default:
-		for _, r := range msg.Text {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos] + string(r) + m.inputBuffer[m.cursorPos:]
-			m.cursorPos++
+		if !m.gotoInput.HandleAction(action) {
+			m.gotoInput.HandleKey(msg)
 		}
 	}
 	return m, nil
 }
 
 func (m *Model) handleSearchKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
-	switch msg.Code {
-	case tea.KeyEscape:
+	action := m.inputAction(msg)
+	switch action {
+	case "entry_cancel":
 		m.mode = ViewHourly
 		return m, nil
-	case tea.KeyEnter:
+	case "entry_complete":
 		// Perform search
-		if m.inputBuffer != "" {
-			m.searchTerm = m.inputBuffer
+		if input := m.searchInput.Value(); input != "" {
+			m.searchInput.Remember()
+			m.searchTerm = input
 			// Search forward from current position
 			found := m.findNextSearchResult()
 			if found {
@@ -1648,27 +2211,9 @@ func (m *Model) handleSearchKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = ViewHourly
 		return m, nil
-	case tea.KeyBackspace:
-		if m.cursorPos > 0 {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos-1] + m.inputBuffer[m.cursorPos:]
-			m.cursorPos--
-		}
-	case tea.KeyLeft:
-		if m.cursorPos > 0 {
-			m.cursorPos--
-		}
-	case tea.KeyRight:
-		if m.cursorPos < len(m.inputBuffer) {
-			m.cursorPos++
-		}
-	case tea.KeySpace:
-		// Handle space explicitly
-		m.inputBuffer = m.inputBuffer[:m.cursorPos] + " " + m.inputBuffer[m.cursorPos:]
-		m.cursorPos++
 	default:
-		for _, r := range msg.Text {
-			m.inputBuffer = m.inputBuffer[:m.cursorPos] + string(r) + m.inputBuffer[m.cursorPos:]
-			m.cursorPos++
+		if !m.searchInput.HandleAction(action) {
+			m.searchInput.HandleKey(msg)
 		}
 	}
 
@@ -1731,21 +2276,16 @@ func (m *Model) handleClipboardSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea
 
 			if m.clipboardOperation == "copy" {
 				// Copy the selected event
-				m.clipboardEvent = &event
-				m.clipboardCut = false
+				m.setClipboard(&event, false)
 				m.showMessage("Event copied to clipboard")
 			} else if m.clipboardOperation == "cut" {
 				// Cut the selected event
-				m.clipboardEvent = &event
-				m.clipboardCut = true
-
-				// Immediately remove from file
-				if m.remindClient == nil {
-					m.showMessage("Cannot remove events: remind client not available")
-				} else if err := m.remindClient.RemoveEvent(event); err != nil {
-					m.showMessage(fmt.Sprintf("Failed to cut event: %v", err))
-					m.clipboardEvent = nil
-					m.clipboardCut = false
+				m.setClipboard(&event, true)
+
+				// Immediately remove from its source
+				if err := m.removeEvent(event); err != nil {
+					m.showError("Failed to cut event", err)
+					m.setClipboard(nil, false)
 				} else {
 					m.showMessage("Event cut to clipboard")
 					// Reload events to show the change
@@ -1772,21 +2312,16 @@ func (m *Model) handleClipboardSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea
 
 			if m.clipboardOperation == "copy" {
 				// Copy the selected event
-				m.clipboardEvent = &event
-				m.clipboardCut = false
+				m.setClipboard(&event, false)
 				m.showMessage("Event copied to clipboard")
 			} else if m.clipboardOperation == "cut" {
 				// Cut the selected event
-				m.clipboardEvent = &event
-				m.clipboardCut = true
-
-				// Immediately remove from file
-				if m.remindClient == nil {
-					m.showMessage("Cannot remove events: remind client not available")
-				} else if err := m.remindClient.RemoveEvent(event); err != nil {
-					m.showMessage(fmt.Sprintf("Failed to cut event: %v", err))
-					m.clipboardEvent = nil
-					m.clipboardCut = false
+				m.setClipboard(&event, true)
+
+				// Immediately remove from its source
+				if err := m.removeEvent(event); err != nil {
+					m.showError("Failed to cut event", err)
+					m.setClipboard(nil, false)
 				} else {
 					m.showMessage("Event cut to clipboard")
 					// Reload events to show the change
@@ -1806,6 +2341,338 @@ func (m *Model) handleClipboardSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea
 	return m, nil
 }
 
+func (m *Model) handleUpcomingKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Handle special key representations
+	switch key {
+	case "up":
+		key = "<up>"
+	case "down":
+		key = "<down>"
+	case "enter":
+		key = "<enter>"
+	case "esc":
+		key = "<esc>"
+	}
+
+	switch key {
+	case "<esc>", "q":
+		// Cancel and return to hourly view
+		m.mode = ViewHourly
+		m.upcomingEvents = nil
+		m.selectedUpcomingIndex = 0
+		return m, nil
+
+	case "<down>", "j":
+		if m.selectedUpcomingIndex < len(m.upcomingEvents)-1 {
+			m.selectedUpcomingIndex++
+		}
+		return m, nil
+
+	case "<up>", "k":
+		if m.selectedUpcomingIndex > 0 {
+			m.selectedUpcomingIndex--
+		}
+		return m, nil
+
+	case "<enter>":
+		// Jump to the selected event
+		if m.selectedUpcomingIndex < len(m.upcomingEvents) {
+			event := m.upcomingEvents[m.selectedUpcomingIndex]
+			m.mode = ViewHourly
+			m.upcomingEvents = nil
+			m.selectedUpcomingIndex = 0
+			m.jumpToEvent(event)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleFuzzyFindKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	action := m.inputAction(msg)
+	if action == "" {
+		// Up and down select a match, so the history is on alt+p/alt+n
+		switch msg.String() {
+		case "down", "ctrl+n":
+			action = "scroll_down"
+		case "up", "ctrl+p":
+			action = "scroll_up"
+		case "alt+p":
+			action = "history_previous"
+		case "alt+n":
+			action = "history_next"
+		}
+	}
+
+	switch action {
+	case "entry_cancel":
+		m.mode = ViewHourly
+		m.fuzzyMatches = nil
+		return m, nil
+
+	case "entry_complete":
+		if m.selectedFuzzyIndex < len(m.fuzzyMatches) {
+			event := m.fuzzyMatches[m.selectedFuzzyIndex]
+			m.fuzzyInput.Remember()
+			m.mode = ViewHourly
+			m.fuzzyMatches = nil
+			m.jumpToEvent(event)
+		}
+		return m, nil
+
+	case "scroll_down":
+		if m.selectedFuzzyIndex < len(m.fuzzyMatches)-1 {
+			m.selectedFuzzyIndex++
+		}
+		return m, nil
+
+	case "scroll_up":
+		if m.selectedFuzzyIndex > 0 {
+			m.selectedFuzzyIndex--
+		}
+		return m, nil
+	}
+
+	if !m.fuzzyInput.HandleAction(action) {
+		m.fuzzyInput.HandleKey(msg)
+	}
+	m.updateFuzzyMatches()
+	return m, nil
+}
+
+// updateFuzzyMatches refilters the loaded events against the fuzzy input
+func (m *Model) updateFuzzyMatches() {
+	// Match what is shown so presentation mode doesn't leak private events
+	loaded := m.wideEvents()
+	events := make([]remind.Event, len(loaded))
+	for i, event := range loaded {
+		events[i] = m.displayEvent(event)
+	}
+
+	m.fuzzyMatches = fuzzyFilter(events, m.fuzzyInput.Value())
+	m.selectedFuzzyIndex = 0
+}
+
+func (m *Model) handleExecuteKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	key := keyName(msg)
+
+	switch m.execPhase {
+	case execConfirm:
+		switch key {
+		case "y", "Y", "<enter>":
+			m.execPhase = execRunning
+			return m, m.runCommandCmd(m.execCommand)
+		case "n", "N", "<esc>", "q":
+			m.mode = ViewHourly
+		}
+		return m, nil
+
+	case execRunning:
+		// Leave the command running, its result is reported when it finishes
+		if key == "<esc>" || key == "q" {
+			m.mode = ViewHourly
+		}
+		return m, nil
+	}
+
+	// Scroll the output
+	visibleLines := m.execVisibleLines()
+	maxScroll := len(m.execOutput) - visibleLines
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	switch key {
+	case "<esc>", "q", "<enter>":
+		m.mode = ViewHourly
+		m.execOutput = nil
+	case "j", "<down>":
+		m.execScroll++
+	case "k", "<up>":
+		m.execScroll--
+	case "<pagedown>", "space":
+		m.execScroll += visibleLines
+	case "<pageup>":
+		m.execScroll -= visibleLines
+	case "g", "<home>":
+		m.execScroll = 0
+	case "G":
+		m.execScroll = maxScroll
+	}
+	if m.execScroll > maxScroll {
+		m.execScroll = maxScroll
+	}
+	if m.execScroll < 0 {
+		m.execScroll = 0
+	}
+	return m, nil
+}
+
+// execVisibleLines returns how many output lines fit in the execute view
+func (m *Model) execVisibleLines() int {
+	// Leave room for the header, command, status and help lines
+	lines := m.height - 7
+	if lines < 5 {
+		lines = 5
+	}
+	return lines
+}
+
+// addTravelBlock adds a travel block before a quick-added event with a
+// location, as long as the travel buffer
+func (m *Model) addTravelBlock(input string) {
+	parsed, _, err := remind.QuickEventLine(input, m.now(), m.config.QuickDayFirst)
+	if err != nil || !parsed.HasTime || m.config.TravelBuffer <= 0 {
+		return
+	}
+	_, location := remind.ParseLocation(parsed.Text)
+	if location == "" {
+		return
+	}
+
+	if _, err := m.remindClient.AddEventStruct(travelBlock(parsed, location, m.config.TravelBuffer)); err != nil {
+		m.showError("Failed to add travel block", err)
+	}
+}
+
+// promptDueMeeting offers to join a meeting starting within the configured
+// join_prompt time, unless something else is going on
+func (m *Model) promptDueMeeting(now time.Time) {
+	if m.config.JoinPrompt <= 0 || m.mode != ViewHourly {
+		return
+	}
+
+	event, ok := m.dueMeeting(now, m.config.JoinPrompt)
+	if !ok {
+		return
+	}
+	if m.promptedMeetings == nil {
+		m.promptedMeetings = make(map[string]bool)
+	}
+	m.promptedMeetings[meetingKey(event)] = true
+	m.joinEvent = event
+	m.mode = ViewJoinPrompt
+}
+
+func (m *Model) handleJoinPromptKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch keyName(msg) {
+	case "y", "Y", "<enter>":
+		m.mode = ViewHourly
+		m.showMessage(fmt.Sprintf("Joining %s", m.displayEvent(m.joinEvent).Description))
+		return m, m.openURLCmd(meetingURL(m.joinEvent))
+	case "n", "N", "<esc>", "q":
+		m.mode = ViewHourly
+	}
+	return m, nil
+}
+
+func (m *Model) handleReviewKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	key := keyName(msg)
+	now := m.now()
+
+	if key == "<esc>" || key == "q" {
+		m.mode = ViewHourly
+		m.reviewItems, m.reviewAgenda, m.reviewTodos = nil, nil, nil
+		m.loadEvents()
+		return m, nil
+	}
+
+	if m.reviewPhase == reviewItems {
+		var err error
+		switch key {
+		case "d":
+			err = m.reviewItem("done", now)
+		case "f", ">":
+			err = m.reviewItem("defer", now)
+		case "x":
+			err = m.reviewItem("delete", now)
+		case "s", "n", "space":
+			// Leave it as it is
+			m.reviewItems = m.reviewItems[1:]
+			if len(m.reviewItems) == 0 {
+				m.planReview(now)
+			}
+		}
+		if err != nil {
+			m.showError("Failed", err)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "j", "<down>":
+		if m.selectedReviewTodo < len(m.reviewTodos)-1 {
+			m.selectedReviewTodo++
+		}
+	case "k", "<up>":
+		if m.selectedReviewTodo > 0 {
+			m.selectedReviewTodo--
+		}
+	case "n", "<tab>":
+		// Suggest the next free slot instead
+		if len(m.reviewOpenings) > 0 {
+			m.selectedOpening = (m.selectedOpening + 1) % len(m.reviewOpenings)
+		}
+	case "<enter>", "s":
+		if err := m.scheduleReviewTodo(now); err != nil {
+			m.showError("Failed to schedule", err)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handlePlanKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch keyName(msg) {
+	case "y", "Y", "<enter>":
+		m.mode = ViewHourly
+		if len(m.planPlacements) == 0 {
+			return m, nil
+		}
+		if err := m.applyPlan(); err != nil {
+			m.showError("Failed to plan", err)
+		} else {
+			m.showMessage(fmt.Sprintf("Scheduled %d tasks", len(m.planPlacements)))
+		}
+		m.planPlacements, m.planUnplaced = nil, nil
+		m.loadEvents()
+	case "n", "N", "<esc>", "q":
+		m.mode = ViewHourly
+		m.planPlacements, m.planUnplaced = nil, nil
+	}
+	return m, nil
+}
+
+func (m *Model) handleSourcesKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	sources, ok := m.source.(remind.ToggleableSource)
+	if !ok {
+		m.mode = ViewHourly
+		return m, nil
+	}
+	names := sources.SourceNames()
+
+	switch keyName(msg) {
+	case "<esc>", "q":
+		m.mode = ViewHourly
+	case "j", "<down>":
+		if m.selectedSourceIndex < len(names)-1 {
+			m.selectedSourceIndex++
+		}
+	case "k", "<up>":
+		if m.selectedSourceIndex > 0 {
+			m.selectedSourceIndex--
+		}
+	case "space", "<enter>":
+		if m.selectedSourceIndex < len(names) {
+			name := names[m.selectedSourceIndex]
+			sources.SetEnabled(name, !sources.Enabled(name))
+			m.loadEvents()
+		}
+	}
+	return m, nil
+}
+
 func (m *Model) handleURLSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	// Get the key string and action
 	key := msg.String()
@@ -1856,7 +2723,7 @@ func (m *Model) handleURLSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd)
 			m.mode = ViewHourly
 			m.urlChoices = nil
 			m.selectedURLIndex = 0
-			return m, openURLCmd(url)
+			return m, m.openURLCmd(url)
 		}
 		return m, nil
 	}
@@ -1891,7 +2758,7 @@ func (m *Model) handleURLSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd)
 			m.mode = ViewHourly
 			m.urlChoices = nil
 			m.selectedURLIndex = 0
-			return m, openURLCmd(url)
+			return m, m.openURLCmd(url)
 		}
 		return m, nil
 	}
@@ -1906,7 +2773,7 @@ func (m *Model) handleURLSelectorKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd)
 			m.mode = ViewHourly
 			m.urlChoices = nil
 			m.selectedURLIndex = 0
-			return m, openURLCmd(url)
+			return m, m.openURLCmd(url)
 		}
 	}
 
@@ -1942,30 +2809,18 @@ func (m *Model) findNextSearchResult() bool {
 			currentTime = currentTime.Add(time.Minute)
 		}
 
-		// Use FindNext to search forward indefinitely
+		// Look in the prefetched months first, then use FindNext to search
+		// forward indefinitely
+		if event, ok := m.findPrefetched(m.searchTerm, currentTime); ok {
+			m.jumpToEvent(event)
+			return true
+		}
 		event, err := m.remindClient.FindNext(m.searchTerm, currentTime)
 		if err != nil || event == nil {
 			return false
 		}
 
-		// Navigate to the found event
-		// First, update the selected date to the event's date
-		m.selectedDate = event.Date
-
-		if event.Time != nil {
-			// For timed events, set the slot to the event's time on the new date
-			m.selectedSlot = m.timeToSlot(event.Time.Hour(), event.Time.Minute())
-			m.focusUntimed = false
-		} else {
-			// For untimed events, focus on untimed section
-			m.focusUntimed = true
-			m.selectedUntimedIndex = 0 // Will need to find correct index
-		}
-
-		// Load events for the new date
-		m.loadEventsForSchedule()
-
-		m.ensureSelectedSlotVisible()
+		m.jumpToEvent(*event)
 		return true
 	}
 
@@ -1973,47 +2828,100 @@ func (m *Model) findNextSearchResult() bool {
 	return false
 }
 
-func (m *Model) loadEvents() {
-	// Get events for the selected month in hourly view
-	start := time.Date(m.selectedDate.Year(), m.selectedDate.Month(), 1, 0, 0, 0, 0, time.Local)
-	end := start.AddDate(0, 1, -1)
+// jumpToEvent moves the selection to the given event's date and time
+func (m *Model) jumpToEvent(event remind.Event) {
+	// First, update the selected date to the event's date
+	m.selectedDate = event.Date
 
-	events, err := m.source.GetEvents(start, end)
-	if err == nil {
-		m.events = events
-		m.syntaxError = nil // Clear any previous syntax error
+	if event.Time != nil {
+		// For timed events, set the slot to the event's time on the new date
+		m.selectedSlot = m.timeToSlot(event.Time.Hour(), event.Time.Minute())
+		m.focusUntimed = false
 	} else {
-		// Check if this is a syntax error
-		var syntaxErr *remind.RemindSyntaxError
-		if errors.As(err, &syntaxErr) {
-			m.syntaxError = err // Store syntax error for persistent display
-		} else {
-			// For other errors, just show a temporary message
-			m.showMessage(fmt.Sprintf("Error loading events: %v", err))
+		// For untimed events, focus on untimed section
+		m.focusUntimed = true
+		m.selectedUntimedIndex = 0
+	}
+
+	// Load events for the new date
+	m.loadEventsForSchedule()
+
+	if event.Time == nil {
+		m.selectedUntimedIndex = m.untimedIndexOf(event)
+		m.followUntimedSelection()
+	}
+
+	m.ensureSelectedSlotVisible()
+}
+
+// loadEvents reloads the days the schedule loaded last, after a refresh or
+// a change. Reloading the same days, rather than the selected month, keeps
+// events near the start or end of a month from disappearing until the next
+// move.
+func (m *Model) loadEvents() {
+	center := m.eventsLoadedFor
+	if center.IsZero() {
+		center = m.selectedDate
+	}
+	m.loadEventsAround(center)
+}
+
+// showSourceWarnings shows the first problem the sources reported while
+// loading events
+func (m *Model) showSourceWarnings() {
+	if warner, ok := m.source.(remind.WarningSource); ok {
+		if warnings := warner.Warnings(); len(warnings) > 0 {
+			m.showMessage(warnings[0])
 		}
 	}
 }
 
 func (m *Model) loadEventsForSchedule() {
-	// Load events for a wider date range for hourly view
-	start := m.selectedDate.AddDate(0, 0, -14) // Load 2 weeks before
-	end := m.selectedDate.AddDate(0, 0, 14)    // Load 2 weeks after
+	m.loadEventsAround(m.selectedDate)
+}
+
+// loadEventsAround loads the events of the two weeks before and after day
+func (m *Model) loadEventsAround(day time.Time) {
+	start := day.AddDate(0, 0, -14) // Load 2 weeks before
+	end := day.AddDate(0, 0, 14)    // Load 2 weeks after
 
 	events, err := m.source.GetEvents(start, end)
 	if err == nil {
-		m.events = events
-		m.eventsLoadedFor = m.selectedDate // Track when we last loaded events
-		m.syntaxError = nil                // Clear any previous syntax error
+		m.setEvents(events)
+		m.eventsLoadedFor = day // Track when we last loaded events
+		m.lastRefresh = m.now()
+		m.loadError = nil // Clear any previous error
+		m.showSourceWarnings()
+		if m.dueGroups {
+			if err := m.loadDue(); err != nil {
+				m.loadError = err
+			}
+		}
 	} else {
-		// Check if this is a syntax error
-		var syntaxErr *remind.RemindSyntaxError
-		if errors.As(err, &syntaxErr) {
-			m.syntaxError = err // Store syntax error for persistent display
+		// Shown until the events load, with what to do about it
+		m.loadError = err
+	}
+}
+
+// setEvents replaces the loaded events and rebuilds their index
+func (m *Model) setEvents(all []remind.Event) {
+	m.events, m.specials = splitSpecials(all)
+	m.events = m.filterTagged(m.events)
+	m.eventIndex = newDayIndex(m.events)
+	m.eventsRevision++
+}
+
+// splitSpecials separates calendar annotations from regular events so
+// they never show up in the schedule
+func splitSpecials(all []remind.Event) (events, specials []remind.Event) {
+	for _, event := range all {
+		if event.IsSpecial() {
+			specials = append(specials, event)
 		} else {
-			// For other errors, just show a temporary message
-			m.showMessage(fmt.Sprintf("Error loading events: %v", err))
+			events = append(events, event)
 		}
 	}
+	return events, specials
 }
 
 // needsEventReload checks if we need to reload events based on current selected date
@@ -2084,14 +2992,7 @@ func (m *Model) getEventAtSlot(slot int) *remind.Event {
 	targetDate := m.selectedDate.AddDate(0, 0, dayOffset)
 
 	// Find an event at this time slot
-	for _, event := range m.events {
-		// Check if event is on the target date
-		if event.Date.Year() != targetDate.Year() ||
-			event.Date.Month() != targetDate.Month() ||
-			event.Date.Day() != targetDate.Day() {
-			continue
-		}
-
+	for _, event := range m.eventsOn(targetDate) {
 		// For timed events, check if it matches the time slot
 		if event.Time != nil {
 			eventHour := event.Time.Hour()
@@ -2112,14 +3013,28 @@ func (m *Model) getEventAtSlot(slot int) *remind.Event {
 	return nil
 }
 
-// getSortedUntimedEvents returns untimed events for the given date, sorted consistently
+// untimedIndexOf returns the position of event among the untimed events of
+// its day, found by ID. Events without a source location, like remind -n
+// results, are found by description instead. Returns 0 if it isn't there.
+func (m *Model) untimedIndexOf(event remind.Event) int {
+	for i, untimed := range m.getSortedUntimedEvents(event.Date) {
+		if untimed.ID == event.ID || event.Filename == "" && untimed.Description == event.Description {
+			return i
+		}
+	}
+	return 0
+}
+
+// getSortedUntimedEvents returns untimed events for the given date, sorted
+// consistently, or those grouped by due date whatever the date
 func (m *Model) getSortedUntimedEvents(date time.Time) []remind.Event {
+	if m.dueGroups {
+		return m.dueEvents()
+	}
+
 	var untimedEvents []remind.Event
-	for _, event := range m.events {
-		if event.Time == nil &&
-			event.Date.Year() == date.Year() &&
-			event.Date.Month() == date.Month() &&
-			event.Date.Day() == date.Day() {
+	for _, event := range m.eventsOn(date) {
+		if event.Time == nil {
 			untimedEvents = append(untimedEvents, event)
 		}
 	}
@@ -2164,14 +3079,7 @@ func (m *Model) getEventsAtSlot(slot int) []remind.Event {
 	targetDate := m.selectedDate.AddDate(0, 0, dayOffset)
 
 	// Find all events at this time slot
-	for _, event := range m.events {
-		// Check if event is on the target date
-		if event.Date.Year() != targetDate.Year() ||
-			event.Date.Month() != targetDate.Month() ||
-			event.Date.Day() != targetDate.Day() {
-			continue
-		}
-
+	for _, event := range m.eventsOn(targetDate) {
 		// For timed events, check if it matches the time slot
 		if event.Time != nil {
 			eventHour := event.Time.Hour()
@@ -2204,16 +3112,86 @@ func (m *Model) getEventsAtSlot(slot int) []remind.Event {
 	return events
 }
 
+// eventCapabilities reports which operations the event's source supports
+func (m *Model) eventCapabilities(event remind.Event) remind.Capabilities {
+	if m.source == nil {
+		// Without a source, writes go straight to the remind client
+		return remind.Capabilities{Add: true, Remove: true, Edit: true}
+	}
+	return remind.SourceCapabilities(m.source, event)
+}
+
+// removeEvent removes an event from the source it came from
+func (m *Model) removeEvent(event remind.Event) error {
+	if !m.eventCapabilities(event).Remove {
+		return fmt.Errorf("%s events are read-only", event.Source)
+	}
+	var err error
+	if writer, ok := m.source.(remind.EventWriter); ok {
+		err = writer.RemoveEvent(event)
+	} else if m.remindClient == nil {
+		err = fmt.Errorf("remind client not available")
+	} else {
+		err = m.remindClient.RemoveEvent(event)
+	}
+	if err == nil {
+		m.runHook(HookEventRemoved, &event)
+	}
+	return err
+}
+
 // findEventFile attempts to locate which remind file contains the given event
 func (m *Model) findEventFile(event remind.Event) (string, error) {
-	if len(m.config.RemindFiles) == 0 {
-		return "", fmt.Errorf("no remind files configured")
+	if !m.eventCapabilities(event).Edit {
+		return "", fmt.Errorf("%s events are read-only", event.Source)
+	}
+
+	// remind reports the file each reminder came from, including files
+	// pulled in via INCLUDE, so prefer that when available
+	if event.Filename != "" {
+		return event.Filename, nil
 	}
 
-	// For now, use the first file as default
-	// In a more sophisticated implementation, we could parse the event ID
-	// or search through files to find the exact match
-	return m.config.RemindFiles[0], nil
+	file := m.primaryFile()
+	if file == "" {
+		return "", remind.ErrNoFiles
+	}
+	return file, nil
+}
+
+// primaryFile returns the remind file new reminders are written to
+func (m *Model) primaryFile() string {
+	if m.remindClient != nil {
+		if file := m.remindClient.PrimaryFile(); file != "" {
+			return file
+		}
+	}
+	if len(m.config.RemindFiles) > 0 {
+		return m.config.RemindFiles[0]
+	}
+	return ""
+}
+
+// addFromTemplate adds a reminder made from template, which is set with the
+// variable name, to the file set for that template with its tags, or to the
+// primary file. Timed reminders last as long as newEventLength. It returns
+// the file and line the reminder is on.
+func (m *Model) addFromTemplate(name, template, dateStr, timeStr string) (string, int, error) {
+	target := m.config.TemplateTargets[name]
+	file := target.File
+	if file == "" {
+		file = m.primaryFile()
+	}
+	if file == "" {
+		return "", 0, remind.ErrNoFiles
+	}
+	var length time.Duration
+	if timeStr != "" {
+		length = m.newEventLength()
+		m.spanLength = 0
+	}
+	line, err := m.remindClient.AddSpanFromTemplateTo(file, target.Tags, template, dateStr, timeStr, length)
+	return file, line, err
 }
 
 // monthName returns the three-letter month name for remind format
@@ -2226,6 +3204,10 @@ func monthName(m time.Month) string {
 
 // getActionForKey returns the action associated with a key binding
 func (m *Model) getActionForKey(key string) string {
+	// Bindings scoped to the current mode take precedence
+	if action := m.modeActionForKey(key); action != "" {
+		return action
+	}
 	// Check if there's a configured binding for this key
 	if action, ok := m.config.KeyBindings[key]; ok {
 		return action
@@ -2233,13 +3215,26 @@ func (m *Model) getActionForKey(key string) string {
 	return ""
 }
 
+// modeActionForKey returns the action bound to a key in the current mode only
+func (m *Model) modeActionForKey(key string) string {
+	return m.config.ModeKeyBindings[m.bindMode()][key]
+}
+
+// messageTimeout is how long messages stay in the status bar
+const messageTimeout = 3 * time.Second
+
+// showMessage shows msg in the status bar. Update clears it after
+// messageTimeout, unless another message was shown in the meantime.
 func (m *Model) showMessage(msg string) {
 	m.message = msg
-	if m.messageTimer != nil {
-		m.messageTimer.Stop()
-	}
-	m.messageTimer = time.AfterFunc(3*time.Second, func() {
-		m.message = ""
+	m.messageSeq++
+}
+
+// clearMessageCmd clears the message shown last once messageTimeout passed
+func (m *Model) clearMessageCmd() tea.Cmd {
+	seq := m.messageSeq
+	return tea.Tick(messageTimeout, func(time.Time) tea.Msg {
+		return messageTimeoutMsg{seq: seq}
 	})
 }
 
@@ -2254,6 +3249,32 @@ func (m *Model) getVisibleSlots() int {
 	return visibleSlots
 }
 
+// openSchedule selects initial_time, the time the schedule opens at, and
+// scrolls it to initial_position
+func (m *Model) openSchedule(now time.Time) {
+	hour, minute := now.Hour(), now.Minute()
+	switch m.config.InitialTime {
+	case "", "now":
+	case "day_start":
+		start, end := m.config.WorkHoursOn(now.Weekday())
+		if end <= start {
+			start = m.config.WorkStart // A day off
+		}
+		hour, minute = int(start.Hours()), int(start.Minutes())%60
+	default:
+		if t, err := time.Parse("15:04", m.config.InitialTime); err == nil {
+			hour, minute = t.Hour(), t.Minute()
+		}
+	}
+
+	m.selectedSlot = m.timeToSlot(hour, minute)
+	if m.config.InitialPosition == "top" {
+		m.topSlot = m.selectedSlot
+	} else {
+		m.centerSelectedSlot()
+	}
+}
+
 // centerSelectedSlot adjusts topSlot to center the selected slot in the view
 func (m *Model) centerSelectedSlot() {
 	visibleSlots := m.getVisibleSlots()
@@ -2266,10 +3287,20 @@ func (m *Model) centerSelectedSlot() {
 }
 
 // ensureSelectedSlotVisible adjusts topSlot to make the selected slot visible,
-// only scrolling if necessary (minimal scroll)
+// only scrolling if necessary (minimal scroll), or centers it with
+// center_cursor
 func (m *Model) ensureSelectedSlotVisible() {
 	visibleSlots := m.getVisibleSlots()
 
+	if m.config != nil && m.config.CenterCursor {
+		m.topSlot = m.selectedSlot - visibleSlots/2
+		return
+	}
+	if m.compact {
+		m.followCompact()
+		return
+	}
+
 	if m.selectedSlot < m.topSlot {
 		// Slot is above visible area, scroll up
 		m.topSlot = m.selectedSlot
@@ -2301,7 +3332,7 @@ func (m *Model) getSlotsPerDay() int {
 
 // getCurrentTimeSlot returns the slot index for the current time
 func (m *Model) getCurrentTimeSlot() int {
-	now := time.Now()
+	now := m.now()
 	return m.timeToSlot(now.Hour(), now.Minute())
 }
 
@@ -2351,6 +3382,11 @@ func (m *Model) isSlotVisible(slot int) bool {
 	// Calculate visible slots
 	visibleSlots := m.getVisibleSlots()
 
+	// In compact mode, as many as the collapsed runs leave room for
+	if m.compact {
+		return slot >= m.topSlot && slot < m.topSlot+m.compactLayout(slotsPerDay, visibleSlots).shown
+	}
+
 	// Simulate the same rendering logic to count actual visible slots
 	prevDay := -999
 	actualSlotsRendered := 0
@@ -2388,6 +3424,22 @@ func (m *Model) tickCmd() tea.Cmd {
 	})
 }
 
+// waitForFileChange waits for the next change the source reports. The
+// change is handled by Update, which waits for the one after.
+func (m *Model) waitForFileChange() tea.Cmd {
+	if m.fileChanges == nil {
+		return nil
+	}
+	changes := m.fileChanges
+	return func() tea.Msg {
+		event, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return fileChangedMsg(event)
+	}
+}
+
 func (m *Model) timeUpdateCmd() tea.Cmd {
 	return tea.Every(time.Minute, func(time.Time) tea.Msg {
 		return timeUpdateMsg{}
@@ -2396,20 +3448,18 @@ func (m *Model) timeUpdateCmd() tea.Cmd {
 
 // editCmd launches an external editor using tea.ExecProcess for proper terminal handling
 func (m *Model) editCmd(command, filePath string, lineNumber int) tea.Cmd {
-	// Expand variables in the command
-	expandedCommand := m.expandCommandVariables(command, filePath, lineNumber)
-
-	// Parse the command into program and arguments
-	parts, err := m.parseCommand(expandedCommand)
-	if err != nil {
+	if m.config.ReadOnly {
 		return func() tea.Msg {
-			return editorFinishedMsg{err: fmt.Errorf("failed to parse edit command: %w", err)}
+			return editorFinishedMsg{err: errReplaying}
 		}
 	}
 
-	if len(parts) == 0 {
+	// Split the command into program and arguments, with %file% and %line%
+	// filled in
+	parts, err := remind.EditorCommand(command, filePath, lineNumber)
+	if err != nil {
 		return func() tea.Msg {
-			return editorFinishedMsg{err: fmt.Errorf("empty edit command")}
+			return editorFinishedMsg{err: err}
 		}
 	}
 
@@ -2418,57 +3468,15 @@ func (m *Model) editCmd(command, filePath string, lineNumber int) tea.Cmd {
 
 	// Use tea.ExecProcess for proper terminal handling
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
-		return editorFinishedMsg{err: err}
+		return editorFinishedMsg{err: err, file: filePath, line: lineNumber}
 	})
 }
 
-// expandCommandVariables replaces template variables in the command string
-func (m *Model) expandCommandVariables(command, filePath string, lineNumber int) string {
-	result := command
-	result = strings.ReplaceAll(result, "%file%", filePath)
-	if lineNumber > 0 {
-		result = strings.ReplaceAll(result, "%line%", fmt.Sprintf("%d", lineNumber))
-	} else {
-		// For new events, go to end of file
-		result = strings.ReplaceAll(result, "%line%", "999999")
-	}
-	return result
-}
-
-// parseCommand splits a command string into program and arguments
-// Handles quoted arguments properly
-func (m *Model) parseCommand(command string) ([]string, error) {
-	var parts []string
-	var current string
-	var inQuotes bool
-	var quoteChar rune
-
-	for _, r := range command {
-		switch {
-		case !inQuotes && (r == '"' || r == '\''):
-			inQuotes = true
-			quoteChar = r
-		case inQuotes && r == quoteChar:
-			inQuotes = false
-		case !inQuotes && r == ' ':
-			if current != "" {
-				parts = append(parts, current)
-				current = ""
-			}
-		default:
-			current += string(r)
-		}
-	}
-
-	if current != "" {
-		parts = append(parts, current)
-	}
-
-	if inQuotes {
-		return nil, fmt.Errorf("unclosed quote in command: %s", command)
-	}
-
-	return parts, nil
+// editNewEventCmd opens the editor on a reminder that was just added, so the
+// on_event_added hook runs once it has been filled in
+func (m *Model) editNewEventCmd(file string, lineNumber int) tea.Cmd {
+	m.editingNew = true
+	return m.editCmd(m.config.EditOldCommand, file, lineNumber)
 }
 
 // extractURLs extracts URLs from the given text
@@ -2503,7 +3511,11 @@ func extractURLs(text string) []string {
 }
 
 // openURLCmd returns a tea.Cmd that opens the given URL in a browser
-func openURLCmd(url string) tea.Cmd {
+func (m *Model) openURLCmd(url string) tea.Cmd {
+	if m.config.ReadOnly {
+		m.showError("Not opening "+url, errReplaying)
+		return nil
+	}
 	return func() tea.Msg {
 		var cmd *exec.Cmd
 
@@ -2552,10 +3564,15 @@ func openURLCmd(url string) tea.Cmd {
 // Message types
 type tickMsg struct{}
 type timeUpdateMsg struct{}
-type messageTimeoutMsg struct{}
+type messageTimeoutMsg struct {
+	seq int // The message to clear, see clearMessageCmd
+}
+type fileChangedMsg remind.FileChangeEvent
 type eventLoadedMsg struct {
 	events []remind.Event
 }
 type editorFinishedMsg struct {
-	err error
+	err  error
+	file string // the file edited, empty when not an editor session
+	line int
 }
@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/cwarden/urd/internal/config"
 	"github.com/cwarden/urd/internal/remind"
 )
@@ -322,74 +327,55 @@ func repeatAction(action string, count int) []string {
 
 // TestInactivityAutoAdvance tests the auto-advance behavior after inactivity
 func TestInactivityAutoAdvance(t *testing.T) {
-	// We'll test by setting lastKeyInput to more than 5 minutes ago
-	// and checking if the slot advances when conditions are met
+	// The clock is fixed at 14:10, so the previous hour slot is 13 and the
+	// previous half hour slot is 27
+	now := time.Date(2025, 8, 25, 14, 10, 0, 0, time.Local)
 
 	tests := []struct {
 		name                   string
 		timeIncrement          int
-		selectedSlot           int              // Current slot user is at
-		setupLastInput         func() time.Time // How to set lastKeyInput relative to now
+		selectedSlot           int           // Current slot user is at
+		idle                   time.Duration // Time since the last key press
 		shouldAdvance          bool
 		expectedSlotAdjustment int // How much the slot should change
 	}{
 		{
-			name:          "Advances when at previous slot after inactivity",
-			timeIncrement: 60,
-			selectedSlot: func() int {
-				now := time.Now()
-				return now.Hour() - 1 // Previous hour slot
-			}(),
-			setupLastInput: func() time.Time {
-				return time.Now().Add(-6 * time.Minute) // 6 minutes ago
-			},
+			name:                   "Advances when at previous slot after inactivity",
+			timeIncrement:          60,
+			selectedSlot:           13, // Previous hour slot
+			idle:                   6 * time.Minute,
 			shouldAdvance:          true,
 			expectedSlotAdjustment: 1, // Should advance by 1 slot
 		},
 		{
-			name:          "Does not advance when not at previous slot",
-			timeIncrement: 60,
-			selectedSlot:  10, // Some arbitrary slot, not the previous one
-			setupLastInput: func() time.Time {
-				return time.Now().Add(-6 * time.Minute) // 6 minutes ago
-			},
+			name:                   "Does not advance when not at previous slot",
+			timeIncrement:          60,
+			selectedSlot:           10, // Some arbitrary slot, not the previous one
+			idle:                   6 * time.Minute,
 			shouldAdvance:          false,
 			expectedSlotAdjustment: 0, // Should stay the same
 		},
 		{
-			name:          "Does not advance when recently active",
-			timeIncrement: 60,
-			selectedSlot: func() int {
-				now := time.Now()
-				return now.Hour() - 1 // Previous hour slot
-			}(),
-			setupLastInput: func() time.Time {
-				return time.Now().Add(-2 * time.Minute) // Only 2 minutes ago
-			},
+			name:                   "Does not advance when recently active",
+			timeIncrement:          60,
+			selectedSlot:           13, // Previous hour slot
+			idle:                   2 * time.Minute,
 			shouldAdvance:          false,
 			expectedSlotAdjustment: 0, // Should stay the same
 		},
 		{
-			name:          "Advances with 30-min increments",
-			timeIncrement: 30,
-			selectedSlot: func() int {
-				now := time.Now()
-				currentSlot := now.Hour()*2 + now.Minute()/30
-				return currentSlot - 1 // Previous slot
-			}(),
-			setupLastInput: func() time.Time {
-				return time.Now().Add(-6 * time.Minute) // 6 minutes ago
-			},
+			name:                   "Advances with 30-min increments",
+			timeIncrement:          30,
+			selectedSlot:           27, // Previous slot
+			idle:                   6 * time.Minute,
 			shouldAdvance:          true,
 			expectedSlotAdjustment: 1, // Should advance by 1 slot
 		},
 		{
-			name:          "Does not advance when user navigated away",
-			timeIncrement: 60,
-			selectedSlot:  20, // Some future slot (user navigated forward)
-			setupLastInput: func() time.Time {
-				return time.Now().Add(-6 * time.Minute) // 6 minutes ago
-			},
+			name:                   "Does not advance when user navigated away",
+			timeIncrement:          60,
+			selectedSlot:           20, // Some future slot (user navigated forward)
+			idle:                   6 * time.Minute,
 			shouldAdvance:          false,
 			expectedSlotAdjustment: 0, // Should stay the same
 		},
@@ -397,12 +383,12 @@ func TestInactivityAutoAdvance(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			now := time.Now()
 			m := &Model{
+				clock:         remind.FixedClock(now),
 				timeIncrement: tt.timeIncrement,
 				selectedSlot:  tt.selectedSlot,
 				selectedDate:  now,
-				lastKeyInput:  tt.setupLastInput(),
+				lastKeyInput:  now.Add(-tt.idle),
 				height:        30,
 				config:        &config.Config{},
 				remindClient:  &remind.Client{},
@@ -539,3 +525,240 @@ func TestIsSlotVisible(t *testing.T) {
 		})
 	}
 }
+
+func TestReadOnlySourceEvents(t *testing.T) {
+	remindClient := remind.NewClient()
+	m := &Model{
+		config:       &config.Config{},
+		source:       remind.NewCompositeSource(remindClient, remind.NewP2Client()),
+		remindClient: remindClient,
+	}
+
+	p2Event := remind.Event{ID: "p2-1", Source: remind.P2SourceName}
+	if err := m.removeEvent(p2Event); err == nil {
+		t.Error("Expected error removing p2 event")
+	}
+	if _, err := m.findEventFile(p2Event); err == nil {
+		t.Error("Expected error editing p2 event")
+	}
+
+	remindEvent := remind.Event{ID: "evt-1", Source: remind.RemindSourceName, Filename: "/tmp/test.rem"}
+	file, err := m.findEventFile(remindEvent)
+	if err != nil {
+		t.Errorf("Unexpected error editing remind event: %v", err)
+	}
+	if file != "/tmp/test.rem" {
+		t.Errorf("Expected /tmp/test.rem, got %s", file)
+	}
+}
+
+// newFileModel returns a model reading content from a remind file, which it
+// also returns, at now, with now's day selected in the hourly view
+func newFileModel(t *testing.T, content string, now time.Time) (*Model, string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Without remind, the built-in evaluator reads the file
+	client := remind.NewClient()
+	client.RemindPath = "urd-test-no-remind"
+	client.SetFiles([]string{file})
+
+	m := &Model{
+		config:        &config.Config{},
+		source:        client,
+		remindClient:  client,
+		clock:         remind.FixedClock(now),
+		selectedDate:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		timeIncrement: 60,
+		selectedSlot:  10,
+	}
+	return m, file
+}
+
+func TestReloadKeepsScheduleDays(t *testing.T) {
+	now := time.Date(2025, 8, 30, 10, 0, 0, 0, time.Local)
+	m, _ := newFileModel(t, "REM 30 Aug 2025 MSG Pack\nREM 2 Sep 2025 AT 9:00 MSG Offsite\n", now)
+	m.loadEventsForSchedule()
+	m.loadEvents()
+
+	if len(m.events) != 2 {
+		t.Errorf("Expected the reload to keep September's event, got %+v", m.events)
+	}
+	if !m.lastRefresh.Equal(now) {
+		t.Errorf("lastRefresh = %v, want %v", m.lastRefresh, now)
+	}
+}
+
+// watchedSource is a source whose changes are sent by the test
+type watchedSource struct {
+	events  []remind.Event
+	changes chan remind.FileChangeEvent
+}
+
+func (s *watchedSource) GetEvents(start, end time.Time) ([]remind.Event, error) {
+	return s.events, nil
+}
+func (s *watchedSource) SetFiles(files []string) {}
+func (s *watchedSource) WatchFiles() (<-chan remind.FileChangeEvent, error) {
+	return s.changes, nil
+}
+func (s *watchedSource) StopWatching() error { return nil }
+
+// TestFileChangeReloadsInUpdate checks that changes are reloaded by Update
+// rather than the goroutine waiting for them. Run with -race, rendering
+// while the change arrives would catch a reload from that goroutine.
+func TestFileChangeReloadsInUpdate(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	now := time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local)
+	source := &watchedSource{changes: make(chan remind.FileChangeEvent)}
+	m := NewModelWithRemind(config.DefaultConfig(), source, nil)
+	m.clock = remind.FixedClock(now)
+	m.width, m.height = 100, 24
+
+	msgs := make(chan tea.Msg)
+	go func() { msgs <- m.waitForFileChange()() }()
+
+	source.events = []remind.Event{{ID: "1", Date: now, Description: "Added"}}
+	go func() { source.changes <- remind.FileChangeEvent{Path: "main.rem"} }()
+	for i := 0; i < 10; i++ {
+		m.View()
+	}
+	if len(m.events) != 0 {
+		t.Fatal("Events reloaded before Update")
+	}
+
+	_, cmd := m.Update(<-msgs)
+	if len(m.events) != 1 {
+		t.Errorf("Expected the change to be reloaded, got %+v", m.events)
+	}
+	if cmd == nil {
+		t.Error("Expected Update to wait for the next change")
+	}
+}
+
+func TestMessageTimeout(t *testing.T) {
+	m := &Model{config: config.DefaultConfig(), styles: defaultStyles()}
+
+	m.showMessage("Event pasted")
+	first := m.messageSeq
+	_, cmd := m.Update(configEditedMsg{err: errors.New("exit status 1")})
+	if cmd == nil {
+		t.Fatal("Expected Update to clear the message it showed later")
+	}
+
+	// The first message's timeout leaves the second alone
+	m.Update(messageTimeoutMsg{seq: first})
+	if m.message != "Editor failed: exit status 1" {
+		t.Errorf("message = %q, want the editor failure", m.message)
+	}
+	m.Update(messageTimeoutMsg{seq: m.messageSeq})
+	if m.message != "" {
+		t.Errorf("message = %q, want it cleared", m.message)
+	}
+}
+
+func TestQuickAddPreview(t *testing.T) {
+	m := &Model{config: &config.Config{}, styles: defaultStyles()}
+
+	if preview := m.quickAddPreview(); preview != nil {
+		t.Errorf("Expected no preview for empty input, got %v", preview)
+	}
+
+	m.quickAddInput.SetValue("tomorrow at 2pm Dentist")
+	preview := strings.Join(m.quickAddPreview(), "\n")
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	for _, want := range []string{
+		"Date: " + tomorrow.Format("Mon Jan 2, 2006"),
+		"Time: 14:00",
+		"REM " + tomorrow.Format("Jan 2 2006") + " AT 14:00 MSG Dentist",
+	} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, preview)
+		}
+	}
+}
+
+func TestOpenSchedule(t *testing.T) {
+	now := time.Date(2025, 8, 25, 14, 40, 0, 0, time.Local)
+
+	tests := []struct {
+		initialTime     string
+		initialPosition string
+		expectedSlot    int
+		expectedTopSlot int
+	}{
+		{"now", "center", 29, 19},
+		{"now", "top", 29, 29},
+		{"day_start", "top", 17, 17},
+		{"08:00", "center", 16, 6},
+		{"03:00", "center", 6, 0},
+	}
+
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.InitialTime = tt.initialTime
+		cfg.InitialPosition = tt.initialPosition
+		cfg.WorkStart = 8*time.Hour + 30*time.Minute
+		m := &Model{config: cfg, timeIncrement: 30, height: 22}
+
+		m.openSchedule(now)
+		if m.selectedSlot != tt.expectedSlot || m.topSlot != tt.expectedTopSlot {
+			t.Errorf("%s at %s: got slot %d top %d, want slot %d top %d", tt.initialTime, tt.initialPosition,
+				m.selectedSlot, m.topSlot, tt.expectedSlot, tt.expectedTopSlot)
+		}
+	}
+}
+
+func TestCenterCursor(t *testing.T) {
+	m := snapshotModel()
+	m.config.CenterCursor = true
+	m.height = 20
+	m.eventsLoadedFor = m.selectedDate // Prevent reload in test
+
+	for i := 1; i <= 20; i++ {
+		m.handleHourlyKeys("j", "scroll_down")
+		if want := m.selectedSlot - m.getVisibleSlots()/2; m.topSlot != want {
+			t.Fatalf("After %d slots down: topSlot = %d, want %d with slot %d centered", i, m.topSlot, want, m.selectedSlot)
+		}
+	}
+	if !m.selectedDate.Equal(time.Date(2025, 8, 26, 0, 0, 0, 0, time.Local)) || m.selectedSlot != 6 {
+		t.Errorf("Expected 06:00 the next day selected, got slot %d of %v", m.selectedSlot, m.selectedDate)
+	}
+
+	m.handleHourlyKeys("k", "scroll_up")
+	if want := m.selectedSlot - m.getVisibleSlots()/2; m.topSlot != want {
+		t.Errorf("After a slot up: topSlot = %d, want %d", m.topSlot, want)
+	}
+}
+
+func TestTemplateTarget(t *testing.T) {
+	dir := t.TempDir()
+	main, work := filepath.Join(dir, "reminders.rem"), filepath.Join(dir, "work.rem")
+	client := remind.NewClient()
+	client.SetFiles([]string{main})
+
+	cfg := config.DefaultConfig()
+	cfg.TemplateTargets = map[string]config.TemplateTarget{
+		"template2": {File: work, Tags: []string{"@work"}},
+	}
+	m := &Model{config: cfg, remindClient: client}
+
+	file, line, err := m.addFromTemplate("template2", cfg.Templates[2], "Aug 25 2025", "09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file != work || line != 1 {
+		t.Errorf("added to %s:%d, want %s:1", file, line, work)
+	}
+	data, _ := os.ReadFile(work)
+	if want := "REM 25 AT 09:00 DURATION 1:00 TAG work MSG\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	// Templates without a target go to the first remind file
+	if file, _, err := m.addFromTemplate("template3", cfg.Templates[3], "Aug 25 2025", ""); err != nil || file != main {
+		t.Errorf("added to %s (%v), want %s", file, err, main)
+	}
+}
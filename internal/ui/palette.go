@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/config"
+)
+
+// palette is the set of colors events and highlights are drawn with
+type palette struct {
+	p2Durations     [4]lipgloss.ANSIColor // p2 tasks of 4+, 2-4, 1-2 and under 1 hour
+	p2Default       lipgloss.ANSIColor    // p2 tasks without a duration
+	remindDurations [4]lipgloss.ANSIColor // Events of 4+, 2-4, 1-2 and under 1 hour
+	priorities      [4]lipgloss.ANSIColor // Events without a duration, by priority from none to high
+	light           []lipgloss.ANSIColor  // Backgrounds that take dark text
+
+	highlight  lipgloss.ANSIColor // Today, headers and the selection background
+	alert      lipgloss.ANSIColor // Priorities and errors
+	selectedFg lipgloss.ANSIColor
+}
+
+// palettes are the palettes set palette chooses from. Besides the default,
+// they keep apart what the color blindness they're named for confuses:
+// red and green for deuteranopia and protanopia (where red also looks dark),
+// blue and yellow for tritanopia.
+var palettes = map[string]palette{
+	"default": {
+		p2Durations:     [4]lipgloss.ANSIColor{88, 208, 220, 48},
+		p2Default:       24,
+		remindDurations: [4]lipgloss.ANSIColor{52, 63, 99, 105},
+		priorities:      [4]lipgloss.ANSIColor{240, 228, 214, 196},
+		light:           []lipgloss.ANSIColor{48, 220, 228, 214, 105},
+		highlight:       220,
+		alert:           196,
+		selectedFg:      235,
+	},
+	"high_contrast": {
+		p2Durations:     [4]lipgloss.ANSIColor{21, 51, 226, 231},
+		p2Default:       21,
+		remindDurations: [4]lipgloss.ANSIColor{16, 90, 201, 231},
+		priorities:      [4]lipgloss.ANSIColor{244, 231, 226, 196},
+		light:           []lipgloss.ANSIColor{51, 226, 231, 244},
+		highlight:       226,
+		alert:           196,
+		selectedFg:      16,
+	},
+	"deuteranopia": {
+		p2Durations:     [4]lipgloss.ANSIColor{18, 25, 74, 153},
+		p2Default:       67,
+		remindDurations: [4]lipgloss.ANSIColor{94, 172, 179, 223},
+		priorities:      [4]lipgloss.ANSIColor{240, 117, 227, 208},
+		light:           []lipgloss.ANSIColor{74, 153, 179, 223, 117, 227, 208},
+		highlight:       227,
+		alert:           208,
+		selectedFg:      16,
+	},
+	"protanopia": {
+		p2Durations:     [4]lipgloss.ANSIColor{18, 25, 74, 153},
+		p2Default:       67,
+		remindDurations: [4]lipgloss.ANSIColor{130, 178, 185, 229},
+		priorities:      [4]lipgloss.ANSIColor{240, 117, 229, 214},
+		light:           []lipgloss.ANSIColor{74, 153, 178, 185, 229, 117, 214},
+		highlight:       229,
+		alert:           214,
+		selectedFg:      16,
+	},
+	"tritanopia": {
+		p2Durations:     [4]lipgloss.ANSIColor{88, 160, 210, 224},
+		p2Default:       125,
+		remindDurations: [4]lipgloss.ANSIColor{23, 30, 37, 116},
+		priorities:      [4]lipgloss.ANSIColor{240, 116, 205, 196},
+		light:           []lipgloss.ANSIColor{210, 224, 37, 116, 205},
+		highlight:       210,
+		alert:           196,
+		selectedFg:      16,
+	},
+}
+
+// colors returns the palette chosen with set palette
+func (m *Model) colors() palette {
+	if m.config != nil {
+		if p, ok := palettes[m.config.Palette]; ok {
+			return p
+		}
+	}
+	return palettes["default"]
+}
+
+// isLight reports whether dark text goes on the background
+func (p palette) isLight(background lipgloss.ANSIColor) bool {
+	for _, light := range p.light {
+		if light == background {
+			return true
+		}
+	}
+	return false
+}
+
+// stylesFor returns the styles for a configuration: the palette, then the
+// colors set in urdrc, or none at all in accessible mode
+func stylesFor(cfg *config.Config) Styles {
+	if cfg.Accessible {
+		return AccessibleStyles()
+	}
+
+	styles := DefaultStyles()
+	p, ok := palettes[cfg.Palette]
+	if !ok {
+		p = palettes["default"]
+	}
+	styles.Selected = styles.Selected.Foreground(p.selectedFg).Background(p.highlight)
+	styles.Today = styles.Today.Foreground(p.highlight)
+	styles.Header = styles.Header.Foreground(p.highlight)
+	styles.Message = styles.Message.Foreground(p.highlight)
+	styles.Priority = styles.Priority.Foreground(p.alert)
+	styles.TodaySeparator = styles.TodaySeparator.Foreground(p.selectedFg).Background(p.highlight)
+	styles.applyColors(cfg.Colors)
+	return styles
+}
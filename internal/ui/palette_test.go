@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestPalettesCoverConfig(t *testing.T) {
+	for _, name := range config.Palettes {
+		p, ok := palettes[name]
+		if !ok {
+			t.Errorf("No colors for palette %s", name)
+			continue
+		}
+		// Each level needs a color of its own to be told apart
+		for _, colors := range [][4]int{
+			{int(p.p2Durations[0]), int(p.p2Durations[1]), int(p.p2Durations[2]), int(p.p2Durations[3])},
+			{int(p.remindDurations[0]), int(p.remindDurations[1]), int(p.remindDurations[2]), int(p.remindDurations[3])},
+			{int(p.priorities[0]), int(p.priorities[1]), int(p.priorities[2]), int(p.priorities[3])},
+		} {
+			seen := map[int]bool{}
+			for _, c := range colors {
+				if seen[c] {
+					t.Errorf("Palette %s uses color %d twice in %v", name, c, colors)
+				}
+				seen[c] = true
+			}
+		}
+	}
+	if len(palettes) != len(config.Palettes) {
+		t.Errorf("%d palettes, but config accepts %d", len(palettes), len(config.Palettes))
+	}
+}
+
+func TestPaletteEventColors(t *testing.T) {
+	high := remind.Event{Date: time.Now(), Priority: remind.PriorityHigh}
+	m := &Model{config: &config.Config{}}
+	if got := m.getEventBackgroundColor(high); got != 196 {
+		t.Errorf("High priority without a palette = %d, want the default red", got)
+	}
+
+	m.config.Palette = "deuteranopia"
+	if got := m.getEventBackgroundColor(high); got != palettes["deuteranopia"].priorities[3] {
+		t.Errorf("High priority with deuteranopia = %d, want %d", got, palettes["deuteranopia"].priorities[3])
+	}
+	if got := m.getEventTextColor(palettes["deuteranopia"].priorities[3]); got != 0 {
+		t.Errorf("Text on orange = %d, want black", got)
+	}
+
+	styles := stylesFor(m.config)
+	if got := styles.Priority.GetForeground(); got != palettes["deuteranopia"].alert {
+		t.Errorf("Priority style foreground = %v, want the palette's alert color", got)
+	}
+}
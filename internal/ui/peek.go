@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// peekWidth is the widest the peek overlay gets, narrower schedules use
+// their whole event area
+const peekWidth = 50
+
+// peekLines describes the selected events in full: their time, the
+// description blocks cut short, and location and body, or the REM line
+// they come from when toggled
+func (m *Model) peekLines() []string {
+	var lines []string
+	for i, event := range m.selectedEvents() {
+		redacted := m.presentationMode && event.IsPrivate()
+		event = m.displayEvent(event)
+		if i > 0 {
+			lines = append(lines, "")
+		}
+
+		when := "all day"
+		if event.Time != nil {
+			when = event.Time.Format("15:04")
+			if event.Duration != nil {
+				when += "-" + event.Time.Add(*event.Duration).Format("15:04")
+			}
+		}
+		lines = append(lines, m.styles.Header.Render(when))
+
+		if m.peekRemLine && !redacted {
+			if line, err := remind.SourceLine(event); err == nil {
+				lines = append(lines, m.highlightRemLine(line))
+				continue
+			}
+		}
+
+		desc := event.Description
+		if event.Priority > remind.PriorityNone {
+			desc = strings.Repeat("!", int(event.Priority)) + " " + desc
+		}
+		lines = append(lines, m.styles.Normal.Render(desc))
+
+		if event.Location != "" {
+			lines = append(lines, m.styles.Help.Render("Location: "+event.Location))
+		}
+		if event.Body != "" {
+			lines = append(lines, m.styles.Help.Render(event.Body))
+		}
+		if len(event.AlsoFrom) > 0 {
+			lines = append(lines, m.styles.Help.Render("Also from: "+strings.Join(event.AlsoFrom, ", ")))
+		}
+	}
+	return lines
+}
+
+// highlightRemLine renders a REM line with its keywords, dates, times and
+// body set apart
+func (m *Model) highlightRemLine(line string) string {
+	var b strings.Builder
+	for _, span := range remind.HighlightLine(line) {
+		style := m.styles.Normal
+		switch span.Kind {
+		case remind.HighlightKeyword:
+			style = m.styles.Header
+		case remind.HighlightDate:
+			style = m.styles.Today
+		case remind.HighlightTime:
+			style = m.styles.Event
+		case remind.HighlightDelta:
+			style = m.styles.Weekend
+		case remind.HighlightComment:
+			style = m.styles.Help
+		}
+		b.WriteString(style.Render(span.Text))
+	}
+	return b.String()
+}
+
+// createPeekLayer creates the overlay showing the selected events in full,
+// just below the selected slot, or above it when there's no room below
+func (m *Model) createPeekLayer(slotsPerDay, visibleSlots, timeWidth, eventAreaWidth int) *lipgloss.Layer {
+	lines := m.peekLines()
+	if len(lines) == 0 {
+		return nil
+	}
+
+	width := eventAreaWidth - 2
+	if width > peekWidth {
+		width = peekWidth
+	}
+	box := m.styles.Border.Copy().Width(width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	// Rows of the schedule the selected slot takes
+	row := 0
+	if !m.focusUntimed && m.isSlotVisible(m.selectedSlot) {
+		row = m.slotToRowIndex(m.selectedSlot-m.topSlot, slotsPerDay)
+	}
+	y := row + 1
+	if height := lipgloss.Height(box); y+height > visibleSlots {
+		y = row - height
+		if y < 0 {
+			y = 0
+		}
+	}
+
+	return lipgloss.NewLayer(box).
+		X(timeWidth + 2).
+		Y(y).
+		Z(1500) // Over the events, under the status bar
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestPeek(t *testing.T) {
+	description := "Quarterly planning with the whole platform team and guests"
+	m := &Model{
+		width:         60,
+		height:        30,
+		timeIncrement: 60,
+		selectedDate:  time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+		topSlot:       8,
+		selectedSlot:  10,
+		config:        &config.Config{},
+		styles:        defaultStyles(),
+		events: []remind.Event{{
+			Date:        time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+			Time:        timePtr(10, 0),
+			Duration:    durationPtr(90),
+			Description: description,
+			Location:    "Room 4",
+			Body:        "Bring the roadmap",
+			AlsoFrom:    []string{"caldav"},
+		}},
+	}
+
+	m.handleHourlyKeys("v", "peek")
+	if !m.peeking {
+		t.Fatal("peek didn't open the overlay")
+	}
+
+	peek := strings.Join(m.peekLines(), "\n")
+	for _, want := range []string{"10:00-11:30", description, "Location: Room 4", "Bring the roadmap", "Also from: caldav"} {
+		if !strings.Contains(peek, want) {
+			t.Errorf("peek missing %q:\n%s", want, peek)
+		}
+	}
+	if layer := m.createPeekLayer(24, 28, 7, 33); layer == nil {
+		t.Error("no peek layer for the selected event")
+	}
+
+	// Any other key closes it again
+	m.handleHourlyKeys("A", "toggle_agenda")
+	if m.peeking {
+		t.Error("peek still open after another key")
+	}
+
+	// Nothing to show on an empty slot
+	m.selectedSlot = 15
+	m.handleHourlyKeys("v", "peek")
+	if m.peeking {
+		t.Error("peek opened on an empty slot")
+	}
+}
+
+func TestPeekRemLine(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	line := "REM Aug 25 2025 AT 10:00 DURATION 1:30 MSG Planning %b"
+	if err := os.WriteFile(file, []byte("# Work\n"+line+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{
+		width:         60,
+		height:        30,
+		timeIncrement: 60,
+		selectedDate:  time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+		topSlot:       8,
+		selectedSlot:  10,
+		config:        &config.Config{},
+		styles:        DefaultStyles(),
+		events: []remind.Event{{
+			Date:        time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+			Time:        timePtr(10, 0),
+			Duration:    durationPtr(90),
+			Description: "Planning",
+			Filename:    file,
+			LineNumber:  2,
+			Tags:        []string{"PRIVATE"},
+		}},
+	}
+
+	m.handleHourlyKeys("r", "toggle_rem_line")
+	if !m.peeking || !m.peekRemLine {
+		t.Fatal("toggle_rem_line didn't peek at the REM line")
+	}
+	peek := m.peekLines()
+	if len(peek) != 2 || ansi.Strip(peek[1]) != line {
+		t.Errorf("expected the REM line, got %q", peek)
+	}
+	if peek[1] == line {
+		t.Error("expected the REM line to be highlighted")
+	}
+
+	// Private events don't show theirs when presenting
+	m.presentationMode = true
+	if peek := strings.Join(m.peekLines(), "\n"); strings.Contains(peek, "Planning") {
+		t.Errorf("private REM line shown when presenting:\n%s", peek)
+	}
+
+	m.handleHourlyKeys("r", "toggle_rem_line")
+	if !m.peeking || m.peekRemLine {
+		t.Error("expected toggling again to peek at the event")
+	}
+}
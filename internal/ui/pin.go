@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// pinKey identifies a pinned occurrence of an event
+func pinKey(event remind.Event) string {
+	return fmt.Sprintf("%s@%s", event.ID, event.Date.Format("2006-01-02"))
+}
+
+// isPinned reports whether the occurrence of event is pinned
+func (m *Model) isPinned(event remind.Event) bool {
+	for _, pinned := range m.pinned {
+		if pinKey(pinned) == pinKey(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// togglePins pins the selected events to the sidebar, or unpins them when
+// they all are pinned already
+func (m *Model) togglePins() {
+	events := m.selectedEvents()
+	if len(events) == 0 {
+		m.showMessage("No reminder selected")
+		return
+	}
+
+	unpin := true
+	for _, event := range events {
+		if !m.isPinned(event) {
+			unpin = false
+		}
+	}
+	if unpin {
+		kept := m.pinned[:0]
+		for _, pinned := range m.pinned {
+			if !containsPin(events, pinned) {
+				kept = append(kept, pinned)
+			}
+		}
+		m.pinned = kept
+		m.showMessage(fmt.Sprintf("Unpinned %s", m.displayEvent(events[0]).Description))
+	} else {
+		for _, event := range events {
+			if !m.isPinned(event) {
+				m.pinned = append(m.pinned, event)
+			}
+		}
+		sort.SliceStable(m.pinned, func(i, j int) bool {
+			return m.pinned[i].Date.Before(m.pinned[j].Date)
+		})
+		m.showMessage(fmt.Sprintf("Pinned %s", m.displayEvent(events[0]).Description))
+	}
+	m.pinsRevision++
+
+	if err := m.savePins(); err != nil {
+		m.showError("Pins not saved", err)
+	}
+}
+
+// containsPin reports whether events holds the occurrence of pinned
+func containsPin(events []remind.Event, pinned remind.Event) bool {
+	for _, event := range events {
+		if pinKey(event) == pinKey(pinned) {
+			return true
+		}
+	}
+	return false
+}
+
+// savePins writes the pinned events to the pins file, removing the file
+// when none are pinned
+func (m *Model) savePins() error {
+	if m.pinsFile == "" {
+		return nil
+	}
+	if len(m.pinned) == 0 {
+		if err := os.Remove(m.pinsFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(m.pinned)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.pinsFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(m.pinsFile, data, 0600)
+}
+
+// restorePins reads the pinned events back from the pins file
+func (m *Model) restorePins() {
+	if m.pinsFile == "" {
+		return
+	}
+	data, err := os.ReadFile(m.pinsFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			m.showError("Pins not restored", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &m.pinned); err != nil {
+		m.showError("Pins not restored", err)
+	}
+	m.pinsRevision++
+}
+
+// pinnedLines renders the pinned events for the sidebar in width cells, as
+// their date and description colored like countdowns by the days left.
+// Those past are dimmed.
+func (m *Model) pinnedLines(now time.Time, width int) []string {
+	if len(m.pinned) == 0 {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	lines := []string{m.styles.Header.Render("Pinned")}
+	for _, event := range m.pinned {
+		date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, now.Location())
+		days := int(math.Round(date.Sub(today).Hours() / 24))
+
+		line := event.Date.Format("Jan 2") + " " + m.displayEvent(event).Description
+		if len(line) > width {
+			line = line[:width-3] + "..."
+		}
+		if days < 0 {
+			lines = append(lines, m.styles.Help.Render(line))
+		} else {
+			lines = append(lines, m.countdownStyle(days).Render(line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestTogglePin(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "urd", "pinned.json")
+	newModel := func() *Model {
+		return &Model{
+			timeIncrement: 60,
+			selectedDate:  time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+			selectedSlot:  10,
+			config:        &config.Config{CountdownWarning: 7, CountdownUrgent: 2},
+			styles:        defaultStyles(),
+			pinsFile:      file,
+			events: []remind.Event{{
+				ID:          "file:3",
+				Date:        time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local),
+				Time:        timePtr(10, 0),
+				Description: "Tax filing deadline",
+			}},
+		}
+	}
+
+	m := newModel()
+	m.handleHourlyKeys("*", "toggle_pin")
+	if len(m.pinned) != 1 || m.message != "Pinned Tax filing deadline" {
+		t.Fatalf("expected the event pinned, got %+v, %q", m.pinned, m.message)
+	}
+
+	// Started again and browsing elsewhere, it's still listed
+	restarted := newModel()
+	restarted.restorePins()
+	restarted.selectedDate = time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local)
+	lines := restarted.pinnedLines(time.Date(2025, 8, 20, 9, 0, 0, 0, time.Local), 30)
+	if len(lines) != 2 || ansi.Strip(lines[0]) != "Pinned" || ansi.Strip(lines[1]) != "Aug 25 Tax filing deadline" {
+		t.Errorf("unexpected pinned lines %q", lines)
+	}
+	if sidebar := ansi.Strip(restarted.renderSidebar(40)); !strings.Contains(sidebar, "Aug 25 Tax filing deadline") {
+		t.Errorf("expected the pinned event in the sidebar:\n%s", sidebar)
+	}
+
+	// Pressed again on the event, it's unpinned and nothing is kept
+	m.handleHourlyKeys("*", "toggle_pin")
+	if len(m.pinned) != 0 || m.message != "Unpinned Tax filing deadline" {
+		t.Errorf("expected the event unpinned, got %+v, %q", m.pinned, m.message)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("expected the pins file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// planDays is how many days ahead, starting today, the planner books tasks into
+const planDays = 7
+
+// estimateRegex matches a ~2h style estimate in a description
+var estimateRegex = regexp.MustCompile(`(^|\s)~(\d+(?:\.\d+)?h(?:\d+m)?|\d+m)\b`)
+
+// taskEstimate returns how long a task is estimated to take, from a ~2h in
+// its description or an est:2h tag
+func taskEstimate(event remind.Event) (time.Duration, bool) {
+	var estimate string
+	if matches := estimateRegex.FindStringSubmatch(event.Description); matches != nil {
+		estimate = matches[2]
+	}
+	for _, tag := range event.Tags {
+		if value, ok := strings.CutPrefix(strings.ToLower(tag), "est:"); ok {
+			estimate = value
+		}
+	}
+	if estimate == "" {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(estimate)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// placement is where the planner proposes to do a task
+type placement struct {
+	event    remind.Event
+	start    time.Time
+	estimate time.Duration
+}
+
+// planTasks books each untimed one-off task with an estimate into the
+// earliest free time, within working hours, from now until planDays ahead.
+// Higher priority tasks and those due sooner are placed first. Tasks that
+// don't fit are returned separately.
+func (m *Model) planTasks(events []remind.Event, now time.Time) ([]placement, []remind.Event) {
+	var tasks []remind.Event
+	for _, event := range events {
+		if event.Time != nil || event.IsRepeating {
+			continue
+		}
+		if _, ok := taskEstimate(event); ok {
+			tasks = append(tasks, event)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Priority != tasks[j].Priority {
+			return tasks[i].Priority > tasks[j].Priority
+		}
+		return eventBefore(tasks[i], tasks[j])
+	})
+
+	// Booked tasks take up time like any other event
+	busy := append([]remind.Event{}, events...)
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var placements []placement
+	var unplaced []remind.Event
+	for _, task := range tasks {
+		estimate, _ := taskEstimate(task)
+
+		placed := false
+		for day := 0; day < planDays && !placed; day++ {
+			openings := m.openings(busy, today.AddDate(0, 0, day), now, estimate)
+			if len(openings) == 0 {
+				continue
+			}
+
+			start := openings[0]
+			placements = append(placements, placement{task, start, estimate})
+			busy = append(busy, remind.Event{Time: &start, Duration: &estimate})
+			placed = true
+		}
+		if !placed {
+			unplaced = append(unplaced, task)
+		}
+	}
+	return placements, unplaced
+}
+
+// startPlan finds the estimated tasks of the coming week and previews where
+// they would go
+func (m *Model) startPlan(now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := m.source.GetEvents(today, today.AddDate(0, 0, planDays-1))
+	if err != nil {
+		return err
+	}
+	_, events = splitSpecials(events)
+
+	m.planPlacements, m.planUnplaced = m.planTasks(events, now)
+	if len(m.planPlacements) == 0 && len(m.planUnplaced) == 0 {
+		return fmt.Errorf("no untimed tasks with an estimate (~2h or TAG est:2h) this week")
+	}
+	m.mode = ViewPlan
+	return nil
+}
+
+// applyPlan writes the previewed placements as AT and DURATION clauses
+func (m *Model) applyPlan() error {
+	scheduled := 0
+	for i, p := range m.planPlacements {
+		updater, err := m.eventUpdater(p.event)
+		joined := 0
+		if err == nil {
+			day := time.Date(p.start.Year(), p.start.Month(), p.start.Day(), 0, 0, 0, 0, p.start.Location())
+			start, estimate := p.start, p.estimate
+			joined, err = rescheduleEvent(updater, p.event, day, &start, &estimate)
+		}
+		if err != nil {
+			return fmt.Errorf("scheduled %d of %d tasks, %s: %w", scheduled, len(m.planPlacements), p.event.Description, err)
+		}
+		for j := i + 1; j < len(m.planPlacements); j++ {
+			shiftLine(&m.planPlacements[j].event, p.event, joined)
+		}
+		event := p.event
+		m.runHook(HookEventEdited, &event)
+		scheduled++
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestTaskEstimate(t *testing.T) {
+	tests := []struct {
+		event remind.Event
+		want  time.Duration
+	}{
+		{remind.Event{Description: "Write report ~2h"}, 2 * time.Hour},
+		{remind.Event{Description: "~90m review"}, 90 * time.Minute},
+		{remind.Event{Description: "Slides ~1h30m"}, 90 * time.Minute},
+		{remind.Event{Description: "Taxes ~1.5h"}, 90 * time.Minute},
+		{remind.Event{Description: "Refactor", Tags: []string{"work", "est:3h"}}, 3 * time.Hour},
+		{remind.Event{Description: "Approx~2h"}, 0},
+		{remind.Event{Description: "Call ~2hours"}, 0},
+		{remind.Event{Description: "Nothing"}, 0},
+	}
+
+	for _, tt := range tests {
+		got, ok := taskEstimate(tt.event)
+		if ok != (tt.want > 0) || got != tt.want {
+			t.Errorf("taskEstimate(%q, %v) = %v, %v; want %v", tt.event.Description, tt.event.Tags, got, ok, tt.want)
+		}
+	}
+}
+
+func TestPlanTasks(t *testing.T) {
+	monday := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(day, hour int) *time.Time {
+		tm := monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour)
+		return &tm
+	}
+	allDay := 8 * time.Hour
+	morning := 3 * time.Hour
+
+	m := &Model{config: &config.Config{WorkStart: 9 * time.Hour, WorkEnd: 17 * time.Hour}}
+	events := []remind.Event{
+		{ID: "busy-1", Date: monday, Time: at(0, 9), Duration: &morning},
+		{ID: "busy-2", Date: monday.AddDate(0, 0, 1), Time: at(1, 9), Duration: &allDay},
+		{ID: "task-1", Date: monday, Description: "Write report ~4h"},
+		{ID: "task-2", Date: monday, Description: "Urgent fix ~2h", Priority: remind.PriorityHigh},
+		{ID: "task-3", Date: monday, Description: "Huge ~9h"},
+		{ID: "task-4", Date: monday, Description: "Weekly ~1h", IsRepeating: true},
+		{ID: "note", Date: monday, Description: "No estimate"},
+	}
+
+	placements, unplaced := m.planTasks(events, *at(0, 8))
+
+	want := map[string]string{
+		"task-2": "Mon 12:00", // Highest priority goes first
+		"task-1": "Wed 09:00", // Only 14:00-17:00 is left Monday, Tuesday is full
+	}
+	if len(placements) != len(want) {
+		t.Fatalf("expected %d placements, got %+v", len(want), placements)
+	}
+	for _, p := range placements {
+		if got := p.start.Format("Mon 15:04"); got != want[p.event.ID] {
+			t.Errorf("%s placed at %s, want %s", p.event.ID, got, want[p.event.ID])
+		}
+	}
+	if len(unplaced) != 1 || unplaced[0].ID != "task-3" {
+		t.Errorf("expected task-3 not to fit, got %+v", unplaced)
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// prefetchIdle is how long no key must have been pressed before the next
+// month is prefetched
+const prefetchIdle = 2 * time.Second
+
+// prefetchMsg asks to prefetch the next month not loaded yet
+type prefetchMsg struct{}
+
+// prefetchedMsg brings the events of a month fetched in the background
+type prefetchedMsg struct {
+	seq    int // The horizon fetched for, see invalidateHorizon
+	month  time.Time
+	events []remind.Event
+	err    error
+}
+
+// prefetchCmd waits to prefetch the next month with prefetch_months, unless
+// that's already pending
+func (m *Model) prefetchCmd() tea.Cmd {
+	if m.config.PrefetchMonths <= 0 || m.prefetching {
+		return nil
+	}
+	m.prefetching = true
+	return tea.Tick(prefetchIdle, func(time.Time) tea.Msg {
+		return prefetchMsg{}
+	})
+}
+
+// handlePrefetch starts fetching the next month of prefetch_months that
+// isn't loaded yet, one at a time and only while no key is pressed. The
+// sources are read in the background, see handlePrefetched.
+func (m *Model) handlePrefetch() tea.Cmd {
+	m.prefetching = false
+	now := m.now()
+	if now.Sub(m.lastKeyInput) < prefetchIdle {
+		return m.prefetchCmd()
+	}
+
+	month, ok := m.nextPrefetchMonth(now)
+	if !ok {
+		return nil
+	}
+	m.prefetching = true
+	source, seq := m.source, m.horizonSeq
+	return func() tea.Msg {
+		events, err := source.GetEvents(month, month.AddDate(0, 1, -1))
+		return prefetchedMsg{seq: seq, month: month, events: events, err: err}
+	}
+}
+
+// handlePrefetched keeps a month fetched in the background and goes on with
+// the next. A month fetched before the sources changed is dropped and
+// fetched again. Prefetching stops once they are all loaded, and when a
+// source fails until the next change.
+func (m *Model) handlePrefetched(msg prefetchedMsg) tea.Cmd {
+	m.prefetching = false
+	if msg.seq != m.horizonSeq {
+		return m.prefetchCmd()
+	}
+	if msg.err != nil {
+		return nil
+	}
+	if m.horizon == nil {
+		m.horizon = make(map[time.Time][]remind.Event)
+	}
+	m.horizon[msg.month] = msg.events
+	return m.prefetchCmd()
+}
+
+// nextPrefetchMonth returns the first day of the first month from now's on
+// that isn't prefetched, reporting false when prefetch_months all are
+func (m *Model) nextPrefetchMonth(now time.Time) (time.Time, bool) {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for i := 0; i < m.config.PrefetchMonths; i++ {
+		month := first.AddDate(0, i, 0)
+		if _, ok := m.horizon[month]; !ok {
+			return month, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// invalidateHorizon drops the prefetched months, after the sources changed,
+// and prefetches them anew
+func (m *Model) invalidateHorizon() tea.Cmd {
+	m.horizon = nil
+	m.horizonSeq++
+	return m.prefetchCmd()
+}
+
+// horizonEnd returns when the months prefetched in a row from now's end,
+// the start of the current month when it isn't
+func (m *Model) horizonEnd(now time.Time) time.Time {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	for {
+		if _, ok := m.horizon[month]; !ok {
+			return month
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+}
+
+// wideEvents returns the loaded events along with the prefetched ones
+// outside the weeks loaded, in no particular order
+func (m *Model) wideEvents() []remind.Event {
+	if len(m.horizon) == 0 {
+		return m.events
+	}
+	// The days loadEventsAround loads
+	start, end := m.eventsLoadedFor.AddDate(0, 0, -14), m.eventsLoadedFor.AddDate(0, 0, 14)
+
+	var beyond []remind.Event
+	for _, events := range m.horizon {
+		for _, event := range events {
+			if event.Date.Before(start) || event.Date.After(end) {
+				beyond = append(beyond, event)
+			}
+		}
+	}
+	beyond, _ = splitSpecials(beyond)
+	return append(slices.Clone(m.events), m.filterTagged(beyond)...)
+}
+
+// findPrefetched returns the first prefetched event after after with term
+// in its description or tags, as remind's FindNext does. ok is false when
+// there's none before the end of the prefetched months, where only remind
+// can tell.
+func (m *Model) findPrefetched(term string, after time.Time) (remind.Event, bool) {
+	now := m.now()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := m.horizonEnd(now)
+	if after.Before(first) || !after.Before(end) {
+		return remind.Event{}, false
+	}
+	term = strings.ToLower(term)
+
+	var found *remind.Event
+	var foundStart time.Time
+	for _, events := range m.horizon {
+		for i, event := range events {
+			start := event.Date
+			if event.Time != nil {
+				start = *event.Time
+			}
+			if !start.After(after) || !start.Before(end) || (found != nil && !start.Before(foundStart)) {
+				continue
+			}
+			matches := strings.Contains(strings.ToLower(event.Description), term)
+			for _, tag := range event.Tags {
+				matches = matches || strings.Contains(strings.ToLower(tag), term)
+			}
+			if matches {
+				found, foundStart = &events[i], start
+			}
+		}
+	}
+	if found == nil {
+		return remind.Event{}, false
+	}
+	return *found, true
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestPrefetch(t *testing.T) {
+	content := "REM Aug 25 2025 AT 9:00 MSG Planning\n" +
+		"REM Oct 20 2025 AT 14:00 MSG Dentist\n" +
+		"REM Dec 1 2025 AT 10:00 MSG Dentist again\n"
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m, _ := newFileModel(t, content, day.Add(8*time.Hour))
+	m.config = config.DefaultConfig()
+	m.config.PrefetchMonths = 3
+	m.loadEvents()
+
+	if _, ok := m.adjacentEvent(true); ok {
+		t.Fatal("expected nothing later in the weeks loaded")
+	}
+
+	// Not while keys are pressed
+	m.lastKeyInput = m.now()
+	if cmd := m.prefetchCmd(); cmd == nil {
+		t.Fatal("expected prefetching to start")
+	}
+	m.handlePrefetch()
+	if len(m.horizon) != 0 {
+		t.Fatal("expected nothing prefetched right after a key press")
+	}
+
+	// A month at a time once idle, in the background, stopping after the
+	// third
+	m.lastKeyInput = m.now().Add(-time.Minute)
+	for i := range 3 {
+		fetch := m.handlePrefetch()
+		if fetch == nil {
+			t.Fatal("expected prefetching to go on")
+		}
+		if len(m.horizon) != i {
+			t.Fatal("expected the month kept only once fetched")
+		}
+		if m.handlePrefetched(fetch().(prefetchedMsg)) == nil {
+			t.Fatal("expected the next month prefetched")
+		}
+		m.prefetching = false
+	}
+	if cmd := m.handlePrefetch(); cmd != nil || len(m.horizon) != 3 {
+		t.Fatalf("expected prefetching to stop after 3 months, got %d", len(m.horizon))
+	}
+
+	event, ok := m.adjacentEvent(true)
+	if !ok || event.Description != "Dentist" {
+		t.Errorf("expected the dentist in October next, got %+v", event)
+	}
+	event, ok = m.findPrefetched("dentist", day)
+	if !ok || !sameDay(event.Date, time.Date(2025, 10, 20, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected the dentist found in October, got %+v", event)
+	}
+	// December is beyond the prefetched months, where remind has to look
+	if _, ok := m.findPrefetched("dentist", time.Date(2025, 10, 21, 0, 0, 0, 0, time.Local)); ok {
+		t.Error("expected nothing found past the prefetched months")
+	}
+
+	m.invalidateHorizon()
+	if m.horizon != nil {
+		t.Error("expected a change to drop the prefetched months")
+	}
+
+	// A month fetched while the sources change is dropped
+	m.prefetching = false
+	fetch := m.handlePrefetch()
+	m.invalidateHorizon()
+	if m.handlePrefetched(fetch().(prefetchedMsg)) == nil || m.horizon != nil {
+		t.Error("expected the month fetched before the change dropped and fetched again")
+	}
+}
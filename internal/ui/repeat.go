@@ -0,0 +1,32 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// repeatableActions are the actions repeat runs again: those acting on the
+// selection, as opposed to moving it or changing what's shown
+var repeatableActions = map[string]bool{
+	"new_timed": true, "new_untimed": true, "paste": true, "cut": true,
+	"cycle_status": true, "toggle_pin": true,
+	"new_template0": true, "new_template1": true, "new_template2": true,
+	"new_template3": true, "new_template4": true, "new_template5": true,
+	"new_template6": true, "new_template7": true, "new_template8": true,
+	"new_template9": true,
+}
+
+// handleRepeatable runs a schedule action, remembering it when it's one
+// repeat can run again, and runs the one remembered for repeat
+func (m *Model) handleRepeatable(key, action string) (tea.Model, tea.Cmd) {
+	if action == "repeat" {
+		if m.lastAction == "" {
+			m.showMessage("Nothing to repeat")
+			return m, nil
+		}
+		return m.handleHourlyKeys(m.lastActionKey, m.lastAction)
+	}
+	if repeatableActions[action] {
+		m.lastAction, m.lastActionKey = action, key
+	}
+	return m.handleHourlyKeys(key, action)
+}
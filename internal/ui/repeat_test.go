@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+)
+
+func TestRepeatLastAction(t *testing.T) {
+	content := "REM Aug 25 2025 AT 10:00 MSG Sync\n" +
+		"REM Aug 25 2025 AT 14:00 MSG Review\n"
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m, file := newFileModel(t, content, day.Add(8*time.Hour))
+	m.config = config.DefaultConfig()
+	m.loadEvents()
+
+	m.dispatchKey(tea.KeyPressMsg{}, ".", "repeat")
+	if m.message != "Nothing to repeat" {
+		t.Errorf("unexpected message %q", m.message)
+	}
+
+	// Moving doesn't count as an action to repeat
+	m.dispatchKey(tea.KeyPressMsg{}, "~", "cycle_status")
+	for range 4 {
+		m.dispatchKey(tea.KeyPressMsg{}, "j", "scroll_down")
+	}
+	m.dispatchKey(tea.KeyPressMsg{}, ".", "repeat")
+	if m.message != "Review: tentative" {
+		t.Errorf("expected the status change repeated on the review, got %q", m.message)
+	}
+
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 25 2025 AT 10:00 TAG status:tentative MSG Sync\n" +
+		"REM Aug 25 2025 AT 14:00 TAG status:tentative MSG Review\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// Daily review phases
+const (
+	reviewItems = iota // going through yesterday's untimed items
+	reviewPlan         // today's agenda and unplanned high-priority todos
+)
+
+// reviewTaskLength is how long todos scheduled from the review are booked for
+const reviewTaskLength = time.Hour
+
+// startReview loads yesterday's and today's reminders and begins the daily
+// review
+func (m *Model) startReview(now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := m.source.GetEvents(today.AddDate(0, 0, -1), today)
+	if err != nil {
+		return err
+	}
+	_, events = splitSpecials(events)
+	m.beginReview(events, now)
+	return nil
+}
+
+// beginReview starts the review of the given events, which cover yesterday
+// and today
+func (m *Model) beginReview(events []remind.Event, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+
+	m.reviewDay = today
+	m.reviewItems = nil
+	m.reviewAgenda = nil
+	for _, event := range events {
+		switch {
+		case sameDay(event.Date, today):
+			m.reviewAgenda = append(m.reviewAgenda, event)
+		case sameDay(event.Date, yesterday) && event.Time == nil && !event.IsRepeating:
+			// Repeating reminders come back anyway, so only one-off
+			// items are left over
+			m.reviewItems = append(m.reviewItems, event)
+		}
+	}
+	sort.SliceStable(m.reviewItems, func(i, j int) bool {
+		return eventBefore(m.reviewItems[i], m.reviewItems[j])
+	})
+
+	m.reviewPhase = reviewItems
+	m.mode = ViewReview
+	if len(m.reviewItems) == 0 {
+		m.planReview(now)
+	}
+}
+
+// planReview moves on to planning today once yesterday's items are done
+func (m *Model) planReview(now time.Time) {
+	m.reviewPhase = reviewPlan
+	sort.SliceStable(m.reviewAgenda, func(i, j int) bool {
+		return eventBefore(m.reviewAgenda[i], m.reviewAgenda[j])
+	})
+
+	m.reviewTodos = nil
+	for _, event := range m.reviewAgenda {
+		if event.Time == nil && event.Priority == remind.PriorityHigh && !event.IsRepeating {
+			m.reviewTodos = append(m.reviewTodos, event)
+		}
+	}
+	if m.selectedReviewTodo >= len(m.reviewTodos) {
+		m.selectedReviewTodo = 0
+	}
+	m.reviewOpenings = m.openings(m.reviewAgenda, m.reviewDay, now, reviewTaskLength)
+	m.selectedOpening = 0
+}
+
+// reviewItem applies done, defer or delete to the current item of
+// yesterday's review
+func (m *Model) reviewItem(action string, now time.Time) error {
+	if len(m.reviewItems) == 0 {
+		return nil
+	}
+	event := m.reviewItems[0]
+
+	switch action {
+	case "done":
+		updater, err := m.eventUpdater(event)
+		if err != nil {
+			return err
+		}
+		if err := updater.CompleteEvent(event, now); err != nil {
+			return err
+		}
+		m.runHook(HookEventEdited, &event)
+		m.showMessage(fmt.Sprintf("Done: %s", m.displayEvent(event).Description))
+
+	case "defer":
+		updater, err := m.eventUpdater(event)
+		if err != nil {
+			return err
+		}
+		joined, err := rescheduleEvent(updater, event, m.reviewDay, nil, nil)
+		if err != nil {
+			return err
+		}
+		m.shiftReview(event, joined)
+		event.Date = m.reviewDay
+		m.reviewAgenda = append(m.reviewAgenda, event)
+		m.runHook(HookEventEdited, &event)
+		m.showMessage(fmt.Sprintf("Deferred to today: %s", m.displayEvent(event).Description))
+
+	case "delete":
+		if err := m.removeEvent(event); err != nil {
+			return err
+		}
+		// Later reminders in the file moved up a line
+		m.shiftReview(event, 1)
+		m.showMessage(fmt.Sprintf("Deleted: %s", m.displayEvent(event).Description))
+	}
+
+	m.reviewItems = m.reviewItems[1:]
+	if len(m.reviewItems) == 0 {
+		m.planReview(now)
+	}
+	return nil
+}
+
+// scheduleReviewTodo books the selected todo into the selected opening
+func (m *Model) scheduleReviewTodo(now time.Time) error {
+	if m.selectedReviewTodo >= len(m.reviewTodos) {
+		return fmt.Errorf("no todo to schedule")
+	}
+	if m.selectedOpening >= len(m.reviewOpenings) {
+		return fmt.Errorf("no free time left today")
+	}
+
+	event := m.reviewTodos[m.selectedReviewTodo]
+	start := m.reviewOpenings[m.selectedOpening]
+	length := reviewTaskLength
+
+	updater, err := m.eventUpdater(event)
+	if err != nil {
+		return err
+	}
+	joined, err := rescheduleEvent(updater, event, m.reviewDay, &start, &length)
+	if err != nil {
+		return err
+	}
+	m.shiftReview(event, joined)
+
+	// Book it in the agenda so the next suggestions take it into account
+	for i := range m.reviewAgenda {
+		if m.reviewAgenda[i].ID == event.ID {
+			m.reviewAgenda[i].Time = &start
+			m.reviewAgenda[i].Duration = &length
+		}
+	}
+	m.runHook(HookEventEdited, &event)
+	m.showMessage(fmt.Sprintf("Scheduled %s at %s", m.displayEvent(event).Description, start.Format("15:04")))
+	m.planReview(now)
+	return nil
+}
+
+// eventUpdater returns what changes the event in place
+func (m *Model) eventUpdater(event remind.Event) (remind.EventUpdater, error) {
+	if !m.eventCapabilities(event).Edit {
+		return nil, fmt.Errorf("%s events are read-only", event.Source)
+	}
+	if updater, ok := m.source.(remind.EventUpdater); ok {
+		return updater, nil
+	}
+	if m.remindClient == nil {
+		return nil, fmt.Errorf("remind client not available")
+	}
+	return m.remindClient, nil
+}
+
+// shiftLines moves events defined after changed in the same file up by n
+// lines, the lines changed lost
+func shiftLines(events []remind.Event, changed remind.Event, n int) []remind.Event {
+	for i := range events {
+		shiftLine(&events[i], changed, n)
+	}
+	return events
+}
+
+// shiftLine moves event up by n lines if it's defined after changed in the
+// same file
+func shiftLine(event *remind.Event, changed remind.Event, n int) {
+	if event.Filename == changed.Filename && event.LineNumber > changed.LineNumber {
+		event.LineNumber -= n
+	}
+}
+
+// shiftReview moves the events of the review defined after changed up by the
+// n lines it lost
+func (m *Model) shiftReview(changed remind.Event, n int) {
+	m.reviewItems = shiftLines(m.reviewItems, changed, n)
+	m.reviewAgenda = shiftLines(m.reviewAgenda, changed, n)
+	m.reviewTodos = shiftLines(m.reviewTodos, changed, n)
+}
+
+// rescheduleEvent moves event with updater, returning how many lines its
+// reminder lost, as the lines continuing it are joined into one
+func rescheduleEvent(updater remind.EventUpdater, event remind.Event, date time.Time, at *time.Time, duration *time.Duration) (int, error) {
+	joined := 0
+	if count, err := remind.SourceLineCount(event); err == nil {
+		joined = count - 1
+	}
+	if err := updater.RescheduleEvent(event, date, at, duration); err != nil {
+		return 0, err
+	}
+	return joined, nil
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestOpenings(t *testing.T) {
+	at := func(hour, minute int) *time.Time {
+		tm := time.Date(2025, 8, 25, hour, minute, 0, 0, time.Local)
+		return &tm
+	}
+	hour := time.Hour
+	half := 30 * time.Minute
+
+	m := &Model{config: &config.Config{WorkStart: 9 * time.Hour, WorkEnd: 17 * time.Hour}}
+	events := []remind.Event{
+		{Time: at(9, 0), Duration: &hour},
+		{Time: at(9, 30), Duration: &hour},  // Overlaps the first
+		{Time: at(11, 0), Duration: &half},  // Leaves 10:30-11:00, too short
+		{Time: at(12, 0)},                   // No duration, doesn't block
+		{Time: at(13, 45), Duration: &hour}, // 11:30-13:45 is free
+		{Time: at(15, 45), Duration: &hour}, // Ends 16:45, leaving 15 minutes
+		{Date: *at(0, 0), Description: "Untimed"},
+	}
+
+	var got []string
+	for _, start := range m.openings(events, *at(0, 0), *at(8, 0), time.Hour) {
+		got = append(got, start.Format("15:04"))
+	}
+	if expected := []string{"11:30", "14:45"}; len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("openings = %v, want %v", got, expected)
+	}
+
+	// Openings start no earlier than now, on a quarter hour
+	got = nil
+	for _, start := range m.openings(events, *at(0, 0), *at(11, 40), time.Hour) {
+		got = append(got, start.Format("15:04"))
+	}
+	if expected := []string{"11:45", "14:45"}; len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("openings after 11:40 = %v, want %v", got, expected)
+	}
+}
+
+func TestDailyReview(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.rem")
+	content := "REM Aug 24 2025 MSG Call plumber\nREM Aug 24 2025 MSG Buy milk\nREM Aug 24 2025 MSG Old note\nREM Aug 25 2025 PRIORITY 9000 MSG Write report\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	yesterday := time.Date(2025, 8, 24, 0, 0, 0, 0, time.Local)
+	today := yesterday.AddDate(0, 0, 1)
+	standup := today.Add(9 * time.Hour)
+	half := 30 * time.Minute
+
+	client := remind.NewClient()
+	client.SetFiles([]string{file})
+	m := &Model{
+		config:       &config.Config{WorkStart: 9 * time.Hour, WorkEnd: 17 * time.Hour},
+		remindClient: client,
+	}
+
+	now := today.Add(8 * time.Hour)
+	m.beginReview([]remind.Event{
+		{ID: "evt-1", Date: yesterday, Description: "Call plumber", Filename: file, LineNumber: 1},
+		{ID: "evt-2", Date: yesterday, Description: "Buy milk", Filename: file, LineNumber: 2},
+		{ID: "evt-3", Date: yesterday, Description: "Old note", Filename: file, LineNumber: 3},
+		{ID: "evt-4", Date: yesterday, Description: "Weekly chores", IsRepeating: true},
+		{ID: "evt-5", Date: today, Description: "Write report", Priority: remind.PriorityHigh, Filename: file, LineNumber: 4},
+		{ID: "evt-6", Date: today, Time: &standup, Duration: &half, Description: "Standup", IsRepeating: true},
+	}, now)
+
+	if m.mode != ViewReview || m.reviewPhase != reviewItems || len(m.reviewItems) != 3 {
+		t.Fatalf("expected 3 items to review, got %d (phase %d)", len(m.reviewItems), m.reviewPhase)
+	}
+
+	for _, action := range []string{"done", "defer", "delete"} {
+		if err := m.reviewItem(action, now); err != nil {
+			t.Fatalf("%s: %v", action, err)
+		}
+	}
+
+	if m.reviewPhase != reviewPlan {
+		t.Fatal("expected to move on to planning today")
+	}
+	if len(m.reviewTodos) != 1 || m.reviewTodos[0].ID != "evt-5" {
+		t.Fatalf("expected the high-priority todo, got %+v", m.reviewTodos)
+	}
+	if len(m.reviewOpenings) == 0 || m.reviewOpenings[0].Format("15:04") != "09:30" {
+		t.Fatalf("expected the first opening after standup, got %v", m.reviewOpenings)
+	}
+
+	if err := m.scheduleReviewTodo(now); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.reviewTodos) != 0 {
+		t.Error("scheduled todo still unplanned")
+	}
+
+	data, _ := os.ReadFile(file)
+	expected := "# DONE 2025-08-25: REM Aug 24 2025 MSG Call plumber\nREM Aug 25 2025 MSG Buy milk\nREM Aug 25 2025 AT 09:30 DURATION 1:00 PRIORITY 9000 MSG Write report\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Size of a cell, and the colors of text and background without SGR
+// colors, in screenshots saved as SVG
+const (
+	svgCellWidth  = 8.4
+	svgCellHeight = 17
+	svgForeground = "#d0d0d0"
+	svgBackground = "#1c1c1c"
+)
+
+// screenshot saves the screen as it is to screenshot_dir, as text with its
+// ANSI escape sequences (shown with cat) and as an SVG image. The redacted
+// screenshot is rendered in presentation mode, whether it's on or not.
+func (m *Model) screenshot(redacted bool) {
+	if m.config.ReadOnly {
+		m.showError("Failed to save screenshot", errReplaying)
+		return
+	}
+	saved := m.presentationMode
+	if redacted {
+		m.presentationMode = true
+	}
+	screen := m.View()
+	m.presentationMode = saved
+
+	name := "urd-" + m.now().Format("20060102-150405")
+	if redacted {
+		name += "-redacted"
+	}
+	base := filepath.Join(m.config.ScreenshotDir, name)
+
+	if err := os.WriteFile(base+".ans", []byte(screen+"\n"), 0644); err != nil {
+		m.showError("Failed to save screenshot", err)
+		return
+	}
+	if err := os.WriteFile(base+".svg", []byte(screenSVG(screen, m.width, m.height)), 0644); err != nil {
+		m.showError("Failed to save screenshot", err)
+		return
+	}
+	m.showMessage(fmt.Sprintf("Saved %s.ans and %s.svg", base, name))
+}
+
+// cellStyle is the look of a cell on screen, from SGR escape sequences
+type cellStyle struct {
+	fg, bg    string // Colors as #rrggbb, empty for the default
+	bold      bool
+	faint     bool
+	italic    bool
+	underline bool
+	reverse   bool
+}
+
+// colors returns the text and background colors, swapped when reversed
+func (s cellStyle) colors() (string, string) {
+	fg, bg := s.fg, s.bg
+	if fg == "" {
+		fg = svgForeground
+	}
+	if bg == "" {
+		bg = svgBackground
+	}
+	if s.reverse {
+		return bg, fg
+	}
+	return fg, bg
+}
+
+// apply updates the style with the parameters of an SGR sequence
+func (s *cellStyle) apply(params string) {
+	var codes []int
+	for _, param := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		n, _ := strconv.Atoi(param)
+		codes = append(codes, n)
+	}
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+
+	// extended reads a 5;n or 2;r;g;b color following 38 or 48
+	extended := func(i int) (string, int) {
+		switch {
+		case i+2 < len(codes) && codes[i+1] == 5:
+			return xtermColor(codes[i+2]), i + 2
+		case i+4 < len(codes) && codes[i+1] == 2:
+			return fmt.Sprintf("#%02x%02x%02x", codes[i+2], codes[i+3], codes[i+4]), i + 4
+		}
+		return "", len(codes)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch code := codes[i]; {
+		case code == 0:
+			*s = cellStyle{}
+		case code == 1:
+			s.bold = true
+		case code == 2:
+			s.faint = true
+		case code == 3:
+			s.italic = true
+		case code == 4:
+			s.underline = true
+		case code == 7:
+			s.reverse = true
+		case code == 22:
+			s.bold, s.faint = false, false
+		case code == 23:
+			s.italic = false
+		case code == 24:
+			s.underline = false
+		case code == 27:
+			s.reverse = false
+		case code >= 30 && code <= 37:
+			s.fg = xtermColor(code - 30)
+		case code == 38:
+			s.fg, i = extended(i)
+		case code == 39:
+			s.fg = ""
+		case code >= 40 && code <= 47:
+			s.bg = xtermColor(code - 40)
+		case code == 48:
+			s.bg, i = extended(i)
+		case code == 49:
+			s.bg = ""
+		case code >= 90 && code <= 97:
+			s.fg = xtermColor(code - 90 + 8)
+		case code >= 100 && code <= 107:
+			s.bg = xtermColor(code - 100 + 8)
+		}
+	}
+}
+
+// xtermBasic are the first 16 colors of the xterm palette
+var xtermBasic = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// xtermColor returns one of the 256 xterm colors as #rrggbb
+func xtermColor(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return xtermBasic[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+// screenSVG draws the screen, with its colors and text attributes, as an
+// SVG image of width by height cells
+func screenSVG(screen string, width, height int) string {
+	lines := strings.Split(screen, "\n")
+	if height < len(lines) {
+		height = len(lines)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%d" font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="14">`+"\n",
+		float64(width)*svgCellWidth, height*svgCellHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgBackground)
+
+	var style cellStyle
+	for row, line := range lines {
+		col := 0
+		var run strings.Builder
+		runStart, runStyle := 0, style
+
+		// flush draws the text written in the same style since runStart
+		flush := func() {
+			defer func() {
+				run.Reset()
+				runStart, runStyle = col, style
+			}()
+			if run.Len() == 0 {
+				return
+			}
+			fg, bg := runStyle.colors()
+			x := float64(runStart) * svgCellWidth
+			y := row * svgCellHeight
+			if bg != svgBackground {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					x, y, float64(col-runStart)*svgCellWidth, svgCellHeight, bg)
+			}
+			if text := run.String(); strings.TrimSpace(text) != "" {
+				fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="%s" xml:space="preserve"%s>%s</text>`+"\n",
+					x, y+svgCellHeight-4, fg, runStyle.attributes(), html.EscapeString(text))
+			}
+		}
+
+		for i := 0; i < len(line); {
+			if line[i] == '\x1b' {
+				seq, n := escapeSequence(line[i:])
+				if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+					flush()
+					style.apply(seq[2 : len(seq)-1])
+					runStyle = style
+				}
+				i += n
+				continue
+			}
+			cluster, _, cells, _ := ansi.FirstGraphemeCluster(line[i:], -1)
+			run.WriteString(cluster)
+			col += cells
+			i += len(cluster)
+		}
+		flush()
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// attributes returns the SVG attributes for bold, faint, italic and
+// underlined text
+func (s cellStyle) attributes() string {
+	var attrs string
+	if s.bold {
+		attrs += ` font-weight="bold"`
+	}
+	if s.faint {
+		attrs += ` opacity="0.6"`
+	}
+	if s.italic {
+		attrs += ` font-style="italic"`
+	}
+	if s.underline {
+		attrs += ` text-decoration="underline"`
+	}
+	return attrs
+}
+
+// escapeSequence returns the escape sequence s starts with and its length:
+// a CSI sequence up to its final byte, an OSC, DCS or APC string up to its
+// terminator, or an escape and the byte after it
+func escapeSequence(s string) (string, int) {
+	if len(s) < 2 {
+		return s, len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return s[:i+1], i + 1
+			}
+		}
+	case ']', 'P', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return s[:i+1], i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return s[:i+2], i + 2
+			}
+		}
+	default:
+		return s[:2], 2
+	}
+	return s, len(s)
+}
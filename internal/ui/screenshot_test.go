@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestScreenshot(t *testing.T) {
+	m := snapshotModel()
+	m.width, m.height = 100, 24
+	m.config.ScreenshotDir = t.TempDir()
+	m.events[1].Tags = []string{remind.PrivateTag}
+
+	m.screenshot(false)
+	m.screenshot(true)
+
+	for name, private := range map[string]bool{"urd-20250825-101700": true, "urd-20250825-101700-redacted": false} {
+		for _, ext := range []string{".ans", ".svg"} {
+			content, err := os.ReadFile(filepath.Join(m.config.ScreenshotDir, name+ext))
+			if err != nil {
+				t.Fatalf("screenshot not saved: %v", err)
+			}
+			if got := strings.Contains(string(content), "Quarterly"); got != private {
+				t.Errorf("%s%s shows the private event: %v, want %v", name, ext, got, private)
+			}
+		}
+	}
+	if m.presentationMode {
+		t.Error("presentation mode left on after the redacted screenshot")
+	}
+	if !strings.HasPrefix(m.message, "Saved ") {
+		t.Errorf("message = %q, want Saved ...", m.message)
+	}
+}
+
+func TestScreenSVG(t *testing.T) {
+	svg := screenSVG("\x1b[1;38;5;196;48;5;220mA&B\x1b[0m plain\n\x1b]8;;https://example.com\x1b\\日本\x1b[7mX\x1b[m", 10, 2)
+
+	for _, want := range []string{
+		`<rect x="0.0" y="0" width="25.2" height="17" fill="#ffd700"/>`,
+		`<text x="0.0" y="13" fill="#ff0000" xml:space="preserve" font-weight="bold">A&amp;B</text>`,
+		`<text x="25.2" y="13" fill="#d0d0d0" xml:space="preserve"> plain</text>`,
+		`<text x="0.0" y="30" fill="#d0d0d0" xml:space="preserve">日本</text>`,
+		`<rect x="33.6" y="17" width="8.4" height="17" fill="#d0d0d0"/>`,
+		`<text x="33.6" y="30" fill="#1c1c1c" xml:space="preserve">X</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG is missing %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestXtermColor(t *testing.T) {
+	tests := map[int]string{1: "#cd0000", 16: "#000000", 196: "#ff0000", 220: "#ffd700", 232: "#080808", 255: "#eeeeee"}
+	for n, want := range tests {
+		if got := xtermColor(n); got != want {
+			t.Errorf("xtermColor(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// errReplaying is returned for what a replay doesn't do: writing files,
+// running commands, opening URLs and copying to the clipboard
+var errReplaying = errors.New("disabled while replaying a session")
+
+// maxReplayGap is the longest a replay waits between two steps, however
+// long the pause was while recording
+const maxReplayGap = time.Second
+
+// Session is a run of urd recorded with --record to reproduce it with
+// --replay: the keys pressed and the window sizes in order, and what the
+// sources returned, with the text of events redacted
+type Session struct {
+	Started   time.Time               `json:"started"`
+	Steps     []SessionStep           `json:"steps"`
+	Responses []remind.SourceResponse `json:"responses"`
+}
+
+// SessionStep is a key pressed or a change of the window size
+type SessionStep struct {
+	After  time.Duration `json:"after"` // Since the previous step
+	Key    *tea.Key      `json:"key,omitempty"`
+	Width  int           `json:"width,omitempty"`
+	Height int           `json:"height,omitempty"`
+}
+
+// msg returns the message the step replays
+func (s SessionStep) msg() tea.Msg {
+	if s.Key != nil {
+		return tea.KeyPressMsg(*s.Key)
+	}
+	return tea.WindowSizeMsg{Width: s.Width, Height: s.Height}
+}
+
+// LoadSession reads a session recorded with --record
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("%s is not a recorded session: %w", path, err)
+	}
+	return &session, nil
+}
+
+// SessionRecorder records a session as the program runs, see Filter and
+// Record
+type SessionRecorder struct {
+	mu      sync.Mutex
+	session Session
+	last    time.Time
+}
+
+// NewSessionRecorder starts recording a session started at started, the
+// time of the clock the model runs on
+func NewSessionRecorder(started time.Time) *SessionRecorder {
+	return &SessionRecorder{
+		session: Session{Started: started},
+		last:    time.Now(),
+	}
+}
+
+// Filter records the keys pressed and the window sizes, as the filter of
+// the program (tea.WithFilter). What is typed into text inputs is redacted
+// like the events, keeping how many keys were typed and their width.
+func (r *SessionRecorder) Filter(model tea.Model, msg tea.Msg) tea.Msg {
+	var step SessionStep
+	switch msg := msg.(type) {
+	case tea.KeyPressMsg:
+		key := tea.Key(msg)
+		if m, ok := model.(*Model); ok && m.inTextInput() && key.Text != "" {
+			key = redactKey(key)
+		}
+		step.Key = &key
+	case tea.WindowSizeMsg:
+		step.Width, step.Height = msg.Width, msg.Height
+	default:
+		return msg
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	step.After, r.last = now.Sub(r.last), now
+	r.session.Steps = append(r.session.Steps, step)
+	return msg
+}
+
+// Record keeps what a source returned with the text of its events
+// redacted, as the recorder of the source (remind.RecordingSource)
+func (r *SessionRecorder) Record(response remind.SourceResponse) {
+	events := make([]remind.Event, len(response.Events))
+	for i, event := range response.Events {
+		events[i] = redactEvent(event)
+	}
+	response.Events = events
+	if response.Error != "" {
+		response.Error = redact(response.Error)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.session.Responses = append(r.session.Responses, response)
+}
+
+// Save writes the session recorded so far to path
+func (r *SessionRecorder) Save(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.session, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// redactKey hides which printable key was typed, keeping its width
+func redactKey(key tea.Key) tea.Key {
+	text := redact(key.Text)
+	code, _ := utf8.DecodeRuneInString(text)
+	return tea.Key{Code: code, Text: text}
+}
+
+// redactEvent hides what an event says and where it comes from, keeping
+// what its layout depends on: its times, the width of its text, its status
+// and priority
+func redactEvent(event remind.Event) remind.Event {
+	event.ID = fmt.Sprintf("%x", sha256.Sum256([]byte(event.ID)))[:12]
+	event.Description = redact(event.Description)
+	event.Body = redact(event.Body)
+	event.Location = redact(event.Location)
+	event.Filename = redact(event.Filename)
+	tags := make([]string, len(event.Tags))
+	for i, tag := range event.Tags {
+		// Tentative and cancelled events look different
+		if strings.HasPrefix(strings.ToLower(tag), "status:") {
+			tags[i] = tag
+		} else {
+			tags[i] = redact(tag)
+		}
+	}
+	event.Tags = tags
+	return event
+}
+
+// redact replaces the letters and digits of text, keeping its width, so
+// layout bugs reproduce without revealing the calendar
+func redact(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) && ansi.StringWidth(string(r)) == 2:
+			b.WriteRune('Ｘ')
+		case unicode.IsLetter(r):
+			b.WriteRune('x')
+		case unicode.IsDigit(r):
+			b.WriteRune('0')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// replayedMsg is a message of a session replayed, telling it apart from
+// the terminal's
+type replayedMsg struct {
+	msg tea.Msg
+}
+
+// SessionPlayer replays a session into a program, see Filter and Play
+type SessionPlayer struct {
+	session *Session
+	done    atomic.Bool
+}
+
+// NewSessionPlayer returns a player of session
+func NewSessionPlayer(session *Session) *SessionPlayer {
+	return &SessionPlayer{session: session}
+}
+
+// Filter lets the replayed keys and window sizes through in place of the
+// terminal's, as the filter of the program (tea.WithFilter). Once the
+// replay is over keys work again, to look around and quit; the window
+// keeps the size recorded. Ctrl+C quits at any time.
+func (p *SessionPlayer) Filter(model tea.Model, msg tea.Msg) tea.Msg {
+	switch msg := msg.(type) {
+	case replayedMsg:
+		return msg.msg
+	case tea.WindowSizeMsg:
+		return nil
+	case tea.KeyPressMsg:
+		if !p.done.Load() && msg.String() != "ctrl+c" {
+			return nil
+		}
+	}
+	return msg
+}
+
+// Play sends the steps of the session with send, like the program's Send,
+// pausing between them as when recording up to maxReplayGap
+func (p *SessionPlayer) Play(send func(tea.Msg)) {
+	for _, step := range p.session.Steps {
+		time.Sleep(min(step.After, maxReplayGap))
+		send(replayedMsg{msg: step.msg()})
+	}
+	p.done.Store(true)
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestSessionRecordAndReplay(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	file := filepath.Join(dir, "reminders.rem")
+	if err := os.WriteFile(file, []byte("REM Aug 25 2025 AT 10:00 DURATION 1:00 TAG health MSG Dentist appointment\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := remind.NewClient()
+	client.RemindPath = "urd-test-no-remind"
+	client.Clock = remind.FixedClock(time.Date(2025, 8, 25, 8, 0, 0, 0, time.Local))
+	client.SetFiles([]string{file})
+
+	recorder := NewSessionRecorder(client.Clock.Now())
+	client.SetRecorder(recorder.Record)
+	recorded := NewModelWithRemind(config.DefaultConfig(), client, client)
+	start := recorded.selectedSlot
+	for _, msg := range []tea.Msg{
+		tea.WindowSizeMsg{Width: 61, Height: 17},
+		tea.KeyPressMsg{Code: 'j', Text: "j"},
+		tea.KeyPressMsg{Code: 'j', Text: "j"},
+	} {
+		recorded.Update(recorder.Filter(recorded, msg))
+	}
+
+	if recorded.selectedSlot != start+2 {
+		t.Fatalf("expected j to move down two slots, from %d to %d", start, recorded.selectedSlot)
+	}
+
+	path := filepath.Join(dir, "session.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, private := range []string{"Dentist", "health", "reminders.rem"} {
+		if strings.Contains(string(data), private) {
+			t.Errorf("expected %q redacted from the session:\n%s", private, data)
+		}
+	}
+
+	session, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Steps) != 3 || len(session.Responses) == 0 {
+		t.Fatalf("expected 3 steps and the events loaded, got %+v", session)
+	}
+
+	replayClient := remind.NewClient()
+	replayClient.Clock = remind.FixedClock(session.Started)
+	replayed := NewModelWithRemind(config.DefaultConfig(), remind.NewReplaySource(session.Responses), replayClient)
+	player := NewSessionPlayer(session)
+	if player.Filter(replayed, tea.KeyPressMsg{Code: 'k', Text: "k"}) != nil {
+		t.Error("expected the terminal's keys ignored while replaying")
+	}
+	player.Play(func(msg tea.Msg) {
+		if msg = player.Filter(replayed, msg); msg != nil {
+			replayed.Update(msg)
+		}
+	})
+
+	if replayed.width != 61 || replayed.height != 17 || replayed.selectedSlot != recorded.selectedSlot {
+		t.Errorf("expected the replay to end as recorded, got %dx%d at slot %d, want slot %d",
+			replayed.width, replayed.height, replayed.selectedSlot, recorded.selectedSlot)
+	}
+	if len(replayed.events) != 1 || replayed.events[0].Description != "xxxxxxx xxxxxxxxxxx" {
+		t.Errorf("expected the redacted event, got %+v", replayed.events)
+	}
+	if player.Filter(replayed, tea.KeyPressMsg{Code: 'k', Text: "k"}) == nil {
+		t.Error("expected keys to work once the replay is over")
+	}
+}
+
+func TestRecordRedactsTypedText(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := NewModelWithRemind(config.DefaultConfig(), &watchedSource{}, nil)
+	recorder := NewSessionRecorder(m.now())
+
+	recorder.Filter(m, tea.KeyPressMsg{Code: '/', Text: "/"})
+	m.mode = ViewSearch
+	for _, r := range "Dr 7" {
+		recorder.Filter(m, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	recorder.Filter(m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	var typed string
+	for _, step := range recorder.session.Steps {
+		typed += step.Key.String()
+	}
+	if typed != "/xxspace0enter" {
+		t.Errorf("expected the search redacted, got %q", typed)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := redact("Call 駅 at 9, ok?"); got != "xxxx Ｘ xx 0, xx?" {
+		t.Errorf("redact = %q", got)
+	}
+	event := redactEvent(remind.Event{ID: "a.rem:3", Tags: []string{"Status:Tentative", "work"}})
+	if event.ID == "a.rem:3" || event.Tags[0] != "Status:Tentative" || event.Tags[1] != "xxxx" {
+		t.Errorf("redactEvent = %+v", event)
+	}
+}
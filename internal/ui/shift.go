@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// shiftDelta is how far a shift moves events: whole days, keeping their
+// time of day, and a length of time
+type shiftDelta struct {
+	days   int
+	length time.Duration
+}
+
+// shiftRe matches a shift like +1h, -30m, +1d or +1w2d
+var shiftRe = regexp.MustCompile(`^([+-]?)((?:\d+[wdhm])+)$`)
+
+// shiftPartRe matches each amount of a shift
+var shiftPartRe = regexp.MustCompile(`(\d+)([wdhm])`)
+
+// parseShift parses how far to shift events, like +1h, -30m, +1d or +1d2h
+func parseShift(value string) (shiftDelta, error) {
+	matches := shiftRe.FindStringSubmatch(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	if matches == nil {
+		return shiftDelta{}, fmt.Errorf("invalid shift: %q, expected e.g. +1h, -30m or +1d", value)
+	}
+	var delta shiftDelta
+	for _, part := range shiftPartRe.FindAllStringSubmatch(matches[2], -1) {
+		n, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "w":
+			delta.days += 7 * n
+		case "d":
+			delta.days += n
+		case "h":
+			delta.length += time.Duration(n) * time.Hour
+		case "m":
+			delta.length += time.Duration(n) * time.Minute
+		}
+	}
+	if matches[1] == "-" {
+		delta.days, delta.length = -delta.days, -delta.length
+	}
+	if delta.days == 0 && delta.length == 0 {
+		return delta, fmt.Errorf("shift by nothing")
+	}
+	return delta, nil
+}
+
+// String writes the shift the way parseShift reads it
+func (d shiftDelta) String() string {
+	sign, days, length := "+", d.days, d.length
+	if days < 0 || days == 0 && length < 0 {
+		sign, days, length = "-", -days, -length
+	}
+	s := sign
+	if days != 0 {
+		s += fmt.Sprintf("%dd", days)
+	}
+	if hours := int(length.Hours()); hours != 0 {
+		s += fmt.Sprintf("%dh", hours)
+	}
+	if minutes := int(length.Minutes()) % 60; minutes != 0 {
+		s += fmt.Sprintf("%dm", minutes)
+	}
+	return s
+}
+
+// shiftMove is an event a shift moves, and where to
+type shiftMove struct {
+	event remind.Event
+	date  time.Time
+	at    *time.Time // nil for untimed events
+}
+
+// shiftMoves returns where delta moves the one-off events of day, or only
+// its timed events from from on when from isn't nil, and the events that
+// can't be moved. Untimed events only move by whole days.
+func (m *Model) shiftMoves(day time.Time, from *time.Time, delta shiftDelta) ([]shiftMove, []remind.Event) {
+	var moves []shiftMove
+	var skipped []remind.Event
+	seen := make(map[string]bool)
+	for _, event := range m.eventsOn(day) {
+		switch {
+		case event.IsSpecial():
+			continue
+		case event.Time == nil && (from != nil || delta.days == 0):
+			continue
+		case event.Time != nil && from != nil && event.Time.Before(*from):
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", event.Filename, event.LineNumber)
+		if event.Filename != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if _, err := m.eventUpdater(event); err != nil || event.IsRepeating || event.Filename == "" {
+			skipped = append(skipped, event)
+			continue
+		}
+
+		move := shiftMove{event: event, date: event.Date.AddDate(0, 0, delta.days)}
+		if event.Time != nil {
+			at := event.Time.AddDate(0, 0, delta.days).Add(delta.length)
+			move.at = &at
+			move.date = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+		}
+		moves = append(moves, move)
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return eventBefore(moves[i].event, moves[j].event)
+	})
+	return moves, skipped
+}
+
+// startShift opens the dialog for shifting the events of the selected day
+func (m *Model) startShift() {
+	m.shiftDay = m.selectedDay()
+	m.shiftFrom = nil
+	m.shiftInput.Reset()
+	m.mode = ViewShift
+}
+
+// applyShift moves the events as previewed, returning how to move the ones
+// it moved back
+func (m *Model) applyShift(moves []shiftMove) ([]shiftMove, error) {
+	var undo []shiftMove
+	for i, move := range moves {
+		updater, err := m.eventUpdater(move.event)
+		joined := 0
+		if err == nil {
+			joined, err = rescheduleEvent(updater, move.event, move.date, move.at, move.event.Duration)
+		}
+		if err != nil {
+			return undo, fmt.Errorf("moved %d of %d events, %s: %w", i, len(moves), move.event.Description, err)
+		}
+		// Joining its continuation lines moved the reminders after it up
+		for j := i + 1; j < len(moves); j++ {
+			shiftLine(&moves[j].event, move.event, joined)
+		}
+		for j := range undo {
+			shiftLine(&undo[j].event, move.event, joined)
+		}
+		event := move.event
+		m.runHook(HookEventEdited, &event)
+		undo = append(undo, shiftMove{event: move.event, date: move.event.Date, at: move.event.Time})
+	}
+	return undo, nil
+}
+
+// undoShift moves the events of the last shift back where they were
+func (m *Model) undoShift() {
+	if len(m.lastShift) == 0 {
+		m.showMessage("No shift to undo")
+		return
+	}
+	if _, err := m.applyShift(m.lastShift); err != nil {
+		m.showError("Failed to undo the shift", err)
+	} else {
+		m.showMessage(fmt.Sprintf("Moved %d events back", len(m.lastShift)))
+	}
+	m.lastShift = nil
+	m.loadEvents()
+}
+
+func (m *Model) handleShiftKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	action := m.inputAction(msg)
+	if action == "" && msg.Code == tea.KeyTab {
+		action = "next_area"
+	}
+	switch action {
+	case "entry_cancel":
+		m.mode = ViewHourly
+
+	case "next_area":
+		// Switch between the whole day and the events from the selected slot on
+		if m.shiftFrom != nil || m.focusUntimed {
+			m.shiftFrom = nil
+		} else {
+			slotsPerDay := m.getSlotsPerDay()
+			hour, minute := m.slotToTime((m.selectedSlot%slotsPerDay + slotsPerDay) % slotsPerDay)
+			from := time.Date(m.shiftDay.Year(), m.shiftDay.Month(), m.shiftDay.Day(), hour, minute, 0, 0, m.shiftDay.Location())
+			m.shiftFrom = &from
+		}
+
+	case "entry_complete":
+		delta, err := parseShift(m.shiftInput.Value())
+		if err != nil {
+			m.showMessage(err.Error())
+			return m, nil
+		}
+		m.shiftInput.Remember()
+		m.mode = ViewHourly
+		moves, _ := m.shiftMoves(m.shiftDay, m.shiftFrom, delta)
+		if len(moves) == 0 {
+			m.showMessage("No events to shift")
+			return m, nil
+		}
+		undo, err := m.applyShift(moves)
+		if err != nil {
+			m.showError("Failed to shift", err)
+		} else {
+			m.showMessage(fmt.Sprintf("Shifted %d events by %s, Ctrl+Z to undo", len(moves), delta))
+		}
+		m.lastShift = undo
+		m.loadEvents()
+
+	default:
+		if !m.shiftInput.HandleAction(action) {
+			m.shiftInput.HandleKey(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) viewShift() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Shift Events"))
+	sections = append(sections, "")
+
+	what := "All events of " + m.shiftDay.Format(m.config.DateLayout("Monday, January 2"))
+	if from := m.shiftFrom; from != nil {
+		what = fmt.Sprintf("Events of %s from %s on", m.shiftDay.Format(m.config.DateLayout("Monday, January 2")), from.Format("15:04"))
+	}
+	sections = append(sections, m.styles.Normal.Render(what+", by:"))
+	sections = append(sections, m.styles.Selected.Render(m.shiftInput.View()))
+	sections = append(sections, "")
+
+	if value := m.shiftInput.Value(); value != "" {
+		delta, err := parseShift(value)
+		if err != nil {
+			sections = append(sections, m.styles.Priority.Render(err.Error()))
+		} else {
+			moves, skipped := m.shiftMoves(m.shiftDay, m.shiftFrom, delta)
+			for _, move := range moves {
+				sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("%s  →  %s  %s",
+					m.shiftWhen(move.event.Date, move.event.Time), m.shiftWhen(move.date, move.at), m.displayEvent(move.event).Description)))
+			}
+			if len(moves) == 0 {
+				sections = append(sections, m.styles.Help.Render("No events to shift"))
+			}
+			for _, event := range skipped {
+				reason := "repeats"
+				if !event.IsRepeating {
+					reason = "read-only"
+				}
+				sections = append(sections, m.styles.Help.Render(fmt.Sprintf("Not moved (%s): %s", reason, m.displayEvent(event).Description)))
+			}
+		}
+		sections = append(sections, "")
+	}
+
+	sections = append(sections, m.styles.Help.Render("e.g. +1h, -30m, +1d  Tab: whole day/from selected slot  Enter: Shift  Esc: Cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// shiftWhen formats the date and time of an event in the shift preview
+func (m *Model) shiftWhen(date time.Time, at *time.Time) string {
+	when := date.Format(m.config.DateLayout("Mon Jan _2"))
+	if at != nil {
+		return when + " " + at.Format("15:04")
+	}
+	return when + " -----"
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseShift(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected shiftDelta
+		wantErr  bool
+	}{
+		{"+1h", shiftDelta{length: time.Hour}, false},
+		{"-30m", shiftDelta{length: -30 * time.Minute}, false},
+		{"2h", shiftDelta{length: 2 * time.Hour}, false},
+		{"+1d", shiftDelta{days: 1}, false},
+		{"+1w2d", shiftDelta{days: 9}, false},
+		{"- 1d 2h", shiftDelta{days: -1, length: -2 * time.Hour}, false},
+		{"+0h", shiftDelta{}, true},
+		{"1x", shiftDelta{}, true},
+		{"", shiftDelta{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseShift(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseShift(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.expected {
+			t.Errorf("parseShift(%q) = %+v, want %+v", tt.input, got, tt.expected)
+		}
+	}
+
+	if s := (shiftDelta{days: -1, length: -90 * time.Minute}).String(); s != "-1d1h30m" {
+		t.Errorf("String() = %q, want -1d1h30m", s)
+	}
+}
+
+func TestShiftDay(t *testing.T) {
+	// Planning takes two lines, which moving it joins
+	content := "REM Aug 25 2025 AT 9:00 DURATION 1:00 \\\n  MSG Planning\n" +
+		"REM Aug 25 2025 AT 14:00 MSG Review\n" +
+		"REM Aug 25 2025 MSG Buy milk\n" +
+		"REM Mon AT 8:00 MSG Standup\n"
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m, file := newFileModel(t, content, day.Add(8*time.Hour))
+	m.loadEvents()
+
+	// From 10:00 on, only the review moves
+	from := day.Add(10 * time.Hour)
+	moves, skipped := m.shiftMoves(day, &from, shiftDelta{length: time.Hour})
+	if len(moves) != 1 || moves[0].event.Description != "Review" || moves[0].at.Format("15:04") != "15:00" {
+		t.Fatalf("expected the review to move to 15:00, got %+v", moves)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("expected the repeating standup before 10:00 to be left out, got %+v", skipped)
+	}
+
+	// The whole day moves by a day, untimed events too, except the standup
+	moves, skipped = m.shiftMoves(day, nil, shiftDelta{days: 1})
+	if len(moves) != 3 || len(skipped) != 1 || skipped[0].Description != "Standup" {
+		t.Fatalf("expected 3 moves and the standup skipped, got %d moves, skipped %+v", len(moves), skipped)
+	}
+
+	undo, err := m.applyShift(moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.lastShift = undo
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 26 2025 AT 09:00 DURATION 1:00 MSG Planning\n" +
+		"REM Aug 26 2025 AT 14:00 MSG Review\n" +
+		"REM Aug 26 2025 MSG Buy milk\n" +
+		"REM Mon AT 8:00 MSG Standup\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+
+	m.undoShift()
+	data, _ = os.ReadFile(file)
+	expected = "REM Aug 25 2025 AT 09:00 DURATION 1:00 MSG Planning\n" +
+		"REM Aug 25 2025 AT 14:00 MSG Review\n" +
+		"REM Aug 25 2025 MSG Buy milk\n" +
+		"REM Mon AT 8:00 MSG Standup\n"
+	if string(data) != expected {
+		t.Errorf("after undo got:\n%s\nwant:\n%s", data, expected)
+	}
+	if m.lastShift != nil {
+		t.Error("expected nothing left to undo")
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+var update = flag.Bool("update", false, "rewrite the snapshot files")
+
+// snapshotModel returns a model on Monday Aug 25 2025 at 10:17, with the
+// clock frozen there and a day of events loaded
+func snapshotModel() *Model {
+	now := time.Date(2025, 8, 25, 10, 17, 0, 0, time.Local)
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(days, hour, minute int) *time.Time {
+		t := time.Date(2025, 8, 25+days, hour, minute, 0, 0, time.Local)
+		return &t
+	}
+
+	cfg := config.DefaultConfig()
+	return &Model{
+		config:        cfg,
+		styles:        stylesFor(cfg),
+		clock:         remind.FixedClock(now),
+		mode:          ViewHourly,
+		timeIncrement: 60,
+		selectedDate:  day,
+		selectedSlot:  10,
+		topSlot:       7,
+		events: []remind.Event{
+			{ID: "1", Date: day, Time: at(0, 8, 0), Duration: durationPtr(30), Description: "Standup"},
+			{ID: "2", Date: day, Time: at(0, 9, 0), Duration: durationPtr(180), Description: "Quarterly planning with the platform team", Location: "Room 4"},
+			{ID: "3", Date: day, Time: at(0, 10, 0), Duration: durationPtr(60), Description: "Design review"},
+			{ID: "4", Date: day, Time: at(0, 10, 30), Duration: durationPtr(30), Description: "Call Sam"},
+			{ID: "5", Date: day, Time: at(0, 14, 0), Description: "Pay invoices", Priority: remind.PriorityHigh},
+			{ID: "6", Date: day, Description: "Pick up dry cleaning"},
+			{ID: "7", Date: day.AddDate(0, 0, 2), Time: at(2, 9, 0), Duration: durationPtr(480), Description: "Offsite"},
+		},
+	}
+}
+
+// TestViewSnapshots renders whole screens and compares them with the files
+// in testdata/snapshots. Run the test with -update to rewrite them after
+// changing the layout on purpose.
+func TestViewSnapshots(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		setup  func(m *Model)
+	}{
+		{name: "schedule", width: 100, height: 24},
+		{name: "narrow", width: 60, height: 20},
+		{name: "agenda", width: 100, height: 24, setup: func(m *Model) { m.showAgenda = true }},
+		{name: "peek", width: 100, height: 24, setup: func(m *Model) { m.peeking = true }},
+		{name: "untimed", width: 100, height: 24, setup: func(m *Model) { m.focusUntimed = true }},
+		{name: "zoom30", width: 100, height: 30, setup: func(m *Model) {
+			m.timeIncrement, m.selectedSlot, m.topSlot = 30, 20, 16
+		}},
+		{name: "message", width: 100, height: 24, setup: func(m *Model) { m.message = "Event pasted" }},
+		{name: "degraded", width: 100, height: 24, setup: func(m *Model) { m.remindMissing = true }},
+		{name: "compact", width: 100, height: 24, setup: func(m *Model) { m.compact = true }},
+		{name: "accessible", width: 100, height: 24, setup: func(m *Model) {
+			m.config.Accessible = true
+			m.styles = stylesFor(m.config)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := snapshotModel()
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			got := m.renderPlain(tt.width, tt.height) + "\n"
+
+			// The same model renders the same screen every time
+			if again := m.renderPlain(tt.width, tt.height) + "\n"; again != got {
+				t.Fatalf("second render differs:\n%s", again)
+			}
+
+			path := filepath.Join("testdata", "snapshots", tt.name+".txt")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("screen differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
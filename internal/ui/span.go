@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// selectedSpan returns how long the span selected from the selected slot
+// lasts, or zero when no span starts there
+func (m *Model) selectedSpan() time.Duration {
+	if m.spanLength > 0 && m.spanStart.Equal(m.selectedSlotStart()) {
+		return m.spanLength
+	}
+	return 0
+}
+
+// resizeSpan makes the span from the selected slot a slot longer, or
+// shorter when grow is false, within the slot's day
+func (m *Model) resizeSpan(grow bool) {
+	step := time.Duration(m.timeIncrement) * time.Minute
+	start := m.selectedSlotStart()
+	length := m.selectedSpan()
+	if length == 0 {
+		length = step
+	}
+
+	if grow {
+		if end := start.Add(length + step); sameDay(end.Add(-time.Minute), start) {
+			length += step
+		}
+	} else if length > step {
+		length -= step
+	}
+
+	m.spanStart, m.spanLength = start, length
+	if length <= step {
+		m.spanLength = 0
+	}
+	m.showMessage(fmt.Sprintf("New events: %s-%s (%s)", start.Format("15:04"), start.Add(length).Format("15:04"), formatDuration(length)))
+}
+
+// inSpan reports whether the slot starting at t is in the selected span
+func (m *Model) inSpan(t time.Time) bool {
+	length := m.selectedSpan()
+	return length > 0 && !t.Before(m.spanStart) && t.Before(m.spanStart.Add(length))
+}
+
+// newEventLength returns how long events created at the selected slot
+// last: the selected span, a slot at 15-minute zoom, or zero for the
+// template's hour otherwise
+func (m *Model) newEventLength() time.Duration {
+	if length := m.selectedSpan(); length > 0 {
+		return length
+	}
+	if m.timeIncrement == 15 {
+		return 15 * time.Minute
+	}
+	return 0
+}
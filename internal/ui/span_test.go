@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestSpanNewEvent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	client := remind.NewClient()
+	client.SetFiles([]string{file})
+
+	cfg := config.DefaultConfig()
+	cfg.TimedTemplate = `REM %monname% %mday% %year% AT %hour%:%min% DURATION %dura%:00 MSG`
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		config:        cfg,
+		remindClient:  client,
+		clock:         remind.FixedClock(day.Add(8 * time.Hour)),
+		selectedDate:  day,
+		timeIncrement: 15,
+		selectedSlot:  37, // 9:15
+	}
+
+	// Three slots down from 9:15, then back one
+	for _, action := range []string{"extend_span", "extend_span", "extend_span", "shrink_span"} {
+		m.handleHourlyKeys("", action)
+	}
+	if m.message != "New events: 09:15-10:00 (45m)" {
+		t.Errorf("unexpected message %q", m.message)
+	}
+	if !m.inSpan(day.Add(9*time.Hour+45*time.Minute)) || m.inSpan(day.Add(10*time.Hour)) {
+		t.Error("expected the span to cover 9:15 up to 10:00")
+	}
+	m.handleHourlyKeys("t", "new_timed")
+
+	// Without a span, a slot at this zoom; moving away drops the span
+	m.handleHourlyKeys("", "extend_span")
+	m.selectedSlot++
+	if m.selectedSpan() != 0 {
+		t.Error("expected no span at another slot")
+	}
+	m.handleHourlyKeys("t", "new_timed")
+
+	// An hour at coarser zooms
+	m.timeIncrement, m.selectedSlot = 60, 14
+	m.handleHourlyKeys("t", "new_timed")
+
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 25 2025 AT 09:15 DURATION 0:45 MSG\n" +
+		"REM Aug 25 2025 AT 09:30 DURATION 0:15 MSG\n" +
+		"REM Aug 25 2025 AT 14:00 DURATION 1:00 MSG\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// cycleStatus moves the selected event on to its next status, from
+// confirmed to tentative to cancelled and back, rewriting its status tag
+func (m *Model) cycleStatus() {
+	events := m.selectedEvents()
+	if len(events) == 0 {
+		m.showMessage("No reminder selected")
+		return
+	}
+	event := events[0]
+
+	updater, err := m.eventUpdater(event)
+	if err != nil {
+		m.showError("Can't change the status", err)
+		return
+	}
+	status := remind.NextStatus(event.Status())
+	if err := updater.SetEventStatus(event, status); err != nil {
+		m.showError("Can't change the status", err)
+		return
+	}
+	m.runHook(HookEventEdited, &event)
+	m.loadEvents()
+
+	if status == remind.StatusConfirmed {
+		status = "confirmed"
+	}
+	m.showMessage(fmt.Sprintf("%s: %s", m.displayEvent(event).Description, status))
+}
+
+// statusBlock renders the text of an event block as its status shows:
+// tentative events hatched where there's no text, cancelled ones struck
+// through
+func statusBlock(style lipgloss.Style, event remind.Event, text string, width, height int) string {
+	switch event.Status() {
+	case remind.StatusTentative:
+		lines := strings.Split(text, "\n")
+		for len(lines) < height {
+			lines = append(lines, "")
+		}
+		for i, line := range lines {
+			if fill := width - lipgloss.Width(line); fill > 0 {
+				lines[i] = line + strings.Repeat("╱", fill)
+			}
+		}
+		text = strings.Join(lines, "\n")
+	case remind.StatusCancelled:
+		style = style.Strikethrough(true).Faint(true)
+	}
+	return style.Width(width).Height(height).Render(text)
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestCycleStatus(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m, file := newFileModel(t, "REM Aug 25 2025 AT 10:00 DURATION 1:00 MSG Sync\n", day.Add(8*time.Hour))
+	m.loadEvents()
+
+	for _, expected := range []string{"tentative", "cancelled", "confirmed"} {
+		m.handleHourlyKeys("~", "cycle_status")
+		if m.message != "Sync: "+expected {
+			t.Errorf("expected the event %s, got %q", expected, m.message)
+		}
+		if expected != "cancelled" {
+			continue
+		}
+		// Once cancelled, it's no longer in the way
+		if conflicts := overlapping(m.eventsOn(day), day.Add(10*time.Hour), time.Hour); len(conflicts) != 0 {
+			t.Errorf("expected no conflict with a cancelled event, got %+v", conflicts)
+		}
+		if busy := busyRanges(m.eventsOn(day), day); len(busy) != 0 {
+			t.Errorf("expected a cancelled event not to block time, got %+v", busy)
+		}
+	}
+	data, _ := os.ReadFile(file)
+	if expected := "REM Aug 25 2025 AT 10:00 DURATION 1:00 MSG Sync\n"; string(data) != expected {
+		t.Errorf("got %q, want %q", data, expected)
+	}
+}
+
+func TestStatusBlock(t *testing.T) {
+	tentative := remind.Event{Tags: []string{"status:tentative"}}
+	lines := strings.Split(ansi.Strip(statusBlock(lipgloss.NewStyle(), tentative, "Sync", 8, 2)), "\n")
+	if len(lines) != 2 || lines[0] != "Sync╱╱╱╱" || lines[1] != "╱╱╱╱╱╱╱╱" {
+		t.Errorf("expected a hatched block, got %q", lines)
+	}
+
+	if block := statusBlock(lipgloss.NewStyle(), remind.Event{}, "Sync", 8, 1); ansi.Strip(block) != "Sync    " {
+		t.Errorf("expected a confirmed event as it is, got %q", block)
+	}
+}
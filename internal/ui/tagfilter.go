@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// filterByTag restricts every view to the events sharing a tag of the
+// selected event, asking which one when it has several. With a filter on,
+// it clears the filter instead.
+func (m *Model) filterByTag() {
+	if m.tagFilter != "" {
+		m.setTagFilter("")
+		m.showMessage("Showing all events")
+		return
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, event := range m.selectedEvents() {
+		for _, tag := range event.Tags {
+			tag = strings.TrimPrefix(tag, "@")
+			if key := strings.ToLower(tag); tag != "" && !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	switch len(tags) {
+	case 0:
+		m.showMessage("The selected event has no tags")
+	case 1:
+		m.setTagFilter(tags[0])
+	default:
+		m.tagChoices = tags
+		m.selectedTagIndex = 0
+		m.mode = ViewTagFilter
+	}
+}
+
+// setTagFilter shows only the events tagged tag, or all of them when tag is
+// empty, reloading the events to apply it
+func (m *Model) setTagFilter(tag string) {
+	m.tagFilter = tag
+	m.loadEvents()
+	if tag != "" {
+		m.showMessage(fmt.Sprintf("Showing only @%s", tag))
+	}
+}
+
+// filterTagged returns the events tagged with the tag filter, all of them
+// when there's no filter
+func (m *Model) filterTagged(events []remind.Event) []remind.Event {
+	if m.tagFilter == "" {
+		return events
+	}
+	var tagged []remind.Event
+	for _, event := range events {
+		if event.HasTag(m.tagFilter) {
+			tagged = append(tagged, event)
+		}
+	}
+	return tagged
+}
+
+func (m *Model) handleTagFilterKeys(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch keyName(msg) {
+	case "<esc>", "q":
+		m.mode = ViewHourly
+		m.tagChoices = nil
+	case "j", "<down>":
+		if m.selectedTagIndex < len(m.tagChoices)-1 {
+			m.selectedTagIndex++
+		}
+	case "k", "<up>":
+		if m.selectedTagIndex > 0 {
+			m.selectedTagIndex--
+		}
+	case "<enter>":
+		if m.selectedTagIndex < len(m.tagChoices) {
+			m.mode = ViewHourly
+			m.setTagFilter(m.tagChoices[m.selectedTagIndex])
+			m.tagChoices = nil
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) viewTagFilter() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Show only events tagged"))
+	sections = append(sections, "")
+
+	for i, tag := range m.tagChoices {
+		line := "@" + tag
+		if i == m.selectedTagIndex {
+			sections = append(sections, m.styles.Selected.Render(line))
+		} else {
+			sections = append(sections, m.styles.Normal.Render(line))
+		}
+	}
+
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Help.Render("Enter: Filter  j/k: Navigate  Esc: Cancel"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestFilterByTag(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	source := &watchedSource{events: []remind.Event{
+		{ID: "1", Date: day, Time: timePtr(9, 0), Description: "Dinner", Tags: []string{"@family", "home"}},
+		{ID: "2", Date: day, Time: timePtr(10, 0), Description: "Standup", Tags: []string{"work"}},
+		{ID: "3", Date: day, Description: "Birthday", Tags: []string{"Family"}},
+	}}
+	m := NewModelWithRemind(config.DefaultConfig(), source, nil)
+	m.clock = remind.FixedClock(day.Add(8 * time.Hour))
+	m.width, m.height = 100, 24
+	m.selectedDate = day
+	m.timeIncrement = 60
+	m.selectedSlot = 9
+	m.loadEvents()
+
+	// Dinner has two tags to choose from
+	m.handleHourlyKeys("#", "filter_tag")
+	if m.mode != ViewTagFilter || len(m.tagChoices) != 2 {
+		t.Fatalf("Expected to choose between 2 tags, got mode %v with %v", m.mode, m.tagChoices)
+	}
+	if view := m.View(); !strings.Contains(view, "@family") || !strings.Contains(view, "@home") {
+		t.Errorf("Tag menu missing the tags:\n%s", view)
+	}
+	m.handleTagFilterKeys(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.mode != ViewHourly || m.tagFilter != "family" {
+		t.Fatalf("Expected to filter by family, got mode %v and filter %q", m.mode, m.tagFilter)
+	}
+	if len(m.events) != 2 || m.events[0].ID != "1" || m.events[1].ID != "3" {
+		t.Errorf("Expected the events tagged family, got %+v", m.events)
+	}
+
+	// The filter stays on when events are reloaded
+	m.loadEvents()
+	if len(m.events) != 2 {
+		t.Errorf("Expected the filter to apply after reloading, got %d events", len(m.events))
+	}
+
+	// Pressing it again shows all events
+	m.handleHourlyKeys("#", "filter_tag")
+	if m.tagFilter != "" || len(m.events) != 3 {
+		t.Errorf("Expected the filter cleared, got %q with %d events", m.tagFilter, len(m.events))
+	}
+
+	// Standup has one tag, used right away
+	m.selectedSlot = 10
+	m.handleHourlyKeys("#", "filter_tag")
+	if m.mode != ViewHourly || m.tagFilter != "work" || len(m.events) != 1 {
+		t.Errorf("Expected to filter by work, got %q with %d events", m.tagFilter, len(m.events))
+	}
+}
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// maxInputHistory is how many previous entries each input remembers
+const maxInputHistory = 50
+
+// textInput is a single-line input with readline-style editing keys and a
+// history of previous entries that can be recalled with up/down
+type textInput struct {
+	value  []rune
+	cursor int
+
+	history []string
+	histPos int    // index into history while browsing, len(history) otherwise
+	draft   string // what was being typed before browsing history
+}
+
+// Value returns the current contents of the input
+func (t *textInput) Value() string {
+	return string(t.value)
+}
+
+// SetValue replaces the contents and moves the cursor to the end
+func (t *textInput) SetValue(s string) {
+	t.value = []rune(s)
+	t.cursor = len(t.value)
+}
+
+// Reset clears the input, ready for a new entry
+func (t *textInput) Reset() {
+	t.SetValue("")
+	t.histPos = len(t.history)
+	t.draft = ""
+}
+
+// Remember adds the current value to the history
+func (t *textInput) Remember() {
+	value := t.Value()
+	if value == "" {
+		return
+	}
+
+	// Move repeated entries to the end rather than storing them twice
+	for i, h := range t.history {
+		if h == value {
+			t.history = append(t.history[:i], t.history[i+1:]...)
+			break
+		}
+	}
+	t.history = append(t.history, value)
+	if len(t.history) > maxInputHistory {
+		t.history = t.history[len(t.history)-maxInputHistory:]
+	}
+	t.histPos = len(t.history)
+}
+
+// View renders the input with a block cursor
+func (t *textInput) View() string {
+	if t.cursor < len(t.value) {
+		return string(t.value[:t.cursor]) + "█" + string(t.value[t.cursor:])
+	}
+	return string(t.value) + "█"
+}
+
+// inputKeyActions are the readline-style keys every text input understands.
+// The actions can also be bound to other keys for a single mode in urdrc.
+var inputKeyActions = map[string]string{
+	"left":          "backward_char",
+	"ctrl+b":        "backward_char",
+	"right":         "forward_char",
+	"ctrl+f":        "forward_char",
+	"home":          "beginning_of_line",
+	"ctrl+a":        "beginning_of_line",
+	"end":           "end_of_line",
+	"ctrl+e":        "end_of_line",
+	"alt+b":         "backward_word",
+	"ctrl+left":     "backward_word",
+	"alt+f":         "forward_word",
+	"ctrl+right":    "forward_word",
+	"backspace":     "delete_backward_char",
+	"ctrl+h":        "delete_backward_char",
+	"delete":        "delete_char",
+	"ctrl+d":        "delete_char",
+	"ctrl+w":        "backward_kill_word",
+	"alt+backspace": "backward_kill_word",
+	"alt+d":         "kill_word",
+	"ctrl+u":        "backward_kill_line",
+	"ctrl+k":        "kill_line",
+	"up":            "history_previous",
+	"ctrl+p":        "history_previous",
+	"down":          "history_next",
+	"ctrl+n":        "history_next",
+}
+
+// HandleKey applies an editing key to the input. Enter and Esc are left to
+// the caller.
+func (t *textInput) HandleKey(msg tea.KeyPressMsg) {
+	key := msg.String()
+	if action, ok := inputKeyActions[key]; ok {
+		t.HandleAction(action)
+		return
+	}
+
+	if key == "space" {
+		t.insert(" ")
+	} else {
+		t.insert(msg.Text)
+	}
+}
+
+// HandleAction applies a named editing action, reporting whether it is one
+func (t *textInput) HandleAction(action string) bool {
+	switch action {
+	case "backward_char":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case "forward_char":
+		if t.cursor < len(t.value) {
+			t.cursor++
+		}
+	case "beginning_of_line":
+		t.cursor = 0
+	case "end_of_line":
+		t.cursor = len(t.value)
+	case "backward_word":
+		t.cursor = t.wordStart()
+	case "forward_word":
+		t.cursor = t.wordEnd()
+
+	case "delete_backward_char":
+		if t.cursor > 0 {
+			t.deleteRange(t.cursor-1, t.cursor)
+		}
+	case "delete_char":
+		if t.cursor < len(t.value) {
+			t.deleteRange(t.cursor, t.cursor+1)
+		}
+	case "backward_kill_word":
+		t.deleteRange(t.wordStart(), t.cursor)
+	case "kill_word":
+		t.deleteRange(t.cursor, t.wordEnd())
+	case "backward_kill_line":
+		t.deleteRange(0, t.cursor)
+	case "kill_line":
+		t.deleteRange(t.cursor, len(t.value))
+
+	case "history_previous":
+		t.recall(-1)
+	case "history_next":
+		t.recall(1)
+
+	default:
+		return false
+	}
+	return true
+}
+
+// insert adds text at the cursor
+func (t *textInput) insert(text string) {
+	if text == "" {
+		return
+	}
+	runes := []rune(text)
+	value := make([]rune, 0, len(t.value)+len(runes))
+	value = append(value, t.value[:t.cursor]...)
+	value = append(value, runes...)
+	value = append(value, t.value[t.cursor:]...)
+	t.value = value
+	t.cursor += len(runes)
+}
+
+// deleteRange removes value[from:to] and leaves the cursor at from
+func (t *textInput) deleteRange(from, to int) {
+	if from >= to {
+		return
+	}
+	t.value = append(t.value[:from], t.value[to:]...)
+	t.cursor = from
+}
+
+// wordStart returns the start of the word before the cursor
+func (t *textInput) wordStart() int {
+	i := t.cursor
+	for i > 0 && !isWordRune(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after the cursor
+func (t *textInput) wordEnd() int {
+	i := t.cursor
+	for i < len(t.value) && !isWordRune(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && isWordRune(t.value[i]) {
+		i++
+	}
+	return i
+}
+
+// recall moves through the history, keeping the current draft so it can be
+// returned to by moving past the newest entry
+func (t *textInput) recall(delta int) {
+	pos := t.histPos + delta
+	if pos < 0 || pos > len(t.history) {
+		return
+	}
+
+	if t.histPos == len(t.history) {
+		t.draft = t.Value()
+	}
+	t.histPos = pos
+
+	if pos == len(t.history) {
+		t.SetValue(t.draft)
+	} else {
+		t.SetValue(t.history[pos])
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func typeText(t *textInput, s string) {
+	for _, r := range s {
+		if r == ' ' {
+			t.HandleKey(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+		} else {
+			t.HandleKey(tea.KeyPressMsg{Code: r, Text: string(r)})
+		}
+	}
+}
+
+func ctrl(r rune) tea.KeyPressMsg {
+	return tea.KeyPressMsg{Code: r, Mod: tea.ModCtrl}
+}
+
+func alt(r rune) tea.KeyPressMsg {
+	return tea.KeyPressMsg{Code: r, Mod: tea.ModAlt}
+}
+
+func TestTextInputEditing(t *testing.T) {
+	tests := []struct {
+		name       string
+		keys       []tea.KeyPressMsg
+		wantValue  string
+		wantCursor int
+	}{
+		{"ctrl+w deletes previous word", []tea.KeyPressMsg{ctrl('w')}, "next monday ", 12},
+		{"ctrl+u kills to start", []tea.KeyPressMsg{alt('b'), ctrl('u')}, "2pm", 0},
+		{"ctrl+k kills to end", []tea.KeyPressMsg{ctrl('a'), alt('f'), ctrl('k')}, "next", 4},
+		{"word movement", []tea.KeyPressMsg{alt('b'), alt('b'), ctrl('d')}, "next onday 2pm", 5},
+		{"home and end", []tea.KeyPressMsg{ctrl('a'), ctrl('f'), ctrl('e'), ctrl('h')}, "next monday 2p", 14},
+		{"left and backspace", []tea.KeyPressMsg{{Code: tea.KeyLeft}, {Code: tea.KeyBackspace}}, "next monday 2m", 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input textInput
+			typeText(&input, "next monday 2pm")
+			for _, key := range tt.keys {
+				input.HandleKey(key)
+			}
+			if input.Value() != tt.wantValue {
+				t.Errorf("Value() = %q, want %q", input.Value(), tt.wantValue)
+			}
+			if input.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", input.cursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestTextInputHistory(t *testing.T) {
+	var input textInput
+	for _, entry := range []string{"meeting", "lunch", "meeting"} {
+		input.Reset()
+		typeText(&input, entry)
+		input.Remember()
+	}
+
+	// Repeated entries are moved to the end
+	if len(input.history) != 2 || input.history[1] != "meeting" {
+		t.Fatalf("Unexpected history: %v", input.history)
+	}
+
+	input.Reset()
+	typeText(&input, "dra")
+
+	up := tea.KeyPressMsg{Code: tea.KeyUp}
+	down := tea.KeyPressMsg{Code: tea.KeyDown}
+
+	input.HandleKey(up)
+	if input.Value() != "meeting" {
+		t.Errorf("After up: %q, want meeting", input.Value())
+	}
+	input.HandleKey(up)
+	if input.Value() != "lunch" {
+		t.Errorf("After up twice: %q, want lunch", input.Value())
+	}
+	input.HandleKey(up) // Already at the oldest entry
+	if input.Value() != "lunch" {
+		t.Errorf("After up past oldest: %q, want lunch", input.Value())
+	}
+	input.HandleKey(down)
+	input.HandleKey(down)
+	if input.Value() != "dra" {
+		t.Errorf("After returning to draft: %q, want dra", input.Value())
+	}
+}
+
+func TestTextInputView(t *testing.T) {
+	var input textInput
+	typeText(&input, "héllo")
+	input.HandleKey(tea.KeyPressMsg{Code: tea.KeyLeft})
+	if got := input.View(); got != "héll█o" {
+		t.Errorf("View() = %q, want %q", got, "héll█o")
+	}
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image/color"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// auditColors are the colors of tags without a color line, picked by the
+// tag's name so a tag keeps its color
+var auditColors = []lipgloss.ANSIColor{33, 35, 172, 133, 37, 166, 99, 142, 167, 68}
+
+// untaggedColor is the color of events without tags in the time audit
+const untaggedColor = lipgloss.ANSIColor(240)
+
+// primaryTag is the tag an event counts towards in the time audit: its
+// first tag with a color line, or else its first tag
+func (m *Model) primaryTag(event remind.Event) string {
+	event = m.displayEvent(event)
+	for _, tag := range event.Tags {
+		if _, ok := m.config.Colors["tag:"+strings.ToLower(strings.TrimPrefix(tag, "@"))]; ok {
+			return strings.TrimPrefix(tag, "@")
+		}
+	}
+	if len(event.Tags) > 0 {
+		return strings.TrimPrefix(event.Tags[0], "@")
+	}
+	return ""
+}
+
+// tagColor returns the color of a tag: the first color of its color line,
+// or one of auditColors. Untagged events are gray.
+func (m *Model) tagColor(tag string) remind.Color {
+	if tag == "" {
+		return rgbOf(untaggedColor)
+	}
+	if spec, ok := m.config.Colors["tag:"+strings.ToLower(tag)]; ok {
+		if style, err := parseColorSpec(spec); err == nil {
+			if fg := style.GetForeground(); fg != (lipgloss.NoColor{}) {
+				return rgbOf(fg)
+			}
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(tag)))
+	return rgbOf(auditColors[h.Sum32()%uint32(len(auditColors))])
+}
+
+// rgbOf returns the RGB value of a color
+func rgbOf(c color.Color) remind.Color {
+	r, g, b, _ := c.RGBA()
+	return remind.Color{R: int(r >> 8), G: int(g >> 8), B: int(b >> 8)}
+}
+
+// tagHours is the time booked for a tag
+type tagHours struct {
+	tag  string
+	time time.Duration
+}
+
+// auditHours adds up the time timed events take from first through last
+// by their primary tag, most first
+func (m *Model) auditHours(first, last time.Time) []tagHours {
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	until := time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, last.Location())
+
+	totals := make(map[string]time.Duration)
+	for _, event := range m.eventsReaching(first, last) {
+		if event.Time == nil || event.Duration == nil {
+			continue
+		}
+		start, end := *event.Time, event.Time.Add(*event.Duration)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(until) {
+			end = until
+		}
+		if end.After(start) {
+			totals[m.primaryTag(event)] += end.Sub(start)
+		}
+	}
+
+	var hours []tagHours
+	for tag, total := range totals {
+		hours = append(hours, tagHours{tag: tag, time: total})
+	}
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].time != hours[j].time {
+			return hours[i].time > hours[j].time
+		}
+		return hours[i].tag < hours[j].tag
+	})
+	return hours
+}
+
+// auditSummary is the status bar row of the time audit: the hours per tag
+// of the visible days, each after a swatch of its color
+func (m *Model) auditSummary() string {
+	first, last := m.visibleDays()
+	days := first.Format(m.config.DateLayout("Mon Jan 2"))
+	if !sameDay(first, last) {
+		days += " - " + last.Format(m.config.DateLayout("Mon Jan 2"))
+	}
+
+	parts := []string{m.styles.Help.Render(" " + days + ":")}
+	hours := m.auditHours(first, last)
+	if len(hours) == 0 {
+		parts = append(parts, m.styles.Help.Render("nothing booked"))
+	}
+	for _, h := range hours {
+		name := h.tag
+		if name == "" {
+			name = "untagged"
+		}
+		swatch := lipgloss.NewStyle().Foreground(rgbToANSI(m.tagColor(h.tag))).Render("■")
+		parts = append(parts, swatch+" "+m.styles.Normal.Render(fmt.Sprintf("%s %s", name, formatDuration(h.time))))
+	}
+	return strings.Join(parts, "  ")
+}
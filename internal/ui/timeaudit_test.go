@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestTimeAudit(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(days, hour int) *time.Time {
+		t := time.Date(2025, 8, 25+days, hour, 0, 0, 0, time.Local)
+		return &t
+	}
+	cfg := config.DefaultConfig()
+	cfg.Colors["tag:health"] = "#ff0000"
+	m := &Model{
+		config:        cfg,
+		styles:        defaultStyles(),
+		selectedDate:  day,
+		timeIncrement: 60,
+		topSlot:       0,
+		height:        20, // A single day on screen
+		events: []remind.Event{
+			{Date: day.AddDate(0, 0, -1), Time: at(-1, 23), Duration: durationPtr(120), Description: "Night shift", Tags: []string{"work"}},
+			{Date: day, Time: at(0, 9), Duration: durationPtr(90), Description: "Planning", Tags: []string{"work"}},
+			{Date: day, Time: at(0, 12), Duration: durationPtr(60), Description: "Lunch run", Tags: []string{"@personal", "health"}},
+			{Date: day, Time: at(0, 15), Duration: durationPtr(30), Description: "Call"},
+			{Date: day, Description: "All day", Tags: []string{"work"}},
+			{Date: day.AddDate(0, 0, 1), Time: at(1, 9), Duration: durationPtr(60), Description: "Tomorrow", Tags: []string{"work"}},
+		},
+	}
+
+	// The tag with a color line is the primary one
+	if tag := m.primaryTag(m.events[2]); tag != "health" {
+		t.Errorf("primaryTag = %q, want health", tag)
+	}
+	if c := m.tagColor("Health"); c != (remind.Color{R: 255}) {
+		t.Errorf("tagColor(Health) = %v, want its color line", c)
+	}
+	if m.tagColor("work") != m.tagColor("work") || m.tagColor("work") == m.tagColor("") {
+		t.Error("Expected tags without a color line to keep a color apart from untagged")
+	}
+
+	// Only the time within the day counts
+	var got []string
+	for _, h := range m.auditHours(day, day) {
+		got = append(got, h.tag+" "+formatDuration(h.time))
+	}
+	want := "work 2h 30m, health 1h,  30m"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("auditHours = %q, want %q", strings.Join(got, ", "), want)
+	}
+
+	m.handleHourlyKeys("%", "toggle_time_audit")
+	summary := m.auditSummary()
+	for _, part := range []string{"Mon Aug 25:", "work 2h 30m", "health 1h", "untagged 30m"} {
+		if !strings.Contains(summary, part) {
+			t.Errorf("Summary %q missing %q", summary, part)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
+)
+
+// travelWarnings returns, by event ID, a warning for each event that
+// doesn't leave the travel buffer after the previous event of the day at
+// a different location
+func (m *Model) travelWarnings() map[string]string {
+	warnings := make(map[string]string)
+	buffer := m.config.TravelBuffer
+	if buffer <= 0 {
+		return warnings
+	}
+
+	// Timed events with a location, by day
+	days := make(map[string][]remind.Event)
+	for _, event := range m.events {
+		// Private locations stay hidden in presentation mode
+		event = m.displayEvent(event)
+		if event.Time == nil || event.Location == "" {
+			continue
+		}
+		day := event.Date.Format("2006-01-02")
+		days[day] = append(days[day], event)
+	}
+
+	for _, events := range days {
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].Time.Before(*events[j].Time)
+		})
+
+		for i := 1; i < len(events); i++ {
+			prev, event := events[i-1], events[i]
+			if strings.EqualFold(prev.Location, event.Location) {
+				continue
+			}
+
+			end := *prev.Time
+			if prev.Duration != nil {
+				end = end.Add(*prev.Duration)
+			}
+			gap := event.Time.Sub(end)
+			if gap >= buffer {
+				continue
+			}
+
+			if gap <= 0 {
+				warnings[event.ID] = fmt.Sprintf("No time to get here from %s", prev.Location)
+			} else {
+				warnings[event.ID] = fmt.Sprintf("Only %s to get here from %s", formatDuration(gap), prev.Location)
+			}
+		}
+	}
+
+	return warnings
+}
+
+// travelBlock returns a block for travelling to a new event's location
+// that ends when the event starts
+func travelBlock(parsed *remind.ParsedEvent, location string, buffer time.Duration) remind.Event {
+	start := parsed.Time.Add(-buffer)
+	return remind.Event{
+		Date:        start,
+		Time:        &start,
+		Duration:    &buffer,
+		Description: "Travel to " + location,
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestTravelWarnings(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) *time.Time {
+		tm := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		return &tm
+	}
+	hour := time.Hour
+
+	m := &Model{
+		config: &config.Config{TravelBuffer: 30 * time.Minute},
+		events: []remind.Event{
+			{ID: "office", Date: day, Time: at(9, 0), Duration: &hour, Location: "Office"},
+			{ID: "call", Date: day, Time: at(10, 0), Duration: &hour}, // no location
+			{ID: "office2", Date: day, Time: at(10, 0), Duration: &hour, Location: "office"},
+			{ID: "clinic", Date: day, Time: at(11, 10), Duration: &hour, Location: "Clinic"},
+			{ID: "gym", Date: day, Time: at(12, 0), Duration: &hour, Location: "Gym"},
+			{ID: "home", Date: day, Time: at(14, 0), Location: "Home"},
+		},
+	}
+
+	warnings := m.travelWarnings()
+	if len(warnings) != 2 {
+		t.Fatalf("expected warnings for clinic and gym, got %v", warnings)
+	}
+	if w := warnings["clinic"]; !strings.Contains(w, "Only 10m") || !strings.Contains(w, "office") {
+		t.Errorf("unexpected clinic warning %q", w)
+	}
+	if w := warnings["gym"]; !strings.Contains(w, "No time") {
+		t.Errorf("unexpected gym warning %q", w)
+	}
+
+	m.config.TravelBuffer = 0
+	if warnings := m.travelWarnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings without a buffer, got %v", warnings)
+	}
+}
+
+func TestTravelBlock(t *testing.T) {
+	parsed, _, err := remind.QuickEventLine("Dentist @@Clinic tomorrow 3pm", time.Date(2025, 8, 25, 10, 0, 0, 0, time.Local), false)
+	if err != nil {
+		t.Fatalf("QuickEventLine: %v", err)
+	}
+	_, location := remind.ParseLocation(parsed.Text)
+
+	block := travelBlock(parsed, location, 30*time.Minute)
+	if block.Description != "Travel to Clinic" {
+		t.Errorf("unexpected description %q", block.Description)
+	}
+	if got := block.Time.Format("2006-01-02 15:04"); got != "2025-08-26 14:30" {
+		t.Errorf("travel block starts %s, want 2025-08-26 14:30", got)
+	}
+	if *block.Duration != 30*time.Minute {
+		t.Errorf("travel block lasts %v", *block.Duration)
+	}
+}
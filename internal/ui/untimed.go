@@ -0,0 +1,51 @@
+package ui
+
+import "time"
+
+// untimedHeight returns how many untimed reminders are listed at once
+func (m *Model) untimedHeight() int {
+	if m.config == nil || m.config.UntimedHeight < 1 {
+		return 5
+	}
+	return m.config.UntimedHeight
+}
+
+// untimedScrollKey is what the scrolling of the untimed reminders of day
+// is remembered by: the day, or the same for every day when grouped by due
+// date
+func (m *Model) untimedScrollKey(day time.Time) string {
+	if m.dueGroups {
+		return "due"
+	}
+	return day.Format("2006-01-02")
+}
+
+// untimedOffset returns the first of the count untimed reminders of day to
+// list, where the list was last scrolled to on that day
+func (m *Model) untimedOffset(day time.Time, count int) int {
+	offset := m.untimedScroll[m.untimedScrollKey(day)]
+	if last := count - m.untimedHeight(); offset > last {
+		offset = last
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
+// followUntimedSelection scrolls the untimed reminders of the selected day
+// so the selected one is listed, and remembers where for that day
+func (m *Model) followUntimedSelection() {
+	day := m.selectedDay()
+	count := len(m.getSortedUntimedEvents(day))
+	offset := m.untimedOffset(day, count)
+	if m.selectedUntimedIndex < offset {
+		offset = m.selectedUntimedIndex
+	} else if height := m.untimedHeight(); m.selectedUntimedIndex >= offset+height {
+		offset = m.selectedUntimedIndex - height + 1
+	}
+	if m.untimedScroll == nil {
+		m.untimedScroll = make(map[string]int)
+	}
+	m.untimedScroll[m.untimedScrollKey(day)] = offset
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestUntimedScroll(t *testing.T) {
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	cfg := config.DefaultConfig()
+	cfg.UntimedHeight = 3
+	m := &Model{
+		config:        cfg,
+		styles:        stylesFor(cfg),
+		clock:         remind.FixedClock(day.Add(10 * time.Hour)),
+		selectedDate:  day,
+		timeIncrement: 60,
+	}
+	for i := 1; i <= 8; i++ {
+		m.events = append(m.events, remind.Event{Date: day, Description: fmt.Sprintf("Errand %d", i), ID: fmt.Sprint(i)})
+	}
+	m.events = append(m.events, remind.Event{Date: day.AddDate(0, 0, 1), Description: "Tomorrow"})
+	sidebar := func() string { return lipgloss.NewCanvas(m.createSidebarLayer(0, 30)).Render() }
+
+	if s := sidebar(); !strings.Contains(s, "Errand 3") || strings.Contains(s, "Errand 4") || !strings.Contains(s, "↓ 5 more") {
+		t.Errorf("Expected the first 3 reminders listed:\n%s", s)
+	}
+
+	m.handleHourlyKeys("<tab>", "next_area")
+	for range 4 {
+		m.handleHourlyKeys("j", "scroll_down")
+	}
+	s := sidebar()
+	for _, want := range []string{"↑ 2 more", "Errand 3", "Errand 5", "↓ 3 more"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected %q once scrolled to Errand 5:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "Errand 2") || strings.Contains(s, "Errand 6") {
+		t.Errorf("Expected only Errand 3 to 5 listed:\n%s", s)
+	}
+
+	// Each day keeps its own position
+	m.handleHourlyKeys("<tab>", "next_area")
+	m.selectedDate = day.AddDate(0, 0, 1)
+	if s := sidebar(); !strings.Contains(s, "Tomorrow") || strings.Contains(s, "more") {
+		t.Errorf("Expected the next day listed from the top:\n%s", s)
+	}
+	m.selectedDate = day
+	if s := sidebar(); !strings.Contains(s, "↑ 2 more") {
+		t.Errorf("Expected the day scrolled where it was left:\n%s", s)
+	}
+	m.handleHourlyKeys("<tab>", "next_area")
+	if m.selectedUntimedIndex != 2 {
+		t.Errorf("Expected focus on the first reminder listed, got index %d", m.selectedUntimedIndex)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/cwarden/urd/internal/remind"
 
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -23,12 +26,26 @@ func (m *Model) viewHelp() string {
 		"home":           "Go to current time",
 		"goto":           "Go to specific date",
 		"zoom":           "Zoom (change time increment)",
+		"scroll_left":    "Scroll event columns left",
+		"scroll_right":   "Scroll event columns right",
+		"goto_monday":    "Go to Monday of the week",
+		"goto_tuesday":   "Go to Tuesday of the week",
+		"goto_wednesday": "Go to Wednesday of the week",
+		"goto_thursday":  "Go to Thursday of the week",
+		"goto_friday":    "Go to Friday of the week",
+		"goto_saturday":  "Go to Saturday of the week",
+		"goto_sunday":    "Go to Sunday of the week",
+		"next_event":     "Jump to the next event",
+		"prev_event":     "Jump to the previous event",
 		// Basic actions
 		"edit":        "Edit/create reminder",
 		"edit_any":    "Edit reminder file",
 		"new_timed":   "Add timed reminder",
 		"new_untimed": "Add untimed reminder",
 		"quick_add":   "Quick add event",
+		"extend_span": "Make new events a slot longer",
+		"shrink_span": "Make new events a slot shorter",
+		"visual":      "Select slots for a new event",
 		// Templates
 		"new_template0":        "Weekly recurring reminder",
 		"new_template1":        "Weekly untimed reminder",
@@ -46,6 +63,42 @@ func (m *Model) viewHelp() string {
 		"paste": "Paste reminder",
 		// URLs
 		"open_url": "Open URL from reminder",
+		// Privacy
+		"toggle_presentation": "Toggle presentation mode",
+		"toggle_agenda":       "Toggle day agenda",
+		"toggle_compact":      "Collapse empty hours",
+		"toggle_time_audit":   "Color by tag, with hours per tag",
+		"peek":                "Show selected event in full",
+		"toggle_rem_line":     "Peek at the REM line instead",
+		"edit_error":          "Edit the line remind reported an error on",
+		"toggle_pin":          "Pin or unpin the selected event in the sidebar",
+		"cycle_status":        "Mark the event tentative, cancelled or confirmed",
+		"toggle_due_groups":   "Group untimed reminders by due date",
+		"toggle_section":      "Collapse the selected reminder's section",
+		"expand_sections":     "Expand all due date sections",
+		"repeat":              "Repeat the last action on the selection",
+		"next":                "List upcoming events",
+		"fuzzy_find":          "Fuzzy find event",
+		"copy_agenda":         "Copy visible agenda as text",
+		"execute":             "Run event's RUN: command",
+		"join":                "Join meeting",
+		"focus":               "Start/stop focus session",
+		"review":              "Daily review",
+		"plan":                "Plan estimated tasks",
+		"shift_day":           "Shift the day's events",
+		"undo_shift":          "Undo the last shift",
+		"compare_days":        "Compare with another day",
+		"toggle_sources":      "Show/hide sources",
+		"filter_tag":          "Show only a tag of the selected event",
+		"edit_note":           "Open/create the day's note",
+		"edit_config":         "Edit and reload urdrc",
+		"copy_description":    "Copy event description",
+		"copy_rem_line":       "Copy event REM line",
+		"copy_date":           "Copy selected date",
+		"screenshot":          "Save screen as text and SVG",
+		"screenshot_redacted": "Save screen, private events redacted",
+		"export_csv":          "Export visible days as CSV",
+		"format_file":         "Tidy the event's remind file",
 		// Search
 		"begin_search": "Begin search",
 		"search_next":  "Search next",
@@ -115,14 +168,16 @@ func (m *Model) viewHelp() string {
 
 	// Navigation section
 	navActions := []string{"scroll_down", "scroll_up", "previous_day", "next_day",
-		"previous_week", "next_week", "previous_month", "next_month", "home", "goto", "zoom"}
+		"previous_week", "next_week", "previous_month", "next_month", "home", "goto", "zoom",
+		"scroll_left", "scroll_right", "next_event", "prev_event"}
+	navActions = append(navActions, weekDayActions...)
 	addBoundActions(navActions)
 
 	help = append(help, "")
 	help = append(help, m.styles.Normal.Render("Actions:"))
 
 	// Basic actions
-	basicActions := []string{"edit", "edit_any", "quick_add", "new_timed", "new_untimed", "open_url", "refresh"}
+	basicActions := []string{"edit", "edit_any", "quick_add", "new_timed", "new_untimed", "extend_span", "shrink_span", "visual", "open_url", "join", "execute", "focus", "review", "plan", "shift_day", "undo_shift", "compare_days", "edit_error", "toggle_presentation", "toggle_agenda", "toggle_compact", "toggle_time_audit", "peek", "toggle_rem_line", "toggle_pin", "cycle_status", "toggle_due_groups", "toggle_section", "expand_sections", "repeat", "toggle_sources", "filter_tag", "next", "edit_config", "edit_note", "screenshot", "screenshot_redacted", "export_csv", "format_file", "refresh"}
 	addBoundActions(basicActions)
 
 	// Templates section
@@ -149,7 +204,7 @@ func (m *Model) viewHelp() string {
 	}
 
 	// Clipboard section (if bound)
-	clipboardActions := []string{"copy", "cut", "paste"}
+	clipboardActions := []string{"copy", "cut", "paste", "copy_description", "copy_rem_line", "copy_date", "copy_agenda"}
 	hasClipboard := false
 	for _, action := range clipboardActions {
 		for _, boundAction := range m.config.KeyBindings {
@@ -166,7 +221,7 @@ func (m *Model) viewHelp() string {
 	}
 
 	// Search section (if bound)
-	searchActions := []string{"begin_search", "search_next"}
+	searchActions := []string{"begin_search", "search_next", "fuzzy_find"}
 	hasSearch := false
 	for _, action := range searchActions {
 		for _, boundAction := range m.config.KeyBindings {
@@ -199,24 +254,29 @@ func (m *Model) viewHelp() string {
 	help = append(help, "")
 	help = append(help, m.styles.Normal.Render("Event Colors:"))
 
+	swatch := func(color lipgloss.ANSIColor, label, description string) string {
+		return "    " + lipgloss.NewStyle().Background(color).Foreground(m.getEventTextColor(color)).Render(label) + " " + description
+	}
+	colors := m.colors()
+
 	// P2 Task colors
 	help = append(help, m.styles.Help.Render("  P2 Tasks:"))
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(88)).Foreground(m.getEventTextColor(88)).Render("  4+ hours  ")+" Long tasks")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(208)).Foreground(m.getEventTextColor(208)).Render("  2-4 hours ")+" Medium tasks")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(220)).Foreground(m.getEventTextColor(220)).Render("  1-2 hours ")+" Short tasks")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(48)).Foreground(m.getEventTextColor(48)).Render("  <1 hour   ")+" Quick tasks")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(24)).Foreground(m.getEventTextColor(24)).Render("  No duration")+" Default P2")
+	help = append(help, swatch(colors.p2Durations[0], "  4+ hours  ", "Long tasks"))
+	help = append(help, swatch(colors.p2Durations[1], "  2-4 hours ", "Medium tasks"))
+	help = append(help, swatch(colors.p2Durations[2], "  1-2 hours ", "Short tasks"))
+	help = append(help, swatch(colors.p2Durations[3], "  <1 hour   ", "Quick tasks"))
+	help = append(help, swatch(colors.p2Default, "  No duration", "Default P2"))
 
 	// Remind event colors
 	help = append(help, m.styles.Help.Render("  Remind Events:"))
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(52)).Foreground(m.getEventTextColor(52)).Render("  4+ hours  ")+" Long events")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(63)).Foreground(m.getEventTextColor(63)).Render("  2-4 hours ")+" Medium events")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(99)).Foreground(m.getEventTextColor(99)).Render("  1-2 hours ")+" Short events")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(105)).Foreground(m.getEventTextColor(105)).Render("  <1 hour   ")+" Brief events")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(196)).Foreground(m.getEventTextColor(196)).Render("  High prio ")+" Important")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(214)).Foreground(m.getEventTextColor(214)).Render("  Med prio  ")+" Medium")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(228)).Foreground(m.getEventTextColor(228)).Render("  Low prio  ")+" Low priority")
-	help = append(help, "    "+lipgloss.NewStyle().Background(lipgloss.ANSIColor(240)).Foreground(m.getEventTextColor(240)).Render("  No prio   ")+" Normal")
+	help = append(help, swatch(colors.remindDurations[0], "  4+ hours  ", "Long events"))
+	help = append(help, swatch(colors.remindDurations[1], "  2-4 hours ", "Medium events"))
+	help = append(help, swatch(colors.remindDurations[2], "  1-2 hours ", "Short events"))
+	help = append(help, swatch(colors.remindDurations[3], "  <1 hour   ", "Brief events"))
+	help = append(help, swatch(colors.priorities[3], "  High prio ", "Important"))
+	help = append(help, swatch(colors.priorities[2], "  Med prio  ", "Medium"))
+	help = append(help, swatch(colors.priorities[1], "  Low prio  ", "Low priority"))
+	help = append(help, swatch(colors.priorities[0], "  No prio   ", "Normal"))
 
 	help = append(help, "")
 	// Show which keys actually exit help based on configuration
@@ -254,6 +314,7 @@ func (m *Model) viewEventSelector() string {
 	} else {
 		for i, event := range m.eventChoices {
 			prefix := fmt.Sprintf("%d. ", i+1)
+			event = m.displayEvent(event)
 
 			// Format the event description
 			var eventStr string
@@ -261,11 +322,11 @@ func (m *Model) viewEventSelector() string {
 				eventStr = fmt.Sprintf("%s %s - %s",
 					event.Time.Format("15:04"),
 					event.Description,
-					event.Date.Format("Jan 2"))
+					event.Date.Format(m.config.DateLayout("Jan 2")))
 			} else {
 				eventStr = fmt.Sprintf("%s - %s",
 					event.Description,
-					event.Date.Format("Jan 2"))
+					event.Date.Format(m.config.DateLayout("Jan 2")))
 			}
 
 			// Highlight the selected item
@@ -299,6 +360,7 @@ func (m *Model) viewClipboardSelector() string {
 	} else {
 		for i, event := range m.eventChoices {
 			prefix := fmt.Sprintf("%d. ", i+1)
+			event = m.displayEvent(event)
 
 			// Format the event description
 			var eventStr string
@@ -306,11 +368,11 @@ func (m *Model) viewClipboardSelector() string {
 				eventStr = fmt.Sprintf("%s %s - %s",
 					event.Time.Format("15:04"),
 					event.Description,
-					event.Date.Format("Jan 2"))
+					event.Date.Format(m.config.DateLayout("Jan 2")))
 			} else {
 				eventStr = fmt.Sprintf("%s - %s",
 					event.Description,
-					event.Date.Format("Jan 2"))
+					event.Date.Format(m.config.DateLayout("Jan 2")))
 			}
 
 			// Highlight the selected item
@@ -340,15 +402,11 @@ func (m *Model) viewEventEditor() string {
 	sections = append(sections, m.styles.Help.Render("Examples: 'tomorrow 2pm Meeting' or 'next friday Lunch with Jim'"))
 
 	// Show input with cursor
-	input := m.inputBuffer
-	if m.cursorPos < len(input) {
-		input = input[:m.cursorPos] + "█" + input[m.cursorPos:]
-	} else {
-		input = input + "█"
-	}
-
-	inputLine := m.styles.Selected.Render(input)
+	inputLine := m.styles.Selected.Render(m.quickAddInput.View())
 	sections = append(sections, inputLine)
+
+	// Live preview of how the input will be interpreted
+	sections = append(sections, m.quickAddPreview()...)
 	sections = append(sections, "")
 
 	help := m.styles.Help.Render("Enter to save, Esc to cancel")
@@ -357,6 +415,50 @@ func (m *Model) viewEventEditor() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// quickAddPreview returns the parsed date, time and duration of the quick-add
+// input and the REM line that will be written for it, followed by the events
+// already scheduled then
+func (m *Model) quickAddPreview() []string {
+	input := m.quickAddInput.Value()
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	parsed, line, err := remind.QuickEventLine(input, m.now(), m.config.QuickDayFirst)
+	if err != nil {
+		return []string{m.styles.Priority.Render(err.Error())}
+	}
+
+	summary := "Date: " + parsed.Date.Format(m.config.DateLayout("Mon Jan 2, 2006"))
+	if parsed.HasTime {
+		summary += "  Time: " + parsed.Time.Format("15:04")
+	} else {
+		summary += "  (untimed)"
+	}
+	if parsed.Duration > 0 {
+		summary += "  Duration: " + formatDuration(parsed.Duration)
+	}
+
+	return append([]string{
+		m.styles.Help.Render(summary),
+		m.styles.Help.Render(line),
+	}, m.conflictLines()...)
+}
+
+// formatDuration formats a duration as e.g. "1h", "1h 30m" or "45m"
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
 func (m *Model) viewGotoDate() string {
 	var sections []string
 
@@ -366,17 +468,14 @@ func (m *Model) viewGotoDate() string {
 
 	prompt := m.styles.Normal.Render("Enter date:")
 	sections = append(sections, prompt)
-	sections = append(sections, m.styles.Help.Render("Formats: YYYY-MM-DD, MM/DD/YYYY, MM/DD, today, tomorrow, next monday, etc."))
-
-	// Show input with cursor
-	input := m.inputBuffer
-	if m.cursorPos < len(input) {
-		input = input[:m.cursorPos] + "█" + input[m.cursorPos:]
-	} else {
-		input = input + "█"
+	formats := "YYYY-MM-DD, MM/DD"
+	if m.config != nil && m.config.GotoDayFirst {
+		formats = "YYYY-MM-DD, DD/MM"
 	}
+	sections = append(sections, m.styles.Help.Render("Formats: "+formats+", today, next fri, +3w, -2d, eom, 2025-W40, etc."))
 
-	inputLine := m.styles.Selected.Render(input)
+	// Show input with cursor
+	inputLine := m.styles.Selected.Render(m.gotoInput.View())
 	sections = append(sections, inputLine)
 	sections = append(sections, "")
 
@@ -398,14 +497,7 @@ func (m *Model) viewSearch() string {
 	sections = append(sections, m.styles.Help.Render("Search in event descriptions, tags, and content"))
 
 	// Show input with cursor
-	input := m.inputBuffer
-	if m.cursorPos < len(input) {
-		input = input[:m.cursorPos] + "█" + input[m.cursorPos:]
-	} else {
-		input = input + "█"
-	}
-
-	inputLine := m.styles.Selected.Render(input)
+	inputLine := m.styles.Selected.Render(m.searchInput.View())
 	sections = append(sections, inputLine)
 	sections = append(sections, "")
 
@@ -455,3 +547,266 @@ func (m *Model) viewURLSelector() string {
 
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
+
+func (m *Model) viewUpcoming() string {
+	var sections []string
+
+	header := m.styles.Header.Render("Upcoming Events")
+	sections = append(sections, header)
+	sections = append(sections, "")
+
+	now := m.now()
+	for i, event := range m.upcomingEvents {
+		event = m.displayEvent(event)
+
+		when := event.Date.Format(m.config.DateLayout("Mon Jan _2"))
+		if event.Time != nil {
+			when += " " + event.Time.Format("15:04")
+		} else {
+			when += "      "
+		}
+		line := fmt.Sprintf("%s  %-15s  %s", when, relativeTime(now, event), event.Description)
+
+		// Highlight the selected item
+		if i == m.selectedUpcomingIndex {
+			sections = append(sections, m.styles.Selected.Render(line))
+		} else {
+			sections = append(sections, m.styles.Normal.Render(line))
+		}
+	}
+
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Help.Render("Enter: Go to event  j/k: Navigate  Esc: Cancel"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewFuzzyFind() string {
+	var sections []string
+
+	sections = append(sections, m.styles.Header.Render("Find Event"))
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Selected.Render(m.fuzzyInput.View()))
+	sections = append(sections, "")
+
+	// Leave room for the header, input and help lines
+	maxResults := m.height - 6
+	if maxResults < 5 {
+		maxResults = 5
+	}
+
+	if len(m.fuzzyMatches) == 0 {
+		sections = append(sections, m.styles.Help.Render("No matching events"))
+	}
+
+	// Scroll so the selected match stays visible
+	start := 0
+	if m.selectedFuzzyIndex >= maxResults {
+		start = m.selectedFuzzyIndex - maxResults + 1
+	}
+	for i := start; i < len(m.fuzzyMatches) && i < start+maxResults; i++ {
+		event := m.fuzzyMatches[i]
+
+		when := event.Date.Format(m.config.DateLayout("Mon Jan _2"))
+		if event.Time != nil {
+			when += " " + event.Time.Format("15:04")
+		} else {
+			when += "      "
+		}
+		line := fmt.Sprintf("%s  %s", when, event.Description)
+
+		if i == m.selectedFuzzyIndex {
+			sections = append(sections, m.styles.Selected.Render(line))
+		} else {
+			sections = append(sections, m.styles.Normal.Render(line))
+		}
+	}
+
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Help.Render(
+		fmt.Sprintf("%d matches  Enter: Go to event  Up/Down: Select  Alt+P/Alt+N: History  Esc: Cancel", len(m.fuzzyMatches))))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewExecute() string {
+	var sections []string
+
+	sections = append(sections, m.styles.Header.Render("Run Command"))
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Normal.Render("$ "+m.execCommand))
+	sections = append(sections, "")
+
+	switch m.execPhase {
+	case execConfirm:
+		sections = append(sections, m.styles.Selected.Render("Run this command? [y/n]"))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	case execRunning:
+		sections = append(sections, m.styles.Help.Render("Running..."))
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Help.Render("Esc: Close (the command keeps running)"))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	if len(m.execOutput) == 0 {
+		sections = append(sections, m.styles.Help.Render("(no output)"))
+	}
+	end := m.execScroll + m.execVisibleLines()
+	if end > len(m.execOutput) {
+		end = len(m.execOutput)
+	}
+	for _, line := range m.execOutput[m.execScroll:end] {
+		sections = append(sections, m.styles.Normal.Render(line))
+	}
+
+	sections = append(sections, "")
+	status := "Exited successfully"
+	if m.execErr != nil {
+		status = fmt.Sprintf("Failed: %v", m.execErr)
+	}
+	if len(m.execOutput) > m.execVisibleLines() {
+		status += fmt.Sprintf("  (lines %d-%d of %d)", m.execScroll+1, end, len(m.execOutput))
+	}
+	sections = append(sections, m.styles.Help.Render(status))
+	sections = append(sections, m.styles.Help.Render("j/k: Scroll  Esc: Close"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewJoinPrompt() string {
+	event := m.displayEvent(m.joinEvent)
+
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Meeting Starting"))
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("%s  %s", event.Time.Format("15:04"), event.Description)))
+	// The link of a private meeting is as identifying as its description
+	if url := meetingURL(event); url != "" {
+		sections = append(sections, m.styles.Help.Render(url))
+	}
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Selected.Render(fmt.Sprintf("Join %s now? [y/n]", event.Description)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewReview() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Daily Review"))
+	sections = append(sections, "")
+
+	if m.reviewPhase == reviewItems {
+		event := m.displayEvent(m.reviewItems[0])
+		yesterday := m.reviewDay.AddDate(0, 0, -1)
+		sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("Left over from %s (%d to go)", yesterday.Format(m.config.DateLayout("Monday, January 2")), len(m.reviewItems))))
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Selected.Render(event.Description))
+		if location := event.SourceLocation(); location != "" {
+			sections = append(sections, m.styles.Help.Render(location))
+		}
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Help.Render("d: Done  f: Defer to today  x: Delete  s: Skip  Esc: Finish"))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	sections = append(sections, m.styles.Normal.Render(m.reviewDay.Format(m.config.DateLayout("Monday, January 2"))))
+	sections = append(sections, "")
+	if len(m.reviewAgenda) == 0 {
+		sections = append(sections, m.styles.Help.Render("Nothing scheduled"))
+	}
+	for _, event := range m.reviewAgenda {
+		event = m.displayEvent(event)
+		when := "     "
+		if event.Time != nil {
+			when = event.Time.Format("15:04")
+		}
+		sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("%s  %s", when, event.Description)))
+	}
+
+	if len(m.reviewTodos) > 0 {
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Header.Render("Unplanned high-priority todos"))
+		for i, event := range m.reviewTodos {
+			line := m.displayEvent(event).Description
+			if i == m.selectedReviewTodo {
+				sections = append(sections, m.styles.Selected.Render(line))
+			} else {
+				sections = append(sections, m.styles.Normal.Render(line))
+			}
+		}
+
+		sections = append(sections, "")
+		if len(m.reviewOpenings) == 0 {
+			sections = append(sections, m.styles.Help.Render("No free time left today"))
+		} else {
+			start := m.reviewOpenings[m.selectedOpening]
+			sections = append(sections, m.styles.Message.Render(fmt.Sprintf("Free at %s-%s (%d of %d openings)",
+				start.Format("15:04"), start.Add(reviewTaskLength).Format("15:04"), m.selectedOpening+1, len(m.reviewOpenings))))
+		}
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Help.Render("j/k: Select todo  n: Next opening  Enter: Schedule  Esc: Finish"))
+	} else {
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Help.Render("Esc: Finish"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewPlan() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Plan Estimated Tasks"))
+	sections = append(sections, "")
+
+	for _, p := range m.planPlacements {
+		when := fmt.Sprintf("%s %s-%s", p.start.Format(m.config.DateLayout("Mon Jan _2")), p.start.Format("15:04"), p.start.Add(p.estimate).Format("15:04"))
+		sections = append(sections, m.styles.Normal.Render(fmt.Sprintf("%s  %s", when, m.displayEvent(p.event).Description)))
+	}
+
+	if len(m.planUnplaced) > 0 {
+		sections = append(sections, "")
+		sections = append(sections, m.styles.Message.Render("No free time this week for:"))
+		for _, event := range m.planUnplaced {
+			sections = append(sections, m.styles.Normal.Render("  "+m.displayEvent(event).Description))
+		}
+	}
+
+	sections = append(sections, "")
+	if len(m.planPlacements) > 0 {
+		sections = append(sections, m.styles.Selected.Render(fmt.Sprintf("Schedule %d tasks? [y/n]", len(m.planPlacements))))
+	} else {
+		sections = append(sections, m.styles.Help.Render("Esc: Close"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m *Model) viewSources() string {
+	var sections []string
+	sections = append(sections, m.styles.Header.Render("Sources"))
+	sections = append(sections, "")
+
+	if sources, ok := m.source.(remind.ToggleableSource); ok {
+		health := m.sourceHealth()
+		for i, name := range sources.SourceNames() {
+			check := "[ ]"
+			if sources.Enabled(name) {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("%s %s", check, name)
+			if i == m.selectedSourceIndex {
+				sections = append(sections, m.styles.Selected.Render(line))
+			} else {
+				sections = append(sections, m.styles.Normal.Render(line))
+			}
+			if h, ok := health[name]; ok {
+				sections = append(sections, m.healthLines(h)...)
+			}
+		}
+	}
+
+	sections = append(sections, "")
+	sections = append(sections, m.styles.Help.Render("Space: Show/hide  j/k: Navigate  Esc: Close"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// startVisual anchors a range of slots at the selected slot, which j and k
+// then extend downward and Enter turns into a new timed event, the way
+// dragging over a GUI calendar does
+func (m *Model) startVisual() {
+	if m.focusUntimed {
+		m.showMessage("Select a time slot to start from")
+		return
+	}
+	m.visual = true
+	m.spanStart, m.spanLength = m.selectedSlotStart(), 0
+	m.showMessage("Visual: j/k to extend, Enter to add an event, Esc to cancel")
+}
+
+// handleVisualKey handles a key while a range is being selected. It
+// reports false, leaving visual mode, for keys that aren't about the
+// range, so they do what they usually do.
+func (m *Model) handleVisualKey(key, action string) (bool, tea.Cmd) {
+	step := time.Duration(m.timeIncrement) * time.Minute
+	switch {
+	case action == "scroll_down" || action == "extend_span":
+		m.resizeSpan(true)
+
+	case action == "scroll_up" || action == "shrink_span":
+		m.resizeSpan(false)
+
+	case key == "<enter>" || action == "edit":
+		// The range is exactly what the event covers, even a single slot
+		m.visual = false
+		if m.spanLength == 0 {
+			m.spanStart, m.spanLength = m.selectedSlotStart(), step
+		}
+		_, cmd := m.handleHourlyKeys(key, "new_timed")
+		return true, cmd
+
+	case key == "<esc>" || action == "visual":
+		m.visual = false
+		m.spanLength = 0
+		m.showMessage("")
+
+	default:
+		m.visual = false
+		return false, nil
+	}
+
+	// Scroll to keep the end of the range on screen
+	if length := m.selectedSpan(); length > step {
+		end := m.selectedSlot + int(length/step) - 1
+		for !m.isSlotVisible(end) && m.topSlot < m.selectedSlot {
+			m.topSlot++
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestVisualNewEvent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "reminders.rem")
+	client := remind.NewClient()
+	client.SetFiles([]string{file})
+
+	cfg := config.DefaultConfig()
+	cfg.TimedTemplate = `REM %monname% %mday% %year% AT %hour%:%min% DURATION %dura%:00 MSG`
+	day := time.Date(2025, 8, 25, 0, 0, 0, 0, time.Local)
+	m := &Model{
+		config:        cfg,
+		source:        client,
+		remindClient:  client,
+		clock:         remind.FixedClock(day.Add(8 * time.Hour)),
+		selectedDate:  day,
+		timeIncrement: 30,
+		selectedSlot:  20, // 10:00
+	}
+
+	// Anchored at 10:00, extended over three slots and back one
+	m.handleHourlyKeys("\\Cv", "visual")
+	for _, action := range []string{"scroll_down", "scroll_down", "scroll_down", "scroll_up"} {
+		m.handleHourlyKeys("", action)
+	}
+	if !m.visual || m.selectedSlot != 20 || !m.inSpan(day.Add(11*time.Hour)) || m.inSpan(day.Add(11*time.Hour+30*time.Minute)) {
+		t.Fatalf("expected 10:00 to 11:30 selected, got slot %d, %v from %v", m.selectedSlot, m.spanLength, m.spanStart)
+	}
+	m.handleHourlyKeys("<enter>", "edit")
+	if m.visual {
+		t.Error("expected visual mode to end")
+	}
+
+	// A single slot is an event of a slot, not the template's hour
+	m.selectedSlot = 28
+	m.handleHourlyKeys("\\Cv", "visual")
+	m.handleHourlyKeys("<enter>", "edit")
+
+	// Esc drops the range, and other keys work as usual
+	m.handleHourlyKeys("\\Cv", "visual")
+	m.handleHourlyKeys("", "scroll_down")
+	m.handleHourlyKeys("<esc>", "")
+	if m.visual || m.selectedSpan() != 0 {
+		t.Error("expected Esc to cancel the selection")
+	}
+	m.handleHourlyKeys("\\Cv", "visual")
+	m.handleHourlyKeys("L", "next_day")
+	if m.visual || !sameDay(m.selectedDate, day.AddDate(0, 0, 1)) {
+		t.Error("expected next_day to leave visual mode and move on")
+	}
+
+	data, _ := os.ReadFile(file)
+	expected := "REM Aug 25 2025 AT 10:00 DURATION 1:30 MSG\n" +
+		"REM Aug 25 2025 AT 14:00 DURATION 0:30 MSG\n"
+	if string(data) != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", data, expected)
+	}
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// busyBars are the bar heights for how much of a day is booked
+var busyBars = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// weekDayActions jump to a day of the selected week, Monday first
+var weekDayActions = []string{
+	"goto_monday", "goto_tuesday", "goto_wednesday", "goto_thursday",
+	"goto_friday", "goto_saturday", "goto_sunday",
+}
+
+// weekStart returns the Monday of the week day is in
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, day.Location())
+}
+
+// busyFraction returns how much of the working hours on day timed events
+// take up, or of the whole day without working hours
+func (m *Model) busyFraction(day time.Time) float64 {
+	from, until := m.workingHours(day)
+	if !until.After(from) {
+		from = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+		until = from.AddDate(0, 0, 1)
+	}
+
+	var busy time.Duration
+	for _, r := range busyRanges(m.eventsOn(day), day) {
+		start, end := r.start, r.end
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(until) {
+			end = until
+		}
+		if end.After(start) {
+			busy += end.Sub(start)
+		}
+	}
+	return float64(busy) / float64(until.Sub(from))
+}
+
+// renderWeekStrip renders the selected week as a row of busy bars, with
+// today marked, to go under the mini calendar
+func (m *Model) renderWeekStrip(now time.Time) string {
+	monday := weekStart(m.selectedDate)
+
+	var labels, bars, marker []string
+	hasToday := false
+	for i := range 7 {
+		day := monday.AddDate(0, 0, i)
+
+		label := day.Format("Mon")[:2]
+		switch {
+		case sameDay(day, m.selectedDate):
+			label = m.styles.Selected.Render(label)
+		case sameDay(day, now):
+			label = m.styles.Today.Render(label)
+		case day.Weekday() == time.Saturday || day.Weekday() == time.Sunday:
+			label = m.styles.Weekend.Render(label)
+		default:
+			label = m.styles.Normal.Render(label)
+		}
+		labels = append(labels, label)
+
+		bar := m.styles.Help.Render("··")
+		if busy := m.busyFraction(day); busy > 0 {
+			level := int(busy*float64(len(busyBars))+0.999) - 1
+			if level >= len(busyBars) {
+				level = len(busyBars) - 1
+			}
+			bar = m.styles.Event.Render(strings.Repeat(busyBars[level], 2))
+		}
+		bars = append(bars, bar)
+
+		if sameDay(day, now) {
+			hasToday = true
+			marker = append(marker, m.styles.Today.Render("▲ "))
+		} else {
+			marker = append(marker, "  ")
+		}
+	}
+
+	lines := []string{strings.Join(labels, " "), strings.Join(bars, " ")}
+	if hasToday {
+		lines = append(lines, strings.Join(marker, " "))
+	}
+	return m.styles.Border.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwarden/urd/internal/config"
+	"github.com/cwarden/urd/internal/remind"
+)
+
+func TestWeekStrip(t *testing.T) {
+	wednesday := time.Date(2025, 8, 27, 0, 0, 0, 0, time.Local)
+	at := func(day time.Time, hour int) *time.Time {
+		at := day.Add(time.Duration(hour) * time.Hour)
+		return &at
+	}
+	m := &Model{
+		selectedDate: wednesday.Add(10 * time.Hour),
+		config:       &config.Config{WorkStart: 9 * time.Hour, WorkEnd: 17 * time.Hour},
+		styles:       defaultStyles(),
+		events: []remind.Event{
+			// Wednesday is booked all day, Thursday a quarter of it
+			{Date: wednesday, Time: at(wednesday, 8), Duration: durationPtr(10 * 60)},
+			{Date: wednesday.AddDate(0, 0, 1), Time: at(wednesday.AddDate(0, 0, 1), 9), Duration: durationPtr(2 * 60)},
+			// Untimed reminders and events without a duration don't count
+			{Date: wednesday.AddDate(0, 0, 2), Description: "Birthday"},
+			{Date: wednesday.AddDate(0, 0, 2), Time: at(wednesday.AddDate(0, 0, 2), 12)},
+		},
+	}
+
+	if got := m.busyFraction(wednesday); got != 1 {
+		t.Errorf("busyFraction(Wednesday) = %v, want 1", got)
+	}
+	if got := m.busyFraction(wednesday.AddDate(0, 0, 1)); got != 0.25 {
+		t.Errorf("busyFraction(Thursday) = %v, want 0.25", got)
+	}
+	if got := m.busyFraction(wednesday.AddDate(0, 0, 2)); got != 0 {
+		t.Errorf("busyFraction(Friday) = %v, want 0", got)
+	}
+
+	strip := m.renderWeekStrip(wednesday.AddDate(0, 0, 1))
+	for _, want := range []string{"Mo Tu We Th Fr Sa Su", "██ ▂▂ ··", "▲"} {
+		if !strings.Contains(strip, want) {
+			t.Errorf("week strip missing %q:\n%s", want, strip)
+		}
+	}
+	if strip := m.renderWeekStrip(wednesday.AddDate(0, 0, 14)); strings.Contains(strip, "▲") {
+		t.Errorf("week strip marks today outside the week:\n%s", strip)
+	}
+
+	// Jumping keeps the selected time of day
+	m.eventsLoadedFor = m.selectedDate
+	m.handleHourlyKeys("1", "goto_monday")
+	if want := wednesday.AddDate(0, 0, -2).Add(10 * time.Hour); !m.selectedDate.Equal(want) {
+		t.Errorf("goto_monday selected %v, want %v", m.selectedDate, want)
+	}
+	m.handleHourlyKeys("7", "goto_sunday")
+	if want := wednesday.AddDate(0, 0, 4).Add(10 * time.Hour); !m.selectedDate.Equal(want) {
+		t.Errorf("goto_sunday selected %v, want %v", m.selectedDate, want)
+	}
+}